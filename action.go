@@ -27,6 +27,8 @@ type Action struct {
 	text                          string
 	toolTip                       string
 	image                         Image
+	imageList                     *ImageList
+	imageIndex                    int
 	checkedCondition              Condition
 	checkedConditionChangedHandle int
 	defaultCondition              Condition
@@ -43,6 +45,7 @@ type Action struct {
 	checked                       bool
 	defawlt                       bool
 	exclusive                     bool
+	radioGroup                    int
 	id                            uint16
 }
 
@@ -137,6 +140,8 @@ func (a *Action) SetChecked(value bool) (err error) {
 		if err = a.raiseChanged(); err != nil {
 			a.checked = old
 			a.raiseChanged()
+		} else if value {
+			err = a.uncheckRadioGroup()
 		}
 	}
 
@@ -292,6 +297,50 @@ func (a *Action) SetExclusive(value bool) (err error) {
 	return
 }
 
+// RadioGroup returns the radio group of the Action, or 0 if it does not belong
+// to one.
+func (a *Action) RadioGroup() int {
+	return a.radioGroup
+}
+
+// SetRadioGroup sets the radio group of the Action.
+//
+// Actions sharing a radio group other than 0 are mutually exclusive: Checking
+// one of them unchecks the others. They are displayed with a radio bullet in
+// menus and triggering a checked one does not uncheck it.
+func (a *Action) SetRadioGroup(group int) (err error) {
+	if group != a.radioGroup {
+		old := a.radioGroup
+
+		a.radioGroup = group
+
+		if err = a.raiseChanged(); err != nil {
+			a.radioGroup = old
+			a.raiseChanged()
+		} else if a.checked {
+			err = a.uncheckRadioGroup()
+		}
+	}
+
+	return
+}
+
+func (a *Action) uncheckRadioGroup() error {
+	if a.radioGroup == 0 {
+		return nil
+	}
+
+	for _, other := range actionsById {
+		if other != a && other.radioGroup == a.radioGroup && other.checked {
+			if err := other.SetChecked(false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (a *Action) Image() Image {
 	return a.image
 }
@@ -311,6 +360,30 @@ func (a *Action) SetImage(value Image) (err error) {
 	return
 }
 
+// ImageListImage returns the ImageList and the index of the image in it that
+// is displayed for the Action in menus, if any.
+func (a *Action) ImageListImage() (imageList *ImageList, index int) {
+	return a.imageList, a.imageIndex
+}
+
+// SetImageListImage sets the image at index of imageList to be displayed for
+// the Action in menus, so no Bitmap needs to be maintained per item. An Image
+// set via SetImage takes precedence. Pass a nil imageList to clear.
+func (a *Action) SetImageListImage(imageList *ImageList, index int) (err error) {
+	if imageList != a.imageList || index != a.imageIndex {
+		oldImageList, oldIndex := a.imageList, a.imageIndex
+
+		a.imageList, a.imageIndex = imageList, index
+
+		if err = a.raiseChanged(); err != nil {
+			a.imageList, a.imageIndex = oldImageList, oldIndex
+			a.raiseChanged()
+		}
+	}
+
+	return
+}
+
 func (a *Action) Shortcut() Shortcut {
 	return a.shortcut
 }
@@ -437,7 +510,9 @@ func (a *Action) Triggered() *Event {
 }
 
 func (a *Action) raiseTriggered() {
-	if a.Checkable() {
+	if a.radioGroup != 0 {
+		a.SetChecked(true)
+	} else if a.Checkable() {
 		a.SetChecked(!a.Checked())
 	}
 
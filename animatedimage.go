@@ -0,0 +1,205 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"time"
+
+	"github.com/lxn/win"
+)
+
+// AnimatedImage is a sequence of Bitmap frames with per-frame delays, as
+// decoded from an animated GIF.
+//
+// When drawn as a plain Image, the current frame is drawn. Use
+// ImageView.SetAnimatedImage to play the animation.
+type AnimatedImage struct {
+	frames    []*Bitmap
+	delays    []time.Duration
+	loopCount int
+	size      Size
+	current   int
+}
+
+// NewAnimatedImageFromFile decodes the animated GIF stored in the file at
+// filePath.
+func NewAnimatedImageFromFile(filePath string) (*AnimatedImage, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	defer f.Close()
+
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return NewAnimatedImageFromGIF(g)
+}
+
+// NewAnimatedImageFromGIF creates an AnimatedImage from an already decoded
+// GIF, compositing the frames according to their disposal methods.
+func NewAnimatedImageFromGIF(g *gif.GIF) (*AnimatedImage, error) {
+	if len(g.Image) == 0 {
+		return nil, newError("gif contains no frames")
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+
+	ai := &AnimatedImage{
+		size: Size{bounds.Dx(), bounds.Dy()},
+	}
+
+	switch g.LoopCount {
+	case 0:
+		// Loop forever.
+
+	case -1:
+		ai.loopCount = 1
+
+	default:
+		ai.loopCount = g.LoopCount + 1
+	}
+
+	var disposables Disposables
+	defer disposables.Treat()
+
+	canvas := image.NewRGBA(bounds)
+	var previous *image.RGBA
+
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		bmp, err := NewBitmapFromImage(canvas)
+		if err != nil {
+			return nil, err
+		}
+		disposables.Add(bmp)
+
+		ai.frames = append(ai.frames, bmp)
+
+		var delay time.Duration
+		if i < len(g.Delay) {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		if delay <= 10*time.Millisecond {
+			// Like web browsers, we treat very short delays as the default.
+			delay = 100 * time.Millisecond
+		}
+		ai.delays = append(ai.delays, delay)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.ZP, draw.Src)
+
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	disposables.Spare()
+
+	return ai, nil
+}
+
+// FrameCount returns the number of frames of the AnimatedImage.
+func (ai *AnimatedImage) FrameCount() int {
+	return len(ai.frames)
+}
+
+// Frame returns the fully composited frame at the specified index.
+func (ai *AnimatedImage) Frame(index int) *Bitmap {
+	return ai.frames[index]
+}
+
+// FrameDelay returns how long the frame at the specified index should be
+// displayed.
+func (ai *AnimatedImage) FrameDelay(index int) time.Duration {
+	return ai.delays[index]
+}
+
+// LoopCount returns how many times the animation should be played.
+//
+// A value of 0 means the animation loops forever.
+func (ai *AnimatedImage) LoopCount() int {
+	return ai.loopCount
+}
+
+// CurrentFrame returns the index of the frame that is drawn when the
+// AnimatedImage is drawn as a plain Image.
+func (ai *AnimatedImage) CurrentFrame() int {
+	return ai.current
+}
+
+// SetCurrentFrame sets the index of the frame that is drawn when the
+// AnimatedImage is drawn as a plain Image.
+func (ai *AnimatedImage) SetCurrentFrame(index int) error {
+	if index < 0 || index >= len(ai.frames) {
+		return newError("index out of range")
+	}
+
+	ai.current = index
+
+	return nil
+}
+
+// Dispose releases all decoded frame bitmaps.
+func (ai *AnimatedImage) Dispose() {
+	for _, bmp := range ai.frames {
+		bmp.Dispose()
+	}
+
+	ai.frames = nil
+	ai.delays = nil
+	ai.current = 0
+}
+
+func (ai *AnimatedImage) Size() Size {
+	return ai.size
+}
+
+func (ai *AnimatedImage) draw(hdc win.HDC, location Point) error {
+	if len(ai.frames) == 0 {
+		return nil
+	}
+
+	return ai.frames[ai.current].draw(hdc, location)
+}
+
+func (ai *AnimatedImage) drawStretched(hdc win.HDC, bounds Rectangle) error {
+	if len(ai.frames) == 0 {
+		return nil
+	}
+
+	return ai.frames[ai.current].drawStretched(hdc, bounds)
+}
+
+func (ai *AnimatedImage) drawStretchedOnCanvas(canvas *Canvas, bounds Rectangle) error {
+	if len(ai.frames) == 0 {
+		return nil
+	}
+
+	return ai.frames[ai.current].drawStretchedOnCanvas(canvas, bounds)
+}
@@ -9,7 +9,13 @@ package walk
 import (
 	"fmt"
 	"image"
-	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -17,10 +23,12 @@ import (
 )
 
 type Bitmap struct {
-	hBmp       win.HBITMAP
-	hPackedDIB win.HGLOBAL
-	size       Size
-	dpi        int // TODO: Unused
+	hBmp          win.HBITMAP
+	hPackedDIB    win.HGLOBAL
+	size          Size
+	dpi           int
+	stretched     *Bitmap // Cached by drawStretchedOnCanvas
+	stretchedMode InterpolationMode
 }
 
 func NewBitmap(size Size) (*Bitmap, error) {
@@ -93,12 +101,29 @@ func NewBitmapFromFile(filePath string) (*Bitmap, error) {
 }
 
 func NewBitmapFromImage(im image.Image) (*Bitmap, error) {
+	return NewBitmapFromImageForDPI(im, 0)
+}
+
+// NewBitmapFromImageForDPI creates a 32 bit Bitmap with premultiplied alpha
+// from im, so transparent pixels are preserved when the Bitmap is drawn.
+//
+// dpi is the resolution the pixels of im are meant for. It is used for
+// Canvases created from the Bitmap. A value of 0 means the screen DPI.
+func NewBitmapFromImageForDPI(im image.Image, dpi int) (*Bitmap, error) {
 	hBmp, err := hBitmapFromImage(im)
 	if err != nil {
 		return nil, err
 	}
 
-	return newBitmapFromHBITMAP(hBmp)
+	bmp, err := newBitmapFromHBITMAP(hBmp)
+	if err != nil {
+		win.DeleteObject(win.HGDIOBJ(hBmp))
+		return nil, err
+	}
+
+	bmp.dpi = dpi
+
+	return bmp, nil
 }
 
 func NewBitmapFromResource(name string) (*Bitmap, error) {
@@ -175,37 +200,123 @@ func NewBitmapFromIcon(icon *Icon, size Size) (*Bitmap, error) {
 	return newBitmapFromHBITMAP(hBmp)
 }
 
+// ToImage returns the pixels of bmp as an *image.RGBA.
+//
+// 32 bit bitmaps are expected to contain premultiplied alpha, like the ones
+// created by NewBitmapFromImage. Bitmaps without an alpha channel result in
+// an opaque image.
 func (bmp *Bitmap) ToImage() (*image.RGBA, error) {
+	img, _, err := bmp.toImage()
+	return img, err
+}
+
+// hasAlpha returns if bmp is a 32 bit bitmap with an alpha channel that is
+// actually in use.
+func (bmp *Bitmap) hasAlpha() bool {
+	_, hasAlpha, err := bmp.toImage()
+
+	return err == nil && hasAlpha
+}
+
+func (bmp *Bitmap) toImage() (img *image.RGBA, hasAlpha bool, err error) {
+	hdc := win.GetDC(0)
+	if hdc == 0 {
+		return nil, false, newError("GetDC failed")
+	}
+	defer win.ReleaseDC(0, hdc)
+
 	var bi win.BITMAPINFO
 	bi.BmiHeader.BiSize = uint32(unsafe.Sizeof(bi.BmiHeader))
-	hdc := win.GetDC(0)
 	if ret := win.GetDIBits(hdc, bmp.hBmp, 0, 0, nil, &bi, win.DIB_RGB_COLORS); ret == 0 {
-		return nil, newError("GetDIBits get bitmapinfo failed")
+		return nil, false, newError("GetDIBits get bitmapinfo failed")
 	}
 
-	buf := make([]byte, bi.BmiHeader.BiSizeImage)
-	bi.BmiHeader.BiCompression = win.BI_RGB
-	if ret := win.GetDIBits(hdc, bmp.hBmp, 0, uint32(bi.BmiHeader.BiHeight), &buf[0], &bi, win.DIB_RGB_COLORS); ret == 0 {
-		return nil, newError("GetDIBits failed")
-	}
+	hasAlpha = bi.BmiHeader.BiBitCount == 32
 
 	width := int(bi.BmiHeader.BiWidth)
 	height := int(bi.BmiHeader.BiHeight)
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
-
-	n := 0
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			a := buf[n+3]
-			r := buf[n+2]
-			g := buf[n+1]
-			b := buf[n+0]
-			n += int(bi.BmiHeader.BiBitCount) / 8
-			img.Set(x, height-y-1, color.RGBA{r, g, b, a})
+	if height < 0 {
+		height = -height
+	}
+
+	// We always ask for top-down 32 bit pixels, so GDI converts other
+	// formats for us.
+	bi.BmiHeader.BiBitCount = 32
+	bi.BmiHeader.BiCompression = win.BI_RGB
+	bi.BmiHeader.BiHeight = -int32(height)
+	bi.BmiHeader.BiSizeImage = uint32(width * height * 4)
+
+	img = image.NewRGBA(image.Rect(0, 0, width, height))
+	if width == 0 || height == 0 {
+		return img, false, nil
+	}
+
+	if ret := win.GetDIBits(hdc, bmp.hBmp, 0, uint32(height), &img.Pix[0], &bi, win.DIB_RGB_COLORS); ret == 0 {
+		return nil, false, newError("GetDIBits failed")
+	}
+
+	if hasAlpha {
+		// GDI leaves the alpha channel alone, so a 32 bit bitmap that has
+		// only been drawn to by GDI is opaque rather than fully transparent.
+		hasAlpha = false
+		for i := 3; i < len(img.Pix); i += 4 {
+			if img.Pix[i] != 0 {
+				hasAlpha = true
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(img.Pix); i += 4 {
+		// BGRA -> RGBA
+		img.Pix[i], img.Pix[i+2] = img.Pix[i+2], img.Pix[i]
+
+		if !hasAlpha {
+			img.Pix[i+3] = 0xff
 		}
 	}
 
-	return img, nil
+	return img, hasAlpha, nil
+}
+
+// SaveToFile encodes bmp as PNG or JPEG, depending on the extension of
+// filePath, and writes it to the file at filePath.
+func (bmp *Bitmap) SaveToFile(filePath string) error {
+	var encode func(w io.Writer, img image.Image) error
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".png":
+		encode = png.Encode
+
+	case ".jpg", ".jpeg":
+		encode = func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: 90})
+		}
+
+	default:
+		return newError("unsupported file extension")
+	}
+
+	img, err := bmp.ToImage()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	if err := encode(f, img); err != nil {
+		f.Close()
+		return wrapError(err)
+	}
+
+	if err := f.Close(); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
 }
 
 func (bmp *Bitmap) postProcess() {
@@ -247,7 +358,92 @@ func (bmp *Bitmap) postProcess() {
 	}
 }
 
+// Crop returns a new Bitmap containing the pixels of bmp inside r.
+//
+// r is in 1/96" units and is scaled to the resolution the bitmap is drawn at
+// on a Canvas. The returned Bitmap owns its own pixels and must be disposed
+// independently of bmp.
+func (bmp *Bitmap) Crop(r Rectangle) (*Bitmap, error) {
+	dpi := bmp.dpi
+	if dpi == 0 {
+		dpi = ScreenDPI()
+	}
+
+	r = r.From96DPI(dpi)
+
+	if r.X < 0 || r.Y < 0 || r.Width <= 0 || r.Height <= 0 ||
+		r.X+r.Width > bmp.size.Width || r.Y+r.Height > bmp.size.Height {
+
+		return nil, newError("r must be a non-empty part of the bitmap")
+	}
+
+	var cropped *Bitmap
+
+	err := bmp.withSelectedIntoMemDC(func(hdcSrc win.HDC) error {
+		return withCompatibleDC(func(hdcDst win.HDC) error {
+			var hdr win.BITMAPINFOHEADER
+			hdr.BiSize = uint32(unsafe.Sizeof(hdr))
+			hdr.BiBitCount = 32
+			hdr.BiCompression = win.BI_RGB
+			hdr.BiPlanes = 1
+			hdr.BiWidth = int32(r.Width)
+			hdr.BiHeight = int32(r.Height)
+			hdr.BiSizeImage = uint32(r.Width * r.Height * 4)
+
+			var bitsPtr unsafe.Pointer
+
+			hBmp := win.CreateDIBSection(hdcDst, &hdr, win.DIB_RGB_COLORS, &bitsPtr, 0, 0)
+			switch hBmp {
+			case 0, win.ERROR_INVALID_PARAMETER:
+				return newError("CreateDIBSection failed")
+			}
+
+			hBmpOld := win.SelectObject(hdcDst, win.HGDIOBJ(hBmp))
+			if hBmpOld == 0 {
+				win.DeleteObject(win.HGDIOBJ(hBmp))
+				return newError("SelectObject failed")
+			}
+
+			ok := win.BitBlt(
+				hdcDst,
+				0,
+				0,
+				int32(r.Width),
+				int32(r.Height),
+				hdcSrc,
+				int32(r.X),
+				int32(r.Y),
+				win.SRCCOPY)
+
+			win.SelectObject(hdcDst, hBmpOld)
+			win.GdiFlush()
+
+			if !ok {
+				win.DeleteObject(win.HGDIOBJ(hBmp))
+				return newError("BitBlt failed")
+			}
+
+			var err error
+			if cropped, err = newBitmapFromHBITMAP(hBmp); err != nil {
+				win.DeleteObject(win.HGDIOBJ(hBmp))
+				return err
+			}
+
+			cropped.dpi = bmp.dpi
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cropped, nil
+}
+
 func (bmp *Bitmap) Dispose() {
+	bmp.disposeStretched()
+
 	if bmp.hBmp != 0 {
 		win.DeleteObject(win.HGDIOBJ(bmp.hBmp))
 
@@ -276,6 +472,171 @@ func (bmp *Bitmap) drawStretched(hdc win.HDC, bounds Rectangle) error {
 	return bmp.alphaBlend(hdc, bounds, 255)
 }
 
+// drawStretchedOnCanvas draws bmp into bounds, resampled according to the
+// InterpolationMode of canvas. AlphaBlend itself always samples the nearest
+// pixel, regardless of the stretch mode of the DC, so we resample the visible
+// part ourselves.
+func (bmp *Bitmap) drawStretchedOnCanvas(canvas *Canvas, bounds Rectangle) error {
+	if canvas.interpolationMode == InterpolationModeNearest || bounds.Size() == bmp.size {
+		return bmp.drawStretched(canvas.hdc, bounds)
+	}
+
+	size := bounds.Size()
+
+	// Painting e.g. an ImageView, maybe in tiles, resamples to the same size
+	// again and again, so we keep the result, unless it would be huge.
+	if size.Width*size.Height <= maxStretchedBitmapPixels {
+		if s := bmp.stretched; s == nil || s.size != size || bmp.stretchedMode != canvas.interpolationMode {
+			src, err := bmp.ToImage()
+			if err != nil {
+				return err
+			}
+
+			dst := resampleRGBA(src, size, Rectangle{Width: size.Width, Height: size.Height}, canvas.interpolationMode)
+
+			stretched, err := NewBitmapFromImageForDPI(dst, bmp.dpi)
+			if err != nil {
+				return err
+			}
+
+			bmp.disposeStretched()
+			bmp.stretched = stretched
+			bmp.stretchedMode = canvas.interpolationMode
+		}
+
+		return bmp.stretched.alphaBlend(canvas.hdc, bounds, 255)
+	}
+
+	var rc win.RECT
+	if getClipBox(canvas.hdc, &rc) == 0 {
+		return bmp.drawStretched(canvas.hdc, bounds)
+	}
+
+	clip := rectangleFromRECT(rc)
+
+	part := Rectangle{X: maxi(bounds.X, clip.X), Y: maxi(bounds.Y, clip.Y)}
+	part.Width = mini(bounds.X+bounds.Width, clip.X+clip.Width) - part.X
+	part.Height = mini(bounds.Y+bounds.Height, clip.Y+clip.Height) - part.Y
+	if part.Width <= 0 || part.Height <= 0 {
+		return nil
+	}
+
+	src, err := bmp.ToImage()
+	if err != nil {
+		return err
+	}
+
+	dst := resampleRGBA(src, bounds.Size(), Rectangle{part.X - bounds.X, part.Y - bounds.Y, part.Width, part.Height}, canvas.interpolationMode)
+
+	resampled, err := NewBitmapFromImageForDPI(dst, bmp.dpi)
+	if err != nil {
+		return err
+	}
+	defer resampled.Dispose()
+
+	return resampled.alphaBlend(canvas.hdc, part, 255)
+}
+
+// maxStretchedBitmapPixels limits the size of the stretched copy a Bitmap
+// keeps around for drawStretchedOnCanvas.
+const maxStretchedBitmapPixels = 2048 * 2048
+
+// disposeStretched drops the stretched copy of the Bitmap, e.g. because its
+// pixels are about to change.
+func (bmp *Bitmap) disposeStretched() {
+	if bmp.stretched != nil {
+		bmp.stretched.Dispose()
+		bmp.stretched = nil
+	}
+}
+
+// resampleRGBA returns the part of src scaled to size. For
+// InterpolationModeHighQuality, the pixels of src covered by a pixel are
+// averaged when shrinking, otherwise the pixel is interpolated bilinearly.
+//
+// As image.RGBA is premultiplied, so is the interpolation.
+func resampleRGBA(src *image.RGBA, size Size, part Rectangle, mode InterpolationMode) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, part.Width, part.Height))
+	if sw == 0 || sh == 0 {
+		return dst
+	}
+
+	xSpans := resampleSpans(part.X, part.Width, sw, size.Width, mode)
+	ySpans := resampleSpans(part.Y, part.Height, sh, size.Height, mode)
+
+	for dy, ys := range ySpans {
+		for dx, xs := range xSpans {
+			var acc [4]float64
+			var total float64
+
+			for j, wy := range ys.weights {
+				row := mini(ys.first+j, sh-1) * src.Stride
+
+				for i, wx := range xs.weights {
+					w := wx * wy
+					if w == 0 {
+						continue
+					}
+
+					p := src.Pix[row+mini(xs.first+i, sw-1)*4:]
+					for c := range acc {
+						acc[c] += w * float64(p[c])
+					}
+					total += w
+				}
+			}
+
+			o := dst.PixOffset(dx, dy)
+			for c := range acc {
+				dst.Pix[o+c] = uint8(acc[c]/total + 0.5)
+			}
+		}
+	}
+
+	return dst
+}
+
+// resampleSpan describes the source pixels along one axis that contribute to
+// a destination pixel.
+type resampleSpan struct {
+	first   int
+	weights []float64
+}
+
+// resampleSpans returns the spans of the count destination pixels starting at
+// offset, when scaling n source pixels to size.
+func resampleSpans(offset, count, n, size int, mode InterpolationMode) []resampleSpan {
+	f := float64(n) / float64(size)
+	box := mode == InterpolationModeHighQuality && f > 1
+
+	spans := make([]resampleSpan, count)
+
+	for i := range spans {
+		d := float64(offset + i)
+
+		if box {
+			first := int(d * f)
+			last := maxi(first, mini(int(math.Ceil((d+1)*f))-1, n-1))
+
+			weights := make([]float64, last-first+1)
+			for k := range weights {
+				weights[k] = 1
+			}
+
+			spans[i] = resampleSpan{first, weights}
+		} else {
+			s := math.Max(0, (d+0.5)*f-0.5)
+			first := int(s)
+			t := s - float64(first)
+
+			spans[i] = resampleSpan{first, []float64{1 - t, t}}
+		}
+	}
+
+	return spans
+}
+
 func (bmp *Bitmap) alphaBlend(hdc win.HDC, bounds Rectangle, opacity byte) error {
 	return bmp.alphaBlendPart(hdc, bounds, Rectangle{0, 0, bmp.size.Width, bmp.size.Height}, opacity)
 }
@@ -322,8 +683,14 @@ func newBitmapFromHBITMAP(hBmp win.HBITMAP) (bmp *Bitmap, err error) {
 
 	bmih := &dib.DsBmih
 
+	height := bmih.BiHeight
+	if height < 0 {
+		// Top-down DIB
+		height = -height
+	}
+
 	bmihSize := uintptr(unsafe.Sizeof(*bmih))
-	pixelsSize := uintptr(int32(bmih.BiBitCount)*bmih.BiWidth*bmih.BiHeight) / 8
+	pixelsSize := uintptr(int32(bmih.BiBitCount)*bmih.BiWidth*height) / 8
 
 	totalSize := uintptr(bmihSize + pixelsSize)
 
@@ -345,7 +712,7 @@ func newBitmapFromHBITMAP(hBmp win.HBITMAP) (bmp *Bitmap, err error) {
 		hPackedDIB: hPackedDIB,
 		size: Size{
 			int(bmih.BiWidth),
-			int(bmih.BiHeight),
+			int(height),
 		},
 	}, nil
 }
@@ -377,17 +744,47 @@ func hBitmapFromImage(im image.Image) (win.HBITMAP, error) {
 		return 0, newError("CreateDIBSection failed")
 	}
 
-	// Fill the image
+	// Fill the image. AlphaBlend expects premultiplied alpha, which is what
+	// color.Color.RGBA returns, so only *image.NRGBA needs converting.
+	bounds := im.Bounds()
 	bitmap_array := (*[1 << 30]byte)(unsafe.Pointer(lpBits))
 	i := 0
-	for y := im.Bounds().Min.Y; y != im.Bounds().Max.Y; y++ {
-		for x := im.Bounds().Min.X; x != im.Bounds().Max.X; x++ {
-			r, g, b, a := im.At(x, y).RGBA()
-			bitmap_array[i+3] = byte(a >> 8)
-			bitmap_array[i+2] = byte(r >> 8)
-			bitmap_array[i+1] = byte(g >> 8)
-			bitmap_array[i+0] = byte(b >> 8)
-			i += 4
+	switch im := im.(type) {
+	case *image.RGBA:
+		for y := bounds.Min.Y; y != bounds.Max.Y; y++ {
+			row := im.Pix[im.PixOffset(bounds.Min.X, y):]
+			for x := 0; x < bounds.Dx()*4; x += 4 {
+				bitmap_array[i+3] = row[x+3]
+				bitmap_array[i+2] = row[x+0]
+				bitmap_array[i+1] = row[x+1]
+				bitmap_array[i+0] = row[x+2]
+				i += 4
+			}
+		}
+
+	case *image.NRGBA:
+		for y := bounds.Min.Y; y != bounds.Max.Y; y++ {
+			row := im.Pix[im.PixOffset(bounds.Min.X, y):]
+			for x := 0; x < bounds.Dx()*4; x += 4 {
+				a := uint32(row[x+3])
+				bitmap_array[i+3] = byte(a)
+				bitmap_array[i+2] = byte(uint32(row[x+0]) * a / 0xff)
+				bitmap_array[i+1] = byte(uint32(row[x+1]) * a / 0xff)
+				bitmap_array[i+0] = byte(uint32(row[x+2]) * a / 0xff)
+				i += 4
+			}
+		}
+
+	default:
+		for y := bounds.Min.Y; y != bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x != bounds.Max.X; x++ {
+				r, g, b, a := im.At(x, y).RGBA()
+				bitmap_array[i+3] = byte(a >> 8)
+				bitmap_array[i+2] = byte(r >> 8)
+				bitmap_array[i+1] = byte(g >> 8)
+				bitmap_array[i+0] = byte(b >> 8)
+				i += 4
+			}
 		}
 	}
 
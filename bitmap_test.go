@@ -0,0 +1,37 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestNewBitmapFromImageForDPI(t *testing.T) {
+	im := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	im.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 128})
+
+	bmp, err := NewBitmapFromImageForDPI(im, 192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bmp.Dispose()
+
+	if bmp.dpi != 192 {
+		t.Errorf("dpi %d, want 192", bmp.dpi)
+	}
+
+	out, err := bmp.ToImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.RGBAAt(0, 0), (color.RGBA{128, 0, 0, 128}); got != want {
+		t.Errorf("pixel is %v, want %v", got, want)
+	}
+}
@@ -7,6 +7,8 @@
 package walk
 
 import (
+	"math"
+	"sort"
 	"unsafe"
 
 	"github.com/lxn/win"
@@ -319,6 +321,8 @@ type GradientBrush struct {
 	triangles    []GradientTriangle
 	orientation  gradientOrientation
 	absolute     bool
+	stops        []GradientStop // Used by linear and radial gradients
+	angle        float64        // Used by linear gradients
 }
 
 type gradientOrientation int
@@ -327,6 +331,8 @@ const (
 	gradientOrientationNone gradientOrientation = iota
 	gradientOrientationHorizontal
 	gradientOrientationVertical
+	gradientOrientationLinear
+	gradientOrientationRadial
 )
 
 func NewHorizontalGradientBrush(stops []GradientStop) (*GradientBrush, error) {
@@ -402,6 +408,208 @@ func newGradientBrush(vertexes []GradientVertex, triangles []GradientTriangle, o
 	return gb, nil
 }
 
+// LinearGradientBrush is a GradientBrush whose colors change along a line at
+// an arbitrary angle.
+type LinearGradientBrush struct {
+	GradientBrush
+}
+
+// NewLinearGradientBrush returns a brush whose colors change along a line
+// through the center of the painted area, at angle degrees clockwise from
+// left to right. Stop offsets are in the range [0, 1], where 0 and 1 are the
+// corners of the area the line passes through last and first.
+func NewLinearGradientBrush(angle float64, stops []GradientStop) (*LinearGradientBrush, error) {
+	if len(stops) < 2 {
+		return nil, newError("at least 2 stops are required")
+	}
+
+	return &LinearGradientBrush{GradientBrush{
+		orientation: gradientOrientationLinear,
+		stops:       sortedGradientStops(stops),
+		angle:       angle,
+	}}, nil
+}
+
+// Angle returns the angle of the gradient line in degrees, clockwise from
+// left to right.
+func (b *LinearGradientBrush) Angle() float64 {
+	return b.angle
+}
+
+// RadialGradientBrush is a GradientBrush whose colors change from the center
+// of the painted area outwards.
+type RadialGradientBrush struct {
+	GradientBrush
+}
+
+// NewRadialGradientBrush returns a brush whose colors change along ellipses
+// around the center of the painted area. Stop offset 0 is the center and 1
+// is the ellipse inscribed into the area; beyond it, the last stop color is
+// used.
+func NewRadialGradientBrush(stops []GradientStop) (*RadialGradientBrush, error) {
+	if len(stops) < 2 {
+		return nil, newError("at least 2 stops are required")
+	}
+
+	return &RadialGradientBrush{GradientBrush{
+		orientation: gradientOrientationRadial,
+		stops:       sortedGradientStops(stops),
+	}}, nil
+}
+
+func sortedGradientStops(stops []GradientStop) []GradientStop {
+	sorted := make([]GradientStop, len(stops))
+	copy(sorted, stops)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Offset < sorted[j].Offset
+	})
+
+	return sorted
+}
+
+// radialGradientSegments is the number of edges of the polygons that
+// approximate the ellipses of a radial gradient.
+const radialGradientSegments = 64
+
+// gradientStopsColorAt returns the color at offset t, interpolating between
+// the surrounding stops.
+func gradientStopsColorAt(stops []GradientStop, t float64) Color {
+	if t <= stops[0].Offset {
+		return stops[0].Color
+	}
+
+	for i := 1; i < len(stops); i++ {
+		s0, s1 := stops[i-1], stops[i]
+		if t > s1.Offset {
+			continue
+		}
+
+		f := 0.0
+		if d := s1.Offset - s0.Offset; d > 0 {
+			f = (t - s0.Offset) / d
+		}
+
+		lerp := func(a, b byte) byte {
+			return byte(float64(a) + (float64(b)-float64(a))*f + 0.5)
+		}
+
+		return RGB(
+			lerp(s0.Color.R(), s1.Color.R()),
+			lerp(s0.Color.G(), s1.Color.G()),
+			lerp(s0.Color.B(), s1.Color.B()))
+	}
+
+	return stops[len(stops)-1].Color
+}
+
+// gradientStopsBetween returns the stops strictly between from and to,
+// enclosed by stops at from and to with the colors at those offsets.
+func gradientStopsBetween(stops []GradientStop, from, to float64) []GradientStop {
+	between := []GradientStop{{from, gradientStopsColorAt(stops, from)}}
+
+	for _, stop := range stops {
+		if stop.Offset > from && stop.Offset < to {
+			between = append(between, stop)
+		}
+	}
+
+	return append(between, GradientStop{to, gradientStopsColorAt(stops, to)})
+}
+
+// meshFromStops returns the vertexes, in pixels, and triangles that
+// GradientFill needs to paint a linear or radial gradient of the given size.
+func (b *GradientBrush) meshFromStops(size Size) ([]GradientVertex, []GradientTriangle) {
+	w, h := float64(size.Width), float64(size.Height)
+
+	var vertexes []GradientVertex
+	var triangles []GradientTriangle
+
+	if b.orientation == gradientOrientationRadial {
+		// The outermost polygon must enclose the corners.
+		outer := math.Sqrt2/math.Cos(math.Pi/radialGradientSegments) + 0.01
+		stops := gradientStopsBetween(b.stops, 0, outer)
+
+		vertexes = append(vertexes, GradientVertex{X: w / 2, Y: h / 2, Color: stops[0].Color})
+
+		for i, stop := range stops[1:] {
+			first := 1 + i*radialGradientSegments
+
+			for j := 0; j < radialGradientSegments; j++ {
+				sin, cos := math.Sincos(2 * math.Pi * float64(j) / radialGradientSegments)
+
+				vertexes = append(vertexes, GradientVertex{
+					X:     w / 2 * (1 + cos*stop.Offset),
+					Y:     h / 2 * (1 + sin*stop.Offset),
+					Color: stop.Color,
+				})
+
+				next := first + (j+1)%radialGradientSegments
+
+				if i == 0 {
+					triangles = append(triangles, GradientTriangle{Vertex1: 0, Vertex2: first + j, Vertex3: next})
+				} else {
+					inner := first - radialGradientSegments
+					triangles = append(triangles, GradientTriangle{Vertex1: inner + j, Vertex2: first + j, Vertex3: next})
+					triangles = append(triangles, GradientTriangle{Vertex1: inner + j, Vertex2: next, Vertex3: next - radialGradientSegments})
+				}
+			}
+		}
+
+		return vertexes, triangles
+	}
+
+	sin, cos := math.Sincos(b.angle * math.Pi / 180)
+	extent := (math.Abs(cos)*w + math.Abs(sin)*h) / 2
+	// Half the length of the lines across the gradient line, which must reach
+	// beyond the area.
+	across := (math.Abs(sin)*w+math.Abs(cos)*h)/2 + 1
+
+	// Going a little beyond 0 and 1 makes sure the edge pixels are painted.
+	for i, stop := range gradientStopsBetween(b.stops, -0.01, 1.01) {
+		d := (2*stop.Offset - 1) * extent
+		x, y := w/2+d*cos, h/2+d*sin
+
+		vertexes = append(vertexes, GradientVertex{X: x + sin*across, Y: y - cos*across, Color: stop.Color})
+		vertexes = append(vertexes, GradientVertex{X: x - sin*across, Y: y + cos*across, Color: stop.Color})
+
+		if i > 0 {
+			triangles = append(triangles, GradientTriangle{Vertex1: i*2 - 2, Vertex2: i*2 + 1, Vertex3: i*2 - 1})
+			triangles = append(triangles, GradientTriangle{Vertex1: i*2 - 2, Vertex2: i * 2, Vertex3: i*2 + 1})
+		}
+	}
+
+	return vertexes, triangles
+}
+
+// Dispose releases the bitmaps and brushes created for the windows the
+// GradientBrush is attached to.
+func (b *GradientBrush) Dispose() {
+	for wb, info := range b.wb2info {
+		if info == nil {
+			continue
+		}
+
+		wb.SizeChanged().Detach(info.SizeChangedHandle)
+
+		if info.Delegate != nil {
+			info.Delegate.bitmap.Dispose()
+			info.Delegate.Dispose()
+			info.Delegate = nil
+		}
+	}
+	b.wb2info = nil
+
+	if b.mainDelegate != nil {
+		b.mainDelegate.bitmap.Dispose()
+		b.mainDelegate.Dispose()
+		b.mainDelegate = nil
+		b.hBrush = 0
+	}
+
+	b.brushBase.Dispose()
+}
+
 func (b *GradientBrush) logbrush() *win.LOGBRUSH {
 	if b.mainDelegate == nil {
 		return nil
@@ -418,12 +626,19 @@ func (b *GradientBrush) create(size Size) (*BitmapBrush, error) {
 	var disposables Disposables
 	defer disposables.Treat()
 
+	srcVertexes, srcTriangles, absolute := b.vertexes, b.triangles, b.absolute
+
 	switch b.orientation {
 	case gradientOrientationHorizontal:
 		size.Height = 1
 
 	case gradientOrientationVertical:
 		size.Width = 1
+
+	case gradientOrientationLinear, gradientOrientationRadial:
+		size = maxSize(size, Size{1, 1})
+		srcVertexes, srcTriangles = b.meshFromStops(size)
+		absolute = true
 	}
 
 	bitmap, err := NewBitmap(size)
@@ -439,14 +654,14 @@ func (b *GradientBrush) create(size Size) (*BitmapBrush, error) {
 	defer canvas.Dispose()
 
 	var scaleX, scaleY float64
-	if b.absolute {
+	if absolute {
 		scaleX, scaleY = 1, 1
 	} else {
 		scaleX, scaleY = float64(size.Width), float64(size.Height)
 	}
 
-	vertexes := make([]win.TRIVERTEX, len(b.vertexes))
-	for i, src := range b.vertexes {
+	vertexes := make([]win.TRIVERTEX, len(srcVertexes))
+	for i, src := range srcVertexes {
 		dst := &vertexes[i]
 
 		dst.X = int32(src.X * scaleX)
@@ -456,8 +671,8 @@ func (b *GradientBrush) create(size Size) (*BitmapBrush, error) {
 		dst.Blue = uint16(src.Color.B()) * 256
 	}
 
-	triangles := make([]win.GRADIENT_TRIANGLE, len(b.triangles))
-	for i, src := range b.triangles {
+	triangles := make([]win.GRADIENT_TRIANGLE, len(srcTriangles))
+	for i, src := range srcTriangles {
 		dst := &triangles[i]
 
 		dst.Vertex1 = uint32(src.Vertex1)
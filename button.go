@@ -9,6 +9,7 @@ package walk
 import (
 	"fmt"
 	"strconv"
+	"syscall"
 	"unsafe"
 
 	"github.com/lxn/win"
@@ -22,6 +23,17 @@ type setCheckeder interface {
 	setChecked(checked bool)
 }
 
+// ImagePosition specifies where a Button displays its image relative to its
+// text.
+type ImagePosition int
+
+const (
+	ImagePositionLeft ImagePosition = iota
+	ImagePositionTop
+	ImagePositionRight
+	ImagePositionBottom
+)
+
 type Button struct {
 	WidgetBase
 	checkedChangedPublisher EventPublisher
@@ -29,6 +41,9 @@ type Button struct {
 	textChangedPublisher    EventPublisher
 	imageChangedPublisher   EventPublisher
 	image                   Image
+	imagePosition           ImagePosition
+	hIml                    win.HIMAGELIST
+	indicator               *buttonIndicator
 	persistent              bool
 }
 
@@ -96,18 +111,31 @@ func (b *Button) Image() Image {
 	return b.image
 }
 
+func (b *Button) Dispose() {
+	b.WidgetBase.Dispose()
+
+	b.destroyImageList()
+}
+
 func (b *Button) SetImage(image Image) error {
-	var handle uintptr
+	var bmp *Bitmap
 	if image != nil {
-		bmp, err := iconCache.Bitmap(image, b.DPI())
-		if err != nil {
+		var err error
+		if bmp, err = iconCache.Bitmap(image, b.DPI()); err != nil {
 			return err
 		}
-
-		handle = uintptr(bmp.hBmp)
 	}
 
-	b.SendMessage(win.BM_SETIMAGE, win.IMAGE_BITMAP, handle)
+	if b.imagePosition == ImagePositionLeft && b.hIml == 0 {
+		var handle uintptr
+		if bmp != nil {
+			handle = uintptr(bmp.hBmp)
+		}
+
+		b.SendMessage(win.BM_SETIMAGE, win.IMAGE_BITMAP, handle)
+	} else if err := b.setImageList(bmp); err != nil {
+		return err
+	}
 
 	b.image = image
 
@@ -122,6 +150,98 @@ func (b *Button) ImageChanged() *Event {
 	return b.imageChangedPublisher.Event()
 }
 
+// ImagePosition returns where the Button displays its image relative to its
+// text.
+func (b *Button) ImagePosition() ImagePosition {
+	return b.imagePosition
+}
+
+// SetImagePosition sets where the Button displays its image relative to its
+// text.
+func (b *Button) SetImagePosition(position ImagePosition) error {
+	if position < ImagePositionLeft || position > ImagePositionBottom {
+		return newError("invalid image position")
+	}
+
+	if position == b.imagePosition {
+		return nil
+	}
+
+	b.imagePosition = position
+
+	return b.SetImage(b.image)
+}
+
+// setImageList displays bmp using a BUTTON_IMAGELIST, which, unlike
+// BM_SETIMAGE, supports all ImagePosition values.
+func (b *Button) setImageList(bmp *Bitmap) error {
+	b.SendMessage(win.BM_SETIMAGE, win.IMAGE_BITMAP, 0)
+
+	var bil buttonImageList
+
+	switch b.imagePosition {
+	case ImagePositionLeft:
+		bil.uAlign = buttonImageListAlignLeft
+
+	case ImagePositionTop:
+		bil.uAlign = buttonImageListAlignTop
+
+	case ImagePositionRight:
+		bil.uAlign = buttonImageListAlignRight
+
+	case ImagePositionBottom:
+		bil.uAlign = buttonImageListAlignBottom
+	}
+
+	if bmp != nil {
+		bil.himl = win.ImageList_Create(int32(bmp.size.Width), int32(bmp.size.Height), win.ILC_COLOR32, 1, 0)
+		if bil.himl == 0 {
+			return newError("ImageList_Create failed")
+		}
+
+		if win.ImageList_Add(bil.himl, bmp.hBmp, 0) == -1 {
+			win.ImageList_Destroy(bil.himl)
+			return newError("ImageList_Add failed")
+		}
+	}
+
+	// A null image list removes the image.
+	if b.SendMessage(win.BCM_SETIMAGELIST, 0, uintptr(unsafe.Pointer(&bil))) == 0 && bil.himl != 0 {
+		win.ImageList_Destroy(bil.himl)
+		return newError("BCM_SETIMAGELIST failed")
+	}
+
+	b.destroyImageList()
+	b.hIml = bil.himl
+
+	return nil
+}
+
+func (b *Button) destroyImageList() {
+	if b.hIml != 0 {
+		win.ImageList_Destroy(b.hIml)
+		b.hIml = 0
+	}
+}
+
+func (b *Button) applyDarkMode(dark bool) {
+	switch b.window.(type) {
+	case *CheckBox, *RadioButton:
+		// Themed check boxes and radio buttons draw their text in the color
+		// of the theme, which is unreadable on a dark background. Unthemed
+		// ones use the text color set on WM_CTLCOLORSTATIC.
+		if dark {
+			empty := syscall.StringToUTF16Ptr("")
+			win.SetWindowTheme(b.hWnd, empty, empty)
+		} else {
+			win.SetWindowTheme(b.hWnd, nil, nil)
+		}
+
+	default:
+		setDarkModeTheme(b.hWnd, dark, "DarkMode_Explorer", "")
+	}
+}
+
 func (b *Button) Text() string {
 	return b.text()
 }
@@ -217,6 +337,11 @@ func (b *Button) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 			}
 		}
 
+	case win.WM_NOTIFY:
+		if ret, handled := b.handleIndicatorNotify(lParam); handled {
+			return ret
+		}
+
 	case win.WM_SETTEXT:
 		b.textChangedPublisher.Publish()
 	}
@@ -229,7 +354,7 @@ func (b *Button) idealSize() Size {
 
 	b.SendMessage(win.BCM_GETIDEALSIZE, 0, uintptr(unsafe.Pointer(&s)))
 
-	return maxSize(Size{int(s.CX), int(s.CY)}, b.dialogBaseUnitsToPixels(Size{50, 14}))
+	return maxSize(b.adjustIdealSizeForIndicator(Size{int(s.CX), int(s.CY)}), b.dialogBaseUnitsToPixels(Size{50, 14}))
 }
 
 func (b *Button) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
@@ -0,0 +1,205 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// The size of the native check box and radio button glyphs at 96 dpi.
+const nativeIndicatorSize96dpi = 13
+
+// ButtonIndicatorState describes the state in which the indicator of a
+// CheckBox or RadioButton is to be rendered.
+type ButtonIndicatorState struct {
+	CheckState CheckState
+	Enabled    bool
+	Focused    bool
+	Hot        bool
+	Pressed    bool
+}
+
+// ButtonIndicatorRenderer renders the indicator of a CheckBox or RadioButton,
+// e.g. as a toggle switch, into bounds, which are in 1/96" units like all
+// Canvas coordinates.
+type ButtonIndicatorRenderer func(canvas *Canvas, bounds Rectangle, state ButtonIndicatorState)
+
+type buttonIndicator struct {
+	renderer ButtonIndicatorRenderer
+	size     Size // in 1/96" units
+}
+
+// IndicatorRenderer returns the ButtonIndicatorRenderer of the CheckBox, or
+// nil if the indicator is drawn natively.
+func (cb *CheckBox) IndicatorRenderer() ButtonIndicatorRenderer {
+	return cb.indicatorRenderer()
+}
+
+// SetIndicatorRenderer sets a ButtonIndicatorRenderer that draws the
+// indicator of the CheckBox, which occupies size (in 1/96" units). Passing a
+// nil renderer restores native drawing.
+//
+// Only the appearance is affected, the CheckBox remains a native control
+// regarding input handling and accessibility.
+func (cb *CheckBox) SetIndicatorRenderer(renderer ButtonIndicatorRenderer, size Size) {
+	cb.setIndicatorRenderer(renderer, size)
+}
+
+// IndicatorRenderer returns the ButtonIndicatorRenderer of the RadioButton,
+// or nil if the indicator is drawn natively.
+func (rb *RadioButton) IndicatorRenderer() ButtonIndicatorRenderer {
+	return rb.indicatorRenderer()
+}
+
+// SetIndicatorRenderer sets a ButtonIndicatorRenderer that draws the
+// indicator of the RadioButton, which occupies size (in 1/96" units). Passing
+// a nil renderer restores native drawing.
+//
+// Only the appearance is affected, the RadioButton remains a native control
+// regarding input handling and accessibility.
+func (rb *RadioButton) SetIndicatorRenderer(renderer ButtonIndicatorRenderer, size Size) {
+	rb.setIndicatorRenderer(renderer, size)
+}
+
+func (b *Button) indicatorRenderer() ButtonIndicatorRenderer {
+	if b.indicator == nil {
+		return nil
+	}
+
+	return b.indicator.renderer
+}
+
+func (b *Button) setIndicatorRenderer(renderer ButtonIndicatorRenderer, size Size) {
+	if renderer == nil {
+		b.indicator = nil
+	} else {
+		if size.Width <= 0 || size.Height <= 0 {
+			size = Size{nativeIndicatorSize96dpi, nativeIndicatorSize96dpi}
+		}
+
+		b.indicator = &buttonIndicator{renderer: renderer, size: size}
+	}
+
+	b.RequestLayout()
+	b.Invalidate()
+}
+
+// adjustIdealSizeForIndicator corrects an ideal size reported by the control,
+// which assumes the native indicator, for a custom indicator.
+func (b *Button) adjustIdealSizeForIndicator(size Size) Size {
+	if b.indicator == nil {
+		return size
+	}
+
+	dpi := b.DPI()
+	indicator := SizeFrom96DPI(b.indicator.size, dpi)
+
+	size.Width += indicator.Width - IntFrom96DPI(nativeIndicatorSize96dpi, dpi)
+	size.Height = maxi(size.Height, indicator.Height)
+
+	return size
+}
+
+func (b *Button) handleIndicatorCustomDraw(nmcd *win.NMCUSTOMDRAW) uintptr {
+	if nmcd.DwDrawStage != win.CDDS_PREPAINT {
+		return win.CDRF_DODEFAULT
+	}
+
+	canvas, err := newCanvasFromHDC(nmcd.Hdc)
+	if err != nil {
+		return win.CDRF_DODEFAULT
+	}
+	defer canvas.Dispose()
+
+	dpi := b.DPI()
+	bounds := rectangleFromRECT(nmcd.Rc)
+	indicatorSize := SizeFrom96DPI(b.indicator.size, dpi)
+	gap := IntFrom96DPI(4, dpi)
+	textOnLeftSide := b.hasStyleBits(win.BS_LEFTTEXT)
+
+	indicatorBounds := Rectangle{
+		X:      bounds.X,
+		Y:      bounds.Y + (bounds.Height-indicatorSize.Height)/2,
+		Width:  indicatorSize.Width,
+		Height: indicatorSize.Height,
+	}
+	textBounds := Rectangle{
+		X:      bounds.X + indicatorSize.Width + gap,
+		Y:      bounds.Y,
+		Width:  bounds.Width - indicatorSize.Width - gap,
+		Height: bounds.Height,
+	}
+	if textOnLeftSide {
+		indicatorBounds.X = bounds.X + bounds.Width - indicatorSize.Width
+		textBounds.X = bounds.X
+	}
+
+	state := ButtonIndicatorState{
+		CheckState: CheckState(b.SendMessage(win.BM_GETCHECK, 0, 0)),
+		Enabled:    nmcd.UItemState&win.CDIS_DISABLED == 0,
+		Focused:    nmcd.UItemState&win.CDIS_FOCUS != 0,
+		Hot:        nmcd.UItemState&win.CDIS_HOT != 0,
+		Pressed:    nmcd.UItemState&win.CDIS_SELECTED != 0,
+	}
+
+	b.indicator.renderer(canvas, indicatorBounds.To96DPI(dpi), state)
+
+	color := Color(win.GetSysColor(win.COLOR_BTNTEXT))
+	if darkModeEnabledForWindow(b) {
+		color = darkModeTextColor
+	}
+	if !state.Enabled {
+		color = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+
+	format := TextVCenter | TextSingleLine
+	if textOnLeftSide {
+		format |= TextRight
+	}
+	if nmcd.UItemState&win.CDIS_SHOWKEYBOARDCUES == 0 {
+		format |= TextHidePrefix
+	}
+
+	text := b.text()
+	canvas.DrawText(text, b.Font(), color, textBounds.To96DPI(canvas.DPI()), format)
+
+	if state.Focused && b.SendMessage(win.WM_QUERYUISTATE, 0, 0)&win.UISF_HIDEFOCUS == 0 {
+		textSize := b.calculateTextSizeImpl(text)
+
+		focusBounds := Rectangle{
+			X:      textBounds.X,
+			Y:      textBounds.Y + (textBounds.Height-textSize.Height)/2,
+			Width:  mini(textSize.Width, textBounds.Width),
+			Height: textSize.Height,
+		}
+		if textOnLeftSide {
+			focusBounds.X = textBounds.X + textBounds.Width - focusBounds.Width
+		}
+
+		rc := focusBounds.toRECT()
+		win.DrawFocusRect(nmcd.Hdc, &rc)
+	}
+
+	return win.CDRF_SKIPDEFAULT
+}
+
+// handleIndicatorNotify handles the NM_CUSTOMDRAW notification of buttons
+// that have a custom indicator.
+func (b *Button) handleIndicatorNotify(lParam uintptr) (uintptr, bool) {
+	if b.indicator == nil {
+		return 0, false
+	}
+
+	nmcd := (*win.NMCUSTOMDRAW)(unsafe.Pointer(lParam))
+	if nmcd.Hdr.Code != win.NM_CUSTOMDRAW {
+		return 0, false
+	}
+
+	return b.handleIndicatorCustomDraw(nmcd), true
+}
@@ -7,6 +7,7 @@
 package walk
 
 import (
+	"math"
 	"syscall"
 	"unsafe"
 
@@ -43,6 +44,24 @@ const (
 	TextPrefixOnly           DrawTextFormat = win.DT_PREFIXONLY
 )
 
+// InterpolationMode specifies how images are resampled when drawn stretched.
+type InterpolationMode int
+
+const (
+	// InterpolationModeHighQuality averages the covered pixels when
+	// shrinking and interpolates bilinearly when enlarging. This is the
+	// default.
+	InterpolationModeHighQuality InterpolationMode = iota
+
+	// InterpolationModeNearest uses nearest-neighbor sampling, keeping pixel
+	// art crisp.
+	InterpolationModeNearest
+
+	// InterpolationModeBilinear interpolates bilinearly, also when shrinking,
+	// which is faster than InterpolationModeHighQuality but may alias.
+	InterpolationModeBilinear
+)
+
 var gM *uint16
 
 func init() {
@@ -51,6 +70,22 @@ func init() {
 	})
 }
 
+// SmoothingMode specifies whether lines, curves and the edges of filled
+// areas are antialiased.
+type SmoothingMode int
+
+const (
+	// SmoothingModeNone draws using plain GDI. This is the default and the
+	// fastest mode.
+	SmoothingModeNone SmoothingMode = iota
+
+	// SmoothingModeAntiAlias draws lines, ellipses, arcs, pies, polygons and
+	// paths using GDI+ with antialiasing. Only solid pens and solid color
+	// brushes can be translated to GDI+, other pens and brushes are still
+	// drawn using GDI.
+	SmoothingModeAntiAlias
+)
+
 type Canvas struct {
 	hdc                 win.HDC
 	hBmpStock           win.HBITMAP
@@ -61,6 +96,10 @@ type Canvas struct {
 	recordingMetafile   *Metafile
 	measureTextMetafile *Metafile
 	doNotDispose        bool
+	interpolationMode   InterpolationMode
+	transformStack      []Transform
+	worldTransformed    bool
+	smoothingMode       SmoothingMode
 }
 
 func NewCanvasFromImage(image Image) (*Canvas, error) {
@@ -83,6 +122,9 @@ func NewCanvasFromImage(image Image) (*Canvas, error) {
 			return nil, newError("SelectObject failed")
 		}
 
+		// The Canvas may change the pixels.
+		img.disposeStretched()
+
 		succeeded = true
 
 		return (&Canvas{hdc: hdc, hBmpStock: hBmpStock, bitmap: img, dpix: img.dpi, dpiy: img.dpi}).init()
@@ -141,6 +183,14 @@ func (c *Canvas) init() (*Canvas, error) {
 }
 
 func (c *Canvas) Dispose() {
+	if c.worldTransformed && c.hdc != 0 {
+		// The HDC may be used for further drawing by its owner.
+		c.SetWorldTransform(IdentityTransform())
+		setGraphicsMode(c.hdc, gmCompatible)
+		c.worldTransformed = false
+	}
+	c.transformStack = nil
+
 	if !c.doNotDispose && c.hdc != 0 {
 		if c.bitmap != nil {
 			win.SelectObject(c.hdc, win.HGDIOBJ(c.hBmpStock))
@@ -164,6 +214,117 @@ func (c *Canvas) Dispose() {
 	}
 }
 
+// InterpolationMode returns how images are resampled when drawn stretched.
+func (c *Canvas) InterpolationMode() InterpolationMode {
+	return c.interpolationMode
+}
+
+// SetInterpolationMode sets how images are resampled when drawn stretched.
+func (c *Canvas) SetInterpolationMode(mode InterpolationMode) error {
+	var bltMode int32
+	switch mode {
+	case InterpolationModeNearest:
+		bltMode = win.COLORONCOLOR
+
+	default:
+		bltMode = win.HALFTONE
+	}
+
+	switch win.SetStretchBltMode(c.hdc, bltMode) {
+	case 0, win.ERROR_INVALID_PARAMETER:
+		return newError("SetStretchBltMode failed")
+	}
+
+	if bltMode == win.HALFTONE {
+		if !win.SetBrushOrgEx(c.hdc, 0, 0, nil) {
+			return newError("SetBrushOrgEx failed")
+		}
+	}
+
+	c.interpolationMode = mode
+
+	return nil
+}
+
+// SmoothingMode returns whether the Canvas draws antialiased.
+func (c *Canvas) SmoothingMode() SmoothingMode {
+	return c.smoothingMode
+}
+
+// SetSmoothingMode sets whether the Canvas draws antialiased.
+func (c *Canvas) SetSmoothingMode(mode SmoothingMode) {
+	c.smoothingMode = mode
+}
+
+// WorldTransform returns the transformation that is applied to everything
+// drawn on the Canvas.
+func (c *Canvas) WorldTransform() Transform {
+	if !c.worldTransformed {
+		return IdentityTransform()
+	}
+
+	var xf xform
+	if !getWorldTransform(c.hdc, &xf) {
+		return IdentityTransform()
+	}
+
+	return transformFromXFORM(xf, c.DPI())
+}
+
+// SetWorldTransform sets the transformation that is applied to everything
+// drawn on the Canvas afterwards, e.g. to draw rotated or sheared content.
+//
+// The transform is applied after coordinates have been scaled for DPI.
+// Clipping regions are in device coordinates and are not transformed.
+func (c *Canvas) SetWorldTransform(t Transform) error {
+	if !c.worldTransformed {
+		if setGraphicsMode(c.hdc, gmAdvanced) == 0 {
+			return newError("SetGraphicsMode failed")
+		}
+		c.worldTransformed = true
+	}
+
+	xf := t.toXFORM(c.DPI())
+	if !setWorldTransform(c.hdc, &xf) {
+		return newError("SetWorldTransform failed")
+	}
+
+	return nil
+}
+
+// PushTransform applies t before the current world transform, remembering
+// the current one so it can be restored by PopTransform.
+func (c *Canvas) PushTransform(t Transform) error {
+	current := c.WorldTransform()
+
+	if err := c.SetWorldTransform(t.Then(current)); err != nil {
+		return err
+	}
+
+	c.transformStack = append(c.transformStack, current)
+
+	return nil
+}
+
+// PopTransform restores the world transform that was current before the
+// matching PushTransform call.
+func (c *Canvas) PopTransform() error {
+	if len(c.transformStack) == 0 {
+		return newError("transform stack is empty")
+	}
+
+	t := c.transformStack[len(c.transformStack)-1]
+	c.transformStack = c.transformStack[:len(c.transformStack)-1]
+
+	return c.SetWorldTransform(t)
+}
+
+// RotateAt pushes a transform that rotates everything drawn afterwards
+// clockwise by degrees around center. Call PopTransform to undo it.
+func (c *Canvas) RotateAt(degrees float64, center Point) error {
+	return c.PushTransform(RotationTransform(degrees, center))
+}
+
 func (c *Canvas) DPI() int {
 	if c.window != nil {
 		return c.window.DPI()
@@ -222,6 +383,12 @@ func (c *Canvas) withBrushAndPen(brush Brush, pen Pen, f func() error) error {
 }
 
 func (c *Canvas) ellipse(brush Brush, pen Pen, bounds Rectangle, sizeCorrection int) error {
+	if handled, err := c.withGdiplus(pen, brush, func(graphics, gpPen, gpBrush uintptr) error {
+		return gpEllipse(graphics, gpPen, gpBrush, bounds.From96DPI(c.DPI()))
+	}); handled {
+		return err
+	}
+
 	return c.withBrushAndPen(brush, pen, func() error {
 		bounds = bounds.From96DPI(c.DPI())
 
@@ -247,6 +414,103 @@ func (c *Canvas) FillEllipse(brush Brush, bounds Rectangle) error {
 	return c.ellipse(brush, nullPenSingleton, bounds, 1)
 }
 
+// arcRadialPoints returns the points where the radials at startAngle and
+// startAngle+sweepAngle degrees, clockwise from 3 o'clock, leave bounds.
+//
+// The returned points are suitable for the GDI Arc and Pie functions with
+// the arc direction set to clockwise.
+func arcRadialPoints(bounds Rectangle, startAngle, sweepAngle float64) (start, end Point) {
+	if sweepAngle < 0 {
+		startAngle, sweepAngle = startAngle+sweepAngle, -sweepAngle
+	}
+
+	cx := float64(bounds.X) + float64(bounds.Width)/2
+	cy := float64(bounds.Y) + float64(bounds.Height)/2
+
+	// Any point on the radial works, so we use one on the circumscribed
+	// circle to avoid precision issues with flat ellipses.
+	r := math.Hypot(float64(bounds.Width), float64(bounds.Height))
+
+	radial := func(degrees float64) Point {
+		sin, cos := math.Sincos(degrees * math.Pi / 180)
+		return Point{int(math.Round(cx + r*cos)), int(math.Round(cy + r*sin))}
+	}
+
+	start = radial(startAngle)
+	if sweepAngle >= 360 {
+		// GDI draws a full ellipse if start and end are the same.
+		return start, start
+	}
+
+	return start, radial(startAngle + sweepAngle)
+}
+
+func (c *Canvas) arcOrPie(brush Brush, pen Pen, bounds Rectangle, startAngle, sweepAngle float64, isPie bool, sizeCorrection int) error {
+	if sweepAngle == 0 {
+		return nil
+	}
+
+	if handled, err := c.withGdiplus(pen, brush, func(graphics, gpPen, gpBrush uintptr) error {
+		return gpArcOrPie(graphics, gpPen, gpBrush, bounds.From96DPI(c.DPI()), startAngle, sweepAngle, isPie)
+	}); handled {
+		return err
+	}
+
+	return c.withBrushAndPen(brush, pen, func() error {
+		bounds = bounds.From96DPI(c.DPI())
+
+		start, end := arcRadialPoints(bounds, startAngle, sweepAngle)
+
+		prevDir := setArcDirection(c.hdc, adClockwise)
+		if prevDir == 0 {
+			return newError("SetArcDirection failed")
+		}
+		defer setArcDirection(c.hdc, prevDir)
+
+		f, name := arc, "Arc"
+		if isPie {
+			f, name = pie, "Pie"
+		}
+
+		if !f(
+			c.hdc,
+			int32(bounds.X),
+			int32(bounds.Y),
+			int32(bounds.X+bounds.Width+sizeCorrection),
+			int32(bounds.Y+bounds.Height+sizeCorrection),
+			int32(start.X),
+			int32(start.Y),
+			int32(end.X),
+			int32(end.Y)) {
+
+			return newError(name + " failed")
+		}
+
+		return nil
+	})
+}
+
+// DrawArc draws the part of the outline of the ellipse inscribed into bounds
+// that starts at startAngle and spans sweepAngle degrees. Angles are
+// measured clockwise from 3 o'clock.
+func (c *Canvas) DrawArc(pen Pen, bounds Rectangle, startAngle, sweepAngle float64) error {
+	return c.arcOrPie(nullBrushSingleton, pen, bounds, startAngle, sweepAngle, false, 0)
+}
+
+// DrawPie draws the outline of a pie slice of the ellipse inscribed into
+// bounds, starting at startAngle and spanning sweepAngle degrees. Angles are
+// measured clockwise from 3 o'clock.
+func (c *Canvas) DrawPie(pen Pen, bounds Rectangle, startAngle, sweepAngle float64) error {
+	return c.arcOrPie(nullBrushSingleton, pen, bounds, startAngle, sweepAngle, true, 0)
+}
+
+// FillPie fills a pie slice of the ellipse inscribed into bounds, starting at
+// startAngle and spanning sweepAngle degrees. Angles are measured clockwise
+// from 3 o'clock.
+func (c *Canvas) FillPie(brush Brush, bounds Rectangle, startAngle, sweepAngle float64) error {
+	return c.arcOrPie(brush, nullPenSingleton, bounds, startAngle, sweepAngle, true, 1)
+}
+
 func (c *Canvas) DrawImage(image Image, location Point) error {
 	if image == nil {
 		return newError("image cannot be nil")
@@ -273,6 +537,67 @@ func (c *Canvas) DrawImageStretched(image Image, bounds Rectangle) error {
 	return image.drawStretched(c.hdc, bounds)
 }
 
+// DrawImageWithOpacity draws image at location, blended with what is already
+// on the Canvas using a constant opacity between 0 (invisible) and 1 (opaque).
+func (c *Canvas) DrawImageWithOpacity(image Image, location Point, opacity float64) error {
+	if opacity == 1 {
+		return c.DrawImage(image, location)
+	}
+
+	bmp, err := c.bitmapForBlending(image, opacity)
+	if err != nil || bmp == nil {
+		return err
+	}
+
+	location = location.From96DPI(c.DPI())
+
+	return bmp.alphaBlend(c.hdc, Rectangle{location.X, location.Y, bmp.size.Width, bmp.size.Height}, opacityByte(opacity))
+}
+
+// DrawImageStretchedWithOpacity draws image stretched to bounds, blended with
+// what is already on the Canvas using a constant opacity between 0
+// (invisible) and 1 (opaque).
+func (c *Canvas) DrawImageStretchedWithOpacity(image Image, bounds Rectangle, opacity float64) error {
+	if opacity == 1 {
+		return c.DrawImageStretched(image, bounds)
+	}
+
+	bmp, err := c.bitmapForBlending(image, opacity)
+	if err != nil || bmp == nil {
+		return err
+	}
+
+	return bmp.alphaBlend(c.hdc, bounds.From96DPI(c.DPI()), opacityByte(opacity))
+}
+
+// bitmapForBlending validates opacity and returns a Bitmap that can be alpha
+// blended to draw image. It returns nil if there is nothing to draw.
+func (c *Canvas) bitmapForBlending(image Image, opacity float64) (*Bitmap, error) {
+	if image == nil {
+		return nil, newError("image cannot be nil")
+	}
+	if opacity < 0 || opacity > 1 {
+		return nil, newError("opacity must be in the range [0, 1]")
+	}
+
+	switch img := image.(type) {
+	case *Bitmap:
+		return img, nil
+
+	case *AnimatedImage:
+		if img.FrameCount() == 0 {
+			return nil, nil
+		}
+		return img.Frame(img.CurrentFrame()), nil
+	}
+
+	return iconCache.Bitmap(image, c.DPI())
+}
+
+func opacityByte(opacity float64) byte {
+	return byte(opacity*255 + 0.5)
+}
+
 func (c *Canvas) DrawBitmapWithOpacity(bmp *Bitmap, bounds Rectangle, opacity byte) error {
 	if bmp == nil {
 		return newError("bmp cannot be nil")
@@ -283,22 +608,48 @@ func (c *Canvas) DrawBitmapWithOpacity(bmp *Bitmap, bounds Rectangle, opacity by
 	return bmp.alphaBlend(c.hdc, bounds, opacity)
 }
 
+// DrawBitmapPart draws the part src of bmp, stretched to dst.
+//
+// dst is in 1/96" units like all other Canvas coordinates, while src is in
+// the native pixels of bmp, so frames can be picked from a sprite sheet
+// regardless of the DPI. The alpha channel of bmp is honored.
 func (c *Canvas) DrawBitmapPart(bmp *Bitmap, dst, src Rectangle) error {
 	return c.DrawBitmapPartWithOpacity(bmp, dst, src, 0xff)
 }
 
+// DrawBitmapPartWithOpacity is like DrawBitmapPart, but additionally applies
+// the constant opacity to all pixels.
 func (c *Canvas) DrawBitmapPartWithOpacity(bmp *Bitmap, dst, src Rectangle, opacity byte) error {
 	if bmp == nil {
 		return newError("bmp cannot be nil")
 	}
 
+	if src.X < 0 || src.Y < 0 || src.Width < 0 || src.Height < 0 ||
+		src.X+src.Width > bmp.size.Width || src.Y+src.Height > bmp.size.Height {
+
+		return newError("src exceeds the bounds of bmp")
+	}
+
+	if src.Width == 0 || src.Height == 0 || dst.Width == 0 || dst.Height == 0 {
+		return nil
+	}
+
 	dst = dst.From96DPI(c.DPI())
-	src = src.From96DPI(c.DPI())
 
 	return bmp.alphaBlendPart(c.hdc, dst, src, opacity)
 }
 
 func (c *Canvas) DrawLine(pen Pen, from, to Point) error {
+	if handled, err := c.withGdiplus(pen, nil, func(graphics, gpPen, _ uintptr) error {
+		if gpPen == 0 {
+			return nil
+		}
+
+		return gpDrawLines(graphics, gpPen, gpPoints([]Point{from, to}, c.DPI()))
+	}); handled {
+		return err
+	}
+
 	dpi := c.DPI()
 
 	from = from.From96DPI(dpi)
@@ -321,6 +672,18 @@ func (c *Canvas) DrawPolyline(pen Pen, points []Point) error {
 		return nil
 	}
 
+	if len(points) > 1 {
+		if handled, err := c.withGdiplus(pen, nil, func(graphics, gpPen, _ uintptr) error {
+			if gpPen == 0 {
+				return nil
+			}
+
+			return gpDrawLines(graphics, gpPen, gpPoints(points, c.DPI()))
+		}); handled {
+			return err
+		}
+	}
+
 	dpi := c.DPI()
 
 	pts := make([]win.POINT, len(points))
@@ -339,6 +702,110 @@ func (c *Canvas) DrawPolyline(pen Pen, points []Point) error {
 	})
 }
 
+func (c *Canvas) polygon(brush Brush, pen Pen, points []Point, fillMode FillMode) error {
+	if len(points) < 2 {
+		return nil
+	}
+
+	if handled, err := c.withGdiplus(pen, brush, func(graphics, gpPen, gpBrush uintptr) error {
+		return gpPolygon(graphics, gpPen, gpBrush, gpPoints(points, c.DPI()), fillMode)
+	}); handled {
+		return err
+	}
+
+	dpi := c.DPI()
+
+	pts := make([]win.POINT, len(points))
+	for i, p := range points {
+		p = p.From96DPI(dpi)
+
+		pts[i] = win.POINT{X: int32(p.X), Y: int32(p.Y)}
+	}
+
+	return c.withBrushAndPen(brush, pen, func() error {
+		prevMode := setPolyFillMode(c.hdc, int32(fillMode))
+		if prevMode == 0 {
+			return newError("SetPolyFillMode failed")
+		}
+		defer setPolyFillMode(c.hdc, prevMode)
+
+		if !polygon(c.hdc, &pts[0], int32(len(pts))) {
+			return newError("Polygon failed")
+		}
+
+		return nil
+	})
+}
+
+// DrawPolygon draws the outline of the polygon with the given vertices,
+// closing it automatically.
+func (c *Canvas) DrawPolygon(pen Pen, points []Point) error {
+	return c.polygon(nullBrushSingleton, pen, points, FillModeAlternate)
+}
+
+// FillPolygon fills the polygon with the given vertices. fillMode determines
+// which parts of a self-intersecting polygon are inside.
+func (c *Canvas) FillPolygon(brush Brush, points []Point, fillMode FillMode) error {
+	return c.polygon(brush, nullPenSingleton, points, fillMode)
+}
+
+// DrawPath strokes path with pen.
+func (c *Canvas) DrawPath(pen Pen, path *GraphicsPath) error {
+	if path == nil {
+		return newError("path cannot be nil")
+	}
+
+	if handled, err := c.withGdiplus(pen, nil, func(graphics, gpPen, _ uintptr) error {
+		return gpPath(graphics, gpPen, 0, path, FillModeAlternate, c.DPI())
+	}); handled {
+		return err
+	}
+
+	return c.withPen(pen, func() error {
+		if err := path.build(c.hdc, c.DPI()); err != nil {
+			return err
+		}
+
+		if !strokePath(c.hdc) {
+			return newError("StrokePath failed")
+		}
+
+		return nil
+	})
+}
+
+// FillPath fills the inside of path with brush. Open figures are closed
+// implicitly.
+func (c *Canvas) FillPath(brush Brush, path *GraphicsPath, fillMode FillMode) error {
+	if path == nil {
+		return newError("path cannot be nil")
+	}
+
+	if handled, err := c.withGdiplus(nil, brush, func(graphics, _, gpBrush uintptr) error {
+		return gpPath(graphics, 0, gpBrush, path, fillMode, c.DPI())
+	}); handled {
+		return err
+	}
+
+	return c.withBrush(brush, func() error {
+		prevMode := setPolyFillMode(c.hdc, int32(fillMode))
+		if prevMode == 0 {
+			return newError("SetPolyFillMode failed")
+		}
+		defer setPolyFillMode(c.hdc, prevMode)
+
+		if err := path.build(c.hdc, c.DPI()); err != nil {
+			return err
+		}
+
+		if !fillPath(c.hdc) {
+			return newError("FillPath failed")
+		}
+
+		return nil
+	})
+}
+
 func (c *Canvas) rectangle(brush Brush, pen Pen, bounds Rectangle, sizeCorrection int) error {
 	return c.rectanglePixels(brush, pen, bounds.From96DPI(c.DPI()), sizeCorrection)
 }
@@ -562,3 +1029,37 @@ func (c *Canvas) MeasureText(text string, font *Font, bounds Rectangle, format D
 
 	return
 }
+
+// MeasureTextPixels returns the size text would take up when drawn with font
+// into bounds, both in native pixels of the Canvas.
+//
+// Unlike MeasureText, format is honored as is, so text is only wrapped at
+// bounds.Width if format contains TextWordbreak. Nothing is drawn.
+func (c *Canvas) MeasureTextPixels(text string, font *Font, bounds Rectangle, format DrawTextFormat) (Size, error) {
+	if font == nil {
+		return Size{}, newError("font cannot be nil")
+	}
+
+	var size Size
+
+	err := c.withGdiObj(win.HGDIOBJ(font.handleForDPI(c.DPI())), func() error {
+		rect := bounds.toRECT()
+
+		height := win.DrawTextEx(
+			c.hdc,
+			syscall.StringToUTF16Ptr(text),
+			-1,
+			&rect,
+			uint32(format)|win.DT_CALCRECT|win.DT_EDITCONTROL,
+			nil)
+		if height == 0 && text != "" {
+			return newError("DrawTextEx failed")
+		}
+
+		size = Size{int(rect.Right - rect.Left), int(height)}
+
+		return nil
+	})
+
+	return size, err
+}
@@ -0,0 +1,17 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import "testing"
+
+func TestArcRadialPoints(t *testing.T) {
+	start, end := arcRadialPoints(Rectangle{0, 0, 100, 100}, 0, 90)
+
+	if start != (Point{191, 50}) || end != (Point{50, 191}) {
+		t.Errorf("got %v, %v, want {191 50}, {50 191}", start, end)
+	}
+}
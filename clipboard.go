@@ -7,6 +7,9 @@
 package walk
 
 import (
+	"bytes"
+	"fmt"
+	"strconv"
 	"syscall"
 	"unsafe"
 
@@ -15,8 +18,35 @@ import (
 
 const clipboardWindowClass = `\o/ Walk_Clipboard_Class \o/`
 
+// ClipboardFormat identifies a format of clipboard data.
+type ClipboardFormat uint32
+
+const (
+	ClipboardFormatText  ClipboardFormat = win.CF_UNICODETEXT
+	ClipboardFormatImage ClipboardFormat = win.CF_DIB
+	ClipboardFormatFiles ClipboardFormat = win.CF_HDROP
+)
+
+// ClipboardFormatHTML is the "HTML Format" used for HTML fragments. It is
+// registered when walk is initialized.
+var ClipboardFormatHTML ClipboardFormat
+
+// RegisterClipboardFormat returns the format registered with the system under
+// name, registering it if necessary. Applications registering the same name
+// get the same format.
+func RegisterClipboardFormat(name string) (ClipboardFormat, error) {
+	format := registerClipboardFormat(syscall.StringToUTF16Ptr(name))
+	if format == 0 {
+		return 0, lastError("RegisterClipboardFormat")
+	}
+
+	return ClipboardFormat(format), nil
+}
+
 func init() {
 	AppendToWalkInit(func() {
+		ClipboardFormatHTML, _ = RegisterClipboardFormat("HTML Format")
+
 		MustRegisterWindowClassWithWndProcPtr(clipboardWindowClass, syscall.NewCallback(clipboardWndProc))
 
 		hwnd := win.CreateWindowEx(
@@ -153,6 +183,146 @@ func (c *ClipboardService) SetText(s string) error {
 	})
 }
 
+// HasFormat returns whether the clipboard currently contains data in format.
+func (c *ClipboardService) HasFormat(format ClipboardFormat) (available bool, err error) {
+	err = c.withOpenClipboard(func() error {
+		available = win.IsClipboardFormatAvailable(uint32(format))
+
+		return nil
+	})
+
+	return
+}
+
+// Image returns a new Bitmap containing the current image data of the
+// clipboard. The caller is responsible for disposing it.
+func (c *ClipboardService) Image() (bmp *Bitmap, err error) {
+	err = c.withOpenClipboard(func() error {
+		// The system converts CF_DIB to CF_BITMAP on demand.
+		hBmp := win.HBITMAP(win.GetClipboardData(win.CF_BITMAP))
+		if hBmp == 0 {
+			return lastError("GetClipboardData")
+		}
+
+		var bm win.BITMAP
+		if win.GetObject(win.HGDIOBJ(hBmp), unsafe.Sizeof(bm), unsafe.Pointer(&bm)) == 0 {
+			return newError("GetObject failed")
+		}
+
+		size := Size{int(bm.BmWidth), int(bm.BmHeight)}
+
+		var err error
+		if bmp, err = NewBitmapWithTransparentPixels(size); err != nil {
+			return err
+		}
+
+		if err = bmp.withSelectedIntoMemDC(func(hdcDst win.HDC) error {
+			return withCompatibleDC(func(hdcSrc win.HDC) error {
+				hBmpOld := win.SelectObject(hdcSrc, win.HGDIOBJ(hBmp))
+				if hBmpOld == 0 {
+					return newError("SelectObject failed")
+				}
+				defer win.SelectObject(hdcSrc, hBmpOld)
+
+				if !win.BitBlt(hdcDst, 0, 0, int32(size.Width), int32(size.Height), hdcSrc, 0, 0, win.SRCCOPY) {
+					return newError("BitBlt failed")
+				}
+
+				return nil
+			})
+		}); err != nil {
+			bmp.Dispose()
+			bmp = nil
+			return err
+		}
+
+		bmp.postProcess()
+
+		return nil
+	})
+
+	return
+}
+
+// SetImage replaces the contents of the clipboard with the pixels of bmp.
+func (c *ClipboardService) SetImage(bmp *Bitmap) error {
+	data, err := packedDIBFromBitmap(bmp)
+	if err != nil {
+		return err
+	}
+
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
+		}
+
+		return setClipboardData(win.CF_DIB, data)
+	})
+}
+
+// HTML returns the HTML fragment currently on the clipboard.
+func (c *ClipboardService) HTML() (html string, err error) {
+	err = c.withOpenClipboard(func() error {
+		data, err := clipboardData(uint32(ClipboardFormatHTML))
+		if err != nil {
+			return err
+		}
+
+		html, err = htmlFragmentFromCFHTML(data)
+		return err
+	})
+
+	return
+}
+
+// SetHTML replaces the contents of the clipboard with the HTML fragment html.
+//
+// The fragment is wrapped as described by the CF_HTML format, so applications
+// like browsers and word processors can paste it.
+func (c *ClipboardService) SetHTML(html string) error {
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
+		}
+
+		return setClipboardData(uint32(ClipboardFormatHTML), cfHTMLFromHTMLFragment(html))
+	})
+}
+
+// FilePaths returns the paths of the files currently on the clipboard, e.g.
+// after files were copied in Explorer.
+func (c *ClipboardService) FilePaths() (paths []string, err error) {
+	err = c.withOpenClipboard(func() error {
+		hDrop := win.HDROP(win.GetClipboardData(win.CF_HDROP))
+		if hDrop == 0 {
+			return lastError("GetClipboardData")
+		}
+
+		paths = filePathsFromHDROP(hDrop)
+
+		return nil
+	})
+
+	return
+}
+
+// SetFilePaths replaces the contents of the clipboard with the paths of
+// files, which can then be pasted e.g. in Explorer.
+func (c *ClipboardService) SetFilePaths(paths []string) error {
+	data, err := hDropDataFromFilePaths(paths)
+	if err != nil {
+		return err
+	}
+
+	return c.withOpenClipboard(func() error {
+		if !win.EmptyClipboard() {
+			return lastError("EmptyClipboard")
+		}
+
+		return setClipboardData(win.CF_HDROP, data)
+	})
+}
+
 func (c *ClipboardService) withOpenClipboard(f func() error) error {
 	if !win.OpenClipboard(c.hwnd) {
 		return lastError("OpenClipboard")
@@ -161,3 +331,192 @@ func (c *ClipboardService) withOpenClipboard(f func() error) error {
 
 	return f()
 }
+
+// clipboardData returns a copy of the clipboard data in format. The clipboard
+// must be open.
+func clipboardData(format uint32) ([]byte, error) {
+	hMem := win.HGLOBAL(win.GetClipboardData(format))
+	if hMem == 0 {
+		return nil, lastError("GetClipboardData")
+	}
+
+	return bytesFromHGLOBAL(hMem)
+}
+
+// bytesFromHGLOBAL returns a copy of the memory referred to by hMem.
+func bytesFromHGLOBAL(hMem win.HGLOBAL) ([]byte, error) {
+	p := win.GlobalLock(hMem)
+	if p == nil {
+		return nil, lastError("GlobalLock()")
+	}
+	defer win.GlobalUnlock(hMem)
+
+	data := make([]byte, globalSize(hMem))
+	if len(data) > 0 {
+		win.MoveMemory(unsafe.Pointer(&data[0]), p, uintptr(len(data)))
+	}
+
+	return data, nil
+}
+
+// setClipboardData sets the clipboard data in format to a copy of data. The
+// clipboard must be open.
+func setClipboardData(format uint32, data []byte) error {
+	hMem := win.GlobalAlloc(win.GMEM_MOVEABLE, uintptr(len(data)))
+	if hMem == 0 {
+		return lastError("GlobalAlloc")
+	}
+
+	p := win.GlobalLock(hMem)
+	if p == nil {
+		win.GlobalFree(hMem)
+
+		return lastError("GlobalLock()")
+	}
+
+	if len(data) > 0 {
+		win.MoveMemory(p, unsafe.Pointer(&data[0]), uintptr(len(data)))
+	}
+
+	win.GlobalUnlock(hMem)
+
+	if 0 == win.SetClipboardData(format, win.HANDLE(hMem)) {
+		// We need to free hMem.
+		defer win.GlobalFree(hMem)
+
+		return lastError("SetClipboardData")
+	}
+
+	// The system now owns the memory referred to by hMem.
+
+	return nil
+}
+
+// packedDIBFromBitmap returns the current pixels of bmp as packed DIB, as
+// used by CF_DIB.
+func packedDIBFromBitmap(bmp *Bitmap) ([]byte, error) {
+	var dib win.DIBSECTION
+	if win.GetObject(win.HGDIOBJ(bmp.hBmp), unsafe.Sizeof(dib), unsafe.Pointer(&dib)) == 0 {
+		return nil, newError("GetObject failed")
+	}
+
+	win.GdiFlush()
+
+	hdr := dib.DsBmih
+
+	height := hdr.BiHeight
+	if height < 0 {
+		height = -height
+	}
+
+	stride := ((hdr.BiWidth*int32(hdr.BiBitCount) + 31) / 32) * 4
+	pixelsSize := int(stride * height)
+
+	hdr.BiSizeImage = uint32(pixelsSize)
+
+	hdrSize := int(unsafe.Sizeof(hdr))
+	data := make([]byte, 0, hdrSize+len(dib.DsBitfields)*4+pixelsSize)
+
+	data = append(data, (*[1 << 30]byte)(unsafe.Pointer(&hdr))[:hdrSize:hdrSize]...)
+
+	if hdr.BiCompression == win.BI_BITFIELDS {
+		data = append(data, (*[12]byte)(unsafe.Pointer(&dib.DsBitfields))[:]...)
+	}
+
+	data = append(data, (*[1 << 30]byte)(dib.DsBm.BmBits)[:pixelsSize:pixelsSize]...)
+
+	return data, nil
+}
+
+// hDropDataFromFilePaths returns the data of a CF_HDROP for paths.
+func hDropDataFromFilePaths(paths []string) ([]byte, error) {
+	var df dropFiles
+	df.pFiles = uint32(unsafe.Sizeof(df))
+	df.fWide = 1
+
+	var files []uint16
+	for _, path := range paths {
+		utf16, err := syscall.UTF16FromString(path)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, utf16...)
+	}
+	// The list is terminated by an additional NUL.
+	files = append(files, 0)
+
+	data := make([]byte, int(df.pFiles)+len(files)*2)
+
+	*(*dropFiles)(unsafe.Pointer(&data[0])) = df
+
+	win.MoveMemory(unsafe.Pointer(&data[df.pFiles]), unsafe.Pointer(&files[0]), uintptr(len(files)*2))
+
+	return data, nil
+}
+
+const (
+	cfHTMLHeader = "Version:0.9\r\n" +
+		"StartHTML:%010d\r\n" +
+		"EndHTML:%010d\r\n" +
+		"StartFragment:%010d\r\n" +
+		"EndFragment:%010d\r\n"
+	cfHTMLPrefix = "<html><body>\r\n<!--StartFragment-->"
+	cfHTMLSuffix = "<!--EndFragment-->\r\n</body></html>"
+)
+
+// cfHTMLFromHTMLFragment wraps the UTF-8 encoded fragment as CF_HTML.
+func cfHTMLFromHTMLFragment(fragment string) []byte {
+	// The offsets have a fixed width, so the length of the header is known.
+	headerLen := len(fmt.Sprintf(cfHTMLHeader, 0, 0, 0, 0))
+
+	startHTML := headerLen
+	startFragment := startHTML + len(cfHTMLPrefix)
+	endFragment := startFragment + len(fragment)
+	endHTML := endFragment + len(cfHTMLSuffix)
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, cfHTMLHeader, startHTML, endHTML, startFragment, endFragment)
+	buf.WriteString(cfHTMLPrefix)
+	buf.WriteString(fragment)
+	buf.WriteString(cfHTMLSuffix)
+	buf.WriteByte(0)
+
+	return buf.Bytes()
+}
+
+// htmlFragmentFromCFHTML returns the fragment of the CF_HTML data.
+func htmlFragmentFromCFHTML(data []byte) (string, error) {
+	if i := bytes.IndexByte(data, 0); i > -1 {
+		data = data[:i]
+	}
+
+	offset := func(name string) int {
+		key := []byte(name + ":")
+
+		i := bytes.Index(data, key)
+		if i == -1 {
+			return -1
+		}
+
+		value := data[i+len(key):]
+		if j := bytes.IndexAny(value, "\r\n"); j > -1 {
+			value = value[:j]
+		}
+
+		n, err := strconv.Atoi(string(bytes.TrimSpace(value)))
+		if err != nil {
+			return -1
+		}
+
+		return n
+	}
+
+	start, end := offset("StartFragment"), offset("EndFragment")
+	if start < 0 || end < start || end > len(data) {
+		return "", newError("invalid CF_HTML data")
+	}
+
+	return string(data[start:end]), nil
+}
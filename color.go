@@ -8,6 +8,10 @@ package walk
 
 type Color uint32
 
+// Transparent is a special Color value that some APIs accept instead of an
+// actual color to request a transparent background.
+const Transparent Color = 0xff000000
+
 func RGB(r, g, b byte) Color {
 	return Color(uint32(r) | uint32(g)<<8 | uint32(b)<<16)
 }
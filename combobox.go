@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"reflect"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 	"unsafe"
@@ -18,6 +19,21 @@ import (
 	"github.com/lxn/win"
 )
 
+// ComboBoxAutoCompleteMode specifies how an editable ComboBox filters its
+// drop-down list while the user types.
+type ComboBoxAutoCompleteMode int
+
+const (
+	// AutoCompleteNone disables filtering.
+	AutoCompleteNone ComboBoxAutoCompleteMode = iota
+
+	// AutoCompletePrefix keeps items whose text starts with the typed text.
+	AutoCompletePrefix
+
+	// AutoCompleteContains keeps items whose text contains the typed text.
+	AutoCompleteContains
+)
+
 type ComboBox struct {
 	WidgetBase
 	bindingValueProvider         BindingValueProvider
@@ -41,6 +57,10 @@ type ComboBox struct {
 	editOrigWndProcPtr           uintptr
 	editing                      bool
 	persistent                   bool
+	autoCompleteMode             ComboBoxAutoCompleteMode
+	filterFunc                   func(itemText, text string) bool
+	filteredIndexes              []int
+	cueBanner                    string
 }
 
 var comboBoxEditWndProcPtr uintptr
@@ -73,6 +93,15 @@ func comboBoxEditWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 		}
 
 	case win.WM_KEYDOWN:
+		if wParam == win.VK_RETURN && len(cb.filteredIndexes) == 1 {
+			cb.SendMessage(win.CB_SHOWDROPDOWN, win.FALSE, 0)
+
+			index := cb.filteredIndexes[0]
+			cb.clearAutoCompleteFilter()
+			cb.SetCurrentIndex(index)
+			cb.Property("Value").Set(cb.model.Value(index))
+		}
+
 		if wParam != win.VK_RETURN || 0 == cb.SendMessage(win.CB_GETDROPPEDSTATE, 0, 0) {
 			cb.handleKeyDown(wParam, lParam)
 		}
@@ -90,6 +119,10 @@ func comboBoxEditWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 	case win.WM_SETFOCUS, win.WM_KILLFOCUS:
 		cb.invalidateBorderInParent()
 
+		if msg == win.WM_KILLFOCUS {
+			cb.clearAutoCompleteFilter()
+		}
+
 		if cb.editing && msg == win.WM_KILLFOCUS {
 			cb.editing = false
 			cb.editingFinishedPublisher.Publish()
@@ -243,6 +276,10 @@ func (cb *ComboBox) applyFont(font *Font) {
 	}
 }
 
+func (cb *ComboBox) applyDarkMode(dark bool) {
+	setDarkModeTheme(cb.hWnd, dark, "DarkMode_CFD", "")
+}
+
 func (cb *ComboBox) Editable() bool {
 	return !cb.hasStyleBits(win.CBS_DROPDOWNLIST)
 }
@@ -289,11 +326,18 @@ func (cb *ComboBox) resetItems() error {
 	defer cb.SetSuspended(false)
 
 	cb.selChangeIndex = -1
+	cb.filteredIndexes = nil
 
 	if win.FALSE == cb.SendMessage(win.CB_RESETCONTENT, 0, 0) {
 		return newError("SendMessage(CB_RESETCONTENT)")
 	}
 
+	if cb.cueBanner != "" {
+		if err := cb.SetCueBanner(cb.cueBanner); err != nil {
+			return err
+		}
+	}
+
 	cb.maxItemTextWidth = 0
 
 	cb.SetCurrentIndex(-1)
@@ -322,6 +366,8 @@ func (cb *ComboBox) attachModel() {
 	cb.itemsResetHandlerHandle = cb.model.ItemsReset().Attach(itemsResetHandler)
 
 	itemChangedHandler := func(index int) {
+		cb.clearAutoCompleteFilter()
+
 		if win.CB_ERR == cb.SendMessage(win.CB_DELETESTRING, uintptr(index), 0) {
 			newError("SendMessage(CB_DELETESTRING)")
 		}
@@ -333,12 +379,16 @@ func (cb *ComboBox) attachModel() {
 	cb.itemChangedHandlerHandle = cb.model.ItemChanged().Attach(itemChangedHandler)
 
 	cb.itemsInsertedHandlerHandle = cb.model.ItemsInserted().Attach(func(from, to int) {
+		cb.clearAutoCompleteFilter()
+
 		for i := from; i <= to; i++ {
 			cb.insertItemAt(i)
 		}
 	})
 
 	cb.itemsRemovedHandlerHandle = cb.model.ItemsRemoved().Attach(func(from, to int) {
+		cb.clearAutoCompleteFilter()
+
 		for i := to; i >= from; i-- {
 			cb.removeItem(i)
 		}
@@ -504,6 +554,29 @@ func (cb *ComboBox) SetPrecision(value int) {
 	cb.precision = value
 }
 
+// CueBanner returns the text that is displayed while the ComboBox shows no
+// text.
+func (cb *ComboBox) CueBanner() string {
+	buf := make([]uint16, 128)
+	if win.FALSE == cb.SendMessage(cbGetCueBanner, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))) {
+		return cb.cueBanner
+	}
+
+	return syscall.UTF16ToString(buf)
+}
+
+// SetCueBanner sets the text that is displayed while the ComboBox shows no
+// text, like a placeholder hint.
+func (cb *ComboBox) SetCueBanner(value string) error {
+	if win.FALSE == cb.SendMessage(cbSetCueBanner, 0, uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(value)))) {
+		return newError("CB_SETCUEBANNER failed")
+	}
+
+	cb.cueBanner = value
+
+	return nil
+}
+
 func (cb *ComboBox) MaxLength() int {
 	return cb.maxLength
 }
@@ -544,10 +617,18 @@ func (cb *ComboBox) calculateMaxItemTextWidth() int {
 }
 
 func (cb *ComboBox) CurrentIndex() int {
-	return int(int32(cb.SendMessage(win.CB_GETCURSEL, 0, 0)))
+	index := int(int32(cb.SendMessage(win.CB_GETCURSEL, 0, 0)))
+
+	if index > -1 && cb.filteredIndexes != nil {
+		return cb.filteredIndexes[index]
+	}
+
+	return index
 }
 
 func (cb *ComboBox) SetCurrentIndex(value int) error {
+	cb.clearAutoCompleteFilter()
+
 	index := int(int32(cb.SendMessage(win.CB_SETCURSEL, uintptr(value), 0)))
 
 	if index != value {
@@ -562,6 +643,121 @@ func (cb *ComboBox) SetCurrentIndex(value int) error {
 	return nil
 }
 
+// AutoCompleteMode returns how an editable ComboBox filters its drop-down list
+// while the user types.
+func (cb *ComboBox) AutoCompleteMode() ComboBoxAutoCompleteMode {
+	return cb.autoCompleteMode
+}
+
+// SetAutoCompleteMode sets how an editable ComboBox filters its drop-down list
+// while the user types.
+//
+// While filtered, the drop-down list only shows the matching items of the
+// model. Pressing the return key when there is exactly one match selects it.
+// Indexes reported by the ComboBox always refer to the model.
+func (cb *ComboBox) SetAutoCompleteMode(mode ComboBoxAutoCompleteMode) {
+	cb.autoCompleteMode = mode
+
+	if mode == AutoCompleteNone {
+		cb.clearAutoCompleteFilter()
+	}
+}
+
+// FilterFunc returns the function that decides which items match the text
+// typed by the user, if any.
+func (cb *ComboBox) FilterFunc() func(itemText, text string) bool {
+	return cb.filterFunc
+}
+
+// SetFilterFunc sets a function that decides which items match the text typed
+// by the user, overriding the case-insensitive comparison implied by
+// AutoCompleteMode.
+func (cb *ComboBox) SetFilterFunc(filterFunc func(itemText, text string) bool) {
+	cb.filterFunc = filterFunc
+}
+
+func (cb *ComboBox) autoCompleteMatches(itemText, text string) bool {
+	if cb.filterFunc != nil {
+		return cb.filterFunc(itemText, text)
+	}
+
+	itemText, text = strings.ToLower(itemText), strings.ToLower(text)
+
+	if cb.autoCompleteMode == AutoCompleteContains {
+		return strings.Contains(itemText, text)
+	}
+
+	return strings.HasPrefix(itemText, text)
+}
+
+func (cb *ComboBox) applyAutoCompleteFilter() {
+	if cb.model == nil {
+		return
+	}
+
+	text := cb.text()
+
+	if text == "" {
+		cb.SendMessage(win.CB_SHOWDROPDOWN, win.FALSE, 0)
+		cb.clearAutoCompleteFilter()
+		return
+	}
+
+	indexes := []int{}
+	count := cb.model.ItemCount()
+	for i := 0; i < count; i++ {
+		if cb.autoCompleteMatches(cb.itemString(i), text) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	cb.fillFilteredItems(indexes, text)
+
+	cb.SendMessage(win.CB_SHOWDROPDOWN, uintptr(win.BoolToBOOL(len(indexes) > 0)), 0)
+
+	// Showing the drop-down list may have replaced the text with a match.
+	cb.setText(text)
+	cb.SetTextSelection(len(text), len(text))
+
+	// The mouse cursor is hidden while typing, but showing the drop-down
+	// list would make it disappear for good.
+	win.SetCursor(win.LoadCursor(0, win.MAKEINTRESOURCE(win.IDC_ARROW)))
+}
+
+func (cb *ComboBox) clearAutoCompleteFilter() {
+	if cb.filteredIndexes == nil {
+		return
+	}
+
+	cb.fillFilteredItems(nil, cb.text())
+}
+
+func (cb *ComboBox) fillFilteredItems(indexes []int, text string) {
+	cb.filteredIndexes = indexes
+
+	cb.SendMessage(win.CB_RESETCONTENT, 0, 0)
+
+	var count int
+	if indexes == nil {
+		count = cb.model.ItemCount()
+	} else {
+		count = len(indexes)
+	}
+
+	for i := 0; i < count; i++ {
+		index := i
+		if indexes != nil {
+			index = indexes[i]
+		}
+
+		lp := uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(cb.itemString(index))))
+		cb.SendMessage(win.CB_ADDSTRING, 0, lp)
+	}
+
+	// CB_RESETCONTENT also clears the edit control.
+	cb.setText(text)
+}
+
 func (cb *ComboBox) CurrentIndexChanged() *Event {
 	return cb.currentIndexChangedPublisher.Event()
 }
@@ -637,6 +833,11 @@ func (cb *ComboBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 		case win.CBN_EDITCHANGE:
 			cb.editing = true
 			cb.selChangeIndex = -1
+
+			if cb.autoCompleteMode != AutoCompleteNone {
+				cb.applyAutoCompleteFilter()
+			}
+
 			cb.textChangedPublisher.Publish()
 
 		case win.CBN_SELCHANGE:
@@ -200,3 +200,150 @@ func (dlg *FileDialog) ShowBrowseFolder(owner Form) (accepted bool, err error) {
 	accepted = dlg.FilePath != ""
 	return
 }
+
+// PrintDialog lets the user choose a printer, its settings and the pages to
+// print.
+type PrintDialog struct {
+	// Printer is the printer initially selected in the dialog and, after the
+	// dialog was accepted, the printer chosen by the user. If it is nil
+	// initially, the default printer is selected.
+	Printer *Printer
+
+	// MinPage and MaxPage are the range of pages the document consists of.
+	// If MaxPage is 0, the user can not choose the pages to print.
+	MinPage int
+	MaxPage int
+
+	// FromPage and ToPage are the pages to print. After the dialog was
+	// accepted, ToPage is 0 if all pages are to be printed.
+	FromPage int
+	ToPage   int
+}
+
+func (dlg *PrintDialog) Show(owner Form) (accepted bool, err error) {
+	if owner == nil {
+		return false, newError("owner must not be nil")
+	}
+
+	var pd win.PRINTDLGEX
+	pd.LStructSize = uint32(unsafe.Sizeof(pd))
+	pd.HwndOwner = owner.Handle()
+	pd.Flags = win.PD_USEDEVMODECOPIESANDCOLLATE | win.PD_NOSELECTION | win.PD_NOCURRENTPAGE
+	pd.NCopies = 1
+	pd.NStartPage = win.START_PAGE_GENERAL
+
+	if dlg.Printer != nil {
+		if pd.HDevMode, pd.HDevNames, err = dlg.Printer.globals(); err != nil {
+			return false, err
+		}
+	}
+	defer func() {
+		freeGlobals(pd.HDevMode, pd.HDevNames)
+	}()
+
+	pageRange := win.PRINTPAGERANGE{NFromPage: uint32(dlg.FromPage), NToPage: uint32(dlg.ToPage)}
+
+	if dlg.MaxPage == 0 {
+		pd.Flags |= win.PD_NOPAGENUMS
+	} else {
+		pd.NMinPage = uint32(maxi(dlg.MinPage, 1))
+		pd.NMaxPage = uint32(dlg.MaxPage)
+		pd.NMaxPageRanges = 1
+		pd.LpPageRanges = &pageRange
+
+		if dlg.ToPage > 0 {
+			pd.NPageRanges = 1
+			pd.Flags |= win.PD_PAGENUMS
+		}
+	}
+
+	if hr := win.PrintDlgEx(&pd); win.FAILED(hr) {
+		return false, errorFromHRESULT("PrintDlgEx", hr)
+	}
+
+	if pd.DwResultAction != win.PD_RESULT_PRINT {
+		return false, nil
+	}
+
+	hDevMode, hDevNames := pd.HDevMode, pd.HDevNames
+	pd.HDevMode, pd.HDevNames = 0, 0
+
+	if dlg.Printer, err = printerFromGlobals(hDevMode, hDevNames); err != nil {
+		return false, err
+	}
+
+	if pd.Flags&win.PD_PAGENUMS != 0 && pd.NPageRanges > 0 {
+		dlg.FromPage = int(pageRange.NFromPage)
+		dlg.ToPage = int(pageRange.NToPage)
+	} else {
+		dlg.FromPage = 0
+		dlg.ToPage = 0
+	}
+
+	return true, nil
+}
+
+// PageSetupDialog lets the user choose the paper settings of a printer and
+// the margins of the pages.
+type PageSetupDialog struct {
+	// Printer is the printer whose settings are shown in the dialog and,
+	// after the dialog was accepted, the printer with the settings chosen by
+	// the user. If it is nil initially, the default printer is used.
+	Printer *Printer
+
+	// Margins are the margins of the pages in 1/96" units. If they are all
+	// 0 initially, the dialog proposes default margins.
+	Margins Margins
+}
+
+func (dlg *PageSetupDialog) Show(owner Form) (accepted bool, err error) {
+	var psd pageSetupDlg
+	psd.lStructSize = uint32(unsafe.Sizeof(psd))
+	psd.flags = psdInThousandthsOfInches
+	if owner != nil {
+		psd.hwndOwner = owner.Handle()
+	}
+
+	if dlg.Printer != nil {
+		if psd.hDevMode, psd.hDevNames, err = dlg.Printer.globals(); err != nil {
+			return false, err
+		}
+	}
+
+	if m := dlg.Margins; m != (Margins{}) {
+		psd.flags |= psdMargins
+		psd.rtMargin = win.RECT{
+			Left:   int32(m.HNear * 1000 / 96),
+			Top:    int32(m.VNear * 1000 / 96),
+			Right:  int32(m.HFar * 1000 / 96),
+			Bottom: int32(m.VFar * 1000 / 96),
+		}
+	}
+
+	if !pageSetupDialog(&psd) {
+		freeGlobals(psd.hDevMode, psd.hDevNames)
+
+		if errno := win.CommDlgExtendedError(); errno != 0 {
+			return false, newError(fmt.Sprintf("PageSetupDlg: Error %d", errno))
+		}
+
+		return false, nil
+	}
+
+	if dlg.Printer, err = printerFromGlobals(psd.hDevMode, psd.hDevNames); err != nil {
+		return false, err
+	}
+
+	from1000ths := func(v int32) int {
+		return (int(v)*96 + 500) / 1000
+	}
+
+	dlg.Margins = Margins{
+		HNear: from1000ths(psd.rtMargin.Left),
+		VNear: from1000ths(psd.rtMargin.Top),
+		HFar:  from1000ths(psd.rtMargin.Right),
+		VFar:  from1000ths(psd.rtMargin.Bottom),
+	}
+
+	return true, nil
+}
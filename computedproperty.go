@@ -0,0 +1,136 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"reflect"
+)
+
+// ComputedProperty is a read-only Property whose value is derived from other
+// Properties or Expressions, its dependencies.
+//
+// Whenever a dependency changes, the value is computed again and the Changed
+// event is published if it differs from the previous one. For example, a total
+// derived from a quantity and a price:
+//
+//	total := walk.NewComputedProperty(func() interface{} {
+//		return quantity.Get().(float64) * price.Get().(float64)
+//	}, quantity, price)
+//
+// As an Expression, a ComputedProperty can be the source of other properties
+// or be made available to declarative Bind expressions, e.g. through the
+// Expressions field of declarative.Dialog.
+type ComputedProperty struct {
+	compute                  func() interface{}
+	dependencies             []Expression
+	dependencyChangedHandles []int
+	value                    interface{}
+	changedPublisher         EventPublisher
+}
+
+// NewComputedProperty returns a new ComputedProperty that computes its value
+// by calling compute whenever one of dependencies changes.
+func NewComputedProperty(compute func() interface{}, dependencies ...Expression) *ComputedProperty {
+	cp := &ComputedProperty{compute: compute}
+
+	cp.SetDependencies(dependencies...)
+
+	return cp
+}
+
+// Dependencies returns the Expressions the value of the ComputedProperty
+// depends on.
+func (cp *ComputedProperty) Dependencies() []Expression {
+	return append([]Expression(nil), cp.dependencies...)
+}
+
+// SetDependencies replaces the Expressions the value of the ComputedProperty
+// depends on and computes the value again.
+func (cp *ComputedProperty) SetDependencies(dependencies ...Expression) {
+	cp.detachDependencies()
+
+	cp.dependencies = append([]Expression(nil), dependencies...)
+
+	for _, dep := range cp.dependencies {
+		cp.dependencyChangedHandles = append(cp.dependencyChangedHandles, dep.Changed().Attach(cp.Update))
+	}
+
+	cp.Update()
+}
+
+// Dispose detaches the ComputedProperty from its dependencies. Its value is
+// not updated anymore afterwards.
+func (cp *ComputedProperty) Dispose() {
+	cp.detachDependencies()
+
+	cp.dependencies = nil
+}
+
+func (cp *ComputedProperty) detachDependencies() {
+	for i, dep := range cp.dependencies {
+		dep.Changed().Detach(cp.dependencyChangedHandles[i])
+	}
+
+	cp.dependencyChangedHandles = nil
+}
+
+// Update computes the value again and publishes the Changed event if it
+// differs from the previous one.
+//
+// It is called automatically when a dependency changes, but can be called
+// directly if the value depends on state that is not tracked.
+func (cp *ComputedProperty) Update() {
+	value := cp.compute()
+
+	if reflect.DeepEqual(value, cp.value) {
+		return
+	}
+
+	cp.value = value
+
+	cp.changedPublisher.Publish()
+}
+
+func (*ComputedProperty) ReadOnly() bool {
+	return true
+}
+
+func (cp *ComputedProperty) Value() interface{} {
+	return cp.value
+}
+
+func (cp *ComputedProperty) Get() interface{} {
+	return cp.value
+}
+
+func (*ComputedProperty) Set(value interface{}) error {
+	return ErrPropertyReadOnly
+}
+
+func (cp *ComputedProperty) Changed() *Event {
+	return cp.changedPublisher.Event()
+}
+
+func (*ComputedProperty) Source() interface{} {
+	return nil
+}
+
+func (*ComputedProperty) SetSource(source interface{}) error {
+	return ErrPropertyReadOnly
+}
+
+func (*ComputedProperty) Validatable() bool {
+	return false
+}
+
+func (*ComputedProperty) Validator() Validator {
+	return nil
+}
+
+func (*ComputedProperty) SetValidator(validator Validator) error {
+	return ErrPropertyReadOnly
+}
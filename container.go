@@ -31,6 +31,12 @@ type ContainerBase struct {
 	dataBinder  *DataBinder
 	nextChildID int32
 	persistent  bool
+
+	layoutAnimationDuration time.Duration
+	layoutAnimation         *layoutAnimationState
+	animateNextLayout       bool
+	debugLayout             bool
+	debugLayoutBounds       map[*WidgetBase]Rectangle
 }
 
 func (cb *ContainerBase) AsWidgetBase() *WidgetBase {
@@ -137,6 +143,13 @@ func (cb *ContainerBase) SetDataBinder(db *DataBinder) {
 				return false
 			}
 
+			if widget, ok := w.(Widget); ok {
+				if _, ok := autoBindableWidget(widget); ok {
+					boundWidgets = append(boundWidgets, widget)
+					return true
+				}
+			}
+
 			for _, prop := range w.AsWindowBase().name2Property {
 				if _, ok := prop.Source().(string); ok {
 					boundWidgets = append(boundWidgets, w.(Widget))
@@ -258,6 +271,10 @@ func (cb *ContainerBase) doPaint() error {
 		}
 	}
 
+	if cb.debugLayout {
+		return cb.drawLayoutDebugOverlay(canvas)
+	}
+
 	return nil
 }
 
@@ -269,7 +286,7 @@ func (cb *ContainerBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintp
 		}
 
 	case win.WM_PAINT:
-		if FocusEffect == nil && InteractionEffect == nil && ValidationErrorEffect == nil {
+		if FocusEffect == nil && InteractionEffect == nil && ValidationErrorEffect == nil && defaultValidationErrorEffect == nil && !cb.debugLayout {
 			break
 		}
 
@@ -366,6 +383,12 @@ func (cb *ContainerBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintp
 			return window.WndProc(hwnd, msg, wParam, lParam)
 		}
 
+	case win.WM_TIMER:
+		if wParam == layoutAnimationTimerId {
+			cb.handleLayoutAnimationTimer()
+			return 0
+		}
+
 	case win.WM_WINDOWPOSCHANGED:
 		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
 
@@ -0,0 +1,156 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+var (
+	darkModeBackgroundColor = RGB(32, 32, 32)
+	darkModeControlColor    = RGB(43, 43, 43)
+	darkModeTextColor       = RGB(240, 240, 240)
+
+	// Like COLOR_BTNFACE for the selection of an unfocused list.
+	darkModeSelectedNotFocusedColor = RGB(70, 70, 70)
+)
+
+var (
+	darkModeBackgroundBrush *SolidColorBrush
+	darkModeControlBrush    *SolidColorBrush
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		darkModeBackgroundBrush, _ = NewSolidColorBrush(darkModeBackgroundColor)
+		darkModeControlBrush, _ = NewSolidColorBrush(darkModeControlColor)
+	})
+}
+
+// SystemUsesDarkMode returns if the user chose the dark app mode in the
+// Windows settings.
+//
+// Applications that want to follow the system theme can pass the result to
+// FormBase.SetDarkMode.
+func SystemUsesDarkMode() bool {
+	value, err := RegistryKeyUint32(
+		CurrentUserKey(),
+		`Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`,
+		"AppsUseLightTheme")
+	if err != nil {
+		// Windows versions without dark mode lack the value.
+		return false
+	}
+
+	return value == 0
+}
+
+// darkModeApplier is implemented by widgets that need more than the default
+// colors to be displayed in dark mode.
+type darkModeApplier interface {
+	applyDarkMode(dark bool)
+}
+
+// DarkMode returns if the Form is displayed in dark mode.
+func (fb *FormBase) DarkMode() bool {
+	return fb.darkMode
+}
+
+// SetDarkMode sets if the Form is displayed in dark mode.
+//
+// Dark mode makes the title bar dark, switches the default background and text
+// colors of the Form and its widgets and applies the dark themes of Windows to
+// the widgets that support them, like LineEdit, TreeView or TableView and its
+// header. Widgets added later do not pick up the dark themes by themselves, so
+// call SetDarkMode after the content of the Form has been created.
+//
+// Buttons, check boxes and radio buttons follow dark mode as well. Other
+// widgets, like ListBox, TabWidget, ToolBar, StatusBar, Slider, ProgressBar,
+// DateEdit, GroupBox titles and menus, keep their light appearance.
+//
+// The title bar stays light on Windows versions before Windows 10 1809.
+func (fb *FormBase) SetDarkMode(dark bool) error {
+	if dark == fb.darkMode {
+		return nil
+	}
+
+	fb.darkMode = dark
+
+	fb.applyDarkModeTitleBar()
+
+	if dark {
+		fb.backgroundBeforeDarkMode = fb.clientComposite.Background()
+		fb.clientComposite.SetBackground(darkModeBackgroundBrush)
+	} else {
+		fb.clientComposite.SetBackground(fb.backgroundBeforeDarkMode)
+		fb.backgroundBeforeDarkMode = nil
+	}
+
+	walkDescendants(fb.window, func(w Window) bool {
+		if dma, ok := w.(darkModeApplier); ok {
+			dma.applyDarkMode(dark)
+		}
+
+		return true
+	})
+
+	win.RedrawWindow(fb.hWnd, nil, 0, win.RDW_ERASE|win.RDW_FRAME|win.RDW_INVALIDATE|win.RDW_ALLCHILDREN)
+
+	return nil
+}
+
+func (fb *FormBase) applyDarkModeTitleBar() {
+	value := win.BOOL(win.FALSE)
+	if fb.darkMode {
+		value = win.TRUE
+	}
+
+	// The attribute is undocumented before Windows 10 20H1, where it got a new
+	// value.
+	if !dwmSetWindowAttribute(fb.hWnd, dwmwaUseImmersiveDarkMode, unsafe.Pointer(&value), uint32(unsafe.Sizeof(value))) {
+		dwmSetWindowAttribute(fb.hWnd, dwmwaUseImmersiveDarkModeBefore20H1, unsafe.Pointer(&value), uint32(unsafe.Sizeof(value)))
+	}
+}
+
+// darkModeEnabledForWindow returns if window is a Form in dark mode or a widget
+// on such a Form.
+func darkModeEnabledForWindow(window Window) bool {
+	var form Form
+
+	switch w := window.(type) {
+	case Form:
+		form = w
+
+	case Widget:
+		form = w.Form()
+	}
+
+	if form == nil {
+		return false
+	}
+
+	return form.AsFormBase().darkMode
+}
+
+// setDarkModeTheme applies darkAppName as the theme of hwnd in dark mode and
+// lightAppName otherwise. An empty name selects the default theme.
+func setDarkModeTheme(hwnd win.HWND, dark bool, darkAppName, lightAppName string) {
+	appName := lightAppName
+	if dark {
+		appName = darkAppName
+	}
+
+	var pszSubAppName *uint16
+	if appName != "" {
+		pszSubAppName = syscall.StringToUTF16Ptr(appName)
+	}
+
+	win.SetWindowTheme(hwnd, pszSubAppName, nil)
+}
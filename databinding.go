@@ -22,6 +22,54 @@ type ErrorPresenter interface {
 	PresentError(err error, widget Widget)
 }
 
+// FieldValidator may be implemented by the data source of a DataBinder to
+// validate the values of its fields, in addition to the Validators of the
+// bound properties.
+//
+// ValidateField is called with the path of a bound field, e.g. "Address.City",
+// and the value the user entered for it. It returns an error if the value is
+// not acceptable.
+type FieldValidator interface {
+	ValidateField(field string, value interface{}) error
+}
+
+// BindableWidget is implemented by widgets, e.g. custom ones, that a
+// DataBinder can bind without any Property being registered for them.
+//
+// A DataBinder binds the value of a BindableWidget to the field of its data
+// source with the same name as the widget, see SetName. Widgets without a name
+// and widgets that have a Property with a data source field as its source
+// are not bound this way.
+//
+// Value returns the current value of the widget. SetValue sets it, converting
+// from the type of the field if required, and returns an error if that is
+// impossible. ValueChanged must be published whenever the value changes,
+// including changes made by the user. For validation, the value may be an
+// error, which is returned from Submit.
+type BindableWidget interface {
+	Widget
+	Value() interface{}
+	SetValue(value interface{}) error
+	ValueChanged() *Event
+}
+
+// autoBindableWidget returns widget as BindableWidget, if a DataBinder shall
+// bind it by its name.
+func autoBindableWidget(widget Widget) (BindableWidget, bool) {
+	bw, ok := widget.(BindableWidget)
+	if !ok || bw.Name() == "" {
+		return nil, false
+	}
+
+	for _, prop := range widget.AsWindowBase().name2Property {
+		if _, ok := prop.Source().(string); ok {
+			return nil, false
+		}
+	}
+
+	return bw, true
+}
+
 type DataBinder struct {
 	dataSource                 interface{}
 	boundWidgets               []Widget
@@ -31,8 +79,10 @@ type DataBinder struct {
 	rootExpression             Expression
 	path2Expression            map[string]Expression
 	errorPresenter             ErrorPresenter
+	errors                     map[string]error
 	dataSourceChangedPublisher EventPublisher
 	canSubmitChangedPublisher  EventPublisher
+	errorsChangedPublisher     EventPublisher
 	submittedPublisher         EventPublisher
 	resetPublisher             EventPublisher
 	autoSubmitDelay            time.Duration
@@ -145,17 +195,37 @@ func (db *DataBinder) SetBoundWidgets(boundWidgets []Widget) {
 
 	db.boundWidgets = boundWidgets
 
+	db.properties = nil
 	db.property2Widget = make(map[Property]Widget)
 	db.property2ChangedHandle = make(map[Property]int)
 
 	for _, widget := range boundWidgets {
 		widget := widget
 
-		for _, prop := range widget.AsWindowBase().name2Property {
-			prop := prop
-			if _, ok := prop.Source().(string); !ok {
-				continue
+		var props []Property
+
+		if bw, ok := autoBindableWidget(widget); ok {
+			prop := NewProperty(
+				func() interface{} {
+					return bw.Value()
+				},
+				func(v interface{}) error {
+					return bw.SetValue(v)
+				},
+				bw.ValueChanged())
+			prop.SetSource(bw.Name())
+
+			props = append(props, prop)
+		} else {
+			for _, prop := range widget.AsWindowBase().name2Property {
+				if _, ok := prop.Source().(string); ok {
+					props = append(props, prop)
+				}
 			}
+		}
+
+		for _, prop := range props {
+			prop := prop
 
 			db.properties = append(db.properties, prop)
 			db.property2Widget[prop] = widget
@@ -216,15 +286,32 @@ func (db *DataBinder) Expression(path string) Expression {
 func (db *DataBinder) validateProperties() {
 	var hasError bool
 
+	errs := make(map[string]error)
+	fieldValidator, _ := db.dataSource.(FieldValidator)
+
 	for _, prop := range db.properties {
 		validator := prop.Validator()
-		if validator == nil {
+		if validator == nil && fieldValidator == nil {
 			continue
 		}
 
-		err := validator.Validate(prop.Get())
+		source, _ := prop.Source().(string)
+		value := prop.Get()
+
+		var err error
+		if validator != nil {
+			err = validator.Validate(value)
+		}
+		if err == nil && fieldValidator != nil && source != "" {
+			err = fieldValidator.ValidateField(source, value)
+		}
+
 		if err != nil {
 			hasError = true
+
+			if source != "" {
+				errs[source] = err
+			}
 		}
 
 		if db.errorPresenter != nil {
@@ -238,6 +325,52 @@ func (db *DataBinder) validateProperties() {
 		db.canSubmit = !hasError
 		db.canSubmitChangedPublisher.Publish()
 	}
+
+	db.setErrors(errs)
+}
+
+func (db *DataBinder) setErrors(errs map[string]error) {
+	if len(errs) == len(db.errors) {
+		equal := true
+
+		for field, err := range errs {
+			if old, ok := db.errors[field]; !ok || old.Error() != err.Error() {
+				equal = false
+				break
+			}
+		}
+
+		if equal {
+			return
+		}
+	}
+
+	db.errors = errs
+
+	db.errorsChangedPublisher.Publish()
+}
+
+// Errors returns the validation errors of the bound fields, keyed by the path
+// of the field, e.g. "Address.City". Fields with valid values are not
+// included.
+//
+// The errors are updated whenever a bound value changes and on Reset, which
+// makes them suitable for showing errors next to the fields as well as a
+// summary of them.
+func (db *DataBinder) Errors() map[string]error {
+	errs := make(map[string]error, len(db.errors))
+
+	for field, err := range db.errors {
+		errs[field] = err
+	}
+
+	return errs
+}
+
+// ErrorsChanged returns the event that is published when the validation
+// errors returned by Errors change.
+func (db *DataBinder) ErrorsChanged() *Event {
+	return db.errorsChangedPublisher.Event()
 }
 
 func (db *DataBinder) ErrorPresenter() ErrorPresenter {
@@ -0,0 +1,423 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// iDataObjectVtbl mirrors the vtable of the COM IDataObject interface.
+type iDataObjectVtbl struct {
+	QueryInterface        uintptr
+	AddRef                uintptr
+	Release               uintptr
+	GetData               uintptr
+	GetDataHere           uintptr
+	QueryGetData          uintptr
+	GetCanonicalFormatEtc uintptr
+	SetData               uintptr
+	EnumFormatEtc         uintptr
+	DAdvise               uintptr
+	DUnadvise             uintptr
+	EnumDAdvise           uintptr
+}
+
+type iDataObject struct {
+	lpVtbl *iDataObjectVtbl
+}
+
+func (obj *iDataObject) AddRef() uint32 {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.AddRef, 1,
+		uintptr(unsafe.Pointer(obj)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+func (obj *iDataObject) Release() uint32 {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.Release, 1,
+		uintptr(unsafe.Pointer(obj)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+func (obj *iDataObject) GetData(pformatetcIn *formatEtc, pmedium *stgMedium) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.GetData, 3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(pformatetcIn)),
+		uintptr(unsafe.Pointer(pmedium)))
+
+	return win.HRESULT(ret)
+}
+
+func (obj *iDataObject) QueryGetData(pformatetc *formatEtc) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.QueryGetData, 2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(pformatetc)),
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func hGlobalFormatEtc(format ClipboardFormat) formatEtc {
+	return formatEtc{
+		cfFormat: uint16(format),
+		dwAspect: dvaspectContent,
+		lindex:   -1,
+		tymed:    tymedHGlobal,
+	}
+}
+
+// DragData is the data carried by a drag and drop operation. It can be
+// available in several formats at once, like the contents of the clipboard.
+//
+// The data received by a drop target is read-only. Data to be dragged is
+// created with NewDragData and passed to WindowBase.DoDragDrop.
+type DragData struct {
+	dataObject       *iDataObject
+	formats          []ClipboardFormat
+	format2Data      map[ClipboardFormat][]byte
+	dragImage        *Bitmap
+	dragImageHotspot Point
+}
+
+// NewDragData returns a new, empty DragData to be dragged.
+func NewDragData() *DragData {
+	return &DragData{format2Data: make(map[ClipboardFormat][]byte)}
+}
+
+// HasFormat returns whether the data is available in format.
+func (d *DragData) HasFormat(format ClipboardFormat) bool {
+	if d.dataObject == nil {
+		_, ok := d.format2Data[format]
+		return ok
+	}
+
+	fe := hGlobalFormatEtc(format)
+
+	return d.dataObject.QueryGetData(&fe) == win.S_OK
+}
+
+// SetData sets the raw data in format, replacing any previous data in that
+// format.
+func (d *DragData) SetData(format ClipboardFormat, data []byte) error {
+	if d.format2Data == nil {
+		return newError("read-only data")
+	}
+
+	d.setData(format, data)
+
+	return nil
+}
+
+func (d *DragData) setData(format ClipboardFormat, data []byte) {
+	if _, ok := d.format2Data[format]; !ok {
+		d.formats = append(d.formats, format)
+	}
+
+	d.format2Data[format] = append([]byte(nil), data...)
+}
+
+// SetText sets the text data.
+func (d *DragData) SetText(text string) error {
+	utf16, err := syscall.UTF16FromString(text)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, len(utf16)*2)
+	win.MoveMemory(unsafe.Pointer(&data[0]), unsafe.Pointer(&utf16[0]), uintptr(len(data)))
+
+	return d.SetData(ClipboardFormatText, data)
+}
+
+// SetFilePaths sets the paths of the files, which can then be dropped e.g. in
+// Explorer.
+func (d *DragData) SetFilePaths(paths []string) error {
+	data, err := hDropDataFromFilePaths(paths)
+	if err != nil {
+		return err
+	}
+
+	return d.SetData(ClipboardFormatFiles, data)
+}
+
+// DragImage returns the image shown at the mouse cursor while dragging and
+// the point of the image at the cursor.
+func (d *DragData) DragImage() (image *Bitmap, hotspot Point) {
+	return d.dragImage, d.dragImageHotspot
+}
+
+// SetDragImage sets the image shown at the mouse cursor while dragging, e.g.
+// a picture of the item dragged. Hotspot is the point of the image, in native
+// pixels, that stays at the cursor.
+func (d *DragData) SetDragImage(image *Bitmap, hotspot Point) error {
+	if d.format2Data == nil {
+		return newError("read-only data")
+	}
+
+	d.dragImage = image
+	d.dragImageHotspot = hotspot
+
+	return nil
+}
+
+// Data returns the raw data in format.
+func (d *DragData) Data(format ClipboardFormat) (data []byte, err error) {
+	err = d.withHGLOBAL(format, func(hMem win.HGLOBAL) error {
+		data, err = bytesFromHGLOBAL(hMem)
+		return err
+	})
+
+	return
+}
+
+// Text returns the text data.
+func (d *DragData) Text() (string, error) {
+	data, err := d.Data(ClipboardFormatText)
+	if err != nil {
+		return "", err
+	}
+
+	utf16 := make([]uint16, len(data)/2)
+	if len(utf16) > 0 {
+		win.MoveMemory(unsafe.Pointer(&utf16[0]), unsafe.Pointer(&data[0]), uintptr(len(utf16)*2))
+	}
+
+	return syscall.UTF16ToString(utf16), nil
+}
+
+// FilePaths returns the paths of the files, e.g. if files are dragged from
+// Explorer.
+func (d *DragData) FilePaths() (paths []string, err error) {
+	err = d.withHGLOBAL(ClipboardFormatFiles, func(hMem win.HGLOBAL) error {
+		paths = filePathsFromHDROP(win.HDROP(hMem))
+		return nil
+	})
+
+	return
+}
+
+func (d *DragData) withHGLOBAL(format ClipboardFormat, f func(hMem win.HGLOBAL) error) error {
+	if d.dataObject == nil {
+		data, ok := d.format2Data[format]
+		if !ok {
+			return newError("no data in the format requested")
+		}
+
+		hMem, err := hGlobalFromBytes(data)
+		if err != nil {
+			return err
+		}
+		defer win.GlobalFree(hMem)
+
+		return f(hMem)
+	}
+
+	fe := hGlobalFormatEtc(format)
+
+	var medium stgMedium
+	if hr := d.dataObject.GetData(&fe, &medium); win.FAILED(hr) {
+		return errorFromHRESULT("IDataObject.GetData", hr)
+	}
+	defer releaseStgMedium(&medium)
+
+	if medium.tymed != tymedHGlobal {
+		return newError("unsupported storage medium")
+	}
+
+	return f(medium.hGlobal)
+}
+
+// hGlobalFromBytes returns a new moveable HGLOBAL containing a copy of data.
+func hGlobalFromBytes(data []byte) (win.HGLOBAL, error) {
+	hMem := win.GlobalAlloc(win.GMEM_MOVEABLE, uintptr(len(data)))
+	if hMem == 0 {
+		return 0, lastError("GlobalAlloc")
+	}
+
+	if len(data) > 0 {
+		p := win.GlobalLock(hMem)
+		if p == nil {
+			win.GlobalFree(hMem)
+
+			return 0, lastError("GlobalLock()")
+		}
+
+		win.MoveMemory(p, unsafe.Pointer(&data[0]), uintptr(len(data)))
+
+		win.GlobalUnlock(hMem)
+	}
+
+	return hMem, nil
+}
+
+var iidIDataObject = win.IID{Data1: 0x0000010E, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+
+var dataObjectVtbl *iDataObjectVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		dataObjectVtbl = &iDataObjectVtbl{
+			syscall.NewCallback(dataObject_QueryInterface),
+			syscall.NewCallback(dataObject_AddRef),
+			syscall.NewCallback(dataObject_Release),
+			syscall.NewCallback(dataObject_GetData),
+			syscall.NewCallback(dataObject_GetDataHere),
+			syscall.NewCallback(dataObject_QueryGetData),
+			syscall.NewCallback(dataObject_GetCanonicalFormatEtc),
+			syscall.NewCallback(dataObject_SetData),
+			syscall.NewCallback(dataObject_EnumFormatEtc),
+			syscall.NewCallback(dataObject_DAdvise),
+			syscall.NewCallback(dataObject_DUnadvise),
+			syscall.NewCallback(dataObject_EnumDAdvise),
+		}
+	})
+}
+
+// dataObject implements IDataObject for the data of a drag and drop
+// operation started by the application.
+type dataObject struct {
+	lpVtbl *iDataObjectVtbl
+	data   *DragData
+
+	// The data the shell attaches via SetData, like the drag image. It is
+	// only handed back to the shell and not offered to drop targets.
+	privateFormat2Data map[ClipboardFormat][]byte
+}
+
+func newDataObject(data *DragData) *dataObject {
+	return &dataObject{lpVtbl: dataObjectVtbl, data: data}
+}
+
+// dataForFormat returns the data for fe, if there is any.
+func (obj *dataObject) dataForFormat(fe *formatEtc) ([]byte, bool) {
+	if fe.tymed&tymedHGlobal == 0 || fe.dwAspect != dvaspectContent {
+		return nil, false
+	}
+
+	format := ClipboardFormat(fe.cfFormat)
+
+	if data, ok := obj.data.format2Data[format]; ok {
+		return data, true
+	}
+
+	data, ok := obj.privateFormat2Data[format]
+	return data, ok
+}
+
+func dataObject_QueryInterface(obj *dataObject, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDataObject) {
+		*ppvObject = unsafe.Pointer(obj)
+	} else {
+		*ppvObject = nil
+		return win.E_NOINTERFACE
+	}
+
+	return win.S_OK
+}
+
+func dataObject_AddRef(obj *dataObject) uintptr {
+	return 1
+}
+
+func dataObject_Release(obj *dataObject) uintptr {
+	return 1
+}
+
+func dataObject_GetData(obj *dataObject, pformatetcIn *formatEtc, pmedium *stgMedium) uintptr {
+	data, ok := obj.dataForFormat(pformatetcIn)
+	if !ok {
+		return dvEFormatEtc
+	}
+
+	hMem, err := hGlobalFromBytes(data)
+	if err != nil {
+		return win.E_OUTOFMEMORY
+	}
+
+	*pmedium = stgMedium{tymed: tymedHGlobal, hGlobal: hMem}
+
+	return win.S_OK
+}
+
+func dataObject_GetDataHere(obj *dataObject, pformatetc *formatEtc, pmedium *stgMedium) uintptr {
+	return win.E_NOTIMPL
+}
+
+func dataObject_QueryGetData(obj *dataObject, pformatetc *formatEtc) uintptr {
+	if _, ok := obj.dataForFormat(pformatetc); !ok {
+		return dvEFormatEtc
+	}
+
+	return win.S_OK
+}
+
+func dataObject_GetCanonicalFormatEtc(obj *dataObject, pformatectIn, pformatetcOut *formatEtc) uintptr {
+	pformatetcOut.ptd = 0
+
+	return dataSSameFormatEtc
+}
+
+func dataObject_SetData(obj *dataObject, pformatetc *formatEtc, pmedium *stgMedium, fRelease win.BOOL) uintptr {
+	// This is used by the shell to attach the drag image to the data.
+	if pmedium.tymed != tymedHGlobal {
+		return win.E_NOTIMPL
+	}
+
+	data, err := bytesFromHGLOBAL(pmedium.hGlobal)
+	if err != nil {
+		return win.E_FAIL
+	}
+
+	if obj.privateFormat2Data == nil {
+		obj.privateFormat2Data = make(map[ClipboardFormat][]byte)
+	}
+	obj.privateFormat2Data[ClipboardFormat(pformatetc.cfFormat)] = data
+
+	if fRelease != 0 {
+		releaseStgMedium(pmedium)
+	}
+
+	return win.S_OK
+}
+
+func dataObject_EnumFormatEtc(obj *dataObject, dwDirection uint32, ppenumFormatEtc *unsafe.Pointer) uintptr {
+	if dwDirection != datadirGet {
+		return win.E_NOTIMPL
+	}
+
+	fes := make([]formatEtc, len(obj.data.formats))
+	for i, format := range obj.data.formats {
+		fes[i] = hGlobalFormatEtc(format)
+	}
+
+	var pfes *formatEtc
+	if len(fes) > 0 {
+		pfes = &fes[0]
+	}
+
+	return uintptr(shCreateStdEnumFmtEtc(uint32(len(fes)), pfes, ppenumFormatEtc))
+}
+
+func dataObject_DAdvise(obj *dataObject, pformatetc *formatEtc, advf uint32, pAdvSink, pdwConnection uintptr) uintptr {
+	return oleEAdviseNotSupported
+}
+
+func dataObject_DUnadvise(obj *dataObject, dwConnection uint32) uintptr {
+	return oleEAdviseNotSupported
+}
+
+func dataObject_EnumDAdvise(obj *dataObject, ppenumAdvise uintptr) uintptr {
+	return oleEAdviseNotSupported
+}
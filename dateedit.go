@@ -18,7 +18,9 @@ import "github.com/lxn/win"
 type DateEdit struct {
 	WidgetBase
 	dateChangedPublisher EventPublisher
+	noneChangedPublisher EventPublisher
 	format               string
+	none                 bool
 }
 
 func newDateEdit(parent Container, style uint32) (*DateEdit, error) {
@@ -35,6 +37,7 @@ func newDateEdit(parent Container, style uint32) (*DateEdit, error) {
 
 	if style&win.DTS_SHOWNONE != 0 {
 		de.setSystemTime(nil)
+		de.none = true
 	}
 
 	de.GraphicsEffects().Add(InteractionEffect)
@@ -49,6 +52,15 @@ func newDateEdit(parent Container, style uint32) (*DateEdit, error) {
 		},
 		de.dateChangedPublisher.Event()))
 
+	de.MustRegisterProperty("None", NewProperty(
+		func() interface{} {
+			return de.None()
+		},
+		func(v interface{}) error {
+			return de.SetNone(v.(bool))
+		},
+		de.noneChangedPublisher.Event()))
+
 	return de, nil
 }
 
@@ -224,6 +236,7 @@ func (de *DateEdit) SetDate(date time.Time) error {
 	}
 
 	de.dateChangedPublisher.Publish()
+	de.updateNone()
 
 	return nil
 }
@@ -232,12 +245,63 @@ func (de *DateEdit) DateChanged() *Event {
 	return de.dateChangedPublisher.Event()
 }
 
+// Optional returns if the DateEdit was created with the option to represent
+// no date, i.e. using NewDateEditWithNoneOption.
+func (de *DateEdit) Optional() bool {
+	return de.hasStyleBits(win.DTS_SHOWNONE)
+}
+
+// None returns if the DateEdit currently represents no date.
+func (de *DateEdit) None() bool {
+	return de.none
+}
+
+// SetNone sets if the DateEdit represents no date.
+//
+// Setting none to true is only possible for DateEdits that are Optional.
+// Setting it to false for a DateEdit that represents no date selects the
+// current date.
+func (de *DateEdit) SetNone(none bool) error {
+	if none == de.none {
+		return nil
+	}
+
+	if none {
+		if !de.Optional() {
+			return newError("DateEdit is not optional")
+		}
+
+		return de.SetDate(time.Time{})
+	}
+
+	return de.SetDate(time.Now())
+}
+
+// NoneChanged returns the event that is published when the DateEdit switched
+// between representing a date and representing no date.
+func (de *DateEdit) NoneChanged() *Event {
+	return de.noneChangedPublisher.Event()
+}
+
+func (de *DateEdit) updateNone() {
+	st, err := de.systemTime()
+	if err != nil {
+		return
+	}
+
+	if none := st == nil; none != de.none {
+		de.none = none
+		de.noneChangedPublisher.Publish()
+	}
+}
+
 func (de *DateEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
 	case win.WM_NOTIFY:
 		switch uint32(((*win.NMHDR)(unsafe.Pointer(lParam))).Code) {
 		case win.DTN_DATETIMECHANGE:
 			de.dateChangedPublisher.Publish()
+			de.updateNone()
 		}
 	}
 
@@ -25,12 +25,15 @@ type Action struct {
 	AssignTo    **walk.Action
 	Text        string
 	Image       interface{}
+	ImageList   *walk.ImageList
+	ImageIndex  int
 	Checked     Property
 	Enabled     Property
 	Visible     Property
 	Shortcut    Shortcut
 	OnTriggered walk.EventHandler
 	Checkable   bool
+	RadioGroup  int
 }
 
 func (a Action) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, error) {
@@ -46,6 +49,14 @@ func (a Action) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, e
 	if err := setActionImage(action, a.Image); err != nil {
 		return nil, err
 	}
+	if a.ImageList != nil {
+		if err := action.SetImageListImage(a.ImageList, a.ImageIndex); err != nil {
+			return nil, err
+		}
+	}
+	if err := action.SetRadioGroup(a.RadioGroup); err != nil {
+		return nil, err
+	}
 
 	if err := setActionBoolOrCondition(action.SetChecked, action.SetCheckedCondition, a.Checked, "Action.Checked", builder); err != nil {
 		return nil, err
@@ -101,6 +112,7 @@ type Menu struct {
 	Enabled        Property
 	Visible        Property
 	Items          []MenuItem
+	OnBeforePopup  walk.EventHandler
 	OnTriggered    walk.EventHandler
 }
 
@@ -137,6 +149,9 @@ func (m Menu) createAction(builder *Builder, menu *walk.Menu) (*walk.Action, err
 		}
 	}
 
+	if m.OnBeforePopup != nil {
+		subMenu.BeforePopup().Attach(m.OnBeforePopup)
+	}
 	if m.OnTriggered != nil {
 		action.Triggered().Attach(m.OnTriggered)
 	}
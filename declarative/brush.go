@@ -64,12 +64,26 @@ func (bb BitmapBrush) Create() (walk.Brush, error) {
 	return walk.NewBitmapBrush(bmp)
 }
 
+// GradientBrush creates a gradient from either Vertexes and Triangles, or
+// from Stops. With Stops, the gradient is radial if Radial is true, else
+// linear at Angle degrees clockwise from left to right.
 type GradientBrush struct {
 	Vertexes  []walk.GradientVertex
 	Triangles []walk.GradientTriangle
+	Stops     []walk.GradientStop
+	Angle     float64
+	Radial    bool
 }
 
 func (gb GradientBrush) Create() (walk.Brush, error) {
+	if len(gb.Stops) > 0 {
+		if gb.Radial {
+			return walk.NewRadialGradientBrush(gb.Stops)
+		}
+
+		return walk.NewLinearGradientBrush(gb.Angle, gb.Stops)
+	}
+
 	return walk.NewGradientBrush(gb.Vertexes, gb.Triangles)
 }
 
@@ -63,6 +63,8 @@ type CheckBox struct {
 
 	AssignTo            **walk.CheckBox
 	CheckState          Property
+	IndicatorRenderer   walk.ButtonIndicatorRenderer
+	IndicatorSize       Size
 	OnCheckStateChanged walk.EventHandler
 	TextOnLeftSide      bool
 	Tristate            bool
@@ -89,6 +91,10 @@ func (cb CheckBox) Create(builder *Builder) error {
 			return err
 		}
 
+		if cb.IndicatorRenderer != nil {
+			w.SetIndicatorRenderer(cb.IndicatorRenderer, cb.IndicatorSize.toW())
+		}
+
 		if cb.Tristate && cb.CheckState == nil {
 			w.SetCheckState(walk.CheckIndeterminate)
 		}
@@ -59,10 +59,13 @@ type ComboBox struct {
 	// ComboBox
 
 	AssignTo              **walk.ComboBox
+	AutoCompleteMode      walk.ComboBoxAutoCompleteMode
 	BindingMember         string
+	CueBanner             string
 	CurrentIndex          Property
 	DisplayMember         string
 	Editable              bool
+	FilterFunc            func(itemText, text string) bool
 	Format                string
 	MaxLength             int
 	Model                 interface{}
@@ -100,6 +103,14 @@ func (cb ComboBox) Create(builder *Builder) error {
 		w.SetFormat(cb.Format)
 		w.SetPrecision(cb.Precision)
 		w.SetMaxLength(cb.MaxLength)
+		w.SetAutoCompleteMode(cb.AutoCompleteMode)
+		w.SetFilterFunc(cb.FilterFunc)
+
+		if cb.CueBanner != "" {
+			if err := w.SetCueBanner(cb.CueBanner); err != nil {
+				return err
+			}
+		}
 
 		if err := w.SetBindingMember(cb.BindingMember); err != nil {
 			return err
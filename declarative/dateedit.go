@@ -63,8 +63,10 @@ type DateEdit struct {
 	Format        string
 	MaxDate       time.Time
 	MinDate       time.Time
+	None          Property
 	NoneOption    bool // Deprecated: use Optional instead
 	OnDateChanged walk.EventHandler
+	OnNoneChanged walk.EventHandler
 	Optional      bool
 }
 
@@ -98,6 +100,10 @@ func (de DateEdit) Create(builder *Builder) error {
 			w.DateChanged().Attach(de.OnDateChanged)
 		}
 
+		if de.OnNoneChanged != nil {
+			w.NoneChanged().Attach(de.OnNoneChanged)
+		}
+
 		return nil
 	})
 }
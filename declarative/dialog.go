@@ -11,6 +11,26 @@ import (
 	"github.com/lxn/win"
 )
 
+// DialogResult is the result of running a Dialog, usually one of the DlgCmd*
+// constants.
+type DialogResult int
+
+const (
+	DlgCmdNone     = DialogResult(walk.DlgCmdNone)
+	DlgCmdOK       = DialogResult(walk.DlgCmdOK)
+	DlgCmdCancel   = DialogResult(walk.DlgCmdCancel)
+	DlgCmdAbort    = DialogResult(walk.DlgCmdAbort)
+	DlgCmdRetry    = DialogResult(walk.DlgCmdRetry)
+	DlgCmdIgnore   = DialogResult(walk.DlgCmdIgnore)
+	DlgCmdYes      = DialogResult(walk.DlgCmdYes)
+	DlgCmdNo       = DialogResult(walk.DlgCmdNo)
+	DlgCmdClose    = DialogResult(walk.DlgCmdClose)
+	DlgCmdHelp     = DialogResult(walk.DlgCmdHelp)
+	DlgCmdTryAgain = DialogResult(walk.DlgCmdTryAgain)
+	DlgCmdContinue = DialogResult(walk.DlgCmdContinue)
+	DlgCmdTimeout  = DialogResult(walk.DlgCmdTimeout)
+)
+
 type Dialog struct {
 	// Window
 
@@ -51,6 +71,7 @@ type Dialog struct {
 
 	// Form
 
+	DarkMode    bool
 	Expressions func() map[string]walk.Expression
 	Functions   map[string]func(args ...interface{}) (interface{}, error)
 	Icon        Property
@@ -59,10 +80,13 @@ type Dialog struct {
 
 	// Dialog
 
-	AssignTo      **walk.Dialog
-	CancelButton  **walk.PushButton
-	DefaultButton **walk.PushButton
-	FixedSize     bool
+	AssignTo        **walk.Dialog
+	CancelButton    **walk.PushButton
+	DefaultButton   **walk.PushButton
+	FixedSize       bool
+	HelpButton      bool
+	Modeless        bool
+	OnHelpRequested walk.HelpEventHandler
 }
 
 func (d Dialog) Create(owner walk.Form) error {
@@ -74,6 +98,11 @@ func (d Dialog) Create(owner walk.Form) error {
 		style |= win.WS_THICKFRAME
 	}
 	style, exStyle = walk.ApplyStyleOverrides(loadStyleOverrides(d), style, exStyle)
+	if d.HelpButton {
+		// The help button can't be combined with minimize and maximize buttons.
+		style &^= win.WS_MINIMIZEBOX | win.WS_MAXIMIZEBOX
+		exStyle |= win.WS_EX_CONTEXTHELP
+	}
 	w, err = walk.NewDialogWithStyleEx(owner, style, exStyle)
 
 	if err != nil {
@@ -134,6 +163,9 @@ func (d Dialog) Create(owner walk.Form) error {
 	}
 
 	return builder.InitWidget(fi, w, func() error {
+		// Together with Name, this makes the dialog remember its bounds.
+		w.SetPersistent(d.Persistent)
+
 		if d.Size.Width > 0 && d.Size.Height > 0 {
 			if err := w.SetSizePixels(d.Size.toW()); err != nil {
 				return err
@@ -161,6 +193,29 @@ func (d Dialog) Create(owner walk.Form) error {
 			}
 		}
 
+		if d.OnHelpRequested != nil {
+			w.HelpRequested().Attach(d.OnHelpRequested)
+		}
+
+		if d.Modeless {
+			// Nobody is blocked in Run waiting for a result, so the
+			// buttons close the dialog themselves.
+			if d.DefaultButton != nil {
+				(*d.DefaultButton).Clicked().Attach(func() {
+					if !w.IsDisposed() {
+						w.Accept()
+					}
+				})
+			}
+			if d.CancelButton != nil {
+				(*d.CancelButton).Clicked().Attach(func() {
+					if !w.IsDisposed() {
+						w.Cancel()
+					}
+				})
+			}
+		}
+
 		if d.Expressions != nil {
 			for name, expr := range d.Expressions() {
 				builder.expressions[name] = expr
@@ -172,11 +227,25 @@ func (d Dialog) Create(owner walk.Form) error {
 			}
 		}
 
+		if d.DarkMode {
+			// Deferred, so the widgets created by the builder are included.
+			builder.Defer(func() error {
+				return w.SetDarkMode(true)
+			})
+		}
+
 		return nil
 	})
 }
 
+// Run creates the dialog and runs it modally, returning its result.
+//
+// If Modeless is true, Run behaves like Show and returns DlgCmdNone.
 func (d Dialog) Run(owner walk.Form) (int, error) {
+	if d.Modeless {
+		return walk.DlgCmdNone, d.Show(owner)
+	}
+
 	var w *walk.Dialog
 
 	if d.AssignTo == nil {
@@ -189,3 +258,45 @@ func (d Dialog) Run(owner walk.Form) (int, error) {
 
 	return (*d.AssignTo).Run(), nil
 }
+
+// Show creates the dialog and shows it without blocking, so it can float
+// next to its owner. The default and cancel buttons close the dialog.
+//
+// The dialog stays alive until it is closed; use AssignTo or the
+// Closing event of the dialog to learn about its result.
+func (d Dialog) Show(owner walk.Form) error {
+	var w *walk.Dialog
+
+	if d.AssignTo == nil {
+		d.AssignTo = &w
+	}
+
+	d.Modeless = true
+
+	if err := d.Create(owner); err != nil {
+		return err
+	}
+
+	(*d.AssignTo).Show()
+
+	return nil
+}
+
+// RunResult creates the dialog, runs it modally and returns its result along
+// with the *walk.Dialog, so that fields can be inspected after it has been
+// closed without having to use AssignTo.
+func (d Dialog) RunResult(owner walk.Form) (DialogResult, *walk.Dialog, error) {
+	var w *walk.Dialog
+
+	if d.AssignTo == nil {
+		d.AssignTo = &w
+	}
+
+	if err := d.Create(owner); err != nil {
+		return DlgCmdNone, nil, err
+	}
+
+	result := (*d.AssignTo).Run()
+
+	return DialogResult(result), *d.AssignTo, nil
+}
@@ -60,10 +60,15 @@ type GroupBox struct {
 
 	// GroupBox
 
-	AssignTo  **walk.GroupBox
-	Checkable bool
-	Checked   Property
-	Title     string
+	AssignTo            **walk.GroupBox
+	AutoDisableChildren bool
+	Checkable           bool
+	Checked             Property
+	Collapsible         bool
+	Collapsed           Property
+	Icon                Property
+	Title               string
+	TitleAlignment      Alignment1D
 }
 
 func (gb GroupBox) Create(builder *Builder) error {
@@ -88,6 +93,12 @@ func (gb GroupBox) Create(builder *Builder) error {
 		}
 
 		w.SetCheckable(gb.Checkable)
+		w.SetAutoDisableChildren(gb.AutoDisableChildren)
+		w.SetCollapsible(gb.Collapsible)
+
+		if err := w.SetTitleAlignment(walk.Alignment1D(gb.TitleAlignment)); err != nil {
+			return err
+		}
 
 		return nil
 	})
@@ -19,6 +19,24 @@ const (
 	ImageViewModeShrink  = ImageViewMode(walk.ImageViewModeShrink)
 	ImageViewModeZoom    = ImageViewMode(walk.ImageViewModeZoom)
 	ImageViewModeStretch = ImageViewMode(walk.ImageViewModeStretch)
+	ImageViewModeTile    = ImageViewMode(walk.ImageViewModeTile)
+)
+
+type ImageViewFitMode int
+
+const (
+	ImageViewFitDefault = ImageViewFitMode(walk.ImageViewFitDefault)
+	ImageViewFitFill    = ImageViewFitMode(walk.ImageViewFitFill)
+	ImageViewFitContain = ImageViewFitMode(walk.ImageViewFitContain)
+	ImageViewFitCover   = ImageViewFitMode(walk.ImageViewFitCover)
+)
+
+type InterpolationMode int
+
+const (
+	InterpolationModeHighQuality = InterpolationMode(walk.InterpolationModeHighQuality)
+	InterpolationModeNearest     = InterpolationMode(walk.InterpolationModeNearest)
+	InterpolationModeBilinear    = InterpolationMode(walk.InterpolationModeBilinear)
 )
 
 type ImageView struct {
@@ -65,10 +83,18 @@ type ImageView struct {
 
 	// ImageView
 
-	AssignTo **walk.ImageView
-	Image    Property
-	Margin   Property
-	Mode     ImageViewMode
+	AssignTo            **walk.ImageView
+	FitMode             ImageViewFitMode
+	Image               Property
+	Interactive         bool
+	InterpolationMode   InterpolationMode
+	KeepAspectRatio     bool
+	Margin              Property
+	Mode                ImageViewMode
+	OnAnimationFinished walk.EventHandler
+	OnImageMouseDown    walk.ImageMouseEventHandler
+	OnZoomChanged       walk.EventHandler
+	ZoomFactor          Property
 }
 
 func (iv ImageView) Create(builder *Builder) error {
@@ -83,6 +109,22 @@ func (iv ImageView) Create(builder *Builder) error {
 
 	return builder.InitWidget(iv, w, func() error {
 		w.SetMode(walk.ImageViewMode(iv.Mode))
+		w.SetFitMode(walk.ImageViewFitMode(iv.FitMode))
+		w.SetKeepAspectRatio(iv.KeepAspectRatio)
+		w.SetInteractive(iv.Interactive)
+		w.SetInterpolationMode(walk.InterpolationMode(iv.InterpolationMode))
+
+		if iv.OnAnimationFinished != nil {
+			w.AnimationFinished().Attach(iv.OnAnimationFinished)
+		}
+
+		if iv.OnImageMouseDown != nil {
+			w.ImageMouseDown().Attach(iv.OnImageMouseDown)
+		}
+
+		if iv.OnZoomChanged != nil {
+			w.ZoomChanged().Attach(iv.OnZoomChanged)
+		}
 
 		return nil
 	})
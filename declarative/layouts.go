@@ -147,6 +147,8 @@ func (vb VBox) Create() (walk.Layout, error) {
 type Grid struct {
 	Rows        int
 	Columns     int
+	RowSizes    []walk.TrackSize
+	ColumnSizes []walk.TrackSize
 	Margins     Margins
 	Alignment   Alignment2D
 	Spacing     int
@@ -173,6 +175,18 @@ func (g Grid) Create() (walk.Layout, error) {
 		return nil, err
 	}
 
+	if g.RowSizes != nil {
+		if err := l.SetRowSizes(g.RowSizes); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.ColumnSizes != nil {
+		if err := l.SetColumnSizes(g.ColumnSizes); err != nil {
+			return nil, err
+		}
+	}
+
 	return l, nil
 }
 
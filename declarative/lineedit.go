@@ -65,8 +65,12 @@ type LineEdit struct {
 	AssignTo          **walk.LineEdit
 	CaseMode          CaseMode
 	CueBanner         string
+	ErrorMessage      string
+	Mask              string
+	MaskPlaceholder   rune
 	MaxLength         int
 	OnEditingFinished walk.EventHandler
+	OnMaskCompleted   walk.EventHandler
 	OnTextChanged     walk.EventHandler
 	PasswordMode      bool
 	ReadOnly          Property
@@ -99,6 +103,18 @@ func (le LineEdit) Create(builder *Builder) error {
 		}
 		w.SetMaxLength(le.MaxLength)
 		w.SetPasswordMode(le.PasswordMode)
+		w.SetErrorMessage(le.ErrorMessage)
+
+		if le.MaskPlaceholder != 0 {
+			if err := w.SetMaskPlaceholder(le.MaskPlaceholder); err != nil {
+				return err
+			}
+		}
+		if le.Mask != "" {
+			if err := w.SetMask(le.Mask); err != nil {
+				return err
+			}
+		}
 
 		if err := w.SetCaseMode(walk.CaseMode(le.CaseMode)); err != nil {
 			return err
@@ -107,6 +123,9 @@ func (le LineEdit) Create(builder *Builder) error {
 		if le.OnEditingFinished != nil {
 			w.EditingFinished().Attach(le.OnEditingFinished)
 		}
+		if le.OnMaskCompleted != nil {
+			w.MaskCompleted().Attach(le.OnMaskCompleted)
+		}
 		if le.OnTextChanged != nil {
 			w.TextChanged().Attach(le.OnTextChanged)
 		}
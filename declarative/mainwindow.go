@@ -51,21 +51,25 @@ type MainWindow struct {
 
 	// Form
 
-	Icon  Property
-	Size  Size
-	Title Property
+	DarkMode bool
+	Icon     Property
+	Size     Size
+	Title    Property
 
 	// MainWindow
 
-	AssignTo          **walk.MainWindow
-	Expressions       func() map[string]walk.Expression
-	Functions         map[string]func(args ...interface{}) (interface{}, error)
-	MenuItems         []MenuItem
-	OnDropFiles       walk.DropFilesEventHandler
-	StatusBarItems    []StatusBarItem
-	SuspendedUntilRun bool
-	ToolBar           ToolBar
-	ToolBarItems      []MenuItem // Deprecated: use ToolBar instead
+	AssignTo               **walk.MainWindow
+	Expressions            func() map[string]walk.Expression
+	Functions              map[string]func(args ...interface{}) (interface{}, error)
+	MenuItems              []MenuItem
+	OnDropFiles            walk.DropFilesEventHandler
+	OnHiddenToTray         walk.EventHandler
+	OnRestoredFromTray     walk.EventHandler
+	OnStatusBarItemClicked walk.IntEventHandler
+	StatusBarItems         []StatusBarItem
+	SuspendedUntilRun      bool
+	ToolBar                ToolBar
+	ToolBarItems           []MenuItem // Deprecated: use ToolBar instead
 }
 
 func (mw MainWindow) Create() error {
@@ -160,6 +164,21 @@ func (mw MainWindow) Create() error {
 			}
 			w.StatusBar().Items().Add(s)
 			w.StatusBar().SetVisible(true)
+
+			if sbi.Widget != nil {
+				if err := sbi.Widget.Create(builder); err != nil {
+					return err
+				}
+
+				children := builder.Parent().Children()
+				if err := s.SetWidget(children.At(children.Len() - 1)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if mw.OnStatusBarItemClicked != nil {
+			w.StatusBar().ItemClicked().Attach(mw.OnStatusBarItemClicked)
 		}
 
 		if mw.Size.Width > 0 && mw.Size.Height > 0 {
@@ -177,6 +196,12 @@ func (mw MainWindow) Create() error {
 		if mw.OnDropFiles != nil {
 			w.DropFiles().Attach(mw.OnDropFiles)
 		}
+		if mw.OnHiddenToTray != nil {
+			w.HiddenToTray().Attach(mw.OnHiddenToTray)
+		}
+		if mw.OnRestoredFromTray != nil {
+			w.RestoredFromTray().Attach(mw.OnRestoredFromTray)
+		}
 
 		// if mw.AssignTo != nil {
 		// 	*mw.AssignTo = w
@@ -193,6 +218,13 @@ func (mw MainWindow) Create() error {
 			}
 		}
 
+		if mw.DarkMode {
+			// Deferred, so the widgets created by the builder are included.
+			builder.Defer(func() error {
+				return w.SetDarkMode(true)
+			})
+		}
+
 		builder.Defer(func() error {
 			if mw.Visible != false {
 				w.Show()
@@ -225,5 +257,6 @@ type StatusBarItem struct {
 	Text        string
 	ToolTipText string
 	Width       int
+	Widget      Widget
 	OnClicked   walk.EventHandler
 }
@@ -54,17 +54,22 @@ type NumberEdit struct {
 
 	// NumberEdit
 
-	AssignTo       **walk.NumberEdit
-	Decimals       int
-	Increment      float64
-	MaxValue       float64
-	MinValue       float64
-	Prefix         Property
-	OnValueChanged walk.EventHandler
-	ReadOnly       Property
-	Suffix         Property
-	TextColor      walk.Color
-	Value          Property
+	AccelerationDisabled bool
+	Accelerations        []walk.NumberEditAcceleration
+	AssignTo             **walk.NumberEdit
+	CoarseIncrement      float64
+	Decimals             int
+	DisplayFormat        walk.NumberDisplayFormat
+	Increment            float64
+	MaxValue             float64
+	MinValue             float64
+	Prefix               Property
+	OnValueChanged       walk.EventHandler
+	ReadOnly             Property
+	Suffix               Property
+	TextColor            walk.Color
+	ThousandsSeparator   Property
+	Value                Property
 }
 
 func (ne NumberEdit) Create(builder *Builder) error {
@@ -84,6 +89,22 @@ func (ne NumberEdit) Create(builder *Builder) error {
 			return err
 		}
 
+		w.SetAccelerationEnabled(!ne.AccelerationDisabled)
+
+		if ne.Accelerations != nil {
+			if err := w.SetAccelerations(ne.Accelerations); err != nil {
+				return err
+			}
+		}
+
+		if err := w.SetCoarseIncrement(ne.CoarseIncrement); err != nil {
+			return err
+		}
+
+		if err := w.SetDisplayFormat(ne.DisplayFormat); err != nil {
+			return err
+		}
+
 		inc := ne.Increment
 		if inc == 0 {
 			inc = 1
@@ -54,11 +54,15 @@ type ProgressBar struct {
 
 	// ProgressBar
 
-	AssignTo    **walk.ProgressBar
-	MarqueeMode bool
-	MaxValue    int
-	MinValue    int
-	Value       int
+	AssignTo        **walk.ProgressBar
+	BackgroundColor walk.Color
+	BarColor        walk.Color
+	MarqueeMode     bool
+	MarqueeSpeed    int
+	MaxValue        int
+	MinValue        int
+	State           walk.ProgressBarState
+	Value           int
 }
 
 func (pb ProgressBar) Create(builder *Builder) error {
@@ -77,10 +81,28 @@ func (pb ProgressBar) Create(builder *Builder) error {
 		}
 		w.SetValue(pb.Value)
 
-		if err := w.SetMarqueeMode(pb.MarqueeMode); err != nil {
+		if err := w.SetMarquee(pb.MarqueeMode, pb.MarqueeSpeed); err != nil {
 			return err
 		}
 
+		if pb.BarColor != 0 {
+			if err := w.SetBarColor(pb.BarColor); err != nil {
+				return err
+			}
+		}
+
+		if pb.BackgroundColor != 0 {
+			if err := w.SetBackgroundColor(pb.BackgroundColor); err != nil {
+				return err
+			}
+		}
+
+		if pb.State != 0 {
+			if err := w.SetState(pb.State); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
@@ -62,6 +62,7 @@ type PushButton struct {
 
 	AssignTo       **walk.PushButton
 	ImageAboveText bool
+	ImagePosition  walk.ImagePosition
 }
 
 func (pb PushButton) Create(builder *Builder) error {
@@ -79,6 +80,10 @@ func (pb PushButton) Create(builder *Builder) error {
 			return err
 		}
 
+		if err := w.SetImagePosition(pb.ImagePosition); err != nil {
+			return err
+		}
+
 		if pb.OnClicked != nil {
 			w.Clicked().Attach(pb.OnClicked)
 		}
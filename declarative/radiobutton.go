@@ -59,9 +59,11 @@ type RadioButton struct {
 
 	// RadioButton
 
-	AssignTo       **walk.RadioButton
-	TextOnLeftSide bool
-	Value          interface{}
+	AssignTo          **walk.RadioButton
+	IndicatorRenderer walk.ButtonIndicatorRenderer
+	IndicatorSize     Size
+	TextOnLeftSide    bool
+	Value             interface{}
 }
 
 func (rb RadioButton) Create(builder *Builder) error {
@@ -81,6 +83,10 @@ func (rb RadioButton) Create(builder *Builder) error {
 			return err
 		}
 
+		if rb.IndicatorRenderer != nil {
+			w.SetIndicatorRenderer(rb.IndicatorRenderer, rb.IndicatorSize.toW())
+		}
+
 		if rb.OnClicked != nil {
 			w.Clicked().Attach(rb.OnClicked)
 		}
@@ -56,11 +56,13 @@ type Slider struct {
 
 	AssignTo       **walk.Slider
 	LineSize       int
+	Mapper         walk.SliderMapper
 	MaxValue       int
 	MinValue       int
 	Orientation    Orientation
 	OnValueChanged walk.EventHandler
 	PageSize       int
+	TickLabels     map[int]string
 	ToolTipsHidden bool
 	Tracking       bool
 	Value          Property
@@ -93,6 +95,14 @@ func (sl Slider) Create(builder *Builder) error {
 			w.SetRange(sl.MinValue, sl.MaxValue)
 		}
 
+		if sl.Mapper != nil {
+			w.SetMapper(sl.Mapper)
+		}
+
+		if sl.TickLabels != nil {
+			w.SetTickLabels(sl.TickLabels)
+		}
+
 		if sl.OnValueChanged != nil {
 			w.ValueChanged().Attach(sl.OnValueChanged)
 		}
@@ -62,7 +62,9 @@ type SplitButton struct {
 
 	AssignTo       **walk.SplitButton
 	ImageAboveText bool
+	ImagePosition  walk.ImagePosition
 	MenuItems      []MenuItem
+	OnDropDown     walk.EventHandler
 }
 
 func (sb SplitButton) Create(builder *Builder) error {
@@ -82,10 +84,18 @@ func (sb SplitButton) Create(builder *Builder) error {
 			return err
 		}
 
+		if err := w.SetImagePosition(sb.ImagePosition); err != nil {
+			return err
+		}
+
 		if sb.OnClicked != nil {
 			w.Clicked().Attach(sb.OnClicked)
 		}
 
+		if sb.OnDropDown != nil {
+			w.DropDown().Attach(sb.OnDropDown)
+		}
+
 		return nil
 	})
 }
@@ -7,6 +7,8 @@
 package declarative
 
 import (
+	"fmt"
+
 	"github.com/lxn/walk"
 	"github.com/lxn/win"
 )
@@ -57,6 +59,7 @@ type TableView struct {
 
 	AlternatingRowBG            bool
 	AssignTo                    **walk.TableView
+	CellFormatter               walk.CellFormatter
 	CellStyler                  walk.CellStyler
 	CheckBoxes                  bool
 	Columns                     []TableViewColumn
@@ -64,6 +67,8 @@ type TableView struct {
 	ColumnsSizable              Property
 	CustomHeaderHeight          int
 	CustomRowHeight             int
+	FrozenColumnCount           int
+	GroupBy                     string
 	ItemStateChangedEventDelay  int
 	HeaderHidden                bool
 	LastColumnStretched         bool
@@ -73,6 +78,8 @@ type TableView struct {
 	OnCurrentIndexChanged       walk.EventHandler
 	OnItemActivated             walk.EventHandler
 	OnSelectedIndexesChanged    walk.EventHandler
+	SearchColumn                int
+	SearchPredicate             func(row int, prefix string) bool
 	SelectionHiddenWithoutFocus bool
 	StyleCell                   func(style *walk.CellStyle)
 }
@@ -121,12 +128,22 @@ func (tv TableView) Create(builder *Builder) error {
 			}
 		}
 
+		if tv.FrozenColumnCount > 0 {
+			if err := w.SetFrozenColumnCount(tv.FrozenColumnCount); err != nil {
+				return err
+			}
+		}
+
 		if err := w.SetModel(tv.Model); err != nil {
 			return err
 		}
 
 		defaultStyler, _ := tv.Model.(walk.CellStyler)
 
+		if tv.CellFormatter != nil {
+			defaultStyler = walk.CellStylerFromFormatter(tv.CellFormatter)
+		}
+
 		if tv.CellStyler != nil {
 			defaultStyler = tv.CellStyler
 		}
@@ -164,6 +181,25 @@ func (tv TableView) Create(builder *Builder) error {
 			w.SetCellStyler(styler)
 		}
 
+		if tv.GroupBy != "" {
+			col := -1
+			for i := 0; i < w.Columns().Len(); i++ {
+				if c := w.Columns().At(i); c.Name() == tv.GroupBy || c.DataMember() == tv.GroupBy {
+					col = i
+					break
+				}
+			}
+			if col == -1 {
+				return fmt.Errorf("TableView.Create: no column %q to group by", tv.GroupBy)
+			}
+
+			if err := w.GroupBy(col); err != nil {
+				return err
+			}
+		}
+
+		w.SetSearchColumn(tv.SearchColumn)
+		w.SetSearchPredicate(tv.SearchPredicate)
 		w.SetAlternatingRowBG(tv.AlternatingRowBG)
 		w.SetCheckBoxes(tv.CheckBoxes)
 		w.SetItemStateChangedEventDelay(tv.ItemStateChangedEventDelay)
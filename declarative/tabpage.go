@@ -60,6 +60,7 @@ type TabPage struct {
 	// TabPage
 
 	AssignTo **walk.TabPage
+	Closable bool
 	Content  Widget
 	Image    Property
 	Title    Property
@@ -76,6 +77,10 @@ func (tp TabPage) Create(builder *Builder) error {
 	}
 
 	return builder.InitWidget(tp, w, func() error {
+		if err := w.SetClosable(tp.Closable); err != nil {
+			return err
+		}
+
 		if tp.Content != nil && len(tp.Children) == 0 {
 			if err := tp.Content.Create(builder); err != nil {
 				return err
@@ -57,8 +57,14 @@ type TabWidget struct {
 	AssignTo              **walk.TabWidget
 	ContentMargins        Margins
 	ContentMarginsZero    bool
+	NewTabButtonVisible   bool
 	OnCurrentIndexChanged walk.EventHandler
+	OnNewTabRequested     walk.EventHandler
+	OnPageCloseRequested  walk.TabPageCloseEventHandler
+	OnPagesReordered      walk.EventHandler
 	Pages                 []TabPage
+	ReorderEnabled        bool
+	TabPosition           walk.TabPosition
 }
 
 func (tw TabWidget) Create(builder *Builder) error {
@@ -91,10 +97,29 @@ func (tw TabWidget) Create(builder *Builder) error {
 			}
 		}
 
+		w.SetNewTabButtonVisible(tw.NewTabButtonVisible)
+		w.SetReorderEnabled(tw.ReorderEnabled)
+
+		if err := w.SetTabPosition(tw.TabPosition); err != nil {
+			return err
+		}
+
 		if tw.OnCurrentIndexChanged != nil {
 			w.CurrentIndexChanged().Attach(tw.OnCurrentIndexChanged)
 		}
 
+		if tw.OnNewTabRequested != nil {
+			w.NewTabRequested().Attach(tw.OnNewTabRequested)
+		}
+
+		if tw.OnPageCloseRequested != nil {
+			w.PageCloseRequested().Attach(tw.OnPageCloseRequested)
+		}
+
+		if tw.OnPagesReordered != nil {
+			w.PagesReordered().Attach(tw.OnPagesReordered)
+		}
+
 		return nil
 	})
 }
@@ -57,10 +57,12 @@ type TextEdit struct {
 
 	AssignTo      **walk.TextEdit
 	CompactHeight bool
+	Highlighter   func(text string) []walk.TextSpan
 	HScroll       bool
 	MaxLength     int
 	OnTextChanged walk.EventHandler
 	ReadOnly      Property
+	RichEdit      bool
 	Text          Property
 	TextAlignment Alignment1D
 	TextColor     walk.Color
@@ -76,7 +78,13 @@ func (te TextEdit) Create(builder *Builder) error {
 		style |= win.WS_VSCROLL
 	}
 
-	w, err := walk.NewTextEditWithStyle(builder.Parent(), style)
+	var w *walk.TextEdit
+	var err error
+	if te.RichEdit || te.Highlighter != nil {
+		w, err = walk.NewRichTextEditWithStyle(builder.Parent(), style)
+	} else {
+		w, err = walk.NewTextEditWithStyle(builder.Parent(), style)
+	}
 	if err != nil {
 		return err
 	}
@@ -97,6 +105,12 @@ func (te TextEdit) Create(builder *Builder) error {
 			w.SetMaxLength(te.MaxLength)
 		}
 
+		if te.Highlighter != nil {
+			if err := w.SetHighlighter(te.Highlighter); err != nil {
+				return err
+			}
+		}
+
 		if te.OnTextChanged != nil {
 			w.TextChanged().Attach(te.OnTextChanged)
 		}
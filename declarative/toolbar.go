@@ -63,12 +63,13 @@ type ToolBar struct {
 
 	// ToolBar
 
-	Actions     []*walk.Action // Deprecated, use Items instead
-	AssignTo    **walk.ToolBar
-	ButtonStyle ToolBarButtonStyle
-	Items       []MenuItem
-	MaxTextRows int
-	Orientation Orientation
+	Actions      []*walk.Action // Deprecated, use Items instead
+	AssignTo     **walk.ToolBar
+	AutoOverflow bool
+	ButtonStyle  ToolBarButtonStyle
+	Items        []MenuItem
+	MaxTextRows  int
+	Orientation  Orientation
 }
 
 func (tb ToolBar) Create(builder *Builder) error {
@@ -90,6 +91,10 @@ func (tb ToolBar) Create(builder *Builder) error {
 			return err
 		}
 
+		if err := w.SetAutoOverflow(tb.AutoOverflow); err != nil {
+			return err
+		}
+
 		if len(tb.Items) > 0 {
 			builder.deferBuildActions(w.Actions(), tb.Items)
 		} else {
@@ -55,11 +55,18 @@ type TreeView struct {
 	// TreeView
 
 	AssignTo             **walk.TreeView
+	CheckBoxes           bool
 	ItemHeight           int
+	ItemMoveValidator    walk.TreeItemMoveValidator
+	ItemsReorderable     bool
 	Model                walk.TreeModel
+	LoadingText          string
+	OnBeforeExpand       walk.TreeItemEventHandler
 	OnCurrentItemChanged walk.EventHandler
 	OnExpandedChanged    walk.TreeItemEventHandler
 	OnItemActivated      walk.EventHandler
+	OnItemMoved          walk.TreeItemMoveEventHandler
+	OnNodeCheckedChanged walk.TreeItemEventHandler
 }
 
 func (tv TreeView) Create(builder *Builder) error {
@@ -77,6 +84,23 @@ func (tv TreeView) Create(builder *Builder) error {
 			w.SetItemHeight(tv.ItemHeight)
 		}
 
+		if tv.CheckBoxes {
+			if err := w.SetCheckBoxes(true); err != nil {
+				return err
+			}
+		}
+
+		w.SetItemsReorderable(tv.ItemsReorderable)
+		w.SetItemMoveValidator(tv.ItemMoveValidator)
+
+		if tv.LoadingText != "" {
+			w.SetLoadingText(tv.LoadingText)
+		}
+
+		if tv.OnBeforeExpand != nil {
+			w.BeforeExpand().Attach(tv.OnBeforeExpand)
+		}
+
 		if err := w.SetModel(tv.Model); err != nil {
 			return err
 		}
@@ -93,6 +117,14 @@ func (tv TreeView) Create(builder *Builder) error {
 			w.ItemActivated().Attach(tv.OnItemActivated)
 		}
 
+		if tv.OnItemMoved != nil {
+			w.ItemMoved().Attach(tv.OnItemMoved)
+		}
+
+		if tv.OnNodeCheckedChanged != nil {
+			w.NodeCheckedChanged().Attach(tv.OnNodeCheckedChanged)
+		}
+
 		return nil
 	})
 }
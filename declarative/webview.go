@@ -62,7 +62,9 @@ type WebView struct {
 	OnNavigated                       walk.StringEventHandler
 	OnNavigatedError                  walk.WebViewNavigatedErrorEventHandler
 	OnNavigating                      walk.WebViewNavigatingEventHandler
+	OnNavigationCompleted             walk.WebViewNavigationCompletedEventHandler
 	OnNewWindow                       walk.WebViewNewWindowEventHandler
+	OnPostMessageReceived             walk.WebViewPostMessageReceivedEventHandler
 	OnProgressChanged                 walk.EventHandler
 	OnQuitting                        walk.EventHandler
 	OnShortcutsEnabledChanged         walk.EventHandler
@@ -121,9 +123,15 @@ func (wv WebView) Create(builder *Builder) error {
 		if wv.OnNavigating != nil {
 			w.Navigating().Attach(wv.OnNavigating)
 		}
+		if wv.OnNavigationCompleted != nil {
+			w.NavigationCompleted().Attach(wv.OnNavigationCompleted)
+		}
 		if wv.OnNewWindow != nil {
 			w.NewWindow().Attach(wv.OnNewWindow)
 		}
+		if wv.OnPostMessageReceived != nil {
+			w.PostMessageReceived().Attach(wv.OnPostMessageReceived)
+		}
 		if wv.OnProgressChanged != nil {
 			w.ProgressChanged().Attach(wv.OnProgressChanged)
 		}
@@ -0,0 +1,140 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+// ListItemDetailProvider is the interface that a list model can implement to
+// provide a secondary line of text for its items, as displayed by
+// DetailListItemStyler.
+type ListItemDetailProvider interface {
+	// ItemDetail returns the secondary text of the item at index. An empty
+	// string leaves the secondary line blank.
+	ItemDetail(index int) string
+}
+
+// DetailListItemStyler is a ready to use ListItemStyler for a ListBox that
+// draws an optional image, the item text and an optional secondary line of
+// text below it.
+//
+// Images are taken from a model implementing ImageProvider, where only Image
+// values are supported, and the secondary line from a model implementing
+// ListItemDetailProvider. The ListBox must be created with the
+// LBS_OWNERDRAWVARIABLE style, which the declarative ListBox does
+// automatically if an ItemStyler is set.
+type DetailListItemStyler struct {
+	// Height is the height of each item in 1/96". If it is 0, the height
+	// is derived from the font of the ListBox and the number of lines.
+	Height int
+
+	// ImageSize is the size of item images in 1/96". If it is empty, images
+	// are drawn as squares filling the item height.
+	ImageSize Size
+
+	lb *ListBox
+}
+
+func (s *DetailListItemStyler) setListBox(lb *ListBox) {
+	s.lb = lb
+}
+
+func (s *DetailListItemStyler) ItemHeightDependsOnWidth() bool {
+	return false
+}
+
+func (s *DetailListItemStyler) DefaultItemHeight() int {
+	if s.Height > 0 || s.lb == nil {
+		return s.Height
+	}
+
+	lines := 1
+	if _, ok := s.detailProvider(); ok {
+		lines = 2
+	}
+
+	lineHeight := s.lb.IntTo96DPI(s.lb.calculateTextSizeImpl("gM").Height)
+
+	return maxi(lines*lineHeight, s.ImageSize.Height) + detailListItemMargin*2
+}
+
+func (s *DetailListItemStyler) ItemHeight(index, width int) int {
+	return s.DefaultItemHeight()
+}
+
+const detailListItemMargin = 4
+
+func (s *DetailListItemStyler) StyleItem(style *ListItemStyle) {
+	canvas := style.Canvas()
+	if canvas == nil || s.lb == nil {
+		return
+	}
+
+	b := style.Bounds()
+	b.X += detailListItemMargin
+	b.Y += detailListItemMargin
+	b.Width -= detailListItemMargin * 2
+	b.Height -= detailListItemMargin * 2
+
+	index := style.Index()
+
+	if image := s.image(index); image != nil {
+		size := s.ImageSize
+		if size.Width == 0 || size.Height == 0 {
+			size = Size{b.Height, b.Height}
+		}
+
+		canvas.DrawImageStretched(image, Rectangle{b.X, b.Y + (b.Height-size.Height)/2, size.Width, size.Height})
+
+		b.X += size.Width + detailListItemMargin
+		b.Width -= size.Width + detailListItemMargin
+	}
+
+	format := TextLeft | TextSingleLine | TextVCenter | TextEndEllipsis
+
+	var detail string
+	if dp, ok := s.detailProvider(); ok {
+		detail = dp.ItemDetail(index)
+	}
+
+	if detail == "" {
+		style.DrawText(s.lb.itemString(index), b, format)
+		return
+	}
+
+	textBounds := b
+	textBounds.Height /= 2
+	style.DrawText(s.lb.itemString(index), textBounds, format)
+
+	// The secondary line uses a color halfway between text and background.
+	mix := func(a, b byte) byte { return byte((int(a) + int(b)) / 2) }
+	tc, bg := style.TextColor, style.BackgroundColor
+	color := RGB(mix(tc.R(), bg.R()), mix(tc.G(), bg.G()), mix(tc.B(), bg.B()))
+
+	detailBounds := b
+	detailBounds.Y += textBounds.Height
+	detailBounds.Height -= textBounds.Height
+	canvas.DrawText(detail, style.Font, color, detailBounds, format)
+}
+
+func (s *DetailListItemStyler) image(index int) Image {
+	for _, model := range []interface{}{s.lb.providedModel, s.lb.model} {
+		if ip, ok := model.(ImageProvider); ok {
+			image, _ := ip.Image(index).(Image)
+			return image
+		}
+	}
+
+	return nil
+}
+
+func (s *DetailListItemStyler) detailProvider() (ListItemDetailProvider, bool) {
+	for _, model := range []interface{}{s.lb.providedModel, s.lb.model} {
+		if dp, ok := model.(ListItemDetailProvider); ok {
+			return dp, true
+		}
+	}
+
+	return nil, false
+}
@@ -43,10 +43,24 @@ type dialogish interface {
 
 type Dialog struct {
 	FormBase
-	result               int
-	defaultButton        *PushButton
-	cancelButton         *PushButton
-	centerInOwnerWhenRun bool
+	result                 int
+	defaultButton          *PushButton
+	cancelButton           *PushButton
+	centerInOwnerWhenRun   bool
+	helpRequestedPublisher HelpEventPublisher
+}
+
+// HELPINFO_WINDOW, help was requested for a control or window.
+const helpInfoWindow = 1
+
+// helpInfo mirrors the Win32 HELPINFO structure passed along with WM_HELP.
+type helpInfo struct {
+	CbSize       uint32
+	IContextType int32
+	ICtrlId      int32
+	HItemHandle  win.HANDLE
+	DwContextId  uintptr
+	MousePos     win.POINT
 }
 
 func NewDialog(owner Form) (*Dialog, error) {
@@ -141,6 +155,33 @@ func (dlg *Dialog) SetCancelButton(button *PushButton) error {
 	return nil
 }
 
+// HelpButton returns if the dialog shows a help (?) button in its title bar.
+func (dlg *Dialog) HelpButton() bool {
+	return dlg.hasExtendedStyleBits(win.WS_EX_CONTEXTHELP)
+}
+
+// SetHelpButton sets if the dialog shows a help (?) button in its title bar.
+//
+// Windows does not show the help button together with minimize or maximize
+// buttons, so those are removed when the help button is enabled.
+func (dlg *Dialog) SetHelpButton(value bool) error {
+	if value {
+		if err := dlg.ensureStyleBits(win.WS_MINIMIZEBOX|win.WS_MAXIMIZEBOX, false); err != nil {
+			return err
+		}
+	}
+
+	return dlg.ensureExtendedStyleBits(win.WS_EX_CONTEXTHELP, value)
+}
+
+// HelpRequested returns the event that is published when the user asks for
+// help, either by pressing F1 or by clicking a widget after clicking the help
+// button. The widget that help was requested for is passed to the handlers,
+// it may be nil.
+func (dlg *Dialog) HelpRequested() *HelpEvent {
+	return dlg.helpRequestedPublisher.Event()
+}
+
 func (dlg *Dialog) Result() int {
 	return dlg.result
 }
@@ -233,6 +274,9 @@ func fitRectToScreen(hWnd win.HWND, r Rectangle) Rectangle {
 }
 
 func (dlg *Dialog) Run() int {
+	dlg.group.PushActiveForm(dlg)
+	defer dlg.group.PopActiveForm()
+
 	dlg.Show()
 
 	dlg.FormBase.Run()
@@ -256,6 +300,25 @@ func (dlg *Dialog) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) ui
 				}
 			}
 		}
+
+	case win.WM_HELP:
+		hi := (*helpInfo)(unsafe.Pointer(lParam))
+
+		var widget Widget
+		if hi.IContextType == helpInfoWindow {
+			// Walk up from native child windows, e.g. the edit
+			// control of a ComboBox, to the owning widget.
+			for hWnd := win.HWND(hi.HItemHandle); hWnd != 0 && hWnd != dlg.hWnd; hWnd = win.GetParent(hWnd) {
+				if w, ok := windowFromHandle(hWnd).(Widget); ok {
+					widget = w
+					break
+				}
+			}
+		}
+
+		dlg.helpRequestedPublisher.Publish(widget)
+
+		return 1
 	}
 
 	return dlg.FormBase.WndProc(hwnd, msg, wParam, lParam)
@@ -0,0 +1,202 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+var (
+	iidIDropSource       = win.IID{Data1: 0x00000121, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+	iidIDragSourceHelper = win.IID{Data1: 0xDE5BF786, Data2: 0x477A, Data3: 0x11D2, Data4: [8]byte{0x83, 0x9D, 0x00, 0xC0, 0x4F, 0xD9, 0x18, 0xD0}}
+	clsidDragDropHelper  = win.CLSID{Data1: 0x4657278A, Data2: 0x411B, Data3: 0x11D2, Data4: [8]byte{0x83, 0x9A, 0x00, 0xC0, 0x4F, 0xD9, 0x18, 0xD0}}
+)
+
+// iDropSourceVtbl mirrors the vtable of the COM IDropSource interface.
+type iDropSourceVtbl struct {
+	QueryInterface    uintptr
+	AddRef            uintptr
+	Release           uintptr
+	QueryContinueDrag uintptr
+	GiveFeedback      uintptr
+}
+
+// iDragSourceHelperVtbl mirrors the vtable of the COM IDragSourceHelper
+// interface.
+type iDragSourceHelperVtbl struct {
+	QueryInterface       uintptr
+	AddRef               uintptr
+	Release              uintptr
+	InitializeFromBitmap uintptr
+	InitializeFromWindow uintptr
+}
+
+type iDragSourceHelper struct {
+	lpVtbl *iDragSourceHelperVtbl
+}
+
+func (h *iDragSourceHelper) Release() uint32 {
+	ret, _, _ := syscall.Syscall(h.lpVtbl.Release, 1,
+		uintptr(unsafe.Pointer(h)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+func (h *iDragSourceHelper) InitializeFromBitmap(pshdi *shDragImage, pDataObject unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(h.lpVtbl.InitializeFromBitmap, 3,
+		uintptr(unsafe.Pointer(h)),
+		uintptr(unsafe.Pointer(pshdi)),
+		uintptr(pDataObject))
+
+	return win.HRESULT(ret)
+}
+
+var dropSourceVtbl *iDropSourceVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		dropSourceVtbl = &iDropSourceVtbl{
+			syscall.NewCallback(dropSource_QueryInterface),
+			syscall.NewCallback(dropSource_AddRef),
+			syscall.NewCallback(dropSource_Release),
+			syscall.NewCallback(dropSource_QueryContinueDrag),
+			syscall.NewCallback(dropSource_GiveFeedback),
+		}
+	})
+}
+
+// dropSource implements IDropSource for drag and drop operations started by
+// the application.
+type dropSource struct {
+	lpVtbl *iDropSourceVtbl
+}
+
+func dropSource_QueryInterface(ds *dropSource, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDropSource) {
+		*ppvObject = unsafe.Pointer(ds)
+	} else {
+		*ppvObject = nil
+		return win.E_NOINTERFACE
+	}
+
+	return win.S_OK
+}
+
+func dropSource_AddRef(ds *dropSource) uintptr {
+	return 1
+}
+
+func dropSource_Release(ds *dropSource) uintptr {
+	return 1
+}
+
+func dropSource_QueryContinueDrag(ds *dropSource, fEscapePressed win.BOOL, grfKeyState uint32) uintptr {
+	if fEscapePressed != 0 {
+		return dragdropSCancel
+	}
+
+	if grfKeyState&(win.MK_LBUTTON|win.MK_RBUTTON) == 0 {
+		return dragdropSDrop
+	}
+
+	return win.S_OK
+}
+
+func dropSource_GiveFeedback(ds *dropSource, dwEffect uint32) uintptr {
+	return dragdropSUseDefaultCursors
+}
+
+// DoDragDrop starts a drag and drop operation carrying data and returns once
+// the data was dropped or the operation was canceled.
+//
+// It is typically called from a MouseDown or MouseMove handler while the
+// left mouse button is pressed. The data can be dropped onto any window that
+// accepts drops and supports one of its formats, e.g. Explorer for files, or
+// windows of the application, see SetAcceptDrop.
+//
+// The effect performed by the drop target is returned, which is one of
+// allowed or DropEffectNone if the operation was canceled. For
+// DropEffectMove, the caller is expected to remove the data from its source.
+func (wb *WindowBase) DoDragDrop(data *DragData, allowed DropEffect) (DropEffect, error) {
+	if data == nil || data.format2Data == nil {
+		return DropEffectNone, newError("data must be created with NewDragData")
+	}
+
+	// DoDragDrop requires OLE to be initialized.
+	if hr := win.OleInitialize(); hr != win.S_OK && hr != win.S_FALSE {
+		return DropEffectNone, newError(fmt.Sprint("OleInitialize Error: ", hr))
+	}
+
+	obj := newDataObject(data)
+	ds := &dropSource{lpVtbl: dropSourceVtbl}
+
+	if data.dragImage != nil {
+		helper, err := initializeDragImage(obj, data.dragImage, data.dragImageHotspot)
+		if err != nil {
+			return DropEffectNone, err
+		}
+		defer helper.Release()
+	}
+
+	var effect uint32
+
+	hr := doDragDrop(unsafe.Pointer(obj), unsafe.Pointer(ds), uint32(allowed), &effect)
+	switch hr {
+	case dragdropSDrop:
+		return DropEffect(effect), nil
+
+	case dragdropSCancel:
+		return DropEffectNone, nil
+	}
+
+	return DropEffectNone, errorFromHRESULT("DoDragDrop", hr)
+}
+
+// initializeDragImage makes the shell show image at the cursor while obj is
+// dragged. The returned helper must be kept alive during the operation.
+func initializeDragImage(obj *dataObject, image *Bitmap, hotspot Point) (*iDragSourceHelper, error) {
+	var helper *iDragSourceHelper
+	if hr := win.CoCreateInstance(&clsidDragDropHelper, nil, win.CLSCTX_INPROC_SERVER, &iidIDragSourceHelper, (*unsafe.Pointer)(unsafe.Pointer(&helper))); win.FAILED(hr) {
+		return nil, errorFromHRESULT("CoCreateInstance", hr)
+	}
+
+	// The helper takes ownership of the bitmap, so it gets a copy.
+	im, err := image.ToImage()
+	if err != nil {
+		helper.Release()
+		return nil, err
+	}
+
+	hBmp, err := hBitmapFromImage(im)
+	if err != nil {
+		helper.Release()
+		return nil, err
+	}
+
+	size := image.Size()
+
+	shdi := shDragImage{
+		sizeDragImage: win.SIZE{CX: int32(size.Width), CY: int32(size.Height)},
+		ptOffset:      win.POINT{X: int32(hotspot.X), Y: int32(hotspot.Y)},
+		hbmpDragImage: hBmp,
+		crColorKey:    win.CLR_NONE,
+	}
+
+	if hr := helper.InitializeFromBitmap(&shdi, unsafe.Pointer(obj)); win.FAILED(hr) {
+		win.DeleteObject(win.HGDIOBJ(hBmp))
+		helper.Release()
+		return nil, errorFromHRESULT("IDragSourceHelper.InitializeFromBitmap", hr)
+	}
+
+	return helper, nil
+}
@@ -43,6 +43,16 @@ func (e *DropFilesEvent) Detach(handle int) {
 	win.DragAcceptFiles(e.hWnd, false)
 }
 
+func (e *DropFilesEvent) hasHandlers() bool {
+	for _, h := range e.handlers {
+		if h != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
 type DropFilesEventPublisher struct {
 	event DropFilesEvent
 }
@@ -53,21 +63,32 @@ func (p *DropFilesEventPublisher) Event(hWnd win.HWND) *DropFilesEvent {
 }
 
 func (p *DropFilesEventPublisher) Publish(hDrop win.HDROP) {
+	files := filePathsFromHDROP(hDrop)
+	win.DragFinish(hDrop)
+
+	p.publishFiles(files)
+}
+
+func (p *DropFilesEventPublisher) publishFiles(files []string) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(files)
+		}
+	}
+}
+
+// filePathsFromHDROP returns the paths of the files hDrop refers to.
+func filePathsFromHDROP(hDrop win.HDROP) []string {
 	var files []string
 
 	n := win.DragQueryFile(hDrop, 0xFFFFFFFF, nil, 0)
 	for i := 0; i < int(n); i++ {
-		bufSize := uint(512)
+		bufSize := win.DragQueryFile(hDrop, uint(i), nil, 0) + 1
 		buf := make([]uint16, bufSize)
 		if win.DragQueryFile(hDrop, uint(i), &buf[0], bufSize) > 0 {
 			files = append(files, syscall.UTF16ToString(buf))
 		}
 	}
-	win.DragFinish(hDrop)
 
-	for _, handler := range p.event.handlers {
-		if handler != nil {
-			handler(files)
-		}
-	}
+	return files
 }
@@ -0,0 +1,329 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// DropEffect specifies what happens to the data of a drag and drop operation.
+// The values can be combined to specify the effects allowed.
+type DropEffect uint32
+
+const (
+	DropEffectNone DropEffect = 0
+	DropEffectCopy DropEffect = 1
+	DropEffectMove DropEffect = 2
+	DropEffectLink DropEffect = 4
+)
+
+var iidIDropTarget = win.IID{Data1: 0x00000122, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}}
+
+// iDropTargetVtbl mirrors the vtable of the COM IDropTarget interface.
+type iDropTargetVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	DragEnter      uintptr
+	DragOver       uintptr
+	DragLeave      uintptr
+	Drop           uintptr
+}
+
+var dropTargetVtbl *iDropTargetVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		dropTargetVtbl = newDropTargetVtbl()
+	})
+}
+
+// dropTarget implements IDropTarget for a window that accepts drops.
+type dropTarget struct {
+	lpVtbl *iDropTargetVtbl
+	wb     *WindowBase
+	data   *DragData
+	effect DropEffect
+}
+
+func dropTarget_QueryInterface(dt *dropTarget, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIDropTarget) {
+		*ppvObject = unsafe.Pointer(dt)
+	} else {
+		*ppvObject = nil
+		return win.E_NOINTERFACE
+	}
+
+	return win.S_OK
+}
+
+func dropTarget_AddRef(dt *dropTarget) uintptr {
+	return 1
+}
+
+func dropTarget_Release(dt *dropTarget) uintptr {
+	return 1
+}
+
+func dropTarget_DragLeave(dt *dropTarget) uintptr {
+	dt.wb.dragLeavePublisher.Publish()
+
+	dt.releaseData()
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) dragEnter(pDataObj *iDataObject, grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	dt.releaseData()
+
+	pDataObj.AddRef()
+	dt.data = &DragData{dataObject: pDataObj}
+
+	dt.effect = DropEffectNone
+	if dt.wb.dropFilesPublisher.event.hasHandlers() && dt.data.HasFormat(ClipboardFormatFiles) {
+		dt.effect = DropEffectCopy
+	}
+
+	*pdwEffect = uint32(dt.publish(&dt.wb.dragEnterPublisher, grfKeyState, x, y, *pdwEffect))
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) dragOver(grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	if dt.data == nil {
+		*pdwEffect = uint32(DropEffectNone)
+		return win.S_OK
+	}
+
+	*pdwEffect = uint32(dt.publish(&dt.wb.dragOverPublisher, grfKeyState, x, y, *pdwEffect))
+
+	return win.S_OK
+}
+
+func (dt *dropTarget) drop(pDataObj *iDataObject, grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	if dt.data == nil {
+		pDataObj.AddRef()
+		dt.data = &DragData{dataObject: pDataObj}
+	}
+	defer dt.releaseData()
+
+	effect := dt.publish(&dt.wb.dropPublisher, grfKeyState, x, y, *pdwEffect)
+
+	if effect != DropEffectNone && dt.wb.dropFilesPublisher.event.hasHandlers() {
+		if files, err := dt.data.FilePaths(); err == nil && len(files) > 0 {
+			dt.wb.dropFilesPublisher.publishFiles(files)
+		}
+	}
+
+	*pdwEffect = uint32(effect)
+
+	return win.S_OK
+}
+
+// publish publishes a DragEvent and returns the resulting effect, which is
+// also the default effect of the next event of the operation.
+func (dt *dropTarget) publish(publisher *DragEventPublisher, grfKeyState uint32, x, y int32, allowed uint32) DropEffect {
+	pt := win.POINT{X: x, Y: y}
+	win.ScreenToClient(dt.wb.hWnd, &pt)
+
+	var modifiers Modifiers
+	if grfKeyState&win.MK_CONTROL != 0 {
+		modifiers |= ModControl
+	}
+	if grfKeyState&win.MK_SHIFT != 0 {
+		modifiers |= ModShift
+	}
+	if grfKeyState&mkAlt != 0 {
+		modifiers |= ModAlt
+	}
+
+	args := &DragEventArgs{
+		data:           dt.data,
+		point:          Point{int(pt.X), int(pt.Y)},
+		modifiers:      modifiers,
+		allowedEffects: DropEffect(allowed),
+		effect:         dt.effect,
+	}
+
+	publisher.Publish(args)
+
+	dt.effect = args.Effect()
+
+	return dt.effect
+}
+
+func (dt *dropTarget) releaseData() {
+	if dt.data == nil {
+		return
+	}
+
+	dt.data.dataObject.Release()
+	dt.data.dataObject = nil
+	dt.data = nil
+}
+
+// DragEventArgs describes the state of a drag and drop operation over a
+// window that accepts drops. See WindowBase.SetAcceptDrop.
+type DragEventArgs struct {
+	data           *DragData
+	point          Point
+	modifiers      Modifiers
+	allowedEffects DropEffect
+	effect         DropEffect
+}
+
+// Data returns the data being dragged. It is only valid during the event.
+func (a *DragEventArgs) Data() *DragData {
+	return a.data
+}
+
+// Point returns the position of the mouse, in native pixels relative to the
+// client area of the window.
+func (a *DragEventArgs) Point() Point {
+	return a.point
+}
+
+// Modifiers returns the modifier keys being pressed.
+func (a *DragEventArgs) Modifiers() Modifiers {
+	return a.modifiers
+}
+
+// AllowedEffects returns the effects the source of the operation allows.
+func (a *DragEventArgs) AllowedEffects() DropEffect {
+	return a.allowedEffects
+}
+
+// Effect returns the effect of dropping the data at the current position.
+func (a *DragEventArgs) Effect() DropEffect {
+	return a.effect & a.allowedEffects
+}
+
+// SetEffect sets the effect of dropping the data at the current position,
+// which determines the mouse cursor shown. It is reduced to the allowed
+// effects.
+//
+// The effect set in the DragEnter event is the default for the DragOver
+// events, and the last one of those is the default for the Drop event.
+func (a *DragEventArgs) SetEffect(effect DropEffect) {
+	a.effect = effect
+}
+
+type DragEventHandler func(args *DragEventArgs)
+
+type DragEvent struct {
+	handlers []DragEventHandler
+}
+
+func (e *DragEvent) Attach(handler DragEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *DragEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type DragEventPublisher struct {
+	event DragEvent
+}
+
+func (p *DragEventPublisher) Event() *DragEvent {
+	return &p.event
+}
+
+func (p *DragEventPublisher) Publish(args *DragEventArgs) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(args)
+		}
+	}
+}
+
+// AcceptDrop returns whether the *WindowBase accepts data dropped onto it.
+func (wb *WindowBase) AcceptDrop() bool {
+	return wb.dropTarget != nil
+}
+
+// SetAcceptDrop sets whether the *WindowBase accepts data dropped onto it.
+//
+// While data is dragged over the window, the DragEnter, DragOver and
+// DragLeave events are published. Their handlers can inspect the data and set
+// the effect of dropping it, see DragEventArgs. The Drop event is published
+// when the data is dropped.
+//
+// Files dropped are delivered by the DropFiles event, too. If it has handlers,
+// dropping files has the effect DropEffectCopy by default.
+func (wb *WindowBase) SetAcceptDrop(accept bool) error {
+	if accept == wb.AcceptDrop() {
+		return nil
+	}
+
+	if !accept {
+		wb.revokeDropTarget()
+		return nil
+	}
+
+	// RegisterDragDrop requires OLE to be initialized.
+	if hr := win.OleInitialize(); hr != win.S_OK && hr != win.S_FALSE {
+		return newError(fmt.Sprint("OleInitialize Error: ", hr))
+	}
+
+	dt := &dropTarget{lpVtbl: dropTargetVtbl, wb: wb}
+
+	if hr := registerDragDrop(wb.hWnd, unsafe.Pointer(dt)); win.FAILED(hr) {
+		return errorFromHRESULT("RegisterDragDrop", hr)
+	}
+
+	wb.dropTarget = dt
+
+	return nil
+}
+
+func (wb *WindowBase) revokeDropTarget() {
+	if wb.dropTarget == nil {
+		return
+	}
+
+	revokeDragDrop(wb.hWnd)
+
+	wb.dropTarget.releaseData()
+	wb.dropTarget = nil
+}
+
+// DragEnter returns the event that is published when data is dragged into
+// the *WindowBase, if it accepts drops.
+func (wb *WindowBase) DragEnter() *DragEvent {
+	return wb.dragEnterPublisher.Event()
+}
+
+// DragOver returns the event that is published when data is dragged over the
+// *WindowBase, if it accepts drops.
+func (wb *WindowBase) DragOver() *DragEvent {
+	return wb.dragOverPublisher.Event()
+}
+
+// DragLeave returns the event that is published when data is dragged out of
+// the *WindowBase or the operation is canceled.
+func (wb *WindowBase) DragLeave() *Event {
+	return wb.dragLeavePublisher.Event()
+}
+
+// Drop returns the event that is published when data is dropped onto the
+// *WindowBase, if it accepts drops.
+func (wb *WindowBase) Drop() *DragEvent {
+	return wb.dropPublisher.Event()
+}
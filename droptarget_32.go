@@ -0,0 +1,38 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows,386 windows,arm
+
+package walk
+
+import (
+	"syscall"
+)
+
+// On 32 bit platforms, the POINTL passed by value to IDropTarget methods
+// arrives in two arguments.
+
+func newDropTargetVtbl() *iDropTargetVtbl {
+	return &iDropTargetVtbl{
+		syscall.NewCallback(dropTarget_QueryInterface),
+		syscall.NewCallback(dropTarget_AddRef),
+		syscall.NewCallback(dropTarget_Release),
+		syscall.NewCallback(dropTarget_DragEnter),
+		syscall.NewCallback(dropTarget_DragOver),
+		syscall.NewCallback(dropTarget_DragLeave),
+		syscall.NewCallback(dropTarget_Drop),
+	}
+}
+
+func dropTarget_DragEnter(dt *dropTarget, pDataObj *iDataObject, grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	return dt.dragEnter(pDataObj, grfKeyState, x, y, pdwEffect)
+}
+
+func dropTarget_DragOver(dt *dropTarget, grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	return dt.dragOver(grfKeyState, x, y, pdwEffect)
+}
+
+func dropTarget_Drop(dt *dropTarget, pDataObj *iDataObject, grfKeyState uint32, x, y int32, pdwEffect *uint32) uintptr {
+	return dt.drop(pDataObj, grfKeyState, x, y, pdwEffect)
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows,!386,!arm
+
+package walk
+
+import (
+	"syscall"
+)
+
+// On 64 bit platforms, the POINTL passed by value to IDropTarget methods
+// arrives in a single argument.
+
+func newDropTargetVtbl() *iDropTargetVtbl {
+	return &iDropTargetVtbl{
+		syscall.NewCallback(dropTarget_QueryInterface),
+		syscall.NewCallback(dropTarget_AddRef),
+		syscall.NewCallback(dropTarget_Release),
+		syscall.NewCallback(dropTarget_DragEnter),
+		syscall.NewCallback(dropTarget_DragOver),
+		syscall.NewCallback(dropTarget_DragLeave),
+		syscall.NewCallback(dropTarget_Drop),
+	}
+}
+
+func dropTarget_DragEnter(dt *dropTarget, pDataObj *iDataObject, grfKeyState uint32, pt uintptr, pdwEffect *uint32) uintptr {
+	return dt.dragEnter(pDataObj, grfKeyState, int32(pt), int32(pt>>32), pdwEffect)
+}
+
+func dropTarget_DragOver(dt *dropTarget, grfKeyState uint32, pt uintptr, pdwEffect *uint32) uintptr {
+	return dt.dragOver(grfKeyState, int32(pt), int32(pt>>32), pdwEffect)
+}
+
+func dropTarget_Drop(dt *dropTarget, pDataObj *iDataObject, grfKeyState uint32, pt uintptr, pdwEffect *uint32) uintptr {
+	return dt.drop(pDataObj, grfKeyState, int32(pt), int32(pt>>32), pdwEffect)
+}
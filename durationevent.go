@@ -0,0 +1,49 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"time"
+)
+
+type DurationEventHandler func(d time.Duration)
+
+type DurationEvent struct {
+	handlers []DurationEventHandler
+}
+
+func (e *DurationEvent) Attach(handler DurationEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *DurationEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type DurationEventPublisher struct {
+	event DurationEvent
+}
+
+func (p *DurationEventPublisher) Event() *DurationEvent {
+	return &p.event
+}
+
+func (p *DurationEventPublisher) Publish(d time.Duration) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(d)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"github.com/lxn/win"
+)
+
+// editUndoer tracks the undo state of a native edit control for LineEdit and
+// TextEdit.
+//
+// The edit control only keeps a single level of undo, where undoing a second
+// time restores the undone change. We treat that second undo as redo. Rich
+// edit controls have real multi-level undo and redo.
+type editUndoer struct {
+	multiLevel            bool
+	undone                bool
+	busy                  bool
+	stateChangedPublisher EventPublisher
+}
+
+func (u *editUndoer) canUndo(w Window) bool {
+	return (u.multiLevel || !u.undone) && w.SendMessage(win.EM_CANUNDO, 0, 0) != 0
+}
+
+func (u *editUndoer) canRedo(w Window) bool {
+	if u.multiLevel {
+		return w.SendMessage(win.EM_CANREDO, 0, 0) != 0
+	}
+
+	return u.undone && w.SendMessage(win.EM_CANUNDO, 0, 0) != 0
+}
+
+func (u *editUndoer) toggle(w Window, undone bool) error {
+	u.busy = true
+	defer func() {
+		u.busy = false
+	}()
+
+	if u.multiLevel && !undone {
+		if 0 == w.SendMessage(win.EM_REDO, 0, 0) {
+			return newError("EM_REDO failed")
+		}
+	} else if 0 == w.SendMessage(win.EM_UNDO, 0, 0) {
+		return newError("EM_UNDO failed")
+	}
+
+	u.undone = undone
+	u.stateChangedPublisher.Publish()
+
+	return nil
+}
+
+func (u *editUndoer) undo(w Window) error {
+	if !u.canUndo(w) {
+		return nil
+	}
+
+	return u.toggle(w, true)
+}
+
+func (u *editUndoer) redo(w Window) error {
+	if !u.canRedo(w) {
+		return nil
+	}
+
+	return u.toggle(w, false)
+}
+
+func (u *editUndoer) clear(w Window) {
+	w.SendMessage(win.EM_EMPTYUNDOBUFFER, 0, 0)
+
+	u.undone = false
+	u.stateChangedPublisher.Publish()
+}
+
+// textChanged must be called for EN_CHANGE notifications.
+func (u *editUndoer) textChanged() {
+	if u.busy {
+		return
+	}
+
+	u.undone = false
+	u.stateChangedPublisher.Publish()
+}
+
+// handleMessage routes the keyboard shortcuts and WM_UNDO through the undoer,
+// so it stays in sync, and reports if msg was consumed.
+func (u *editUndoer) handleMessage(w Window, msg uint32, wParam uintptr) bool {
+	if u.multiLevel {
+		// The control handles all of this itself.
+		return false
+	}
+
+	switch msg {
+	case win.WM_UNDO:
+		if u.undone {
+			u.redo(w)
+		} else {
+			u.undo(w)
+		}
+		return true
+
+	case win.WM_CHAR:
+		// Ctrl+Z and Ctrl+Y arrive as control characters.
+		switch wParam {
+		case 0x1a:
+			u.undo(w)
+			return true
+
+		case 0x19:
+			u.redo(w)
+			return true
+		}
+	}
+
+	return false
+}
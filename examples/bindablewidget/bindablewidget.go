@@ -0,0 +1,183 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/lxn/walk"
+
+	. "github.com/lxn/walk/declarative"
+)
+
+type Movie struct {
+	Title  string
+	Rating int
+}
+
+func main() {
+	var mw *walk.MainWindow
+	var ratingContainer *walk.Composite
+
+	movie := &Movie{Title: "Metropolis", Rating: 4}
+
+	if err := (MainWindow{
+		AssignTo: &mw,
+		Title:    "Walk Bindable Widget Example",
+		MinSize:  Size{300, 150},
+		Layout:   VBox{},
+		Children: []Widget{
+			Composite{
+				AssignTo: &ratingContainer,
+				Layout:   Grid{Columns: 2},
+				Children: []Widget{
+					Label{
+						Text: "Title:",
+					},
+					LineEdit{
+						Text: Bind("Title"),
+					},
+					Label{
+						Text: "Rating:",
+					},
+				},
+			},
+			Composite{
+				Layout: HBox{},
+				Children: []Widget{
+					HSpacer{},
+					PushButton{
+						Text: "Reset",
+						OnClicked: func() {
+							if err := ratingContainer.DataBinder().Reset(); err != nil {
+								log.Print(err)
+							}
+						},
+					},
+					PushButton{
+						Text: "Submit",
+						OnClicked: func() {
+							if err := ratingContainer.DataBinder().Submit(); err != nil {
+								log.Print(err)
+								return
+							}
+
+							walk.MsgBox(mw, "Movie", fmt.Sprintf("%+v", movie), walk.MsgBoxIconInformation)
+						},
+					},
+				},
+			},
+		},
+	}).Create(); err != nil {
+		log.Fatal(err)
+	}
+
+	rating, err := NewRatingWidget(ratingContainer)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The DataBinder binds the RatingWidget to the field with its name.
+	rating.SetName("Rating")
+
+	// Set the DataBinder after all widgets were created, so it discovers the
+	// RatingWidget, too.
+	db := walk.NewDataBinder()
+	if err := db.SetDataSource(movie); err != nil {
+		log.Fatal(err)
+	}
+	ratingContainer.SetDataBinder(db)
+	if err := db.Reset(); err != nil {
+		log.Fatal(err)
+	}
+
+	mw.Run()
+}
+
+const maxRating = 5
+
+// RatingWidget lets the user pick a rating from 0 to 5 stars. It implements
+// walk.BindableWidget, so a walk.DataBinder binds it by its name.
+type RatingWidget struct {
+	*walk.CustomWidget
+	value                 int
+	valueChangedPublisher walk.EventPublisher
+}
+
+func NewRatingWidget(parent walk.Container) (*RatingWidget, error) {
+	rw := new(RatingWidget)
+
+	cw, err := walk.NewCustomWidget(parent, 0, rw.paint)
+	if err != nil {
+		return nil, err
+	}
+	rw.CustomWidget = cw
+
+	if err := walk.InitWrapperWindow(rw); err != nil {
+		return nil, err
+	}
+
+	rw.SetPaintMode(walk.PaintBuffered)
+	rw.SetMinMaxSize(walk.Size{100, 20}, walk.Size{})
+
+	rw.MouseDown().Attach(func(x, y int, button walk.MouseButton) {
+		if button != walk.LeftButton {
+			return
+		}
+
+		value := x*maxRating/rw.ClientBoundsPixels().Width + 1
+
+		// Clicking the highest star picked clears the rating.
+		if value == rw.value {
+			value = 0
+		}
+
+		rw.setValue(value)
+	})
+
+	return rw, nil
+}
+
+func (rw *RatingWidget) Value() interface{} {
+	return rw.value
+}
+
+func (rw *RatingWidget) SetValue(value interface{}) error {
+	v, ok := value.(int)
+	if !ok {
+		return fmt.Errorf("RatingWidget: int value expected, got %T", value)
+	}
+	if v < 0 || v > maxRating {
+		return fmt.Errorf("RatingWidget: value must be in the range 0 through %d", maxRating)
+	}
+
+	rw.setValue(v)
+
+	return nil
+}
+
+func (rw *RatingWidget) ValueChanged() *walk.Event {
+	return rw.valueChangedPublisher.Event()
+}
+
+func (rw *RatingWidget) setValue(value int) {
+	if value == rw.value {
+		return
+	}
+
+	rw.value = value
+
+	rw.Invalidate()
+
+	rw.valueChangedPublisher.Publish()
+}
+
+func (rw *RatingWidget) paint(canvas *walk.Canvas, updateBounds walk.Rectangle) error {
+	text := strings.Repeat("★", rw.value) + strings.Repeat("☆", maxRating-rw.value)
+
+	return canvas.DrawText(text, rw.Font(), walk.RGB(255, 160, 0), rw.ClientBounds(), walk.TextLeft|walk.TextVCenter|walk.TextSingleLine)
+}
@@ -78,6 +78,21 @@ func (m *FooModel) SetChecked(row int, checked bool) error {
 	return nil
 }
 
+// Called by the TableView when the mouse hovers over a given cell.
+func (m *FooModel) CellToolTip(row, col int) string {
+	item := m.items[row]
+
+	switch col {
+	case 2:
+		return fmt.Sprintf("Baz: %v", item.Baz)
+
+	case 3:
+		return item.Quux.Format(time.RFC1123)
+	}
+
+	return ""
+}
+
 // Called by the TableView to sort the model.
 func (m *FooModel) Sort(col int, order walk.SortOrder) error {
 	m.sortColumn, m.sortOrder = col, order
@@ -0,0 +1,147 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldBinding keeps a Property and a struct field, or any other variable,
+// in sync. It is created by BindField.
+type FieldBinding struct {
+	prop          Property
+	field         reflect.Value
+	changedHandle int
+	disposed      bool
+}
+
+// BindField binds prop to the variable fieldPtr points to, e.g. a struct
+// field, without the need for a DataBinder or getter and setter closures:
+//
+//	b, err := walk.BindField(lineEdit.AsWindowBase().Property("Text"), &cfg.Name)
+//
+// The property is set to the current value of the variable at once. From then
+// on, the variable is updated whenever the property changes. Changes made to
+// the variable by code are not noticed, call Reset to pass them to the
+// property.
+//
+// Values are converted between compatible types, e.g. the float64 value of
+// a NumberEdit and an int field. A nil property value sets the variable to
+// its zero value, and error values, which some widgets report for invalid
+// input, leave it unchanged.
+//
+// An error is returned if fieldPtr is not a non-nil pointer or the property
+// cannot be set to the current value of the variable.
+func BindField(prop Property, fieldPtr interface{}) (*FieldBinding, error) {
+	if prop == nil {
+		return nil, newError("prop must not be nil")
+	}
+
+	ptr := reflect.ValueOf(fieldPtr)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() {
+		return nil, newError("fieldPtr must be a non-nil pointer")
+	}
+
+	b := &FieldBinding{prop: prop, field: ptr.Elem()}
+
+	if err := b.Reset(); err != nil {
+		return nil, err
+	}
+
+	b.changedHandle = prop.Changed().Attach(func() {
+		b.Submit()
+	})
+
+	return b, nil
+}
+
+// Property returns the Property of the FieldBinding.
+func (b *FieldBinding) Property() Property {
+	return b.prop
+}
+
+// Reset sets the property to the current value of the variable.
+func (b *FieldBinding) Reset() error {
+	if b.prop.ReadOnly() {
+		return nil
+	}
+
+	value := b.field.Interface()
+
+	// Pass the value in the type the property currently holds, if possible.
+	if current := b.prop.Get(); current != nil {
+		if v, err := convertFieldValue(value, reflect.TypeOf(current)); err == nil {
+			value = v.Interface()
+		}
+	}
+
+	return b.prop.Set(value)
+}
+
+// Submit sets the variable to the current value of the property. It is called
+// automatically whenever the property changes.
+func (b *FieldBinding) Submit() error {
+	if b.disposed {
+		return nil
+	}
+
+	value := b.prop.Get()
+	if _, ok := value.(error); ok {
+		return nil
+	}
+
+	v, err := convertFieldValue(value, b.field.Type())
+	if err != nil {
+		return err
+	}
+
+	b.field.Set(v)
+
+	return nil
+}
+
+// Dispose stops updating the variable when the property changes.
+func (b *FieldBinding) Dispose() {
+	if b.disposed {
+		return
+	}
+
+	b.prop.Changed().Detach(b.changedHandle)
+
+	b.disposed = true
+}
+
+// convertFieldValue converts value to typ. Numbers are converted among each
+// other, but not to strings.
+func convertFieldValue(value interface{}, typ reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(typ), nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(typ) {
+		return v, nil
+	}
+
+	if isNumberKind(v.Kind()) == isNumberKind(typ.Kind()) && v.Type().ConvertibleTo(typ) {
+		return v.Convert(typ), nil
+	}
+
+	return reflect.Value{}, newError(fmt.Sprintf("can't convert %s to %s", v.Type(), typ))
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
@@ -85,22 +85,85 @@ type flowLayoutSection struct {
 }
 
 type flowLayoutSectionItem struct {
-	item    LayoutItem
-	minSize Size
+	item        LayoutItem
+	minSize     Size
+	primarySize int
 }
 
 func (*flowLayoutItem) LayoutFlags() LayoutFlags {
 	return ShrinkableHorz | ShrinkableVert | GrowableHorz | GrowableVert | GreedyHorz | GreedyVert
 }
 
+// MinSize returns the size needed at the current width. As the items wrap, it
+// is only as wide as the widest item needs to be.
 func (li *flowLayoutItem) MinSize() Size {
-	return li.MinSizeForSize(li.geometry.ClientSize)
+	var width int
+	for _, item := range li.children {
+		if shouldLayoutItem(item) {
+			width = maxi(width, li.MinSizeEffectiveForChild(item).Width)
+		}
+	}
+	width += li.margins.HNear + li.margins.HFar
+
+	return Size{width, li.MinSizeForSize(li.geometry.ClientSize).Height}
+}
+
+// IdealSize returns the size needed to place all items in a single row at
+// their ideal sizes.
+func (li *flowLayoutItem) IdealSize() Size {
+	var s Size
+	var count int
+
+	for _, item := range li.children {
+		if !shouldLayoutItem(item) {
+			continue
+		}
+
+		ideal := li.idealSizeForChild(item)
+
+		s.Width += ideal.Width
+		s.Height = maxi(s.Height, ideal.Height)
+		count++
+	}
+
+	if count > 1 {
+		s.Width += (count - 1) * li.spacing
+	}
+
+	s.Width += li.margins.HNear + li.margins.HFar
+	s.Height += li.margins.VNear + li.margins.VFar
+
+	return s
+}
+
+// HasHeightForWidth returns true, as the items wrap into more rows the
+// narrower the layout gets.
+func (*flowLayoutItem) HasHeightForWidth() bool {
+	return true
 }
 
 func (li *flowLayoutItem) HeightForWidth(width int) int {
 	return li.MinSizeForSize(Size{width, li.geometry.ClientSize.Height}).Height
 }
 
+// idealSizeForChild returns the ideal size of item, but at least its min size.
+func (li *flowLayoutItem) idealSizeForChild(item LayoutItem) Size {
+	size := li.MinSizeEffectiveForChild(item)
+
+	if is, ok := item.(IdealSizer); ok {
+		ideal := is.IdealSize()
+
+		if max := item.Geometry().MaxSize; max.Width > 0 && ideal.Width > max.Width {
+			ideal.Width = max.Width
+		}
+
+		size.Width = maxi(size.Width, ideal.Width)
+		size.Height = maxi(size.Height, ideal.Height)
+	}
+
+	return size
+}
+
 func (li *flowLayoutItem) MinSizeForSize(size Size) Size {
 	if min, ok := li.size2MinSize[size]; ok {
 		return min
@@ -238,20 +301,25 @@ func (li *flowLayoutItem) sectionsForPrimarySize(primarySize int) []flowLayoutSe
 
 		sectionItem.minSize = li.MinSizeEffectiveForChild(item)
 
+		// Wrap by ideal width, so items don't get squeezed to their min size,
+		// unless an item does not even fit into a row of its own that way.
+		sectionItem.primarySize = mini(li.idealSizeForChild(item).Width, primarySize-li.margins.HNear-li.margins.HFar)
+		sectionItem.primarySize = maxi(sectionItem.primarySize, sectionItem.minSize.Width)
+
 		addItem := func() {
 			section.items = append(section.items, sectionItem)
 			if len(section.items) > 1 {
 				section.primarySpaceLeft -= li.spacing
 			}
-			section.primarySpaceLeft -= sectionItem.minSize.Width
+			section.primarySpaceLeft -= sectionItem.primarySize
 
 			section.secondaryMinSize = maxi(section.secondaryMinSize, sectionItem.minSize.Height)
 		}
 
-		if section.primarySpaceLeft < sectionItem.minSize.Width && len(section.items) == 0 {
+		if section.primarySpaceLeft < sectionItem.primarySize && len(section.items) == 0 {
 			addItem()
 			addSection()
-		} else if section.primarySpaceLeft < li.spacing+sectionItem.minSize.Width && len(section.items) > 0 {
+		} else if section.primarySpaceLeft < li.spacing+sectionItem.primarySize && len(section.items) > 0 {
 			addSection()
 			addItem()
 		} else {
@@ -98,6 +98,11 @@ type FormBase struct {
 	isInRestoreState            bool
 	started                     bool
 	layoutScheduled             bool
+	showAnimation               formAnimation
+	hideAnimation               formAnimation
+	animation                   *formAnimationState
+	darkMode                    bool
+	backgroundBeforeDarkMode    Brush
 }
 
 func (fb *FormBase) init(form Form) error {
@@ -562,16 +567,41 @@ func (fb *FormBase) IconChanged() *Event {
 }
 
 func (fb *FormBase) Hide() {
+	if fb.animation != nil && fb.animation.hiding {
+		return
+	}
+
+	if fb.hideAnimation.enabled() && fb.Visible() {
+		fb.startAnimation(fb.hideAnimation, true)
+		return
+	}
+
+	fb.stopAnimation()
+
 	fb.window.SetVisible(false)
 }
 
 func (fb *FormBase) Show() {
+	fb.stopAnimation()
+
 	fb.proposedSize = maxSize(fb.minSize, fb.SizePixels())
 
+	animate := fb.showAnimation.enabled() && !fb.Visible()
+	if animate {
+		// Start transparent, so restoring the state does not flash the Form.
+		fb.startAnimation(fb.showAnimation, false)
+	}
+
 	if p, ok := fb.window.(Persistable); ok && p.Persistent() && App().Settings() != nil {
 		p.RestoreState()
 	}
 
+	if animate && fb.animation != nil {
+		b := fb.BoundsPixels()
+		fb.animation.destination = Point{b.X, b.Y}
+		fb.applyAnimationProgress(0)
+	}
+
 	fb.window.SetVisible(true)
 }
 
@@ -656,6 +686,28 @@ func (fb *FormBase) RestoreState() error {
 
 	wp.Length = uint32(unsafe.Sizeof(wp))
 
+	// The monitor the form was on last time may be gone by now. A maximized
+	// form gets maximized on the monitor its normal position ends up on, so
+	// the stale minimized and maximized positions are not used.
+	fitRECTToWorkArea(&wp.RcNormalPosition)
+	wp.Flags &^= win.WPF_SETMINPOSITION
+
+	switch wp.ShowCmd {
+	case win.SW_SHOWMAXIMIZED:
+		// Keep it maximized.
+
+	case win.SW_SHOWMINIMIZED, win.SW_MINIMIZE, win.SW_SHOWMINNOACTIVE, win.SW_FORCEMINIMIZE:
+		// Do not come back minimized, but in the state before minimizing.
+		if wp.Flags&win.WPF_RESTORETOMAXIMIZED != 0 {
+			wp.ShowCmd = win.SW_SHOWMAXIMIZED
+		} else {
+			wp.ShowCmd = win.SW_SHOWNORMAL
+		}
+
+	default:
+		wp.ShowCmd = win.SW_SHOWNORMAL
+	}
+
 	if layout := fb.Layout(); layout != nil && fb.fixedSize() {
 		layoutItem := CreateLayoutItemsForContainer(fb)
 		minSize := fb.sizeFromClientSizePixels(layoutItem.MinSize()) // TODO: MinSize() returns 96dpi pixels
@@ -671,6 +723,55 @@ func (fb *FormBase) RestoreState() error {
 	return fb.clientComposite.RestoreState()
 }
 
+// fitRECTToWorkArea moves r into the work area of the monitor nearest to it,
+// shrinking it if it does not fit. r is in workspace coordinates, like the
+// normal position of a WINDOWPLACEMENT.
+func fitRECTToWorkArea(r *win.RECT) {
+	var mi win.MONITORINFO
+	mi.CbSize = uint32(unsafe.Sizeof(mi))
+
+	if !win.GetMonitorInfo(monitorFromRect(r, win.MONITOR_DEFAULTTONEAREST), &mi) {
+		return
+	}
+
+	// Workspace coordinates are offset by the taskbar and other app bars on
+	// the top or left of the monitor, relative to screen coordinates.
+	dx := mi.RcWork.Left - mi.RcMonitor.Left
+	dy := mi.RcWork.Top - mi.RcMonitor.Top
+
+	work := win.RECT{
+		Left:   mi.RcWork.Left - dx,
+		Top:    mi.RcWork.Top - dy,
+		Right:  mi.RcWork.Right - dx,
+		Bottom: mi.RcWork.Bottom - dy,
+	}
+
+	width := r.Right - r.Left
+	if max := work.Right - work.Left; width > max {
+		width = max
+	}
+	height := r.Bottom - r.Top
+	if max := work.Bottom - work.Top; height > max {
+		height = max
+	}
+
+	switch {
+	case r.Left < work.Left:
+		r.Left = work.Left
+	case r.Left+width > work.Right:
+		r.Left = work.Right - width
+	}
+	switch {
+	case r.Top < work.Top:
+		r.Top = work.Top
+	case r.Top+height > work.Bottom:
+		r.Top = work.Bottom - height
+	}
+
+	r.Right = r.Left + width
+	r.Bottom = r.Top + height
+}
+
 func (fb *FormBase) Closing() *CloseEvent {
 	return fb.closingPublisher.Event()
 }
@@ -719,13 +820,23 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 		case win.WA_INACTIVE:
 			fb.prevFocusHWnd = win.GetFocus()
 
-			fb.group.SetActiveForm(nil)
+			// Only clear our own entry, e.g. not the one a Dialog pushed to
+			// restore the focus to when it closes.
+			if fb.group.ActiveForm() == fb.window.(Form) {
+				fb.group.SetActiveForm(nil)
+			}
 
 			fb.deactivatingPublisher.Publish()
 		}
 
 		return 0
 
+	case win.WM_TIMER:
+		if wParam == formAnimationTimerId {
+			fb.handleAnimationTimer()
+			return 0
+		}
+
 	case win.WM_CLOSE:
 		fb.closeReason = CloseReasonUnknown
 		var canceled bool
@@ -746,14 +857,10 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 		return fb.clientComposite.WndProc(hwnd, msg, wParam, lParam)
 
 	case win.WM_GETMINMAXINFO:
-		if fb.Suspended() || fb.proposedSize == (Size{}) {
-			break
-		}
-
 		mmi := (*win.MINMAXINFO)(unsafe.Pointer(lParam))
 
 		var min Size
-		if layout := fb.clientComposite.layout; layout != nil {
+		if layout := fb.clientComposite.layout; layout != nil && !fb.Suspended() && fb.proposedSize != (Size{}) {
 			size := fb.clientSizeFromSizePixels(fb.proposedSize)
 			layoutItem := CreateLayoutItemsForContainer(fb)
 			min = fb.sizeFromClientSizePixels(layoutItem.MinSizeForSize(size))
@@ -763,9 +870,19 @@ func (fb *FormBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 			}
 		}
 
+		// MinSize and MaxSize are stored in 96dpi, but the OS wants pixels
+		// at the current DPI of the form.
+		minSize, maxSize := fb.MinSizePixels(), fb.MaxSizePixels()
+
 		mmi.PtMinTrackSize = win.POINT{
-			int32(maxi(min.Width, fb.minSize.Width)),
-			int32(maxi(min.Height, fb.minSize.Height)),
+			int32(maxi(min.Width, minSize.Width)),
+			int32(maxi(min.Height, minSize.Height)),
+		}
+		if maxSize.Width > 0 {
+			mmi.PtMaxTrackSize.X = int32(maxi(maxSize.Width, int(mmi.PtMinTrackSize.X)))
+		}
+		if maxSize.Height > 0 {
+			mmi.PtMaxTrackSize.Y = int32(maxi(maxSize.Height, int(mmi.PtMinTrackSize.Y)))
 		}
 		return 0
 
@@ -0,0 +1,38 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+func TestFormGetMinMaxInfo(t *testing.T) {
+	mw, err := NewMainWindow()
+	if err != nil {
+		t.Skipf("cannot create a window: %v", err)
+	}
+	defer mw.Dispose()
+
+	if err := mw.SetMinMaxSize(Size{300, 200}, Size{800, 600}); err != nil {
+		t.Fatal(err)
+	}
+
+	var mmi win.MINMAXINFO
+	mw.SendMessage(win.WM_GETMINMAXINFO, 0, uintptr(unsafe.Pointer(&mmi)))
+
+	min, max := mw.SizeFrom96DPI(Size{300, 200}), mw.SizeFrom96DPI(Size{800, 600})
+
+	if int(mmi.PtMinTrackSize.X) < min.Width || int(mmi.PtMinTrackSize.Y) < min.Height {
+		t.Errorf("min track size %v, want at least %v", mmi.PtMinTrackSize, min)
+	}
+	if int(mmi.PtMaxTrackSize.X) != max.Width || int(mmi.PtMaxTrackSize.Y) != max.Height {
+		t.Errorf("max track size %v, want %v", mmi.PtMaxTrackSize, max)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"time"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// AnimationKind specifies how a Form is animated when shown or hidden.
+type AnimationKind int
+
+const (
+	AnimationNone AnimationKind = iota
+	AnimationFade
+	AnimationSlideUp
+	AnimationSlideDown
+	AnimationSlideLeft
+	AnimationSlideRight
+)
+
+const (
+	formAnimationTimerId       = 1
+	formAnimationTimerInterval = 15
+	formAnimationSlideDistance = 32
+)
+
+var animationsDisabled bool
+
+// AnimationsEnabled returns if Forms animate when shown or hidden.
+//
+// This is false if animations were disabled via SetAnimationsEnabled or if the
+// user turned off animations in the Windows settings.
+func AnimationsEnabled() bool {
+	if animationsDisabled {
+		return false
+	}
+
+	var enabled win.BOOL = win.TRUE
+	win.SystemParametersInfo(spiGetClientAreaAnimation, 0, unsafe.Pointer(&enabled), 0)
+
+	return enabled != win.FALSE
+}
+
+// SetAnimationsEnabled sets if Forms animate when shown or hidden, e.g. to
+// honor an accessibility setting of the application.
+func SetAnimationsEnabled(enabled bool) {
+	animationsDisabled = !enabled
+}
+
+type formAnimation struct {
+	kind     AnimationKind
+	duration time.Duration
+}
+
+// formAnimationState is the state of a running show or hide animation.
+type formAnimationState struct {
+	formAnimation
+	hiding      bool
+	wasLayered  bool
+	start       time.Time
+	destination Point
+}
+
+// ShowAnimation returns the animation used when the Form is shown.
+func (fb *FormBase) ShowAnimation() (kind AnimationKind, duration time.Duration) {
+	return fb.showAnimation.kind, fb.showAnimation.duration
+}
+
+// SetShowAnimation sets the animation used when the Form is shown.
+//
+// Fading uses the alpha of a layered window. Sliding moves the Form into
+// place, while fading it in. The animation is driven by a timer, so layout and
+// painting of the Form go on while it runs.
+func (fb *FormBase) SetShowAnimation(kind AnimationKind, duration time.Duration) {
+	fb.showAnimation = formAnimation{kind, duration}
+}
+
+// HideAnimation returns the animation used when the Form is hidden.
+func (fb *FormBase) HideAnimation() (kind AnimationKind, duration time.Duration) {
+	return fb.hideAnimation.kind, fb.hideAnimation.duration
+}
+
+// SetHideAnimation sets the animation used when the Form is hidden via Hide.
+//
+// Closing a Form is not animated.
+func (fb *FormBase) SetHideAnimation(kind AnimationKind, duration time.Duration) {
+	fb.hideAnimation = formAnimation{kind, duration}
+}
+
+func (a formAnimation) enabled() bool {
+	return a.kind != AnimationNone && a.duration > 0 && AnimationsEnabled()
+}
+
+// slideOffset returns the offset, in native pixels, of the Form from its
+// destination at the beginning of a show or the end of a hide animation.
+func (fb *FormBase) slideOffset(kind AnimationKind) Point {
+	d := fb.IntFrom96DPI(formAnimationSlideDistance)
+
+	switch kind {
+	case AnimationSlideUp:
+		return Point{0, d}
+
+	case AnimationSlideDown:
+		return Point{0, -d}
+
+	case AnimationSlideLeft:
+		return Point{d, 0}
+
+	case AnimationSlideRight:
+		return Point{-d, 0}
+	}
+
+	return Point{}
+}
+
+// startAnimation starts a show or hide animation. The Form must be made
+// visible by the caller for a show animation.
+func (fb *FormBase) startAnimation(animation formAnimation, hiding bool) {
+	fb.stopAnimation()
+
+	b := fb.BoundsPixels()
+
+	state := &formAnimationState{
+		formAnimation: animation,
+		hiding:        hiding,
+		wasLayered:    fb.hasExtendedStyleBits(win.WS_EX_LAYERED),
+		start:         time.Now(),
+		destination:   Point{b.X, b.Y},
+	}
+
+	if err := fb.ensureExtendedStyleBits(win.WS_EX_LAYERED, true); err != nil {
+		return
+	}
+
+	fb.animation = state
+
+	fb.applyAnimationProgress(0)
+
+	if 0 == win.SetTimer(fb.hWnd, formAnimationTimerId, formAnimationTimerInterval, 0) {
+		lastError("SetTimer")
+		fb.stopAnimation()
+	}
+}
+
+// applyAnimationProgress sets alpha and position of the Form for progress t,
+// which ranges from 0 to 1.
+func (fb *FormBase) applyAnimationProgress(t float64) {
+	state := fb.animation
+
+	visibility := t
+	if state.hiding {
+		visibility = 1 - t
+	}
+
+	setLayeredWindowAttributes(fb.hWnd, 0, byte(visibility*255), lwaAlpha)
+
+	if offset := fb.slideOffset(state.kind); offset != (Point{}) {
+		x := state.destination.X + int(float64(offset.X)*(1-visibility))
+		y := state.destination.Y + int(float64(offset.Y)*(1-visibility))
+
+		win.SetWindowPos(fb.hWnd, 0, int32(x), int32(y), 0, 0, win.SWP_NOACTIVATE|win.SWP_NOSIZE|win.SWP_NOZORDER)
+	}
+}
+
+// stopAnimation stops a running animation, if any, and leaves the Form in its
+// final state.
+func (fb *FormBase) stopAnimation() {
+	state := fb.animation
+	if state == nil {
+		return
+	}
+
+	win.KillTimer(fb.hWnd, formAnimationTimerId)
+
+	fb.applyAnimationProgress(1)
+	fb.animation = nil
+
+	if state.hiding {
+		fb.window.SetVisible(false)
+
+		if fb.slideOffset(state.kind) != (Point{}) {
+			d := state.destination
+			win.SetWindowPos(fb.hWnd, 0, int32(d.X), int32(d.Y), 0, 0, win.SWP_NOACTIVATE|win.SWP_NOSIZE|win.SWP_NOZORDER)
+		}
+	}
+
+	if state.wasLayered {
+		setLayeredWindowAttributes(fb.hWnd, 0, 255, lwaAlpha)
+	} else {
+		fb.ensureExtendedStyleBits(win.WS_EX_LAYERED, false)
+	}
+}
+
+func (fb *FormBase) handleAnimationTimer() {
+	state := fb.animation
+	if state == nil {
+		win.KillTimer(fb.hWnd, formAnimationTimerId)
+		return
+	}
+
+	t := float64(time.Since(state.start)) / float64(state.duration)
+	if t >= 1 {
+		fb.stopAnimation()
+		return
+	}
+
+	fb.applyAnimationProgress(t)
+}
@@ -0,0 +1,445 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lxn/win"
+)
+
+// This file contains the parts of the GDI+ flat API that are used for
+// antialiased drawing on a Canvas.
+
+var (
+	libGdiplus = windows.NewLazySystemDLL("gdiplus.dll")
+
+	procGdipAddPathBezierI    = libGdiplus.NewProc("GdipAddPathBezierI")
+	procGdipAddPathLineI      = libGdiplus.NewProc("GdipAddPathLineI")
+	procGdipClosePathFigure   = libGdiplus.NewProc("GdipClosePathFigure")
+	procGdipCreateFromHDC     = libGdiplus.NewProc("GdipCreateFromHDC")
+	procGdipCreateMatrix2     = libGdiplus.NewProc("GdipCreateMatrix2")
+	procGdipCreatePath        = libGdiplus.NewProc("GdipCreatePath")
+	procGdipCreatePen1        = libGdiplus.NewProc("GdipCreatePen1")
+	procGdipCreateSolidFill   = libGdiplus.NewProc("GdipCreateSolidFill")
+	procGdipDeleteBrush       = libGdiplus.NewProc("GdipDeleteBrush")
+	procGdipDeleteGraphics    = libGdiplus.NewProc("GdipDeleteGraphics")
+	procGdipDeleteMatrix      = libGdiplus.NewProc("GdipDeleteMatrix")
+	procGdipDeletePath        = libGdiplus.NewProc("GdipDeletePath")
+	procGdipDeletePen         = libGdiplus.NewProc("GdipDeletePen")
+	procGdipDrawArc           = libGdiplus.NewProc("GdipDrawArc")
+	procGdipDrawEllipseI      = libGdiplus.NewProc("GdipDrawEllipseI")
+	procGdipDrawLinesI        = libGdiplus.NewProc("GdipDrawLinesI")
+	procGdipDrawPath          = libGdiplus.NewProc("GdipDrawPath")
+	procGdipDrawPie           = libGdiplus.NewProc("GdipDrawPie")
+	procGdipDrawPolygonI      = libGdiplus.NewProc("GdipDrawPolygonI")
+	procGdipFillEllipseI      = libGdiplus.NewProc("GdipFillEllipseI")
+	procGdipFillPath          = libGdiplus.NewProc("GdipFillPath")
+	procGdipFillPie           = libGdiplus.NewProc("GdipFillPie")
+	procGdipFillPolygonI      = libGdiplus.NewProc("GdipFillPolygonI")
+	procGdipSetSmoothingMode  = libGdiplus.NewProc("GdipSetSmoothingMode")
+	procGdipSetWorldTransform = libGdiplus.NewProc("GdipSetWorldTransform")
+	procGdipStartPathFigure   = libGdiplus.NewProc("GdipStartPathFigure")
+)
+
+const (
+	gpSmoothingModeAntiAlias = 4
+	gpUnitPixel              = 2
+	gpFillModeAlternate      = 0
+	gpFillModeWinding        = 1
+)
+
+// gpPoint mirrors the GDI+ Point structure.
+type gpPoint struct {
+	X, Y int32
+}
+
+var gdiplusStartupOnce sync.Once
+var gdiplusStartupStatus win.GpStatus
+
+// ensureGdiplus starts GDI+ once for the lifetime of the process.
+func ensureGdiplus() error {
+	gdiplusStartupOnce.Do(func() {
+		var si win.GdiplusStartupInput
+		si.GdiplusVersion = 1
+		gdiplusStartupStatus = win.GdiplusStartup(&si, nil)
+	})
+
+	if gdiplusStartupStatus != win.Ok {
+		return newError(fmt.Sprintf("GdiplusStartup failed with status '%s'", gdiplusStartupStatus))
+	}
+
+	return nil
+}
+
+func gpError(name string, status uintptr) error {
+	if win.GpStatus(status) == win.Ok {
+		return nil
+	}
+
+	return newError(fmt.Sprintf("%s failed with status '%s'", name, win.GpStatus(status)))
+}
+
+func gpReal(f float64) uintptr {
+	return uintptr(math.Float32bits(float32(f)))
+}
+
+func gpARGB(color Color) uintptr {
+	return uintptr(0xff000000 | uint32(color.R())<<16 | uint32(color.G())<<8 | uint32(color.B()))
+}
+
+// gpPenColor returns the color of pen, if it can be drawn using GDI+.
+// A nil pen means nothing has to be stroked.
+func gpPenColor(pen Pen) (color *Color, ok bool) {
+	if pen == nil || pen == nullPenSingleton || pen.Style()&win.PS_STYLE_MASK == win.PS_NULL {
+		return nil, true
+	}
+
+	switch pen.Style() & win.PS_STYLE_MASK {
+	case win.PS_SOLID, win.PS_INSIDEFRAME:
+
+	default:
+		return nil, false
+	}
+
+	switch p := pen.(type) {
+	case *CosmeticPen:
+		c := p.Color()
+		return &c, true
+
+	case *GeometricPen:
+		return gpBrushColor(p.Brush())
+	}
+
+	return nil, false
+}
+
+// gpBrushColor returns the color of brush, if it can be drawn using GDI+.
+// A nil color means nothing has to be filled.
+func gpBrushColor(brush Brush) (color *Color, ok bool) {
+	switch b := brush.(type) {
+	case nil:
+		return nil, true
+
+	case *SolidColorBrush:
+		c := b.Color()
+		return &c, true
+
+	case *SystemColorBrush:
+		c := b.Color()
+		return &c, true
+	}
+
+	if brush == nullBrushSingleton {
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// withGdiplus runs f with a GDI+ graphics object for the Canvas, as well as
+// a GDI+ pen and brush corresponding to pen and brush, which may be 0 if
+// there is nothing to stroke or fill.
+//
+// It returns false if the Canvas is not antialiased or pen or brush can't be
+// translated to GDI+, in which case the caller should fall back to GDI.
+func (c *Canvas) withGdiplus(pen Pen, brush Brush, f func(graphics, gpPen, gpBrush uintptr) error) (bool, error) {
+	if c.smoothingMode != SmoothingModeAntiAlias {
+		return false, nil
+	}
+
+	penColor, ok := gpPenColor(pen)
+	if !ok {
+		return false, nil
+	}
+	brushColor, ok := gpBrushColor(brush)
+	if !ok {
+		return false, nil
+	}
+
+	if err := ensureGdiplus(); err != nil {
+		return true, err
+	}
+
+	var graphics uintptr
+	if ret, _, _ := syscall.Syscall(procGdipCreateFromHDC.Addr(), 2,
+		uintptr(c.hdc),
+		uintptr(unsafe.Pointer(&graphics)),
+		0); ret != 0 {
+
+		return true, gpError("GdipCreateFromHDC", ret)
+	}
+	defer syscall.Syscall(procGdipDeleteGraphics.Addr(), 1, graphics, 0, 0)
+
+	syscall.Syscall(procGdipSetSmoothingMode.Addr(), 2, graphics, gpSmoothingModeAntiAlias, 0)
+
+	if c.worldTransformed {
+		var xf xform
+		if getWorldTransform(c.hdc, &xf) {
+			var matrix uintptr
+			if ret, _, _ := syscall.Syscall9(procGdipCreateMatrix2.Addr(), 7,
+				gpReal(float64(xf.eM11)),
+				gpReal(float64(xf.eM12)),
+				gpReal(float64(xf.eM21)),
+				gpReal(float64(xf.eM22)),
+				gpReal(float64(xf.eDx)),
+				gpReal(float64(xf.eDy)),
+				uintptr(unsafe.Pointer(&matrix)),
+				0,
+				0); ret == 0 {
+
+				syscall.Syscall(procGdipSetWorldTransform.Addr(), 2, graphics, matrix, 0)
+				syscall.Syscall(procGdipDeleteMatrix.Addr(), 1, matrix, 0, 0)
+			}
+		}
+	}
+
+	var gpPen uintptr
+	if penColor != nil {
+		width := pen.Width()
+		if width < 1 {
+			width = 1
+		}
+
+		if ret, _, _ := syscall.Syscall6(procGdipCreatePen1.Addr(), 4,
+			gpARGB(*penColor),
+			gpReal(float64(width)),
+			gpUnitPixel,
+			uintptr(unsafe.Pointer(&gpPen)),
+			0,
+			0); ret != 0 {
+
+			return true, gpError("GdipCreatePen1", ret)
+		}
+		defer syscall.Syscall(procGdipDeletePen.Addr(), 1, gpPen, 0, 0)
+	}
+
+	var gpBrush uintptr
+	if brushColor != nil {
+		if ret, _, _ := syscall.Syscall(procGdipCreateSolidFill.Addr(), 2,
+			gpARGB(*brushColor),
+			uintptr(unsafe.Pointer(&gpBrush)),
+			0); ret != 0 {
+
+			return true, gpError("GdipCreateSolidFill", ret)
+		}
+		defer syscall.Syscall(procGdipDeleteBrush.Addr(), 1, gpBrush, 0, 0)
+	}
+
+	return true, f(graphics, gpPen, gpBrush)
+}
+
+func gpPoints(points []Point, dpi int) []gpPoint {
+	pts := make([]gpPoint, len(points))
+	for i, p := range points {
+		p = p.From96DPI(dpi)
+		pts[i] = gpPoint{int32(p.X), int32(p.Y)}
+	}
+
+	return pts
+}
+
+func gpFillMode(fillMode FillMode) uintptr {
+	if fillMode == FillModeWinding {
+		return gpFillModeWinding
+	}
+
+	return gpFillModeAlternate
+}
+
+func gpDrawLines(graphics, pen uintptr, pts []gpPoint) error {
+	ret, _, _ := syscall.Syscall6(procGdipDrawLinesI.Addr(), 4,
+		graphics,
+		pen,
+		uintptr(unsafe.Pointer(&pts[0])),
+		uintptr(len(pts)),
+		0,
+		0)
+
+	return gpError("GdipDrawLinesI", ret)
+}
+
+func gpEllipse(graphics, pen, brush uintptr, bounds Rectangle) error {
+	if brush != 0 {
+		if ret, _, _ := syscall.Syscall6(procGdipFillEllipseI.Addr(), 6,
+			graphics,
+			brush,
+			uintptr(bounds.X),
+			uintptr(bounds.Y),
+			uintptr(bounds.Width),
+			uintptr(bounds.Height)); ret != 0 {
+
+			return gpError("GdipFillEllipseI", ret)
+		}
+	}
+
+	if pen != 0 {
+		if ret, _, _ := syscall.Syscall6(procGdipDrawEllipseI.Addr(), 6,
+			graphics,
+			pen,
+			uintptr(bounds.X),
+			uintptr(bounds.Y),
+			uintptr(bounds.Width),
+			uintptr(bounds.Height)); ret != 0 {
+
+			return gpError("GdipDrawEllipseI", ret)
+		}
+	}
+
+	return nil
+}
+
+func gpPolygon(graphics, pen, brush uintptr, pts []gpPoint, fillMode FillMode) error {
+	if brush != 0 {
+		if ret, _, _ := syscall.Syscall6(procGdipFillPolygonI.Addr(), 5,
+			graphics,
+			brush,
+			uintptr(unsafe.Pointer(&pts[0])),
+			uintptr(len(pts)),
+			gpFillMode(fillMode),
+			0); ret != 0 {
+
+			return gpError("GdipFillPolygonI", ret)
+		}
+	}
+
+	if pen != 0 {
+		if ret, _, _ := syscall.Syscall6(procGdipDrawPolygonI.Addr(), 4,
+			graphics,
+			pen,
+			uintptr(unsafe.Pointer(&pts[0])),
+			uintptr(len(pts)),
+			0,
+			0); ret != 0 {
+
+			return gpError("GdipDrawPolygonI", ret)
+		}
+	}
+
+	return nil
+}
+
+func gpArcOrPie(graphics, pen, brush uintptr, bounds Rectangle, startAngle, sweepAngle float64, isPie bool) error {
+	if sweepAngle > 360 {
+		sweepAngle = 360
+	} else if sweepAngle < -360 {
+		sweepAngle = -360
+	}
+
+	if isPie && brush != 0 {
+		if ret, _, _ := syscall.Syscall9(procGdipFillPie.Addr(), 8,
+			graphics,
+			brush,
+			gpReal(float64(bounds.X)),
+			gpReal(float64(bounds.Y)),
+			gpReal(float64(bounds.Width)),
+			gpReal(float64(bounds.Height)),
+			gpReal(startAngle),
+			gpReal(sweepAngle),
+			0); ret != 0 {
+
+			return gpError("GdipFillPie", ret)
+		}
+	}
+
+	if pen != 0 {
+		proc, name := procGdipDrawArc, "GdipDrawArc"
+		if isPie {
+			proc, name = procGdipDrawPie, "GdipDrawPie"
+		}
+
+		if ret, _, _ := syscall.Syscall9(proc.Addr(), 8,
+			graphics,
+			pen,
+			gpReal(float64(bounds.X)),
+			gpReal(float64(bounds.Y)),
+			gpReal(float64(bounds.Width)),
+			gpReal(float64(bounds.Height)),
+			gpReal(startAngle),
+			gpReal(sweepAngle),
+			0); ret != 0 {
+
+			return gpError(name, ret)
+		}
+	}
+
+	return nil
+}
+
+func gpPath(graphics, pen, brush uintptr, path *GraphicsPath, fillMode FillMode, dpi int) error {
+	var gpPath uintptr
+	if ret, _, _ := syscall.Syscall(procGdipCreatePath.Addr(), 2,
+		gpFillMode(fillMode),
+		uintptr(unsafe.Pointer(&gpPath)),
+		0); ret != 0 {
+
+		return gpError("GdipCreatePath", ret)
+	}
+	defer syscall.Syscall(procGdipDeletePath.Addr(), 1, gpPath, 0, 0)
+
+	var current gpPoint
+	for _, seg := range path.segments {
+		pts := gpPoints(seg.points, dpi)
+
+		var ret uintptr
+		switch seg.op {
+		case graphicsPathMoveTo:
+			ret, _, _ = syscall.Syscall(procGdipStartPathFigure.Addr(), 1, gpPath, 0, 0)
+
+		case graphicsPathLineTo:
+			ret, _, _ = syscall.Syscall6(procGdipAddPathLineI.Addr(), 5,
+				gpPath,
+				uintptr(current.X),
+				uintptr(current.Y),
+				uintptr(pts[0].X),
+				uintptr(pts[0].Y),
+				0)
+
+		case graphicsPathBezierTo:
+			ret, _, _ = syscall.Syscall9(procGdipAddPathBezierI.Addr(), 9,
+				gpPath,
+				uintptr(current.X),
+				uintptr(current.Y),
+				uintptr(pts[0].X),
+				uintptr(pts[0].Y),
+				uintptr(pts[1].X),
+				uintptr(pts[1].Y),
+				uintptr(pts[2].X),
+				uintptr(pts[2].Y))
+
+		case graphicsPathClose:
+			ret, _, _ = syscall.Syscall(procGdipClosePathFigure.Addr(), 1, gpPath, 0, 0)
+		}
+		if ret != 0 {
+			return gpError("building path", ret)
+		}
+
+		if len(pts) > 0 {
+			current = pts[len(pts)-1]
+		}
+	}
+
+	if brush != 0 {
+		if ret, _, _ := syscall.Syscall(procGdipFillPath.Addr(), 3, graphics, brush, gpPath); ret != 0 {
+			return gpError("GdipFillPath", ret)
+		}
+	}
+
+	if pen != 0 {
+		if ret, _, _ := syscall.Syscall(procGdipDrawPath.Addr(), 3, graphics, pen, gpPath); ret != 0 {
+			return gpError("GdipDrawPath", ret)
+		}
+	}
+
+	return nil
+}
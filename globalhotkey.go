@@ -0,0 +1,155 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/lxn/win"
+)
+
+const globalHotKeyWindowClass = `\o/ Walk_GlobalHotKey_Class \o/`
+
+var (
+	globalHotKeyHWnd  win.HWND
+	globalHotKeysById = make(map[int]*GlobalHotKey)
+)
+
+func init() {
+	AppendToWalkInit(func() {
+		MustRegisterWindowClassWithWndProcPtr(globalHotKeyWindowClass, syscall.NewCallback(globalHotKeyWndProc))
+	})
+}
+
+// ensureGlobalHotKeyWindow creates the message-only window that receives
+// WM_HOTKEY, on the calling thread, if that has not happened yet.
+func ensureGlobalHotKeyWindow() error {
+	if globalHotKeyHWnd != 0 {
+		return nil
+	}
+
+	hwnd := win.CreateWindowEx(
+		0,
+		syscall.StringToUTF16Ptr(globalHotKeyWindowClass),
+		nil,
+		0,
+		0,
+		0,
+		0,
+		0,
+		win.HWND_MESSAGE,
+		0,
+		0,
+		nil)
+
+	if hwnd == 0 {
+		return lastError("CreateWindowEx")
+	}
+
+	globalHotKeyHWnd = hwnd
+
+	return nil
+}
+
+func globalHotKeyWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
+	switch msg {
+	case win.WM_HOTKEY:
+		if hk, ok := globalHotKeysById[int(wp)]; ok {
+			hk.triggeredPublisher.Publish()
+		}
+		return 0
+	}
+
+	return win.DefWindowProc(hwnd, msg, wp, lp)
+}
+
+// GlobalHotKey is a keyboard shortcut that is registered with the system, so
+// it triggers even if no window of the application has the focus.
+type GlobalHotKey struct {
+	id                 int
+	modifiers          Modifiers
+	key                Key
+	triggeredPublisher EventPublisher
+}
+
+// RegisterGlobalHotKey registers the shortcut of mods and key system wide.
+//
+// The id identifies the hotkey within the application and must be in the
+// range 0 through 0xBFFF. An error is returned if the id is already in use or
+// if the shortcut is registered already, e.g. by another application.
+//
+// RegisterGlobalHotKey must be called from the thread that runs the message
+// loop, where the Triggered event of the hotkey is published. Call Dispose to
+// unregister the hotkey again.
+func RegisterGlobalHotKey(id int, mods Modifiers, key Key) (*GlobalHotKey, error) {
+	if id < 0 || id > 0xBFFF {
+		return nil, newError("id must be in the range 0 through 0xBFFF")
+	}
+
+	if _, ok := globalHotKeysById[id]; ok {
+		return nil, newError(fmt.Sprintf("a global hotkey with id %d is registered already", id))
+	}
+
+	var fsModifiers uint32 = modNoRepeat
+	if mods&ModAlt != 0 {
+		fsModifiers |= modAlt
+	}
+	if mods&ModControl != 0 {
+		fsModifiers |= modControl
+	}
+	if mods&ModShift != 0 {
+		fsModifiers |= modShift
+	}
+
+	if err := ensureGlobalHotKeyWindow(); err != nil {
+		return nil, err
+	}
+
+	if ok, errno := registerHotKey(globalHotKeyHWnd, int32(id), fsModifiers, uint32(key)); !ok {
+		if errno == errorHotKeyAlreadyRegistered {
+			return nil, newError(fmt.Sprintf("the hotkey %s is registered already", Shortcut{mods, key}))
+		}
+
+		return nil, newError(fmt.Sprintf("RegisterHotKey: Error %d", errno))
+	}
+
+	hk := &GlobalHotKey{id: id, modifiers: mods, key: key}
+
+	globalHotKeysById[id] = hk
+
+	return hk, nil
+}
+
+// Dispose unregisters the GlobalHotKey.
+func (hk *GlobalHotKey) Dispose() {
+	if globalHotKeysById[hk.id] != hk {
+		return
+	}
+
+	delete(globalHotKeysById, hk.id)
+
+	if !unregisterHotKey(globalHotKeyHWnd, int32(hk.id)) {
+		lastError("UnregisterHotKey")
+	}
+}
+
+// ID returns the id the GlobalHotKey was registered with.
+func (hk *GlobalHotKey) ID() int {
+	return hk.id
+}
+
+// Shortcut returns the keyboard shortcut of the GlobalHotKey.
+func (hk *GlobalHotKey) Shortcut() Shortcut {
+	return Shortcut{hk.modifiers, hk.key}
+}
+
+// Triggered returns the event that is published when the user presses the
+// shortcut of the GlobalHotKey.
+func (hk *GlobalHotKey) Triggered() *Event {
+	return hk.triggeredPublisher.Event()
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"github.com/lxn/win"
+)
+
+// FillMode specifies how the inside of self-intersecting polygons and paths
+// is determined.
+type FillMode int
+
+const (
+	// FillModeAlternate fills areas between odd and even numbered edges.
+	FillModeAlternate FillMode = 1
+
+	// FillModeWinding fills all areas with a nonzero winding value.
+	FillModeWinding FillMode = 2
+)
+
+type graphicsPathOp int
+
+const (
+	graphicsPathMoveTo graphicsPathOp = iota
+	graphicsPathLineTo
+	graphicsPathBezierTo
+	graphicsPathClose
+)
+
+type graphicsPathSegment struct {
+	op     graphicsPathOp
+	points []Point
+}
+
+// GraphicsPath is a sequence of figures made of lines and Bezier curves,
+// which can be stroked or filled using Canvas.DrawPath and Canvas.FillPath.
+//
+// Coordinates are in 1/96" units like all other Canvas coordinates.
+type GraphicsPath struct {
+	segments []graphicsPathSegment
+}
+
+// NewGraphicsPath returns a new empty GraphicsPath.
+func NewGraphicsPath() *GraphicsPath {
+	return new(GraphicsPath)
+}
+
+// MoveTo starts a new figure at p.
+func (gp *GraphicsPath) MoveTo(p Point) *GraphicsPath {
+	gp.segments = append(gp.segments, graphicsPathSegment{graphicsPathMoveTo, []Point{p}})
+	return gp
+}
+
+// LineTo adds a line from the current point to p.
+func (gp *GraphicsPath) LineTo(p Point) *GraphicsPath {
+	gp.segments = append(gp.segments, graphicsPathSegment{graphicsPathLineTo, []Point{p}})
+	return gp
+}
+
+// BezierTo adds a cubic Bezier curve from the current point to end, using
+// control points c1 and c2.
+func (gp *GraphicsPath) BezierTo(c1, c2, end Point) *GraphicsPath {
+	gp.segments = append(gp.segments, graphicsPathSegment{graphicsPathBezierTo, []Point{c1, c2, end}})
+	return gp
+}
+
+// Close closes the current figure with a line back to its start.
+func (gp *GraphicsPath) Close() *GraphicsPath {
+	gp.segments = append(gp.segments, graphicsPathSegment{op: graphicsPathClose})
+	return gp
+}
+
+// build records the path into the current GDI path of hdc.
+func (gp *GraphicsPath) build(hdc win.HDC, dpi int) error {
+	if !beginPath(hdc) {
+		return newError("BeginPath failed")
+	}
+
+	for _, seg := range gp.segments {
+		pts := make([]win.POINT, len(seg.points))
+		for i, p := range seg.points {
+			p = p.From96DPI(dpi)
+			pts[i] = win.POINT{X: int32(p.X), Y: int32(p.Y)}
+		}
+
+		var ok bool
+		switch seg.op {
+		case graphicsPathMoveTo:
+			ok = win.MoveToEx(hdc, int(pts[0].X), int(pts[0].Y), nil)
+
+		case graphicsPathLineTo:
+			ok = win.LineTo(hdc, pts[0].X, pts[0].Y)
+
+		case graphicsPathBezierTo:
+			ok = polyBezierTo(hdc, &pts[0], uint32(len(pts)))
+
+		case graphicsPathClose:
+			ok = closeFigure(hdc)
+		}
+
+		if !ok {
+			endPath(hdc)
+			return newError("building path failed")
+		}
+	}
+
+	if !endPath(hdc) {
+		return newError("EndPath failed")
+	}
+
+	return nil
+}
@@ -33,6 +33,8 @@ type GridLayout struct {
 	LayoutBase
 	rowStretchFactors    []int
 	columnStretchFactors []int
+	rowSizes             []TrackSize
+	columnSizes          []TrackSize
 	widgetBase2Info      map[*WidgetBase]*gridLayoutWidgetInfo
 	cells                [][]gridLayoutCell
 }
@@ -307,6 +309,8 @@ func (l *GridLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
 		size2MinSize:         make(map[Size]Size),
 		rowStretchFactors:    append([]int(nil), l.rowStretchFactors...),
 		columnStretchFactors: append([]int(nil), l.columnStretchFactors...),
+		rowSizes:             trackSizesForDPI(l.rowSizes, ctx.dpi),
+		columnSizes:          trackSizesForDPI(l.columnSizes, ctx.dpi),
 		item2Info:            item2Info,
 		cells:                cells,
 	}
@@ -318,6 +322,8 @@ type gridLayoutItem struct {
 	size2MinSize         map[Size]Size
 	rowStretchFactors    []int
 	columnStretchFactors []int
+	rowSizes             []TrackSize
+	columnSizes          []TrackSize
 	item2Info            map[LayoutItem]*gridLayoutItemInfo
 	cells                [][]gridLayoutItemCell
 	minSize              Size
@@ -469,6 +475,15 @@ func (li *gridLayoutItem) MinSizeForSize(size Size) Size {
 		heights[row] = maxHeight
 	}
 
+	// With explicit track sizes, resolving them for no space yields the
+	// minimum sizes.
+	if li.columnSizes != nil {
+		ws = li.sectionSizesForSpace(Horizontal, 0, nil)
+	}
+	if li.rowSizes != nil {
+		heights = li.sectionSizesForSpace(Vertical, 0, widths)
+	}
+
 	width := li.margins.HNear + li.margins.HFar
 	height := li.margins.VNear + li.margins.VFar
 
@@ -667,6 +682,7 @@ func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space in
 	var minSizesRemaining int
 	minSizes := make([]int, len(stretchFactors))
 	maxSizes := make([]int, len(stretchFactors))
+	autoSizes := make([]int, len(stretchFactors))
 	sizes := make([]int, len(stretchFactors))
 	sortedSections := gridLayoutSectionInfoList(make([]gridLayoutSectionInfo, len(stretchFactors)))
 
@@ -709,6 +725,7 @@ func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space in
 			if orientation == Horizontal {
 				if info.spanHorz == 1 {
 					minSizes[i] = maxi(minSizes[i], li.MinSizeEffectiveForChild(item).Width)
+					autoSizes[i] = maxi(autoSizes[i], pref.Width)
 				}
 
 				if max.Width > 0 {
@@ -733,6 +750,8 @@ func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space in
 					} else {
 						minSizes[i] = maxi(minSizes[i], li.MinSizeEffectiveForChild(item).Height)
 					}
+
+					autoSizes[i] = maxi(autoSizes[i], pref.Height)
 				}
 
 				if max.Height > 0 {
@@ -781,6 +800,10 @@ func (li *gridLayoutItem) sectionSizesForSpace(orientation Orientation, space in
 		space -= li.margins.VNear + li.margins.VFar
 	}
 
+	if tracks := li.trackSizes(orientation); tracks != nil {
+		return li.resolveTrackSizes(tracks, minSizes, autoSizes, space)
+	}
+
 	var spacingRemaining int
 	for _, max := range maxSizes {
 		if max > 0 {
@@ -0,0 +1,237 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+// TrackSizeKind specifies how the size of a GridLayout row or column is
+// determined.
+type TrackSizeKind int
+
+const (
+	// TrackAuto sizes a row or column to the ideal size of its content.
+	TrackAuto TrackSizeKind = iota
+
+	// TrackFixed sizes a row or column to a fixed size in 1/96" units,
+	// regardless of its content.
+	TrackFixed
+
+	// TrackStar makes a row or column share the space left by the TrackAuto
+	// and TrackFixed ones, in proportion to its weight.
+	TrackStar
+)
+
+// TrackSize is the explicit size of a GridLayout row or column.
+type TrackSize struct {
+	Kind TrackSizeKind
+
+	// Value is the size in 1/96" units for TrackFixed and the weight for
+	// TrackStar. It is ignored for TrackAuto.
+	Value int
+}
+
+// AutoTrack returns a TrackSize that sizes a row or column to its content.
+func AutoTrack() TrackSize {
+	return TrackSize{Kind: TrackAuto}
+}
+
+// FixedTrack returns a TrackSize that sizes a row or column to size, in 1/96"
+// units.
+func FixedTrack(size int) TrackSize {
+	return TrackSize{Kind: TrackFixed, Value: size}
+}
+
+// StarTrack returns a TrackSize that makes a row or column share the remaining
+// space in proportion to weight.
+func StarTrack(weight int) TrackSize {
+	return TrackSize{Kind: TrackStar, Value: weight}
+}
+
+// RowSizes returns the explicit sizes of the rows, or nil if the rows are
+// sized by their stretch factors.
+func (l *GridLayout) RowSizes() []TrackSize {
+	return append([]TrackSize(nil), l.rowSizes...)
+}
+
+// SetRowSizes sets explicit sizes for the rows, overriding their stretch
+// factors. Rows without an entry in sizes are sized like TrackAuto ones.
+//
+// Pass nil to size the rows by their stretch factors again.
+func (l *GridLayout) SetRowSizes(sizes []TrackSize) error {
+	if err := validateTrackSizes(sizes); err != nil {
+		return err
+	}
+
+	l.ensureSufficientSize(maxi(len(l.rowStretchFactors), len(sizes)), len(l.columnStretchFactors))
+
+	l.rowSizes = append([]TrackSize(nil), sizes...)
+
+	if l.container != nil {
+		l.container.RequestLayout()
+	}
+
+	return nil
+}
+
+// ColumnSizes returns the explicit sizes of the columns, or nil if the columns
+// are sized by their stretch factors.
+func (l *GridLayout) ColumnSizes() []TrackSize {
+	return append([]TrackSize(nil), l.columnSizes...)
+}
+
+// SetColumnSizes sets explicit sizes for the columns, overriding their stretch
+// factors. Columns without an entry in sizes are sized like TrackAuto ones.
+//
+// Pass nil to size the columns by their stretch factors again.
+func (l *GridLayout) SetColumnSizes(sizes []TrackSize) error {
+	if err := validateTrackSizes(sizes); err != nil {
+		return err
+	}
+
+	l.ensureSufficientSize(len(l.rowStretchFactors), maxi(len(l.columnStretchFactors), len(sizes)))
+
+	l.columnSizes = append([]TrackSize(nil), sizes...)
+
+	if l.container != nil {
+		l.container.RequestLayout()
+	}
+
+	return nil
+}
+
+func validateTrackSizes(sizes []TrackSize) error {
+	for _, size := range sizes {
+		switch size.Kind {
+		case TrackAuto:
+
+		case TrackFixed:
+			if size.Value < 0 {
+				return newError("fixed track size must be >= 0")
+			}
+
+		case TrackStar:
+			if size.Value < 1 {
+				return newError("star track weight must be >= 1")
+			}
+
+		default:
+			return newError("invalid track size kind")
+		}
+	}
+
+	return nil
+}
+
+// trackSizesForDPI returns sizes with the values of fixed tracks converted to
+// native pixels.
+func trackSizesForDPI(sizes []TrackSize, dpi int) []TrackSize {
+	if sizes == nil {
+		return nil
+	}
+
+	scaled := make([]TrackSize, len(sizes))
+
+	for i, size := range sizes {
+		if size.Kind == TrackFixed {
+			size.Value = IntFrom96DPI(size.Value, dpi)
+		}
+
+		scaled[i] = size
+	}
+
+	return scaled
+}
+
+// trackSizes returns the explicit sizes for orientation, or nil.
+func (li *gridLayoutItem) trackSizes(orientation Orientation) []TrackSize {
+	if orientation == Horizontal {
+		return li.columnSizes
+	}
+
+	return li.rowSizes
+}
+
+// resolveTrackSizes returns the sizes of the sections for the explicit sizes
+// in tracks. Fixed and auto tracks are sized first, then star tracks share
+// the remaining space by weight, but never get less than their minimum size.
+func (li *gridLayoutItem) resolveTrackSizes(tracks []TrackSize, minSizes, autoSizes []int, space int) []int {
+	sizes := make([]int, len(minSizes))
+	track := func(i int) TrackSize {
+		if i < len(tracks) {
+			return tracks[i]
+		}
+
+		return TrackSize{Kind: TrackAuto}
+	}
+
+	var star []int
+	var sectionCount int
+
+	for i := range sizes {
+		t := track(i)
+
+		switch t.Kind {
+		case TrackFixed:
+			sizes[i] = t.Value
+
+		case TrackStar:
+			star = append(star, i)
+
+		default:
+			sizes[i] = maxi(minSizes[i], autoSizes[i])
+		}
+
+		if t.Kind != TrackAuto || sizes[i] > 0 {
+			sectionCount++
+		}
+
+		space -= sizes[i]
+	}
+
+	if sectionCount > 1 {
+		space -= (sectionCount - 1) * li.spacing
+	}
+
+	// Give star tracks that would end up smaller than their minimum size just
+	// that and distribute among the others again, until none is too small.
+	for {
+		var weightTotal int
+		for _, i := range star {
+			weightTotal += track(i).Value
+		}
+
+		var remaining []int
+		for _, i := range star {
+			if share := space * track(i).Value / weightTotal; share < minSizes[i] {
+				sizes[i] = minSizes[i]
+				space -= minSizes[i]
+			} else {
+				remaining = append(remaining, i)
+			}
+		}
+
+		if len(remaining) == len(star) {
+			break
+		}
+
+		star = remaining
+	}
+
+	var weightTotal int
+	for _, i := range star {
+		weightTotal += track(i).Value
+	}
+
+	for _, i := range star {
+		weight := track(i).Value
+
+		sizes[i] = space * weight / weightTotal
+
+		space -= sizes[i]
+		weightTotal -= weight
+	}
+
+	return sizes
+}
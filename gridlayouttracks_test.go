@@ -0,0 +1,31 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGridLayoutItemResolveTrackSizes(t *testing.T) {
+	li := new(gridLayoutItem)
+	li.spacing = 10
+
+	tracks := []TrackSize{FixedTrack(50), AutoTrack(), StarTrack(1)}
+
+	got := li.resolveTrackSizes(tracks, []int{0, 10, 0}, []int{0, 30, 0}, 200)
+	if want := []int{50, 30, 100}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	li.spacing = 0
+
+	got = li.resolveTrackSizes([]TrackSize{StarTrack(1), StarTrack(3)}, []int{0, 0}, []int{0, 0}, 200)
+	if want := []int{50, 150}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
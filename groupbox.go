@@ -7,6 +7,8 @@
 package walk
 
 import (
+	"strconv"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -15,19 +17,42 @@ import (
 
 const groupBoxWindowClass = `\o/ Walk_GroupBox_Class \o/`
 
+// Chevrons prefixed to the title of a collapsible GroupBox.
+const (
+	groupBoxExpandedChevron  = "\u25BE "
+	groupBoxCollapsedChevron = "\u25B8 "
+)
+
+var (
+	groupBoxWndProcPtr uintptr
+
+	// buttonWndProcPtr is the original window procedure of the subclassed
+	// BUTTON controls, shared by all of them.
+	buttonWndProcPtr uintptr
+)
+
 func init() {
 	AppendToWalkInit(func() {
 		MustRegisterWindowClass(groupBoxWindowClass)
+		groupBoxWndProcPtr = syscall.NewCallback(groupBoxWndProc)
 	})
 }
 
 type GroupBox struct {
 	WidgetBase
-	hWndGroupBox          win.HWND
-	checkBox              *CheckBox
-	composite             *Composite
-	headerHeight          int
-	titleChangedPublisher EventPublisher
+	hWndGroupBox              win.HWND
+	origGroupBoxWndProcPtr    uintptr
+	checkBox                  *CheckBox
+	composite                 *Composite
+	headerHeight              int
+	collapsible               bool
+	collapsed                 bool
+	titleIcon                 Image
+	autoDisableChildren       bool
+	childrenEnabled           map[*WidgetBase]bool // Enabled state of descendants before unchecking
+	titleChangedPublisher     EventPublisher
+	collapsedChangedPublisher EventPublisher
+	titleIconChangedPublisher EventPublisher
 }
 
 func NewGroupBox(parent Container) (*GroupBox, error) {
@@ -61,6 +86,12 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 	}
 	win.SetWindowLong(gb.hWndGroupBox, win.GWL_ID, 1)
 
+	gb.origGroupBoxWndProcPtr = win.SetWindowLongPtr(gb.hWndGroupBox, win.GWLP_WNDPROC, groupBoxWndProcPtr)
+	if gb.origGroupBoxWndProcPtr == 0 {
+		return nil, lastError("SetWindowLongPtr")
+	}
+	buttonWndProcPtr = gb.origGroupBoxWndProcPtr
+
 	gb.applyFont(gb.Font())
 	gb.updateHeaderHeight()
 
@@ -77,6 +108,10 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 
 	gb.checkBox.CheckedChanged().Attach(func() {
 		gb.applyEnabledFromCheckBox(gb.checkBox.Checked())
+
+		if gb.autoDisableChildren {
+			gb.applyCheckedToChildren(gb.checkBox.Checked())
+		}
 	})
 
 	setWindowVisible(gb.checkBox.hWnd, false)
@@ -111,6 +146,49 @@ func NewGroupBox(parent Container) (*GroupBox, error) {
 		},
 		gb.CheckedChanged()))
 
+	gb.MustRegisterProperty("Icon", NewProperty(
+		func() interface{} {
+			return gb.TitleIcon()
+		},
+		func(v interface{}) error {
+			var img Image
+
+			switch val := v.(type) {
+			case Image:
+				img = val
+
+			case int:
+				var err error
+				if img, err = Resources.Image(strconv.Itoa(val)); err != nil {
+					return err
+				}
+
+			case string:
+				var err error
+				if img, err = Resources.Image(val); err != nil {
+					return err
+				}
+
+			case nil:
+
+			default:
+				return ErrInvalidType
+			}
+
+			return gb.SetTitleIcon(img)
+		},
+		gb.titleIconChangedPublisher.Event()))
+
+	gb.MustRegisterProperty("Collapsed", NewBoolProperty(
+		func() bool {
+			return gb.Collapsed()
+		},
+		func(v bool) error {
+			gb.SetCollapsed(v)
+			return nil
+		},
+		gb.collapsedChangedPublisher.Event()))
+
 	succeeded = true
 
 	return gb, nil
@@ -203,6 +281,11 @@ func (gb *GroupBox) applyFont(font *Font) {
 	}
 
 	gb.updateHeaderHeight()
+
+	if gb.titleIcon != nil && gb.composite != nil {
+		// The room left for the icon depends on the font.
+		gb.SetTitle(gb.Title())
+	}
 }
 
 func (gb *GroupBox) SetSuspended(suspend bool) {
@@ -220,14 +303,27 @@ func (gb *GroupBox) SetDataBinder(dataBinder *DataBinder) {
 }
 
 func (gb *GroupBox) Title() string {
+	var title string
 	if gb.Checkable() {
-		return gb.checkBox.Text()
+		title = gb.checkBox.Text()
+	} else {
+		title = windowText(gb.hWndGroupBox)
+	}
+
+	if gb.titleIcon != nil && !gb.Checkable() {
+		title = strings.TrimLeft(title, " ")
 	}
 
-	return windowText(gb.hWndGroupBox)
+	return strings.TrimPrefix(title, gb.titlePrefix())
 }
 
 func (gb *GroupBox) SetTitle(title string) error {
+	title = gb.titlePrefix() + title
+
+	if gb.titleIcon != nil && !gb.Checkable() {
+		title = gb.titleIconPadding() + title
+	}
+
 	if gb.Checkable() {
 		if err := setWindowText(gb.hWndGroupBox, ""); err != nil {
 			return err
@@ -239,6 +335,227 @@ func (gb *GroupBox) SetTitle(title string) error {
 	return setWindowText(gb.hWndGroupBox, title)
 }
 
+// TitleIcon returns the icon shown left of the title.
+func (gb *GroupBox) TitleIcon() Image {
+	return gb.titleIcon
+}
+
+// SetTitleIcon sets the icon shown left of the title. The icon is scaled to
+// the DPI of the GroupBox.
+//
+// The icon is not shown while the GroupBox is checkable.
+func (gb *GroupBox) SetTitleIcon(icon Image) error {
+	if icon == gb.titleIcon {
+		return nil
+	}
+
+	title := gb.Title()
+
+	gb.titleIcon = icon
+
+	if err := gb.SetTitle(title); err != nil {
+		return err
+	}
+
+	win.InvalidateRect(gb.hWndGroupBox, nil, true)
+
+	gb.titleIconChangedPublisher.Publish()
+
+	return nil
+}
+
+// titleIconBounds returns the bounds of the title icon in native group box
+// coordinates.
+func (gb *GroupBox) titleIconBounds() Rectangle {
+	size := IntFrom96DPI(16, gb.DPI())
+	if size > gb.headerHeight {
+		size = gb.headerHeight
+	}
+
+	x := gb.headerHeight * 2 / 3
+
+	switch gb.TitleAlignment() {
+	case AlignCenter, AlignFar:
+		width := windowClientBounds(gb.hWndGroupBox).Width
+		textWidth := gb.calculateTextSizeImpl(windowText(gb.hWndGroupBox)).Width
+
+		if gb.TitleAlignment() == AlignCenter {
+			x = (width - textWidth) / 2
+		} else {
+			x = width - x - textWidth
+		}
+	}
+
+	return Rectangle{x, (gb.headerHeight - size) / 2, size, size}
+}
+
+// titleIconPadding returns the spaces that make the native group box leave
+// room for the title icon.
+func (gb *GroupBox) titleIconPadding() string {
+	space := gb.calculateTextSizeImpl(" ").Width
+	if space <= 0 {
+		space = 1
+	}
+
+	room := gb.titleIconBounds().Width + IntFrom96DPI(3, gb.DPI())
+
+	return strings.Repeat(" ", (room+space-1)/space)
+}
+
+func (gb *GroupBox) drawTitleIcon() {
+	if gb.titleIcon == nil || gb.Checkable() {
+		return
+	}
+
+	hdc := win.GetDC(gb.hWndGroupBox)
+	if hdc == 0 {
+		return
+	}
+	defer win.ReleaseDC(gb.hWndGroupBox, hdc)
+
+	gb.titleIcon.drawStretched(hdc, gb.titleIconBounds())
+}
+
+// TitleAlignment returns the horizontal alignment of the title.
+func (gb *GroupBox) TitleAlignment() Alignment1D {
+	switch win.GetWindowLong(gb.hWndGroupBox, win.GWL_STYLE) & win.BS_CENTER {
+	case win.BS_CENTER:
+		return AlignCenter
+
+	case win.BS_RIGHT:
+		return AlignFar
+	}
+
+	return AlignNear
+}
+
+// SetTitleAlignment sets the horizontal alignment of the title.
+//
+// AlignNear and AlignFar are mirrored for right-to-left layouts.
+func (gb *GroupBox) SetTitleAlignment(alignment Alignment1D) error {
+	var bit uint32
+
+	switch alignment {
+	case AlignCenter:
+		bit = win.BS_CENTER
+
+	case AlignFar:
+		bit = win.BS_RIGHT
+
+	default:
+		bit = win.BS_LEFT
+	}
+
+	if err := setAndClearWindowLongBits(gb.hWndGroupBox, win.GWL_STYLE, bit, win.BS_CENTER); err != nil {
+		return err
+	}
+
+	gb.updateCheckBoxBounds()
+
+	win.SetWindowPos(gb.hWndGroupBox, 0, 0, 0, 0, 0,
+		win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOZORDER|win.SWP_NOACTIVATE|win.SWP_FRAMECHANGED)
+	win.InvalidateRect(gb.hWndGroupBox, nil, true)
+
+	return nil
+}
+
+// updateCheckBoxBounds places the check box of a checkable GroupBox
+// according to the title alignment.
+func (gb *GroupBox) updateCheckBoxBounds() {
+	if !gb.Checkable() {
+		return
+	}
+
+	s := createLayoutItemForWidget(gb.checkBox).(MinSizer).MinSize() // TODO: MinSize() returns 96dpi pixels, we're using it in SetBoundsPixels()
+	var x int
+	if l := gb.Layout(); l != nil {
+		x = l.Margins().HNear
+	} else {
+		x = gb.headerHeight * 2 / 3
+	}
+
+	switch gb.TitleAlignment() {
+	case AlignCenter:
+		x = (gb.WidgetBase.ClientBoundsPixels().Width - s.Width) / 2
+
+	case AlignFar:
+		x = gb.WidgetBase.ClientBoundsPixels().Width - x - s.Width
+	}
+
+	gb.checkBox.SetBoundsPixels(Rectangle{x, gb.headerHeight, s.Width, s.Height})
+}
+
+// titlePrefix returns the chevron shown in front of the title, if the
+// GroupBox is collapsible.
+func (gb *GroupBox) titlePrefix() string {
+	switch {
+	case !gb.collapsible:
+		return ""
+
+	case gb.collapsed:
+		return groupBoxCollapsedChevron
+
+	default:
+		return groupBoxExpandedChevron
+	}
+}
+
+// Collapsible returns if the user can collapse the GroupBox by clicking its
+// title.
+func (gb *GroupBox) Collapsible() bool {
+	return gb.collapsible
+}
+
+// SetCollapsible sets if the user can collapse the GroupBox by clicking its
+// title. A chevron in front of the title shows the collapsed state.
+func (gb *GroupBox) SetCollapsible(collapsible bool) {
+	if collapsible == gb.collapsible {
+		return
+	}
+
+	if !collapsible {
+		gb.SetCollapsed(false)
+	}
+
+	title := gb.Title()
+
+	gb.collapsible = collapsible
+
+	gb.SetTitle(title)
+}
+
+// Collapsed returns if the children of the GroupBox are hidden, leaving only
+// its title visible.
+func (gb *GroupBox) Collapsed() bool {
+	return gb.collapsed
+}
+
+// SetCollapsed sets if the children of the GroupBox are hidden, shrinking it
+// to its title.
+func (gb *GroupBox) SetCollapsed(collapsed bool) {
+	if collapsed == gb.collapsed {
+		return
+	}
+
+	title := gb.Title()
+
+	gb.collapsed = collapsed
+
+	gb.SetTitle(title)
+
+	setWindowVisible(gb.composite.hWnd, !collapsed)
+
+	gb.collapsedChangedPublisher.Publish()
+
+	gb.RequestLayout()
+}
+
+// CollapsedChanged returns the event that is published when the GroupBox has
+// been collapsed or expanded.
+func (gb *GroupBox) CollapsedChanged() *Event {
+	return gb.collapsedChangedPublisher.Event()
+}
+
 func (gb *GroupBox) Checkable() bool {
 	return gb.checkBox.visible
 }
@@ -265,8 +582,67 @@ func (gb *GroupBox) CheckedChanged() *Event {
 	return gb.checkBox.CheckedChanged()
 }
 
+// AutoDisableChildren returns if unchecking a checkable GroupBox disables
+// its descendant widgets.
+func (gb *GroupBox) AutoDisableChildren() bool {
+	return gb.autoDisableChildren
+}
+
+// SetAutoDisableChildren sets if unchecking a checkable GroupBox disables
+// its descendant widgets. When the GroupBox is checked again, each widget
+// gets back the enabled state it had before.
+func (gb *GroupBox) SetAutoDisableChildren(value bool) {
+	if value == gb.autoDisableChildren {
+		return
+	}
+
+	gb.autoDisableChildren = value
+
+	if value {
+		gb.applyCheckedToChildren(gb.Checked())
+	} else {
+		gb.applyCheckedToChildren(true)
+	}
+}
+
+func (gb *GroupBox) applyCheckedToChildren(checked bool) {
+	if checked {
+		for wb, enabled := range gb.childrenEnabled {
+			if !wb.IsDisposed() {
+				wb.window.SetEnabled(enabled)
+			}
+		}
+
+		gb.childrenEnabled = nil
+
+		return
+	}
+
+	if gb.childrenEnabled != nil {
+		return
+	}
+
+	gb.childrenEnabled = make(map[*WidgetBase]bool)
+
+	walkDescendants(gb.composite, func(w Window) bool {
+		if widget, ok := w.(Widget); ok && w != Window(gb.composite) {
+			wb := widget.AsWidgetBase()
+			gb.childrenEnabled[wb] = wb.enabled
+		}
+
+		return true
+	})
+
+	for wb := range gb.childrenEnabled {
+		wb.window.SetEnabled(false)
+	}
+}
+
 func (gb *GroupBox) ApplyDPI(dpi int) {
 	gb.WidgetBase.ApplyDPI(dpi)
+	if gb.titleIcon != nil {
+		gb.SetTitle(gb.Title())
+	}
 	if gb.checkBox != nil {
 		gb.checkBox.ApplyDPI(dpi)
 	}
@@ -331,6 +707,13 @@ func (gb *GroupBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 		case win.WM_SETTEXT:
 			gb.titleChangedPublisher.Publish()
 
+		case win.WM_LBUTTONDOWN:
+			// The native group box is transparent to the mouse, so clicks
+			// on its title end up here.
+			if gb.collapsible && int(win.GET_Y_LPARAM(lParam)) < gb.headerHeight {
+				gb.SetCollapsed(!gb.collapsed)
+			}
+
 		case win.WM_PAINT:
 			win.UpdateWindow(gb.checkBox.hWnd)
 
@@ -355,16 +738,7 @@ func (gb *GroupBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 				break
 			}
 
-			if gb.Checkable() {
-				s := createLayoutItemForWidget(gb.checkBox).(MinSizer).MinSize() // TODO: MinSize() returns 96dpi pixels, we're using it in SetBoundsPixels()
-				var x int
-				if l := gb.Layout(); l != nil {
-					x = l.Margins().HNear
-				} else {
-					x = gb.headerHeight * 2 / 3
-				}
-				gb.checkBox.SetBoundsPixels(Rectangle{x, gb.headerHeight, s.Width, s.Height})
-			}
+			gb.updateCheckBoxBounds()
 
 			gbcb := gb.ClientBoundsPixels()
 			gbcb.Y -= offset
@@ -375,6 +749,21 @@ func (gb *GroupBox) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 	return gb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
 }
 
+func groupBoxWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
+	gb, ok := windowFromHandle(win.GetParent(hwnd)).(*GroupBox)
+	if !ok {
+		return win.CallWindowProc(buttonWndProcPtr, hwnd, msg, wp, lp)
+	}
+
+	result := win.CallWindowProc(gb.origGroupBoxWndProcPtr, hwnd, msg, wp, lp)
+
+	if msg == win.WM_PAINT {
+		gb.drawTitleIcon()
+	}
+
+	return result
+}
+
 func (gb *GroupBox) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	compositePos := Point{1, gb.headerHeight}
 	if gb.Checkable() {
@@ -386,6 +775,7 @@ func (gb *GroupBox) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	li := &groupBoxLayoutItem{
 		compositePos: compositePos,
 		title:        gb.Title(),
+		collapsed:    gb.collapsed,
 	}
 
 	gbli := CreateLayoutItemsForContainerWithContext(gb.composite, ctx)
@@ -400,16 +790,32 @@ type groupBoxLayoutItem struct {
 	ContainerLayoutItemBase
 	compositePos Point
 	title        string
+	collapsed    bool
 }
 
 func (li *groupBoxLayoutItem) LayoutFlags() LayoutFlags {
-	return li.children[0].LayoutFlags()
+	flags := li.children[0].LayoutFlags()
+	if li.collapsed {
+		flags &^= GrowableVert | GreedyVert
+	}
+
+	return flags
+}
+
+// collapsedHeight returns the height of the GroupBox when only its title is
+// visible.
+func (li *groupBoxLayoutItem) collapsedHeight() int {
+	return li.compositePos.Y + IntFrom96DPI(5, li.ctx.dpi)
 }
 
 func (li *groupBoxLayoutItem) MinSize() Size {
 	min := li.children[0].(MinSizer).MinSize()
 	min.Width += li.compositePos.X * 2
-	min.Height += li.compositePos.Y + IntFrom96DPI(5, li.ctx.dpi)
+	if li.collapsed {
+		min.Height = li.collapsedHeight()
+	} else {
+		min.Height += li.collapsedHeight()
+	}
 
 	return min
 }
@@ -419,6 +825,10 @@ func (li *groupBoxLayoutItem) MinSizeForSize(size Size) Size {
 }
 
 func (li *groupBoxLayoutItem) HasHeightForWidth() bool {
+	if li.collapsed {
+		return false
+	}
+
 	return li.children[0].(HeightForWidther).HasHeightForWidth()
 }
 
@@ -428,11 +838,19 @@ func (li *groupBoxLayoutItem) HeightForWidth(width int) int {
 
 func (li *groupBoxLayoutItem) IdealSize() Size {
 	size := li.children[0].(IdealSizer).IdealSize()
-	size.Height += li.compositePos.Y
+	if li.collapsed {
+		size.Height = li.collapsedHeight()
+	} else {
+		size.Height += li.compositePos.Y
+	}
 	return size
 }
 
 func (li *groupBoxLayoutItem) PerformLayout() []LayoutResultItem {
+	if li.collapsed {
+		return nil
+	}
+
 	return []LayoutResultItem{
 		{
 			Item:   li.children[0],
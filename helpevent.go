@@ -0,0 +1,45 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+type HelpEventHandler func(widget Widget)
+
+type HelpEvent struct {
+	handlers []HelpEventHandler
+}
+
+func (e *HelpEvent) Attach(handler HelpEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *HelpEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type HelpEventPublisher struct {
+	event HelpEvent
+}
+
+func (p *HelpEventPublisher) Event() *HelpEvent {
+	return &p.event
+}
+
+func (p *HelpEventPublisher) Publish(widget Widget) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(widget)
+		}
+	}
+}
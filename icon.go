@@ -7,8 +7,12 @@
 package walk
 
 import (
+	"encoding/binary"
 	"image"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"unsafe"
 
@@ -138,6 +142,173 @@ func NewIconExtractedFromFileWithSize(filePath string, index, size int) (*Icon,
 	return checkNewIcon(&Icon{filePath: filePath, index: index, hasIndex: true, size96dpi: Size{size, size}})
 }
 
+// NewIconFromResourceModule returns a new Icon, as identified by index,
+// from the icon resources of the executable or DLL module.
+//
+// If module does not contain a directory and is not found in the working
+// directory, it is looked up in the system directory. size is in 1/96" units
+// and defaults to the small icon size. For each DPI, the icon variant that
+// best matches the scaled size is used, falling back to the largest variant.
+func NewIconFromResourceModule(module string, index int, size Size) (*Icon, error) {
+	filePath, err := resourceModulePath(module)
+	if err != nil {
+		return nil, err
+	}
+
+	if size.Width == 0 || size.Height == 0 {
+		size = defaultIconSize
+	}
+
+	return checkNewIcon(&Icon{filePath: filePath, index: index, hasIndex: true, size96dpi: size})
+}
+
+// ResourceModuleIconCount returns the number of icons contained in the
+// executable, DLL or icon file module.
+//
+// Valid indexes for NewIconFromResourceModule range from 0 to the count - 1.
+func ResourceModuleIconCount(module string) (int, error) {
+	filePath, err := resourceModulePath(module)
+	if err != nil {
+		return 0, err
+	}
+
+	pathPtr, err := syscall.UTF16PtrFromString(filePath)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+
+	return int(extractIconEx(pathPtr, -1, nil, nil, 0)), nil
+}
+
+func resourceModulePath(module string) (string, error) {
+	if filepath.Base(module) == module {
+		if _, err := os.Stat(module); os.IsNotExist(err) {
+			system32, err := windows.GetSystemDirectory()
+			if err != nil {
+				return "", wrapError(err)
+			}
+
+			module = filepath.Join(system32, module)
+		}
+	}
+
+	absFilePath, err := filepath.Abs(module)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	return absFilePath, nil
+}
+
+type largestGroupIconSizeState struct {
+	index int
+	size  int
+}
+
+var largestGroupIconSizeCallbackPtr uintptr
+
+func init() {
+	AppendToWalkInit(func() {
+		largestGroupIconSizeCallbackPtr = syscall.NewCallback(largestGroupIconSizeCallback)
+	})
+}
+
+// largestIconSize returns the width of the largest variant of the icon
+// identified by index in the executable, DLL or icon file at filePath, or 0
+// if it cannot be determined.
+func largestIconSize(filePath string, index int) int {
+	if strings.EqualFold(filepath.Ext(filePath), ".ico") {
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return 0
+		}
+
+		// The entries of an ICONDIR are 16 bytes long.
+		return largestIconDirEntryWidth(data, 16)
+	}
+
+	hModule, err := windows.LoadLibraryEx(filePath, 0, loadLibraryAsDatafile|loadLibraryAsImageResource)
+	if err != nil {
+		return 0
+	}
+	defer windows.FreeLibrary(hModule)
+
+	if index < 0 {
+		// A negative index is the resource id.
+		return largestGroupIconSize(win.HMODULE(hModule), win.MAKEINTRESOURCE(uintptr(-index)))
+	}
+
+	// Otherwise it is the position among the icon groups.
+	state := largestGroupIconSizeState{index: index}
+
+	enumResourceNames(win.HMODULE(hModule), win.MAKEINTRESOURCE(rtGroupIcon), largestGroupIconSizeCallbackPtr, uintptr(unsafe.Pointer(&state)))
+
+	return state.size
+}
+
+func largestGroupIconSizeCallback(hModule win.HMODULE, lpType, lpName *uint16, lParam uintptr) uintptr {
+	state := (*largestGroupIconSizeState)(unsafe.Pointer(lParam))
+
+	if state.index > 0 {
+		state.index--
+		return win.TRUE
+	}
+
+	state.size = largestGroupIconSize(hModule, lpName)
+
+	return win.FALSE
+}
+
+func largestGroupIconSize(hModule win.HMODULE, name *uint16) int {
+	hRes := win.FindResource(hModule, name, win.MAKEINTRESOURCE(rtGroupIcon))
+	if hRes == 0 {
+		return 0
+	}
+
+	size := win.SizeofResource(hModule, hRes)
+
+	hResLoad := win.LoadResource(hModule, hRes)
+	if hResLoad == 0 {
+		return 0
+	}
+
+	ptr := win.LockResource(hResLoad)
+	if ptr == 0 {
+		return 0
+	}
+
+	// The entries of a GRPICONDIR are 14 bytes long.
+	return largestIconDirEntryWidth((*[1 << 20]byte)(unsafe.Pointer(ptr))[:size:size], 14)
+}
+
+// largestIconDirEntryWidth returns the largest width among the entries of an
+// ICONDIR or GRPICONDIR, which only differ in the length of their entries.
+func largestIconDirEntryWidth(dir []byte, entryLen int) int {
+	if len(dir) < 6 {
+		return 0
+	}
+
+	var largest int
+
+	count := int(binary.LittleEndian.Uint16(dir[4:]))
+	for i := 0; i < count; i++ {
+		offset := 6 + i*entryLen
+		if offset+entryLen > len(dir) {
+			break
+		}
+
+		width := int(dir[offset])
+		if width == 0 {
+			// 0 means 256 pixels.
+			width = 256
+		}
+
+		largest = maxi(largest, width)
+	}
+
+	return largest
+}
+
 // NewIconFromImage returns a new Icon, using the specified image.Image as source.
 func NewIconFromImage(im image.Image, dpi int) (ic *Icon, err error) {
 	hIcon, err := createAlphaCursorOrIconFromImage(im, image.Pt(0, 0), true)
@@ -264,6 +435,19 @@ func (i *Icon) handleForDPIWithError(dpi int) (win.HICON, error) {
 			nil,
 			&hIcon,
 			win.MAKELONG(0, uint16(size.Width)))
+		if hIcon == 0 {
+			// No variant close to the requested size, so we settle for the
+			// largest one.
+			if largest := largestIconSize(win.UTF16PtrToString(name), i.index); largest > 0 {
+				win.SHDefExtractIcon(
+					name,
+					int32(i.index),
+					0,
+					nil,
+					&hIcon,
+					win.MAKELONG(0, uint16(largest)))
+			}
+		}
 		if hIcon == 0 {
 			return 0, newError("SHDefExtractIcon")
 		}
@@ -330,6 +514,108 @@ func (i *Icon) Size() Size {
 	return i.size96dpi
 }
 
+// ToBitmapForDPI returns a new Bitmap containing the variant of the Icon
+// for dpi.
+//
+// If background is Transparent, the alpha channel of the Icon is kept,
+// otherwise the Icon is drawn onto a solid background of that color.
+func (i *Icon) ToBitmapForDPI(dpi int, background Color) (*Bitmap, error) {
+	size := i.size96dpi.From96DPI(dpi)
+	if size.Width <= 0 || size.Height <= 0 {
+		return nil, newError("invalid icon size")
+	}
+
+	// We draw the icon onto black and white and derive the alpha channel from
+	// the difference. This works for both alpha and masked icons.
+	onBlack, err := i.pixelsOnBackground(size, 0x00)
+	if err != nil {
+		return nil, err
+	}
+	onWhite, err := i.pixelsOnBackground(size, 0xff)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size.Width, size.Height))
+
+	for p := 0; p < len(img.Pix); p += 4 {
+		// The DIB pixels are BGRA, the image pixels RGBA.
+		b, g, r := int(onBlack[p]), int(onBlack[p+1]), int(onBlack[p+2])
+
+		a := 0xff - (int(onWhite[p+1]) - g)
+		if a < 0 {
+			a = 0
+		} else if a > 0xff {
+			a = 0xff
+		}
+
+		if background != Transparent {
+			inv := 0xff - a
+			r += int(background.R()) * inv / 0xff
+			g += int(background.G()) * inv / 0xff
+			b += int(background.B()) * inv / 0xff
+			a = 0xff
+		}
+
+		// The colors drawn onto black already are premultiplied.
+		img.Pix[p] = byte(mini(r, a))
+		img.Pix[p+1] = byte(mini(g, a))
+		img.Pix[p+2] = byte(mini(b, a))
+		img.Pix[p+3] = byte(a)
+	}
+
+	return NewBitmapFromImageForDPI(img, dpi)
+}
+
+// pixelsOnBackground draws the Icon onto a top-down 32 bit DIB that is
+// filled with fill and returns a copy of the resulting BGRA pixels.
+func (i *Icon) pixelsOnBackground(size Size, fill byte) ([]byte, error) {
+	var pixels []byte
+
+	err := withCompatibleDC(func(hdc win.HDC) error {
+		var hdr win.BITMAPINFOHEADER
+		hdr.BiSize = uint32(unsafe.Sizeof(hdr))
+		hdr.BiBitCount = 32
+		hdr.BiCompression = win.BI_RGB
+		hdr.BiPlanes = 1
+		hdr.BiWidth = int32(size.Width)
+		hdr.BiHeight = -int32(size.Height)
+
+		var bitsPtr unsafe.Pointer
+
+		hBmp := win.CreateDIBSection(hdc, &hdr, win.DIB_RGB_COLORS, &bitsPtr, 0, 0)
+		switch hBmp {
+		case 0, win.ERROR_INVALID_PARAMETER:
+			return newError("CreateDIBSection failed")
+		}
+		defer win.DeleteObject(win.HGDIOBJ(hBmp))
+
+		bits := (*[1 << 30]byte)(bitsPtr)[: size.Width*size.Height*4 : size.Width*size.Height*4]
+		for j := range bits {
+			bits[j] = fill
+		}
+
+		hBmpOld := win.SelectObject(hdc, win.HGDIOBJ(hBmp))
+		if hBmpOld == 0 {
+			return newError("SelectObject failed")
+		}
+		defer win.SelectObject(hdc, hBmpOld)
+
+		if err := i.drawStretched(hdc, Rectangle{Width: size.Width, Height: size.Height}); err != nil {
+			return err
+		}
+
+		win.GdiFlush()
+
+		pixels = make([]byte, len(bits))
+		copy(pixels, bits)
+
+		return nil
+	})
+
+	return pixels, err
+}
+
 // create an Alpha Icon or Cursor from an Image
 // http://support.microsoft.com/kb/318876
 func createAlphaCursorOrIconFromImage(im image.Image, hotspot image.Point, fIcon bool) (win.HICON, error) {
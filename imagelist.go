@@ -17,8 +17,21 @@ type ImageList struct {
 	hIml                     win.HIMAGELIST
 	maskColor                Color
 	imageSize96dpi           Size
+	dpi                      int
 	colorMaskedBitmap2Index  map[*Bitmap]int
 	bitmapMaskedBitmap2Index map[bitmapMaskedBitmap]int
+	icon2Index               map[*Icon]int
+	entries                  []imageListEntry // In the order they were added, so they can be re-added on DPI change
+	handleChangedPublisher   EventPublisher
+}
+
+type imageListEntry struct {
+	bitmap      *Bitmap
+	mask        *Bitmap
+	icon        *Icon
+	colorMasked bool
+	dpi         int // The dpi of the ImageList when the entry was added
+	count       int // Number of images the entry occupies in the list
 }
 
 type bitmapMaskedBitmap struct {
@@ -48,8 +61,10 @@ func newImageList(imageSize Size, maskColor Color, dpi int) (*ImageList, error)
 		hIml:                     hIml,
 		maskColor:                maskColor,
 		imageSize96dpi:           imageSize,
+		dpi:                      dpi,
 		colorMaskedBitmap2Index:  make(map[*Bitmap]int),
 		bitmapMaskedBitmap2Index: make(map[bitmapMaskedBitmap]int),
+		icon2Index:               make(map[*Icon]int),
 	}, nil
 }
 
@@ -73,12 +88,15 @@ func (il *ImageList) Add(bitmap, maskBitmap *Bitmap) (int, error) {
 		maskHandle = maskBitmap.handle()
 	}
 
+	count := il.Count()
+
 	index := int(win.ImageList_Add(il.hIml, bitmap.handle(), maskHandle))
 	if index == -1 {
 		return 0, newError("ImageList_Add failed")
 	}
 
 	il.bitmapMaskedBitmap2Index[key] = index
+	il.entries = append(il.entries, imageListEntry{bitmap: bitmap, mask: maskBitmap, dpi: il.dpi, count: il.Count() - count})
 
 	return index, nil
 }
@@ -92,6 +110,8 @@ func (il *ImageList) AddMasked(bitmap *Bitmap) (int32, error) {
 		return int32(index), nil
 	}
 
+	count := il.Count()
+
 	index := win.ImageList_AddMasked(
 		il.hIml,
 		bitmap.handle(),
@@ -101,10 +121,304 @@ func (il *ImageList) AddMasked(bitmap *Bitmap) (int32, error) {
 	}
 
 	il.colorMaskedBitmap2Index[bitmap] = int(index)
+	il.entries = append(il.entries, imageListEntry{bitmap: bitmap, colorMasked: true, dpi: il.dpi, count: il.Count() - count})
 
 	return index, nil
 }
 
+// AddIcon adds the variant of icon that best matches the image size of the
+// ImageList and returns its index. Adding the same icon again returns the
+// index it was added at before.
+func (il *ImageList) AddIcon(icon *Icon) (int, error) {
+	if icon == nil {
+		return 0, newError("icon cannot be nil")
+	}
+
+	if index, ok := il.icon2Index[icon]; ok {
+		return index, nil
+	}
+
+	hIcon, err := icon.handleForDPIWithError(il.iconDPI(icon, il.dpi))
+	if err != nil {
+		return 0, err
+	}
+
+	index := int(win.ImageList_ReplaceIcon(il.hIml, -1, hIcon))
+	if index == -1 {
+		return 0, newError("ImageList_ReplaceIcon failed")
+	}
+
+	il.icon2Index[icon] = index
+	il.entries = append(il.entries, imageListEntry{icon: icon, dpi: il.dpi, count: 1})
+
+	return index, nil
+}
+
+// iconDPI returns the dpi for which icon yields the variant that matches the
+// image size of the ImageList at dpi.
+func (il *ImageList) iconDPI(icon *Icon, dpi int) int {
+	size := icon.Size()
+	if size.Width == 0 {
+		return dpi
+	}
+
+	return dpi * il.imageSize96dpi.Width / size.Width
+}
+
+// imageSizePixels returns the size of the images stored in the ImageList in
+// native pixels.
+func (il *ImageList) imageSizePixels() Size {
+	return scaleSize(il.imageSize96dpi, float64(il.dpi)/96.0)
+}
+
+// Count returns the number of images stored in the ImageList.
+func (il *ImageList) Count() int {
+	return int(imageListGetImageCount(il.hIml))
+}
+
+// DrawImage draws the image at index to canvas, with its top left corner at
+// pos, which is specified in 1/96" units.
+func (il *ImageList) DrawImage(canvas *Canvas, index int, pos Point) error {
+	if canvas == nil {
+		return newError("canvas cannot be nil")
+	}
+
+	pos = pos.From96DPI(canvas.DPI())
+
+	if !win.ImageList_DrawEx(
+		il.hIml,
+		int32(index),
+		canvas.hdc,
+		int32(pos.X),
+		int32(pos.Y),
+		0,
+		0,
+		win.CLR_NONE,
+		win.CLR_NONE,
+		win.ILD_TRANSPARENT) {
+
+		return newError("ImageList_DrawEx failed")
+	}
+
+	return nil
+}
+
+// Remove removes the image at index from the ImageList.
+//
+// The indexes of all images after the removed one are decremented by one.
+func (il *ImageList) Remove(index int) error {
+	if index < 0 {
+		return newError("index out of range")
+	}
+
+	if !imageListRemove(il.hIml, int32(index)) {
+		return newError("ImageList_Remove failed")
+	}
+
+	for key, i := range il.bitmapMaskedBitmap2Index {
+		if i == index {
+			delete(il.bitmapMaskedBitmap2Index, key)
+		} else if i > index {
+			il.bitmapMaskedBitmap2Index[key] = i - 1
+		}
+	}
+
+	for key, i := range il.colorMaskedBitmap2Index {
+		if i == index {
+			delete(il.colorMaskedBitmap2Index, key)
+		} else if i > index {
+			il.colorMaskedBitmap2Index[key] = i - 1
+		}
+	}
+
+	for key, i := range il.icon2Index {
+		if i == index {
+			delete(il.icon2Index, key)
+		} else if i > index {
+			il.icon2Index[key] = i - 1
+		}
+	}
+
+	first := 0
+	for i, entry := range il.entries {
+		if index < first+entry.count {
+			if entry.count == 1 {
+				il.entries = append(il.entries[:i], il.entries[i+1:]...)
+			} else {
+				// Part of a strip was removed, so the entry can no longer be
+				// re-added as a whole on DPI change.
+				il.entries[i].count--
+				il.entries[i].bitmap = nil
+			}
+			break
+		}
+
+		first += entry.count
+	}
+
+	return nil
+}
+
+// Clear removes all images from the ImageList.
+func (il *ImageList) Clear() error {
+	if !imageListRemove(il.hIml, -1) {
+		return newError("ImageList_Remove failed")
+	}
+
+	il.bitmapMaskedBitmap2Index = make(map[bitmapMaskedBitmap]int)
+	il.colorMaskedBitmap2Index = make(map[*Bitmap]int)
+	il.icon2Index = make(map[*Icon]int)
+	il.entries = nil
+
+	return nil
+}
+
+// HandleChanged returns the event that is published when the underlying
+// HIMAGELIST has been recreated, e.g. by ApplyDPI. Controls using the
+// ImageList should re-associate the new Handle.
+func (il *ImageList) HandleChanged() *Event {
+	return il.handleChangedPublisher.Event()
+}
+
+// ApplyDPI recreates the ImageList for the specified dpi, re-adding all images
+// from the bitmaps and icons they were added from. Image indexes remain valid.
+//
+// Bitmaps are scaled from their original size, color-masked bitmaps without
+// interpolation, so the mask color stays intact. Icons provide the variant
+// that best matches the new image size.
+//
+// HandleChanged is published once the new list is in place.
+func (il *ImageList) ApplyDPI(dpi int) error {
+	if dpi == il.dpi || il.hIml == 0 {
+		return nil
+	}
+
+	sz := il.imageSize96dpi.From96DPI(dpi).toSIZE()
+
+	hIml := win.ImageList_Create(sz.CX, sz.CY, win.ILC_MASK|win.ILC_COLOR32, 8, 8)
+	if hIml == 0 {
+		return newError("ImageList_Create failed")
+	}
+
+	for _, entry := range il.entries {
+		if err := il.addEntryForDPI(hIml, entry, dpi); err != nil {
+			win.ImageList_Destroy(hIml)
+			return err
+		}
+	}
+
+	win.ImageList_Destroy(il.hIml)
+
+	il.hIml = hIml
+	il.dpi = dpi
+
+	il.handleChangedPublisher.Publish()
+
+	return nil
+}
+
+func (il *ImageList) addEntryForDPI(hIml win.HIMAGELIST, entry imageListEntry, dpi int) error {
+	if entry.icon != nil {
+		hIcon, err := entry.icon.handleForDPIWithError(il.iconDPI(entry.icon, dpi))
+		if err != nil {
+			return err
+		}
+
+		if win.ImageList_ReplaceIcon(hIml, -1, hIcon) == -1 {
+			return newError("ImageList_ReplaceIcon failed")
+		}
+
+		return nil
+	}
+
+	if entry.bitmap == nil {
+		// Keep the indexes of later entries stable by adding blank images.
+		bmp, err := NewBitmapWithTransparentPixels(il.imageSize96dpi.From96DPI(dpi))
+		if err != nil {
+			return err
+		}
+		defer bmp.Dispose()
+
+		for i := 0; i < entry.count; i++ {
+			if win.ImageList_Add(hIml, bmp.handle(), 0) == -1 {
+				return newError("ImageList_Add failed")
+			}
+		}
+
+		return nil
+	}
+
+	scale := float64(dpi) / float64(entry.dpi)
+
+	if entry.colorMasked {
+		bmp, err := bitmapScaledNearest(entry.bitmap, scaleSize(entry.bitmap.Size(), scale))
+		if err != nil {
+			return err
+		}
+		defer bmp.Dispose()
+
+		if win.ImageList_AddMasked(hIml, bmp.handle(), win.COLORREF(il.maskColor)) == -1 {
+			return newError("ImageList_AddMasked failed")
+		}
+
+		return nil
+	}
+
+	bmp, err := NewBitmapFromImageWithSize(entry.bitmap, scaleSize(entry.bitmap.Size(), scale))
+	if err != nil {
+		return err
+	}
+	defer bmp.Dispose()
+
+	var maskHandle win.HBITMAP
+	if entry.mask != nil {
+		mask, err := bitmapScaledNearest(entry.mask, scaleSize(entry.mask.Size(), scale))
+		if err != nil {
+			return err
+		}
+		defer mask.Dispose()
+
+		maskHandle = mask.handle()
+	}
+
+	if win.ImageList_Add(hIml, bmp.handle(), maskHandle) == -1 {
+		return newError("ImageList_Add failed")
+	}
+
+	return nil
+}
+
+// bitmapScaledNearest returns a copy of bmp scaled to size without
+// interpolation, so it contains no colors that are not in bmp.
+func bitmapScaledNearest(bmp *Bitmap, size Size) (*Bitmap, error) {
+	scaled, err := NewBitmap(size)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bmp.withSelectedIntoMemDC(func(hdcSrc win.HDC) error {
+		return scaled.withSelectedIntoMemDC(func(hdcDst win.HDC) error {
+			win.SetStretchBltMode(hdcDst, win.COLORONCOLOR)
+
+			if !win.StretchBlt(
+				hdcDst, 0, 0, int32(size.Width), int32(size.Height),
+				hdcSrc, 0, 0, int32(bmp.size.Width), int32(bmp.size.Height),
+				win.SRCCOPY) {
+
+				return newError("StretchBlt failed")
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		scaled.Dispose()
+		return nil, err
+	}
+
+	return scaled, nil
+}
+
 func (il *ImageList) Dispose() {
 	if il.hIml != 0 {
 		win.ImageList_Destroy(il.hIml)
@@ -203,7 +517,12 @@ func imageIndexAddIfNotExists(image interface{}, hIml win.HIMAGELIST, imageUintp
 
 		switch img := image.(type) {
 		case *Bitmap:
-			imageIndex = win.ImageList_AddMasked(hIml, img.hBmp, 0)
+			if img.hasAlpha() {
+				// Keep the alpha channel instead of masking out black.
+				imageIndex = win.ImageList_Add(hIml, img.hBmp, 0)
+			} else {
+				imageIndex = win.ImageList_AddMasked(hIml, img.hBmp, 0)
+			}
 
 		case *Icon:
 			imageIndex = win.ImageList_ReplaceIcon(hIml, -1, img.handleForDPI(dpi))
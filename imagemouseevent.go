@@ -0,0 +1,48 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+// ImageMouseEventHandler is called with the position of a mouse event in
+// 96dpi image coordinates. inImage reports whether the position lies within
+// the drawn image.
+type ImageMouseEventHandler func(imagePoint Point, inImage bool, button MouseButton)
+
+type ImageMouseEvent struct {
+	handlers []ImageMouseEventHandler
+}
+
+func (e *ImageMouseEvent) Attach(handler ImageMouseEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *ImageMouseEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type ImageMouseEventPublisher struct {
+	event ImageMouseEvent
+}
+
+func (p *ImageMouseEventPublisher) Event() *ImageMouseEvent {
+	return &p.event
+}
+
+func (p *ImageMouseEventPublisher) Publish(imagePoint Point, inImage bool, button MouseButton) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(imagePoint, inImage, button)
+		}
+	}
+}
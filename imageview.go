@@ -9,6 +9,8 @@ package walk
 import (
 	"math"
 	"strconv"
+	"sync/atomic"
+	"time"
 
 	"github.com/lxn/win"
 )
@@ -22,19 +24,93 @@ const (
 	ImageViewModeShrink
 	ImageViewModeZoom
 	ImageViewModeStretch
+	ImageViewModeTile
+)
+
+// ImageViewFitMode controls how ImageViewModeShrink, ImageViewModeZoom and
+// ImageViewModeStretch scale the image to the client area.
+type ImageViewFitMode int
+
+const (
+	// ImageViewFitDefault lets the mode decide: ImageViewModeStretch fills,
+	// ImageViewModeShrink and ImageViewModeZoom contain.
+	ImageViewFitDefault ImageViewFitMode = iota
+
+	// ImageViewFitFill stretches the image to the client area. If
+	// KeepAspectRatio is set, only the width is filled and the height
+	// follows from the aspect ratio.
+	ImageViewFitFill
+
+	// ImageViewFitContain scales the image to fit entirely into the client
+	// area, preserving its aspect ratio.
+	ImageViewFitContain
+
+	// ImageViewFitCover scales the image to cover the whole client area,
+	// preserving its aspect ratio and clipping any overflow.
+	ImageViewFitCover
+)
+
+const (
+	imageViewMinZoomFactor = 0.1
+	imageViewMaxZoomFactor = 32.0
+	imageViewZoomStep      = 1.25
 )
 
 type ImageView struct {
 	*CustomWidget
-	image                  Image
-	imageChangedPublisher  EventPublisher
-	margin96dpi            int
-	marginChangedPublisher EventPublisher
-	mode                   ImageViewMode
+	image                      Image
+	imageChangedPublisher      EventPublisher
+	margin96dpi                int
+	marginChangedPublisher     EventPublisher
+	mode                       ImageViewMode
+	animation                  *AnimatedImage
+	animationTimer             *time.Timer
+	animationSeq               int
+	animationLoops             int
+	animationPlaying           bool
+	animationFinishedPublisher EventPublisher
+	interactive                bool
+	zoomFactor                 float64
+	zoomChangedPublisher       EventPublisher
+	panOffset                  Point
+	dragging                   bool
+	dragStart                  Point
+	dragStartPanOffset         Point
+	imageMouseDownPublisher    ImageMouseEventPublisher
+	interpolationMode          InterpolationMode
+	placeholderImage           Image
+	errorImage                 Image
+	loadedImage                Image
+	imageLoadSeq               int
+	imageLoadFailedPublisher   ErrorEventPublisher
+	fitMode                    ImageViewFitMode
+	keepAspectRatio            bool
+	overlay                    Image
+	overlayCorner              Corner
+	overlayMargin96dpi         int
+}
+
+// ImageLoad is a handle to an image being loaded by
+// ImageView.SetImageFromFileAsync.
+type ImageLoad struct {
+	canceled int32
+}
+
+// Cancel discards the result of the load. The ImageView keeps showing
+// whatever it shows at the time the load completes.
+//
+// Cancel can be called from any thread.
+func (l *ImageLoad) Cancel() {
+	atomic.StoreInt32(&l.canceled, 1)
+}
+
+// Canceled returns whether Cancel has been called.
+func (l *ImageLoad) Canceled() bool {
+	return atomic.LoadInt32(&l.canceled) != 0
 }
 
 func NewImageView(parent Container) (*ImageView, error) {
-	iv := new(ImageView)
+	iv := &ImageView{zoomFactor: 1.0}
 
 	cw, err := NewCustomWidget(parent, 0, func(canvas *Canvas, updateBounds Rectangle) error {
 		return iv.drawImage(canvas, updateBounds)
@@ -94,9 +170,38 @@ func NewImageView(parent Container) (*ImageView, error) {
 		},
 		iv.MarginChanged()))
 
+	iv.MustRegisterProperty("ZoomFactor", NewProperty(
+		func() interface{} {
+			return iv.ZoomFactor()
+		},
+		func(v interface{}) error {
+			return iv.SetZoomFactor(assertFloat64Or(v, 1.0))
+		},
+		iv.ZoomChanged()))
+
 	return iv, nil
 }
 
+// Dispose stops a running animation and releases the AnimatedImage, if one
+// is set, before disposing of the ImageView.
+func (iv *ImageView) Dispose() {
+	iv.stopAnimationTimer()
+
+	if iv.animation != nil {
+		iv.animation.Dispose()
+		iv.animation = nil
+	}
+
+	if iv.loadedImage != nil {
+		iv.loadedImage.Dispose()
+		iv.loadedImage = nil
+	}
+
+	iv.imageLoadSeq++
+
+	iv.CustomWidget.Dispose()
+}
+
 func (iv *ImageView) Mode() ImageViewMode {
 	return iv.mode
 }
@@ -108,11 +213,91 @@ func (iv *ImageView) SetMode(mode ImageViewMode) {
 
 	iv.mode = mode
 
+	iv.resetTransform()
+
 	iv.Invalidate()
 
 	iv.RequestLayout()
 }
 
+// Overlay returns the Image drawn on top of the image, if any.
+func (iv *ImageView) Overlay() Image {
+	return iv.overlay
+}
+
+// SetOverlay sets an Image, e.g. a status badge, that is drawn on top of the
+// image in the specified corner, margin 96dpi pixels away from the image
+// edges. Pass nil to remove the overlay.
+func (iv *ImageView) SetOverlay(image Image, corner Corner, margin int) error {
+	iv.overlay = image
+	iv.overlayCorner = corner
+	iv.overlayMargin96dpi = margin
+
+	return iv.Invalidate()
+}
+
+// FitMode returns how the scaling modes fit the image to the client area.
+func (iv *ImageView) FitMode() ImageViewFitMode {
+	return iv.fitMode
+}
+
+// SetFitMode sets how the scaling modes fit the image to the client area.
+func (iv *ImageView) SetFitMode(fitMode ImageViewFitMode) {
+	if fitMode == iv.fitMode {
+		return
+	}
+
+	iv.fitMode = fitMode
+
+	iv.Invalidate()
+}
+
+// KeepAspectRatio returns whether ImageViewFitFill preserves the aspect
+// ratio of the image.
+func (iv *ImageView) KeepAspectRatio() bool {
+	return iv.keepAspectRatio
+}
+
+// SetKeepAspectRatio sets whether ImageViewFitFill preserves the aspect ratio
+// of the image, filling the width of the client area.
+func (iv *ImageView) SetKeepAspectRatio(keep bool) {
+	if keep == iv.keepAspectRatio {
+		return
+	}
+
+	iv.keepAspectRatio = keep
+
+	iv.Invalidate()
+}
+
+func (iv *ImageView) effectiveFitMode() ImageViewFitMode {
+	if iv.fitMode != ImageViewFitDefault {
+		return iv.fitMode
+	}
+
+	if iv.mode == ImageViewModeStretch {
+		return ImageViewFitFill
+	}
+
+	return ImageViewFitContain
+}
+
+// InterpolationMode returns how the image is resampled when drawn stretched.
+func (iv *ImageView) InterpolationMode() InterpolationMode {
+	return iv.interpolationMode
+}
+
+// SetInterpolationMode sets how the image is resampled when drawn stretched.
+func (iv *ImageView) SetInterpolationMode(mode InterpolationMode) {
+	if mode == iv.interpolationMode {
+		return
+	}
+
+	iv.interpolationMode = mode
+
+	iv.Invalidate()
+}
+
 func (iv *ImageView) applyDPI(dpi int) {
 	iv.CustomWidget.ApplyDPI(dpi)
 
@@ -140,13 +325,38 @@ func (iv *ImageView) SetImage(image Image) error {
 
 	iv.image = image
 
+	if iv.loadedImage != nil && iv.loadedImage != image {
+		iv.loadedImage.Dispose()
+		iv.loadedImage = nil
+	}
+
+	iv.stopAnimationTimer()
+	iv.animationPlaying = false
+	iv.animationLoops = 0
+	if iv.animation != nil {
+		// We own the AnimatedImage, see SetAnimatedImage.
+		if old := Image(iv.animation); old != image && old != iv.placeholderImage && old != iv.errorImage {
+			iv.animation.Dispose()
+		}
+	}
+	if ai, ok := image.(*AnimatedImage); ok {
+		iv.animation = ai
+		ai.current = 0
+	} else {
+		iv.animation = nil
+	}
+
 	_, isMetafile := image.(*Metafile)
 	iv.SetClearsBackground(isMetafile)
 
 	err := iv.Invalidate()
 
-	if iv.mode == ImageViewModeIdeal && newSize != oldSize {
-		iv.RequestLayout()
+	if newSize != oldSize {
+		iv.resetTransform()
+
+		if iv.mode == ImageViewModeIdeal {
+			iv.RequestLayout()
+		}
 	}
 
 	iv.imageChangedPublisher.Publish()
@@ -158,6 +368,206 @@ func (iv *ImageView) ImageChanged() *Event {
 	return iv.imageChangedPublisher.Event()
 }
 
+// SetImageFromFileAsync decodes the image file at filePath on a separate
+// goroutine and displays it once it is ready.
+//
+// While loading, the PlaceholderImage is displayed, if one is set. If loading
+// fails, the ErrorImage is displayed, if one is set, and ImageLoadFailed is
+// published. Only the most recent call displays its result, earlier loads
+// still in progress are discarded.
+//
+// The loaded image is owned by the ImageView and disposed of when it is
+// replaced or the ImageView is disposed of.
+func (iv *ImageView) SetImageFromFileAsync(filePath string) *ImageLoad {
+	iv.imageLoadSeq++
+	seq := iv.imageLoadSeq
+
+	load := new(ImageLoad)
+
+	if iv.placeholderImage != nil {
+		iv.SetImage(iv.placeholderImage)
+	}
+
+	// Only the goroutine's own variables and group may be used off the UI
+	// thread, the state of the ImageView is checked once we are back on it.
+	group := iv.group
+
+	go func() {
+		img, err := NewImageFromFile(filePath)
+
+		defer group.wake()
+
+		group.Synchronize(func() {
+			if load.Canceled() || seq != iv.imageLoadSeq || iv.hWnd == 0 {
+				if err == nil {
+					img.Dispose()
+				}
+				return
+			}
+
+			if err != nil {
+				if iv.errorImage != nil {
+					iv.SetImage(iv.errorImage)
+				}
+
+				iv.imageLoadFailedPublisher.Publish(err)
+				return
+			}
+
+			iv.SetImage(img)
+			iv.loadedImage = img
+		})
+	}()
+
+	return load
+}
+
+// ImageLoadFailed returns the event that is published when
+// SetImageFromFileAsync fails to load an image.
+func (iv *ImageView) ImageLoadFailed() *ErrorEvent {
+	return iv.imageLoadFailedPublisher.Event()
+}
+
+// PlaceholderImage returns the Image displayed while SetImageFromFileAsync
+// is loading.
+func (iv *ImageView) PlaceholderImage() Image {
+	return iv.placeholderImage
+}
+
+// SetPlaceholderImage sets the Image displayed while SetImageFromFileAsync is
+// loading.
+func (iv *ImageView) SetPlaceholderImage(image Image) {
+	iv.placeholderImage = image
+}
+
+// ErrorImage returns the Image displayed when SetImageFromFileAsync fails.
+func (iv *ImageView) ErrorImage() Image {
+	return iv.errorImage
+}
+
+// SetErrorImage sets the Image displayed when SetImageFromFileAsync fails.
+func (iv *ImageView) SetErrorImage(image Image) {
+	iv.errorImage = image
+}
+
+// AnimatedImage returns the *AnimatedImage displayed by the ImageView, if
+// any.
+func (iv *ImageView) AnimatedImage() *AnimatedImage {
+	return iv.animation
+}
+
+// SetAnimatedImage sets the *AnimatedImage to display and starts playing it.
+//
+// The ImageView takes ownership of the AnimatedImage and disposes of it,
+// together with its decoded frames, when another image is set or the
+// ImageView is disposed of.
+func (iv *ImageView) SetAnimatedImage(ai *AnimatedImage) error {
+	var image Image
+	if ai != nil {
+		image = ai
+	}
+
+	if err := iv.SetImage(image); err != nil {
+		return err
+	}
+
+	iv.Play()
+
+	return nil
+}
+
+// Playing returns whether the animation is currently running.
+func (iv *ImageView) Playing() bool {
+	return iv.animationPlaying
+}
+
+// Play starts or resumes the animation of the current AnimatedImage.
+func (iv *ImageView) Play() {
+	if iv.animation == nil || iv.animationPlaying || iv.animation.FrameCount() < 2 {
+		return
+	}
+
+	iv.animationPlaying = true
+
+	iv.scheduleNextFrame()
+}
+
+// Pause halts the animation, keeping the current frame.
+func (iv *ImageView) Pause() {
+	iv.stopAnimationTimer()
+	iv.animationPlaying = false
+}
+
+// Stop halts the animation and rewinds it to the first frame.
+func (iv *ImageView) Stop() {
+	iv.Pause()
+
+	iv.animationLoops = 0
+
+	if iv.animation != nil && iv.animation.current != 0 {
+		iv.animation.current = 0
+		iv.Invalidate()
+	}
+}
+
+// AnimationFinished returns the event that is published when the animation
+// stops after having been played LoopCount times.
+func (iv *ImageView) AnimationFinished() *Event {
+	return iv.animationFinishedPublisher.Event()
+}
+
+func (iv *ImageView) scheduleNextFrame() {
+	iv.animationSeq++
+	seq := iv.animationSeq
+
+	group := iv.group
+
+	iv.animationTimer = time.AfterFunc(iv.animation.FrameDelay(iv.animation.current), func() {
+		defer group.wake()
+
+		group.Synchronize(func() {
+			if seq == iv.animationSeq && iv.hWnd != 0 {
+				iv.advanceAnimation()
+			}
+		})
+	})
+}
+
+func (iv *ImageView) advanceAnimation() {
+	if iv.animation == nil || !iv.animationPlaying {
+		return
+	}
+
+	next := iv.animation.current + 1
+	if next == iv.animation.FrameCount() {
+		iv.animationLoops++
+
+		if lc := iv.animation.LoopCount(); lc > 0 && iv.animationLoops >= lc {
+			iv.animationPlaying = false
+			iv.animationTimer = nil
+			iv.animationFinishedPublisher.Publish()
+			return
+		}
+
+		next = 0
+	}
+
+	iv.animation.current = next
+
+	iv.Invalidate()
+
+	iv.scheduleNextFrame()
+}
+
+func (iv *ImageView) stopAnimationTimer() {
+	iv.animationSeq++
+
+	if iv.animationTimer != nil {
+		iv.animationTimer.Stop()
+		iv.animationTimer = nil
+	}
+}
+
 func (iv *ImageView) Margin() int {
 	return iv.margin96dpi
 }
@@ -189,62 +599,432 @@ func (iv *ImageView) drawImage(canvas *Canvas, _ Rectangle) error {
 		return nil
 	}
 
+	cb := iv.contentBoundsPixels()
+	bounds := iv.imageBoundsPixels()
+
+	if err := canvas.SetInterpolationMode(iv.interpolationMode); err != nil {
+		return err
+	}
+
+	if iv.mode != ImageViewModeIdeal || iv.transformed() {
+		win.IntersectClipRect(canvas.hdc, int32(cb.X), int32(cb.Y), int32(cb.X+cb.Width), int32(cb.Y+cb.Height))
+	}
+
+	var err error
+
+	switch {
+	case iv.mode == ImageViewModeShrink, iv.mode == ImageViewModeZoom, iv.mode == ImageViewModeStretch, iv.transformed():
+		err = canvas.DrawImageStretched(iv.image, bounds.To96DPI(iv.DPI()))
+
+	case iv.mode == ImageViewModeTile:
+		err = iv.drawTiles(canvas, cb, bounds.Size())
+
+		// The overlay belongs to the tiled area as a whole.
+		bounds = cb
+
+	default:
+		err = canvas.DrawImage(iv.image, bounds.Location().To96DPI(iv.DPI()))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return iv.drawOverlay(canvas, bounds)
+}
+
+// drawOverlay draws the overlay image into the configured corner of
+// imageBounds.
+func (iv *ImageView) drawOverlay(canvas *Canvas, imageBounds Rectangle) error {
+	if iv.overlay == nil {
+		return nil
+	}
+
+	s := iv.SizeFrom96DPI(iv.overlay.Size())
+	margin := iv.IntFrom96DPI(iv.overlayMargin96dpi)
+
+	bounds := Rectangle{Width: s.Width, Height: s.Height}
+
+	switch iv.overlayCorner {
+	case CornerTopLeft, CornerBottomLeft:
+		bounds.X = imageBounds.X + margin
+
+	default:
+		bounds.X = imageBounds.X + imageBounds.Width - margin - s.Width
+	}
+
+	switch iv.overlayCorner {
+	case CornerTopLeft, CornerTopRight:
+		bounds.Y = imageBounds.Y + margin
+
+	default:
+		bounds.Y = imageBounds.Y + imageBounds.Height - margin - s.Height
+	}
+
+	return canvas.DrawImageStretched(iv.overlay, bounds.To96DPI(iv.DPI()))
+}
+
+// drawTiles repeats the image across cb, starting at its top left corner.
+func (iv *ImageView) drawTiles(canvas *Canvas, cb Rectangle, tileSize Size) error {
+	if tileSize.Width <= 0 || tileSize.Height <= 0 {
+		return nil
+	}
+
+	dpi := iv.DPI()
+
+	for y := cb.Y; y < cb.Y+cb.Height; y += tileSize.Height {
+		for x := cb.X; x < cb.X+cb.Width; x += tileSize.Width {
+			bounds := Rectangle{x, y, tileSize.Width, tileSize.Height}
+
+			if err := canvas.DrawImageStretched(iv.image, bounds.To96DPI(dpi)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// contentBoundsPixels returns the client bounds minus the margin.
+func (iv *ImageView) contentBoundsPixels() Rectangle {
 	cb := iv.ClientBoundsPixels()
 
 	margin := iv.IntFrom96DPI(iv.margin96dpi)
 
+	cb.X += margin
+	cb.Y += margin
 	cb.Width -= margin * 2
 	cb.Height -= margin * 2
 
+	return cb
+}
+
+// untransformedImageBoundsPixels returns the bounds the image is drawn to
+// according to the current mode, ignoring any zoom or pan applied
+// interactively.
+func (iv *ImageView) untransformedImageBoundsPixels() Rectangle {
+	cb := iv.contentBoundsPixels()
+
 	s := iv.SizeFrom96DPI(iv.image.Size())
 
+	var bounds Rectangle
+
 	switch iv.mode {
 	case ImageViewModeShrink, ImageViewModeZoom, ImageViewModeStretch:
-		var bounds Rectangle
-
-		if iv.mode == ImageViewModeStretch {
-			bounds.X = margin
-			bounds.Y = margin
-			bounds.Width = cb.Width
-			bounds.Height = cb.Height
-		} else {
-			var scale float64
-			if iv.mode == ImageViewModeZoom || s.Width > cb.Width || s.Height > cb.Height {
-				sx := float64(cb.Width) / float64(s.Width)
-				sy := float64(cb.Height) / float64(s.Height)
-
-				scale = math.Min(sx, sy)
-			} else {
-				scale = 1.0
+		sx := float64(cb.Width) / float64(s.Width)
+		sy := float64(cb.Height) / float64(s.Height)
+
+		var scale float64
+
+		switch iv.effectiveFitMode() {
+		case ImageViewFitFill:
+			if !iv.keepAspectRatio {
+				return cb
 			}
 
-			s = scaleSize(s, scale)
+			scale = sx
 
-			bounds.Width = s.Width
-			bounds.Height = s.Height
-			bounds.X = margin + (cb.Width-bounds.Width)/2
-			bounds.Y = margin + (cb.Height-bounds.Height)/2
+		case ImageViewFitContain:
+			scale = math.Min(sx, sy)
+
+		case ImageViewFitCover:
+			scale = math.Max(sx, sy)
 		}
 
-		return canvas.DrawImageStretched(iv.image, bounds.To96DPI(iv.DPI()))
+		if iv.mode == ImageViewModeShrink && scale > 1.0 {
+			scale = 1.0
+		}
 
-	case ImageViewModeCorner, ImageViewModeCenter:
-		win.IntersectClipRect(canvas.hdc, int32(margin), int32(margin), int32(cb.Width+margin), int32(cb.Height+margin))
-	}
+		s = scaleSize(s, scale)
 
-	var pos Point
+		bounds.Width = s.Width
+		bounds.Height = s.Height
+		bounds.X = cb.X + (cb.Width-bounds.Width)/2
+		bounds.Y = cb.Y + (cb.Height-bounds.Height)/2
 
-	switch iv.mode {
-	case ImageViewModeIdeal, ImageViewModeCorner:
-		pos.X = margin
-		pos.Y = margin
+	case ImageViewModeIdeal, ImageViewModeCorner, ImageViewModeTile:
+		bounds = Rectangle{cb.X, cb.Y, s.Width, s.Height}
 
 	case ImageViewModeCenter:
-		pos.X = margin + (cb.Width-s.Width)/2
-		pos.Y = margin + (cb.Height-s.Height)/2
+		bounds = Rectangle{cb.X + (cb.Width-s.Width)/2, cb.Y + (cb.Height-s.Height)/2, s.Width, s.Height}
+	}
+
+	return bounds
+}
+
+// imageBoundsPixels returns the bounds the image is drawn to, including any
+// zoom or pan applied interactively.
+func (iv *ImageView) imageBoundsPixels() Rectangle {
+	bounds := iv.untransformedImageBoundsPixels()
+
+	if !iv.transformable() {
+		return bounds
+	}
+
+	return Rectangle{
+		X:      bounds.X + iv.panOffset.X,
+		Y:      bounds.Y + iv.panOffset.Y,
+		Width:  int(float64(bounds.Width) * iv.zoomFactor),
+		Height: int(float64(bounds.Height) * iv.zoomFactor),
+	}
+}
+
+// transformable returns whether interactive zoom and pan apply to the current
+// mode.
+func (iv *ImageView) transformable() bool {
+	return iv.interactive && (iv.mode == ImageViewModeZoom || iv.mode == ImageViewModeIdeal)
+}
+
+// transformed returns whether an interactive zoom or pan is in effect.
+func (iv *ImageView) transformed() bool {
+	return iv.transformable() && (iv.zoomFactor != 1.0 || iv.panOffset != Point{})
+}
+
+// Interactive returns whether the mouse wheel zooms and dragging pans the
+// image in ImageViewModeZoom and ImageViewModeIdeal.
+func (iv *ImageView) Interactive() bool {
+	return iv.interactive
+}
+
+// SetInteractive sets whether the mouse wheel zooms and dragging pans the
+// image in ImageViewModeZoom and ImageViewModeIdeal.
+//
+// Disabling interactive mode resets the zoom and pan.
+func (iv *ImageView) SetInteractive(interactive bool) {
+	if interactive == iv.interactive {
+		return
+	}
+
+	iv.interactive = interactive
+
+	if !interactive {
+		iv.resetTransform()
+	}
+}
+
+// ZoomFactor returns the zoom factor applied on top of the current mode.
+//
+// A value of 1.0 means the image is drawn as the mode dictates.
+func (iv *ImageView) ZoomFactor() float64 {
+	return iv.zoomFactor
+}
+
+// SetZoomFactor sets the zoom factor applied on top of the current mode,
+// zooming around the center of the client area.
+func (iv *ImageView) SetZoomFactor(factor float64) error {
+	cb := iv.contentBoundsPixels()
+
+	return iv.zoomAround(factor, Point{cb.X + cb.Width/2, cb.Y + cb.Height/2})
+}
+
+// ZoomChanged returns the event that is published when the zoom factor
+// changed.
+func (iv *ImageView) ZoomChanged() *Event {
+	return iv.zoomChangedPublisher.Event()
+}
+
+func (iv *ImageView) zoomAround(factor float64, pivot Point) error {
+	factor = math.Max(imageViewMinZoomFactor, math.Min(imageViewMaxZoomFactor, factor))
+
+	if factor == iv.zoomFactor {
+		return nil
+	}
+
+	if iv.image != nil && iv.transformable() {
+		old := iv.imageBoundsPixels()
+
+		// Keep the image point under the pivot in place.
+		var ux, uy float64
+		if old.Width > 0 && old.Height > 0 {
+			ux = float64(pivot.X-old.X) / float64(old.Width)
+			uy = float64(pivot.Y-old.Y) / float64(old.Height)
+		}
+
+		base := iv.untransformedImageBoundsPixels()
+		width := float64(base.Width) * factor
+		height := float64(base.Height) * factor
+
+		iv.panOffset.X = pivot.X - int(ux*width) - base.X
+		iv.panOffset.Y = pivot.Y - int(uy*height) - base.Y
+	}
+
+	iv.zoomFactor = factor
+
+	iv.clampPanOffset()
+
+	err := iv.Invalidate()
+
+	iv.zoomChangedPublisher.Publish()
+
+	return err
+}
+
+// clampPanOffset keeps the transformed image from leaving the client area.
+//
+// An image larger than the client area can not be panned far enough to
+// reveal a gap, an image smaller than the client area stays entirely
+// visible.
+func (iv *ImageView) clampPanOffset() {
+	if iv.image == nil || !iv.transformable() {
+		return
+	}
+
+	cb := iv.contentBoundsPixels()
+	base := iv.untransformedImageBoundsPixels()
+	bounds := iv.imageBoundsPixels()
+
+	clamp := func(pos, size, min, extent int) int {
+		lo, hi := min, min+extent-size
+		if size > extent {
+			lo, hi = hi, lo
+		}
+
+		if pos < lo {
+			return lo
+		}
+		if pos > hi {
+			return hi
+		}
+		return pos
+	}
+
+	iv.panOffset.X = clamp(bounds.X, bounds.Width, cb.X, cb.Width) - base.X
+	iv.panOffset.Y = clamp(bounds.Y, bounds.Height, cb.Y, cb.Height) - base.Y
+}
+
+func (iv *ImageView) resetTransform() {
+	changed := iv.zoomFactor != 1.0
+
+	iv.zoomFactor = 1.0
+	iv.panOffset = Point{}
+	iv.dragging = false
+
+	iv.Invalidate()
+
+	if changed {
+		iv.zoomChangedPublisher.Publish()
+	}
+}
+
+// ImageMouseDown returns the event that is published when a mouse button is
+// pressed over the ImageView, carrying the position in image coordinates.
+func (iv *ImageView) ImageMouseDown() *ImageMouseEvent {
+	return iv.imageMouseDownPublisher.Event()
+}
+
+// imagePointFromPixels maps a point in native client pixels back to 96dpi
+// image coordinates. The returned bool reports whether the point lies within
+// the visible part of the image.
+func (iv *ImageView) imagePointFromPixels(p Point) (Point, bool) {
+	if iv.image == nil {
+		return Point{}, false
+	}
+
+	bounds := iv.imageBoundsPixels()
+	if bounds.Width <= 0 || bounds.Height <= 0 {
+		return Point{}, false
+	}
+
+	size := iv.image.Size()
+
+	if iv.mode == ImageViewModeTile {
+		// Every tile maps to the same image, so fold p into the first one.
+		p.X = bounds.X + (p.X-bounds.X)%bounds.Width
+		p.Y = bounds.Y + (p.Y-bounds.Y)%bounds.Height
+	}
+
+	imagePoint := Point{
+		int(math.Floor(float64(p.X-bounds.X) * float64(size.Width) / float64(bounds.Width))),
+		int(math.Floor(float64(p.Y-bounds.Y) * float64(size.Height) / float64(bounds.Height))),
+	}
+
+	cb := iv.contentBoundsPixels()
+
+	inImage := p.X >= bounds.X && p.X < bounds.X+bounds.Width &&
+		p.Y >= bounds.Y && p.Y < bounds.Y+bounds.Height &&
+		p.X >= cb.X && p.X < cb.X+cb.Width &&
+		p.Y >= cb.Y && p.Y < cb.Y+cb.Height
+
+	return imagePoint, inImage
+}
+
+func (iv *ImageView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_LBUTTONDOWN, win.WM_MBUTTONDOWN, win.WM_RBUTTONDOWN:
+		var button MouseButton
+		switch msg {
+		case win.WM_LBUTTONDOWN:
+			button = LeftButton
+
+		case win.WM_MBUTTONDOWN:
+			button = MiddleButton
+
+		case win.WM_RBUTTONDOWN:
+			button = RightButton
+		}
+
+		p := Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+		imagePoint, inImage := iv.imagePointFromPixels(p)
+
+		iv.imageMouseDownPublisher.Publish(imagePoint, inImage, button)
+	}
+
+	if iv.interactive && iv.image != nil && iv.transformable() {
+		switch msg {
+		case win.WM_MOUSEWHEEL:
+			p := win.POINT{X: win.GET_X_LPARAM(lParam), Y: win.GET_Y_LPARAM(lParam)}
+			if !win.ScreenToClient(iv.hWnd, &p) {
+				break
+			}
+
+			factor := iv.zoomFactor
+			if delta := int16(win.HIWORD(uint32(wParam))); delta < 0 {
+				factor /= imageViewZoomStep
+			} else {
+				factor *= imageViewZoomStep
+			}
+
+			iv.zoomAround(factor, Point{int(p.X), int(p.Y)})
+
+			return 0
+
+		case win.WM_LBUTTONDOWN:
+			// We keep receiving WM_MOUSEMOVE while dragging outside of the
+			// ImageView, until the button is released or the capture lost.
+			win.SetCapture(hwnd)
+
+			iv.dragging = true
+			iv.dragStart = Point{int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))}
+			iv.dragStartPanOffset = iv.panOffset
+
+		case win.WM_MOUSEMOVE:
+			if !iv.dragging {
+				break
+			}
+
+			x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+			iv.panOffset = Point{
+				iv.dragStartPanOffset.X + x - iv.dragStart.X,
+				iv.dragStartPanOffset.Y + y - iv.dragStart.Y,
+			}
+
+			iv.clampPanOffset()
+
+			iv.Invalidate()
+
+		case win.WM_LBUTTONUP:
+			if iv.dragging {
+				iv.dragging = false
+				win.ReleaseCapture()
+			}
+
+		case win.WM_CAPTURECHANGED:
+			iv.dragging = false
+		}
 	}
 
-	return canvas.DrawImage(iv.image, pos.To96DPI(iv.DPI()))
+	return iv.CustomWidget.WndProc(hwnd, msg, wParam, lParam)
 }
 
 func (iv *ImageView) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
@@ -226,7 +226,7 @@ func layoutTree(root ContainerLayoutItem, size Size, cancel chan struct{}, done
 				case <-cancel:
 					return
 
-				case results <- LayoutResult{container, items}:
+				case results <- LayoutResult{container: container, items: items}:
 				}
 
 				for _, item := range items {
@@ -294,17 +294,34 @@ func applyLayoutResults(results []LayoutResult, stopwatch *stopwatch) error {
 			continue
 		}
 
+		wnd := windowFromHandle(result.container.Handle())
+		if wnd == nil {
+			// The container has been disposed of since the layout was computed.
+			continue
+		}
+
 		hdwp := win.BeginDeferWindowPos(int32(len(result.items)))
 		if hdwp == 0 {
 			return lastError("BeginDeferWindowPos")
 		}
 
 		var maybeInvalidate bool
-		if wnd := windowFromHandle(result.container.Handle()); wnd != nil {
-			if ctr, ok := wnd.(Container); ok {
-				if cb := ctr.AsContainerBase(); cb != nil {
-					maybeInvalidate = cb.hasComplexBackground()
+		if ctr, ok := wnd.(Container); ok {
+			if cb := ctr.AsContainerBase(); cb != nil {
+				if !result.animationFrame {
+					if cb.animateNextLayout {
+						cb.animateNextLayout = false
+
+						if cb.startLayoutAnimation(result) {
+							win.EndDeferWindowPos(hdwp)
+							continue
+						}
+					} else {
+						cb.stopLayoutAnimation()
+					}
 				}
+
+				maybeInvalidate = cb.hasComplexBackground()
 			}
 		}
 
@@ -363,6 +380,12 @@ func applyLayoutResults(results []LayoutResult, stopwatch *stopwatch) error {
 		if !win.EndDeferWindowPos(hdwp) {
 			return lastError("EndDeferWindowPos")
 		}
+
+		if ctr, ok := wnd.(Container); ok && !result.animationFrame {
+			if cb := ctr.AsContainerBase(); cb != nil && cb.debugLayout {
+				cb.handleDebugLayoutApplied(stopwatch)
+			}
+		}
 	}
 
 	return nil
@@ -729,8 +752,9 @@ type Geometry struct {
 }
 
 type LayoutResult struct {
-	container ContainerLayoutItem
-	items     []LayoutResultItem
+	container      ContainerLayoutItem
+	items          []LayoutResultItem
+	animationFrame bool
 }
 
 type LayoutResultItem struct {
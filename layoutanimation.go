@@ -0,0 +1,144 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"time"
+
+	"github.com/lxn/win"
+)
+
+const (
+	// Distinct from the small ids widgets use for their own timers.
+	layoutAnimationTimerId       = 0x4C41
+	layoutAnimationTimerInterval = 15
+)
+
+// layoutAnimationState is the state of a running layout animation.
+type layoutAnimationState struct {
+	result LayoutResult
+	from   map[win.HWND]Rectangle
+	start  time.Time
+}
+
+// LayoutAnimationDuration returns how long the children of the container take
+// to move to their new bounds after a child was shown or hidden.
+func (cb *ContainerBase) LayoutAnimationDuration() time.Duration {
+	return cb.layoutAnimationDuration
+}
+
+// SetLayoutAnimationDuration sets how long the children of the container take
+// to move to their new bounds after a child was shown or hidden, e.g. when
+// expanding a detail panel. Pass 0 to move them at once, which is the
+// default.
+//
+// Only showing or hiding children is animated, resizing the container is not.
+// No animation takes place if animations are disabled, see AnimationsEnabled.
+func (cb *ContainerBase) SetLayoutAnimationDuration(duration time.Duration) {
+	cb.layoutAnimationDuration = duration
+
+	if duration <= 0 {
+		cb.animateNextLayout = false
+		cb.stopLayoutAnimation()
+	}
+}
+
+// requestLayoutAnimation makes the container animate the next layout applied
+// to it, if enabled.
+func (cb *ContainerBase) requestLayoutAnimation() {
+	if cb.layoutAnimationDuration > 0 && AnimationsEnabled() {
+		cb.animateNextLayout = true
+	}
+}
+
+// startLayoutAnimation moves the children from their current bounds to the
+// ones in result over the layout animation duration.
+func (cb *ContainerBase) startLayoutAnimation(result LayoutResult) bool {
+	cb.stopLayoutAnimation()
+
+	state := &layoutAnimationState{
+		result: result,
+		from:   make(map[win.HWND]Rectangle, len(result.items)),
+		start:  time.Now(),
+	}
+
+	for _, ri := range result.items {
+		hwnd := ri.Item.Handle()
+		if hwnd == 0 {
+			continue
+		}
+
+		if widget, ok := windowFromHandle(hwnd).(Widget); ok && widget.Visible() {
+			state.from[hwnd] = widget.BoundsPixels()
+		}
+	}
+
+	if 0 == win.SetTimer(cb.hWnd, layoutAnimationTimerId, layoutAnimationTimerInterval, 0) {
+		lastError("SetTimer")
+		return false
+	}
+
+	cb.layoutAnimation = state
+
+	return true
+}
+
+// stopLayoutAnimation stops a running layout animation, if any. The children
+// are left where they are, as a new layout is about to be applied.
+func (cb *ContainerBase) stopLayoutAnimation() {
+	if cb.layoutAnimation == nil {
+		return
+	}
+
+	win.KillTimer(cb.hWnd, layoutAnimationTimerId)
+
+	cb.layoutAnimation = nil
+}
+
+// frame returns the layout result for progress t, which ranges from 0 to 1.
+func (state *layoutAnimationState) frame(t float64) LayoutResult {
+	// Ease out, so the children slow down when approaching their new bounds.
+	t = 1 - (1-t)*(1-t)
+
+	interpolate := func(from, to int) int {
+		return from + int(float64(to-from)*t)
+	}
+
+	items := make([]LayoutResultItem, len(state.result.items))
+
+	for i, ri := range state.result.items {
+		if from, ok := state.from[ri.Item.Handle()]; ok {
+			ri.Bounds = Rectangle{
+				interpolate(from.X, ri.Bounds.X),
+				interpolate(from.Y, ri.Bounds.Y),
+				interpolate(from.Width, ri.Bounds.Width),
+				interpolate(from.Height, ri.Bounds.Height),
+			}
+		}
+
+		items[i] = ri
+	}
+
+	return LayoutResult{container: state.result.container, items: items, animationFrame: true}
+}
+
+func (cb *ContainerBase) handleLayoutAnimationTimer() {
+	state := cb.layoutAnimation
+	if state == nil {
+		win.KillTimer(cb.hWnd, layoutAnimationTimerId)
+		return
+	}
+
+	t := float64(time.Since(state.start)) / float64(cb.layoutAnimationDuration)
+	if t >= 1 {
+		cb.stopLayoutAnimation()
+
+		t = 1
+	}
+
+	applyLayoutResults([]LayoutResult{state.frame(t)}, nil)
+}
@@ -0,0 +1,219 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/lxn/win"
+)
+
+var (
+	layoutDebugMarginsColor = RGB(0, 120, 215)
+	layoutDebugCellColor    = RGB(232, 17, 35)
+)
+
+// DebugLayout returns if the container draws layout debugging information.
+func (cb *ContainerBase) DebugLayout() bool {
+	return cb.debugLayout
+}
+
+// SetDebugLayout sets if the container draws layout debugging information, to
+// help diagnosing why widgets end up with unexpected sizes.
+//
+// The container then outlines the area inside the margins of its layout and
+// the bounds of its children, labeled with their stretch factors if not 1.
+// Whenever a layout is applied to it, the durations measured for the layouts
+// of its Form are logged.
+func (cb *ContainerBase) SetDebugLayout(debug bool) {
+	if debug == cb.debugLayout {
+		return
+	}
+
+	cb.debugLayout = debug
+	cb.debugLayoutBounds = nil
+
+	if debug {
+		if form := cb.Form(); form != nil {
+			if fb := form.AsFormBase(); fb.stopwatch == nil {
+				fb.setStopwatch(newStopwatch())
+			}
+		}
+	}
+
+	cb.Invalidate()
+}
+
+// handleDebugLayoutApplied is called after a layout was applied to the
+// container while layout debugging is on.
+func (cb *ContainerBase) handleDebugLayoutApplied(stopwatch *stopwatch) {
+	cb.invalidateChangedLayoutDebugBounds()
+
+	log.Printf("walk: layout applied to %q (%T)", cb.Name(), cb.window)
+
+	if stopwatch != nil {
+		stopwatch.Print()
+	}
+}
+
+// invalidateChangedLayoutDebugBounds invalidates the union of the old and new
+// overlay bounds of the items whose bounds changed since the last layout.
+func (cb *ContainerBase) invalidateChangedLayoutDebugBounds() {
+	bounds := cb.layoutDebugBounds()
+
+	var dirty Rectangle
+
+	for wb, b := range bounds {
+		if old, ok := cb.debugLayoutBounds[wb]; !ok {
+			dirty = unionRectangles(dirty, b)
+		} else if old != b {
+			dirty = unionRectangles(dirty, unionRectangles(old, b))
+		}
+	}
+	for wb, old := range cb.debugLayoutBounds {
+		if _, ok := bounds[wb]; !ok {
+			dirty = unionRectangles(dirty, old)
+		}
+	}
+
+	cb.debugLayoutBounds = bounds
+
+	if dirty.Width > 0 && dirty.Height > 0 {
+		rc := dirty.toRECT()
+		win.InvalidateRect(cb.hWnd, &rc, true)
+	}
+}
+
+// layoutDebugBounds returns the areas the overlay draws to, keyed by child.
+// The area inside the margins of the layout is stored under the nil key.
+func (cb *ContainerBase) layoutDebugBounds() map[*WidgetBase]Rectangle {
+	bounds := make(map[*WidgetBase]Rectangle)
+
+	if inner, ok := cb.layoutDebugMarginsBounds(); ok {
+		bounds[nil] = Rectangle{inner.X - 1, inner.Y - 1, inner.Width + 2, inner.Height + 2}
+	}
+
+	labelHeight := cb.IntFrom96DPI(14)
+
+	for _, wb := range cb.children.items {
+		if !wb.window.(Widget).Visible() {
+			continue
+		}
+
+		b := wb.window.(Widget).BoundsPixels()
+
+		bounds[wb] = Rectangle{b.X - 1, b.Y - labelHeight - 1, b.Width + 2, b.Height + labelHeight + 2}
+	}
+
+	return bounds
+}
+
+func (cb *ContainerBase) layoutDebugMarginsBounds() (Rectangle, bool) {
+	if cb.layout == nil {
+		return Rectangle{}, false
+	}
+
+	lb := cb.layout.asLayoutBase()
+	if lb == nil {
+		return Rectangle{}, false
+	}
+
+	cr := cb.ClientBoundsPixels()
+	m := lb.margins
+
+	return Rectangle{
+		cr.X + m.HNear,
+		cr.Y + m.VNear,
+		cr.Width - m.HNear - m.HFar,
+		cr.Height - m.VNear - m.VFar,
+	}, true
+}
+
+func unionRectangles(a, b Rectangle) Rectangle {
+	if a.Width <= 0 || a.Height <= 0 {
+		return b
+	}
+	if b.Width <= 0 || b.Height <= 0 {
+		return a
+	}
+
+	left, top := a.X, a.Y
+	if b.X < left {
+		left = b.X
+	}
+	if b.Y < top {
+		top = b.Y
+	}
+
+	right, bottom := a.Right(), a.Bottom()
+	if b.Right() > right {
+		right = b.Right()
+	}
+	if b.Bottom() > bottom {
+		bottom = b.Bottom()
+	}
+
+	return Rectangle{left, top, right - left + 1, bottom - top + 1}
+}
+
+func (cb *ContainerBase) drawLayoutDebugOverlay(canvas *Canvas) error {
+	marginsPen, err := NewCosmeticPen(PenDot, layoutDebugMarginsColor)
+	if err != nil {
+		return err
+	}
+	defer marginsPen.Dispose()
+
+	cellPen, err := NewCosmeticPen(PenSolid, layoutDebugCellColor)
+	if err != nil {
+		return err
+	}
+	defer cellPen.Dispose()
+
+	if inner, ok := cb.layoutDebugMarginsBounds(); ok {
+		if err := canvas.rectanglePixels(NullBrush(), marginsPen, inner, 0); err != nil {
+			return err
+		}
+	}
+
+	type StretchFactorer interface {
+		StretchFactor(widget Widget) int
+	}
+
+	sfer, _ := cb.layout.(StretchFactorer)
+	dpi := cb.DPI()
+
+	for _, wb := range cb.children.items {
+		widget := wb.window.(Widget)
+		if !widget.Visible() {
+			continue
+		}
+
+		b := widget.BoundsPixels()
+
+		// The children are clipped, so outline them just outside.
+		outline := Rectangle{b.X - 1, b.Y - 1, b.Width + 2, b.Height + 2}
+
+		if err := canvas.rectanglePixels(NullBrush(), cellPen, outline, 0); err != nil {
+			return err
+		}
+
+		if sfer == nil {
+			continue
+		}
+
+		if factor := sfer.StretchFactor(widget); factor != 1 {
+			bounds := Rectangle{b.X, b.Y - cb.IntFrom96DPI(14), b.Width, cb.IntFrom96DPI(14)}.To96DPI(dpi)
+
+			if err := canvas.DrawText(fmt.Sprintf("×%d", factor), cb.Font(), layoutDebugCellColor, bounds, TextRight|TextSingleLine|TextVCenter); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -36,6 +36,13 @@ type LineEdit struct {
 	charWidthFont            *Font
 	charWidth                int
 	textColor                Color
+	mask                     string
+	maskSlots                []lineEditMaskSlot
+	maskPlaceholder          rune
+	maskWasComplete          bool
+	maskCompletedPublisher   EventPublisher
+	undoer                   editUndoer
+	errorMessage             string
 }
 
 func newLineEdit(parent Window) (*LineEdit, error) {
@@ -132,6 +139,13 @@ func (le *LineEdit) Text() string {
 }
 
 func (le *LineEdit) SetText(value string) error {
+	if le.maskSlots != nil {
+		le.clearMask(0, len(le.maskSlots))
+		le.fillMask(0, value)
+
+		return le.updateMaskedText(0)
+	}
+
 	return le.setText(value)
 }
 
@@ -284,6 +298,38 @@ func (le *LineEdit) EditingFinished() *Event {
 	return le.editingFinishedPublisher.Event()
 }
 
+// CanUndo returns if the last change to the text can be undone.
+func (le *LineEdit) CanUndo() bool {
+	return le.undoer.canUndo(le)
+}
+
+// CanRedo returns if the last undone change can be redone.
+func (le *LineEdit) CanRedo() bool {
+	return le.undoer.canRedo(le)
+}
+
+// Undo undoes the last change to the text, if possible.
+func (le *LineEdit) Undo() error {
+	return le.undoer.undo(le)
+}
+
+// Redo redoes the last undone change, if possible.
+func (le *LineEdit) Redo() error {
+	return le.undoer.redo(le)
+}
+
+// ClearUndo empties the undo buffer, so neither Undo nor Redo are possible
+// until the text changes again.
+func (le *LineEdit) ClearUndo() {
+	le.undoer.clear(le)
+}
+
+// UndoStateChanged returns the event that is published when the results of
+// CanUndo or CanRedo may have changed.
+func (le *LineEdit) UndoStateChanged() *Event {
+	return le.undoer.stateChangedPublisher.Event()
+}
+
 func (le *LineEdit) TextChanged() *Event {
 	return le.textChangedPublisher.Event()
 }
@@ -298,15 +344,25 @@ func (le *LineEdit) SetTextColor(c Color) {
 	le.Invalidate()
 }
 
+func (le *LineEdit) applyDarkMode(dark bool) {
+	setDarkModeTheme(le.hWnd, dark, "DarkMode_CFD", "")
+}
+
 func (*LineEdit) NeedsWmSize() bool {
 	return true
 }
 
 func (le *LineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if le.handleMaskMessage(msg, wParam) || le.undoer.handleMessage(le, msg, wParam) {
+		return 0
+	}
+
 	switch msg {
 	case win.WM_COMMAND:
 		switch win.HIWORD(uint32(wParam)) {
 		case win.EN_CHANGE:
+			le.undoer.textChanged()
+			le.validate(false)
 			le.textChangedPublisher.Publish()
 		}
 
@@ -333,12 +389,14 @@ func (le *LineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 			}
 
 		case KeyReturn:
+			le.validate(true)
 			le.editingFinishedPublisher.Publish()
 		}
 
 	case win.WM_KILLFOCUS:
 		// FIXME: This may be dangerous, see remarks section:
 		// http://msdn.microsoft.com/en-us/library/ms646282(v=vs.85).aspx
+		le.validate(true)
 		le.editingFinishedPublisher.Publish()
 	}
 
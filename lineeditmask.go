@@ -0,0 +1,308 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unicode"
+
+	"github.com/lxn/win"
+)
+
+const defaultLineEditMaskPlaceholder = '_'
+
+// lineEditMaskSlot is a single position of a LineEdit input mask. Either
+// literal is set, or kind is one of the mask characters and value holds the
+// character entered by the user, if any.
+type lineEditMaskSlot struct {
+	literal rune
+	kind    rune
+	value   rune
+}
+
+func (s *lineEditMaskSlot) accepts(r rune) bool {
+	switch s.kind {
+	case '0':
+		return unicode.IsDigit(r)
+
+	case 'A':
+		return unicode.IsLetter(r)
+
+	case 'a':
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+
+	case '*':
+		return unicode.IsPrint(r)
+	}
+
+	return false
+}
+
+func parseLineEditMask(mask string) []lineEditMaskSlot {
+	var slots []lineEditMaskSlot
+
+	runes := []rune(mask)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '0', 'A', 'a', '*':
+			slots = append(slots, lineEditMaskSlot{kind: r})
+
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			slots = append(slots, lineEditMaskSlot{literal: runes[i]})
+
+		default:
+			slots = append(slots, lineEditMaskSlot{literal: r})
+		}
+	}
+
+	return slots
+}
+
+// Mask returns the input mask of the LineEdit, if any.
+func (le *LineEdit) Mask() string {
+	return le.mask
+}
+
+// SetMask sets an input mask that restricts and formats what the user can
+// enter, character by character.
+//
+// In the mask, 0 stands for a digit, A for a letter, a for a letter or digit
+// and * for any printable character. All other characters are literals that
+// are displayed as is, a backslash makes the next character a literal. For
+// example, "00/00/0000" accepts a date and "AA-000" two letters followed by a
+// dash and three digits. Positions that have not been filled yet show
+// MaskPlaceholder.
+//
+// The current text is reapplied to the new mask. An empty mask removes any
+// restriction.
+func (le *LineEdit) SetMask(mask string) error {
+	value := le.Text()
+	if le.maskSlots != nil {
+		value = le.MaskedValue()
+	}
+
+	le.mask = mask
+	le.maskSlots = parseLineEditMask(mask)
+
+	if le.maskSlots == nil {
+		return le.setText(value)
+	}
+
+	le.fillMask(0, value)
+
+	return le.updateMaskedText(0)
+}
+
+// MaskPlaceholder returns the character that is displayed for positions of the
+// input mask that have not been filled yet.
+func (le *LineEdit) MaskPlaceholder() rune {
+	if le.maskPlaceholder == 0 {
+		return defaultLineEditMaskPlaceholder
+	}
+
+	return le.maskPlaceholder
+}
+
+// SetMaskPlaceholder sets the character that is displayed for positions of the
+// input mask that have not been filled yet. The default is '_'.
+func (le *LineEdit) SetMaskPlaceholder(placeholder rune) error {
+	le.maskPlaceholder = placeholder
+
+	if le.maskSlots == nil {
+		return nil
+	}
+
+	start, _ := le.TextSelection()
+
+	return le.updateMaskedText(start)
+}
+
+// MaskedValue returns the characters entered into the input mask, without any
+// literals and placeholders. Without a mask, it returns the text.
+func (le *LineEdit) MaskedValue() string {
+	if le.maskSlots == nil {
+		return le.Text()
+	}
+
+	var value []rune
+	for _, slot := range le.maskSlots {
+		if slot.value != 0 {
+			value = append(value, slot.value)
+		}
+	}
+
+	return string(value)
+}
+
+// MaskCompleted returns the event that is published when the user has filled
+// all positions of the input mask.
+func (le *LineEdit) MaskCompleted() *Event {
+	return le.maskCompletedPublisher.Event()
+}
+
+func (le *LineEdit) maskComplete() bool {
+	for _, slot := range le.maskSlots {
+		if slot.kind != 0 && slot.value == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// insertIntoMask enters r at the first position at or after pos that can
+// take it. Literals are skipped, unless r matches one of them. It returns the
+// position after r and if r was accepted.
+func (le *LineEdit) insertIntoMask(pos int, r rune) (int, bool) {
+	for pos < len(le.maskSlots) && le.maskSlots[pos].kind == 0 && le.maskSlots[pos].literal != r {
+		pos++
+	}
+	if pos >= len(le.maskSlots) {
+		return pos, false
+	}
+
+	if slot := &le.maskSlots[pos]; slot.kind == 0 {
+		return pos + 1, true
+	} else if slot.accepts(r) {
+		slot.value = r
+		return pos + 1, true
+	}
+
+	return pos, false
+}
+
+// fillMask enters the characters of value into the mask, starting at pos, so
+// both raw and formatted values are accepted. Characters that don't fit are
+// dropped. It returns the position after the last character entered.
+func (le *LineEdit) fillMask(pos int, value string) int {
+	for _, r := range value {
+		if next, ok := le.insertIntoMask(pos, r); ok {
+			pos = next
+		}
+	}
+
+	return pos
+}
+
+func (le *LineEdit) clearMask(start, end int) {
+	for i := start; i < end && i < len(le.maskSlots); i++ {
+		le.maskSlots[i].value = 0
+	}
+}
+
+func (le *LineEdit) updateMaskedText(caret int) error {
+	wasComplete := le.maskWasComplete
+
+	text := make([]rune, len(le.maskSlots))
+	for i, slot := range le.maskSlots {
+		switch {
+		case slot.kind == 0:
+			text[i] = slot.literal
+
+		case slot.value != 0:
+			text[i] = slot.value
+
+		default:
+			text[i] = le.MaskPlaceholder()
+		}
+	}
+
+	if err := le.setText(string(text)); err != nil {
+		return err
+	}
+
+	le.SetTextSelection(caret, caret)
+
+	le.maskWasComplete = le.maskComplete()
+	if le.maskWasComplete && !wasComplete {
+		le.maskCompletedPublisher.Publish()
+	}
+
+	return nil
+}
+
+// handleMaskMessage processes input while a mask is set and reports if msg
+// was consumed.
+func (le *LineEdit) handleMaskMessage(msg uint32, wParam uintptr) bool {
+	if le.maskSlots == nil || le.ReadOnly() {
+		return false
+	}
+
+	start, end := le.TextSelection()
+
+	switch msg {
+	case win.WM_CHAR:
+		r := rune(wParam)
+
+		switch {
+		case r == '\b':
+			if start == end {
+				for start > 0 {
+					start--
+					if le.maskSlots[start].kind != 0 {
+						break
+					}
+				}
+			}
+			le.clearMask(start, end)
+			le.updateMaskedText(start)
+
+		case unicode.IsPrint(r):
+			le.clearMask(start, end)
+			if pos, ok := le.insertIntoMask(start, r); ok {
+				start = pos
+			} else {
+				win.MessageBeep(win.MB_OK)
+			}
+			le.updateMaskedText(start)
+
+		default:
+			// Let shortcuts like Ctrl+C pass.
+			return false
+		}
+
+		return true
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) != KeyDelete {
+			return false
+		}
+
+		if start == end {
+			end = start + 1
+		}
+		le.clearMask(start, end)
+		le.updateMaskedText(start)
+
+		return true
+
+	case win.WM_CUT, win.WM_CLEAR:
+		if msg == win.WM_CUT && start < end {
+			Clipboard().SetText(string([]rune(le.Text())[start:end]))
+		}
+
+		le.clearMask(start, end)
+		le.updateMaskedText(start)
+
+		return true
+
+	case win.WM_PASTE:
+		text, err := Clipboard().Text()
+		if err != nil {
+			return true
+		}
+
+		le.clearMask(start, end)
+		le.updateMaskedText(le.fillMask(start, text))
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,152 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// Validator returns the Validator of the Text property of the LineEdit.
+func (le *LineEdit) Validator() Validator {
+	return le.Property("Text").Validator()
+}
+
+// SetValidator sets the Validator of the Text property of the LineEdit.
+//
+// While the text is invalid, the LineEdit is decorated with
+// ValidationErrorEffect, or a red glow if that is nil, and once the user
+// finished editing, an error balloon is shown next to it. The decoration is
+// removed as soon as the text becomes valid. Since the Validator belongs to
+// the Text property, a DataBinder the LineEdit is bound to takes it into
+// account for CanSubmit.
+func (le *LineEdit) SetValidator(validator Validator) error {
+	if err := le.Property("Text").SetValidator(validator); err != nil {
+		return err
+	}
+
+	le.validate(false)
+
+	return nil
+}
+
+// ErrorMessage returns the message that is shown when validation fails,
+// instead of the one provided by the Validator.
+func (le *LineEdit) ErrorMessage() string {
+	return le.errorMessage
+}
+
+// SetErrorMessage sets the message that is shown when validation fails,
+// instead of the one provided by the Validator. An empty message restores the
+// default.
+func (le *LineEdit) SetErrorMessage(message string) {
+	le.errorMessage = message
+}
+
+// ShowError decorates the LineEdit as invalid and shows message in an error
+// balloon next to it.
+func (le *LineEdit) ShowError(message string) {
+	le.setErrorDecoration(true)
+
+	title := syscall.StringToUTF16Ptr(tr("Invalid Input"))
+	text := syscall.StringToUTF16Ptr(message)
+
+	ebt := editBalloonTip{
+		pszTitle: title,
+		pszText:  text,
+		ttiIcon:  win.TTI_ERROR,
+	}
+	ebt.cbStruct = uint32(unsafe.Sizeof(ebt))
+
+	le.SendMessage(emShowBalloonTip, 0, uintptr(unsafe.Pointer(&ebt)))
+}
+
+// ClearError removes the decoration and balloon shown by ShowError.
+func (le *LineEdit) ClearError() {
+	le.setErrorDecoration(false)
+
+	le.SendMessage(emHideBalloonTip, 0, 0)
+}
+
+// defaultValidationErrorEffect is the red glow LineEdit uses to decorate
+// invalid text, if the application did not set up ValidationErrorEffect.
+var defaultValidationErrorEffect WidgetGraphicsEffect
+
+// validationErrorEffect returns the effect LineEdit decorates invalid text
+// with, or nil if there is none.
+func validationErrorEffect() WidgetGraphicsEffect {
+	if ValidationErrorEffect != nil {
+		return ValidationErrorEffect
+	}
+
+	if defaultValidationErrorEffect == nil {
+		effect, err := NewBorderGlowEffect(RGB(255, 0, 0))
+		if err != nil {
+			return nil
+		}
+		defaultValidationErrorEffect = effect
+	}
+
+	return defaultValidationErrorEffect
+}
+
+// setErrorDecoration adds or removes the effect returned by
+// validationErrorEffect.
+func (le *LineEdit) setErrorDecoration(invalid bool) {
+	effects := le.GraphicsEffects()
+
+	if !invalid {
+		for _, effect := range [...]WidgetGraphicsEffect{ValidationErrorEffect, defaultValidationErrorEffect} {
+			if effect != nil {
+				effects.Remove(effect)
+			}
+		}
+		return
+	}
+
+	effect := validationErrorEffect()
+	if effect == nil {
+		return
+	}
+
+	if !effects.Contains(effect) {
+		effects.Add(effect)
+	}
+}
+
+// validate checks the text against the Validator, if any, and updates the
+// error decoration. With showBalloon, the error message is shown as well.
+func (le *LineEdit) validate(showBalloon bool) {
+	validator := le.Validator()
+	if validator == nil {
+		return
+	}
+
+	err := validator.Validate(le.Text())
+	if err == nil {
+		le.ClearError()
+		return
+	}
+
+	if !showBalloon {
+		le.setErrorDecoration(true)
+		return
+	}
+
+	message := le.errorMessage
+	if message == "" {
+		if ve, ok := err.(*ValidationError); ok {
+			message = ve.Message()
+		} else {
+			message = err.Error()
+		}
+	}
+
+	le.ShowError(message)
+}
@@ -120,6 +120,10 @@ func (lb *ListBox) ItemStyler() ListItemStyler {
 
 func (lb *ListBox) SetItemStyler(styler ListItemStyler) {
 	lb.styler = styler
+
+	if s, ok := styler.(interface{ setListBox(lb *ListBox) }); ok {
+		s.setListBox(lb)
+	}
 }
 
 func (lb *ListBox) ApplySysColors() {
@@ -24,10 +24,17 @@ func init() {
 
 type MainWindow struct {
 	FormBase
-	windowPlacement *win.WINDOWPLACEMENT
-	menu            *Menu
-	toolBar         *ToolBar
-	statusBar       *StatusBar
+	windowPlacement             *win.WINDOWPLACEMENT
+	menu                        *Menu
+	toolBar                     *ToolBar
+	statusBar                   *StatusBar
+	trayIcon                    *NotifyIcon
+	trayIconDoubleClickedHandle int
+	trayIconShownByHide         bool
+	hiddenToTray                bool
+	hiddenToTrayPublisher       EventPublisher
+	restoredFromTrayPublisher   EventPublisher
+	taskbarOwner                win.HWND
 }
 
 func NewMainWindow() (*MainWindow, error) {
@@ -216,6 +223,10 @@ func (mw *MainWindow) SetFullscreen(fullscreen bool) error {
 }
 
 func (mw *MainWindow) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if mw.handleTrayMessage(msg, wParam) {
+		return 0
+	}
+
 	switch msg {
 	case win.WM_WINDOWPOSCHANGED:
 		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
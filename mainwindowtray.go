@@ -0,0 +1,195 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+
+	"github.com/lxn/win"
+)
+
+// MinimizeToTray returns the NotifyIcon the MainWindow is hidden to when it
+// gets minimized, or nil.
+func (mw *MainWindow) MinimizeToTray() *NotifyIcon {
+	return mw.trayIcon
+}
+
+// SetMinimizeToTray sets the NotifyIcon the MainWindow is hidden to when it
+// gets minimized. Pass nil to minimize normally again.
+//
+// While the MainWindow is hidden, the NotifyIcon is visible. Double clicking
+// it restores the MainWindow.
+func (mw *MainWindow) SetMinimizeToTray(notifyIcon *NotifyIcon) {
+	if notifyIcon == mw.trayIcon {
+		return
+	}
+
+	if mw.trayIcon != nil {
+		mw.trayIcon.DoubleClicked().Detach(mw.trayIconDoubleClickedHandle)
+	}
+
+	mw.trayIcon = notifyIcon
+
+	if notifyIcon != nil {
+		mw.trayIconDoubleClickedHandle = notifyIcon.DoubleClicked().Attach(func() {
+			mw.RestoreFromTray()
+		})
+	}
+}
+
+// HideToTray hides the MainWindow and shows the NotifyIcon set via
+// SetMinimizeToTray.
+func (mw *MainWindow) HideToTray() error {
+	if mw.trayIcon == nil {
+		return newError("no NotifyIcon set via SetMinimizeToTray")
+	}
+
+	if mw.hiddenToTray {
+		return nil
+	}
+
+	if !mw.trayIcon.Visible() {
+		if err := mw.trayIcon.SetVisible(true); err != nil {
+			return err
+		}
+
+		mw.trayIconShownByHide = true
+	}
+
+	win.ShowWindow(mw.hWnd, win.SW_HIDE)
+
+	mw.hiddenToTray = true
+
+	mw.hiddenToTrayPublisher.Publish()
+
+	return nil
+}
+
+// RestoreFromTray shows and activates the MainWindow after it was hidden via
+// HideToTray.
+func (mw *MainWindow) RestoreFromTray() error {
+	if !mw.hiddenToTray {
+		return nil
+	}
+
+	mw.hiddenToTray = false
+
+	if win.IsIconic(mw.hWnd) {
+		win.ShowWindow(mw.hWnd, win.SW_RESTORE)
+	} else {
+		win.ShowWindow(mw.hWnd, win.SW_SHOW)
+	}
+	win.SetForegroundWindow(mw.hWnd)
+
+	if mw.trayIconShownByHide && mw.trayIcon != nil {
+		mw.trayIconShownByHide = false
+
+		if err := mw.trayIcon.SetVisible(false); err != nil {
+			return err
+		}
+	}
+
+	mw.restoredFromTrayPublisher.Publish()
+
+	return nil
+}
+
+// HiddenToTray returns the event that is published when the MainWindow was
+// hidden to the tray.
+func (mw *MainWindow) HiddenToTray() *Event {
+	return mw.hiddenToTrayPublisher.Event()
+}
+
+// RestoredFromTray returns the event that is published when the MainWindow
+// was restored from the tray.
+func (mw *MainWindow) RestoredFromTray() *Event {
+	return mw.restoredFromTrayPublisher.Event()
+}
+
+// ShowInTaskbar returns if the MainWindow has a taskbar button.
+func (mw *MainWindow) ShowInTaskbar() bool {
+	return mw.taskbarOwner == 0
+}
+
+// SetShowInTaskbar sets if the MainWindow has a taskbar button.
+//
+// The taskbar button is removed by making the MainWindow owned by a hidden
+// window, so its appearance does not change.
+func (mw *MainWindow) SetShowInTaskbar(show bool) error {
+	if show == mw.ShowInTaskbar() {
+		return nil
+	}
+
+	var owner win.HWND
+	if !show {
+		owner = win.CreateWindowEx(
+			0,
+			syscall.StringToUTF16Ptr("STATIC"),
+			nil,
+			win.WS_POPUP,
+			0,
+			0,
+			0,
+			0,
+			0,
+			0,
+			0,
+			nil)
+		if owner == 0 {
+			return lastError("CreateWindowEx")
+		}
+	}
+
+	// The taskbar only notices the change while the window is hidden.
+	visible := win.IsWindowVisible(mw.hWnd)
+	if visible {
+		win.ShowWindow(mw.hWnd, win.SW_HIDE)
+	}
+
+	if err := mw.ensureExtendedStyleBits(win.WS_EX_APPWINDOW, false); err != nil {
+		return err
+	}
+
+	win.SetWindowLongPtr(mw.hWnd, win.GWLP_HWNDPARENT, uintptr(owner))
+
+	if mw.taskbarOwner != 0 {
+		win.DestroyWindow(mw.taskbarOwner)
+	}
+	mw.taskbarOwner = owner
+
+	if visible {
+		win.ShowWindow(mw.hWnd, win.SW_SHOW)
+	}
+
+	return nil
+}
+
+// handleTrayMessage handles the messages that implement minimizing to the
+// tray.
+func (mw *MainWindow) handleTrayMessage(msg uint32, wParam uintptr) (handled bool) {
+	switch msg {
+	case win.WM_SYSCOMMAND:
+		if wParam&0xFFF0 == win.SC_MINIMIZE && mw.trayIcon != nil {
+			mw.HideToTray()
+			return true
+		}
+
+	case win.WM_SIZE:
+		if wParam == sizeMinimized && mw.trayIcon != nil {
+			mw.HideToTray()
+		}
+
+	case win.WM_NCDESTROY:
+		if mw.taskbarOwner != 0 {
+			// Only now, because destroying the owner destroys the window.
+			win.DestroyWindow(mw.taskbarOwner)
+			mw.taskbarOwner = 0
+		}
+	}
+
+	return false
+}
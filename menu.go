@@ -15,12 +15,15 @@ import (
 )
 
 type Menu struct {
-	hMenu   win.HMENU
-	window  Window
-	actions *ActionList
-	getDPI  func() int
+	hMenu                win.HMENU
+	window               Window
+	actions              *ActionList
+	getDPI               func() int
+	beforePopupPublisher EventPublisher
 }
 
+var menusByHandle = make(map[win.HMENU]*Menu)
+
 func newMenuBar(window Window) (*Menu, error) {
 	hMenu := win.CreateMenu()
 	if hMenu == 0 {
@@ -33,6 +36,8 @@ func newMenuBar(window Window) (*Menu, error) {
 	}
 	m.actions = newActionList(m)
 
+	menusByHandle[hMenu] = m
+
 	return m, nil
 }
 
@@ -61,6 +66,8 @@ func NewMenu() (*Menu, error) {
 	}
 	m.actions = newActionList(m)
 
+	menusByHandle[hMenu] = m
+
 	return m, nil
 }
 
@@ -68,6 +75,7 @@ func (m *Menu) Dispose() {
 	m.actions.Clear()
 
 	if m.hMenu != 0 {
+		delete(menusByHandle, m.hMenu)
 		win.DestroyMenu(m.hMenu)
 		m.hMenu = 0
 	}
@@ -81,6 +89,30 @@ func (m *Menu) Actions() *ActionList {
 	return m.actions
 }
 
+// BeforePopup returns the event that is published right before the Menu pops
+// up, e.g. as a context menu or submenu.
+//
+// Handlers may add, remove or update actions of the Menu, so it can be built
+// just in time, based on what was clicked or the current selection.
+func (m *Menu) BeforePopup() *Event {
+	return m.beforePopupPublisher.Event()
+}
+
+// handleInitMenuPopup publishes the BeforePopup event of the Menu with handle
+// hMenu, which is about to be shown by window.
+func handleInitMenuPopup(hMenu win.HMENU, window Window) {
+	m, ok := menusByHandle[hMenu]
+	if !ok {
+		return
+	}
+
+	m.beforePopupPublisher.Publish()
+
+	if window != nil && !m.IsDisposed() {
+		m.updateItemsWithImageForWindow(window)
+	}
+}
+
 func (m *Menu) updateItemsWithImageForWindow(window Window) {
 	if m.window == nil {
 		m.window = window
@@ -113,6 +145,10 @@ func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action)
 		if bmp, err := iconCache.Bitmap(action.image, dpi); err == nil {
 			mii.HbmpItem = bmp.hBmp
 		}
+	} else if action.imageList != nil {
+		// Drawn from the ImageList on WM_DRAWITEM.
+		mii.FMask |= win.MIIM_BITMAP
+		mii.HbmpItem = hbmMenuCallback
 	}
 	if action.IsSeparator() {
 		mii.FType |= win.MFT_SEPARATOR
@@ -120,7 +156,8 @@ func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action)
 		mii.FType |= win.MFT_STRING
 		var text string
 		if s := action.shortcut; s.Key != 0 {
-			text = fmt.Sprintf("%s\t%s", action.text, s.String())
+			// \b right-aligns the shortcut in its column.
+			text = fmt.Sprintf("%s\b%s", action.text, s.String())
 		} else {
 			text = action.text
 		}
@@ -141,7 +178,7 @@ func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action)
 	if action.Checked() {
 		mii.FState |= win.MFS_CHECKED
 	}
-	if action.Exclusive() {
+	if action.Exclusive() || action.radioGroup != 0 {
 		mii.FType |= win.MFT_RADIOCHECK
 	}
 
@@ -152,6 +189,61 @@ func (m *Menu) initMenuItemInfoFromAction(mii *win.MENUITEMINFO, action *Action)
 	}
 }
 
+// measureMenuItemImage handles WM_MEASUREITEM for a menu item that displays an
+// image of an ImageList.
+func measureMenuItemImage(mis *win.MEASUREITEMSTRUCT) bool {
+	if mis.CtlType != odtMenu {
+		return false
+	}
+
+	action, ok := actionsById[uint16(mis.ItemID)]
+	if !ok || action.image != nil || action.imageList == nil {
+		return false
+	}
+
+	size := action.imageList.imageSizePixels()
+
+	mis.ItemWidth = uint32(size.Width)
+	mis.ItemHeight = uint32(size.Height)
+
+	return true
+}
+
+// drawMenuItemImage handles WM_DRAWITEM for a menu item that displays an
+// image of an ImageList.
+func drawMenuItemImage(dis *win.DRAWITEMSTRUCT) bool {
+	if dis.CtlType != odtMenu {
+		return false
+	}
+
+	action, ok := actionsById[uint16(dis.ItemID)]
+	if !ok || action.image != nil || action.imageList == nil {
+		return false
+	}
+
+	size := action.imageList.imageSizePixels()
+	rc := dis.RcItem
+
+	style := uint32(win.ILD_TRANSPARENT)
+	if dis.ItemState&odsGrayed != 0 {
+		style |= win.ILD_BLEND50
+	}
+
+	win.ImageList_DrawEx(
+		action.imageList.hIml,
+		int32(action.imageIndex),
+		dis.HDC,
+		rc.Left,
+		rc.Top+(rc.Bottom-rc.Top-int32(size.Height))/2,
+		0,
+		0,
+		win.CLR_NONE,
+		win.CLR_NONE,
+		style)
+
+	return true
+}
+
 func (m *Menu) handleDefaultState(action *Action) {
 	if action.Default() {
 		// Unset other default actions before we set this one. Otherwise insertion fails.
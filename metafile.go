@@ -7,6 +7,7 @@
 package walk
 
 import (
+	"math"
 	"syscall"
 	"unsafe"
 )
@@ -119,3 +120,89 @@ func (mf *Metafile) drawStretched(hdc win.HDC, bounds Rectangle) error {
 
 	return nil
 }
+
+// ToBitmap plays the Metafile into a new Bitmap of size pixels with a
+// transparent background, stretching it to fill the whole Bitmap.
+//
+// dpi is the resolution the Bitmap is meant for. It is used for Canvases
+// created from the Bitmap.
+func (mf *Metafile) ToBitmap(size Size, dpi int) (*Bitmap, error) {
+	return mf.ToBitmapWithOptions(size, dpi, false, Transparent)
+}
+
+// ToBitmapWithOptions is like ToBitmap, but allows to keep the aspect ratio
+// of the Metafile, in which case it is centered in the Bitmap, and to fill
+// the Bitmap with a background color first. Pass Transparent as background
+// to keep the undrawn pixels transparent.
+func (mf *Metafile) ToBitmapWithOptions(size Size, dpi int, preserveAspectRatio bool, background Color) (*Bitmap, error) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return nil, newError("invalid size")
+	}
+
+	if err := mf.ensureFinished(); err != nil {
+		return nil, err
+	}
+
+	var disposables Disposables
+	defer disposables.Treat()
+
+	bmp, err := newBitmap(size, background == Transparent)
+	if err != nil {
+		return nil, err
+	}
+	disposables.Add(bmp)
+
+	bmp.dpi = dpi
+
+	canvas, err := NewCanvasFromImage(bmp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mf.playOntoCanvasPixels(canvas, size, preserveAspectRatio, background); err != nil {
+		canvas.Dispose()
+		return nil, err
+	}
+
+	// Disposing the Canvas fixes up the alpha channel of the Bitmap.
+	canvas.Dispose()
+
+	disposables.Spare()
+
+	return bmp, nil
+}
+
+func (mf *Metafile) playOntoCanvasPixels(canvas *Canvas, size Size, preserveAspectRatio bool, background Color) error {
+	if background != Transparent {
+		brush, err := NewSolidColorBrush(background)
+		if err != nil {
+			return err
+		}
+		defer brush.Dispose()
+
+		if err := canvas.withBrushAndPen(brush, nullPenSingleton, func() error {
+			if !win.Rectangle_(canvas.hdc, 0, 0, int32(size.Width+1), int32(size.Height+1)) {
+				return newError("Rectangle_ failed")
+			}
+
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	bounds := Rectangle{Width: size.Width, Height: size.Height}
+
+	if preserveAspectRatio && mf.size.Width > 0 && mf.size.Height > 0 {
+		scale := math.Min(
+			float64(size.Width)/float64(mf.size.Width),
+			float64(size.Height)/float64(mf.size.Height))
+
+		bounds.Width = int(math.Round(float64(mf.size.Width) * scale))
+		bounds.Height = int(math.Round(float64(mf.size.Height) * scale))
+		bounds.X = (size.Width - bounds.Width) / 2
+		bounds.Y = (size.Height - bounds.Height) / 2
+	}
+
+	return mf.drawStretched(canvas.hdc, bounds)
+}
@@ -289,6 +289,30 @@ type ImageProvider interface {
 	Image(index int) interface{}
 }
 
+// CellToolTipProvider is the interface that a model can implement to provide
+// tool tip texts for individual cells of a TableView.
+type CellToolTipProvider interface {
+	// CellToolTip returns the tool tip text for the cell at row and col. An
+	// empty string suppresses the tool tip.
+	CellToolTip(row, col int) string
+}
+
+// TableGroupProvider is the interface that a model can implement to provide
+// the titles and the initial collapsed state of the groups of a TableView,
+// see TableView.GroupBy.
+//
+// A group is identified by key, the text its rows display in the group
+// column.
+type TableGroupProvider interface {
+	// GroupTitle returns the title displayed in the header of the group of
+	// key, which contains rowCount rows.
+	GroupTitle(key string, rowCount int) string
+
+	// GroupCollapsed returns whether the group of key is collapsed when it
+	// first appears.
+	GroupCollapsed(key string) bool
+}
+
 // CellStyler is the interface that must be implemented to provide a tabular
 // widget like TableView with cell display style information.
 type CellStyler interface {
@@ -296,6 +320,64 @@ type CellStyler interface {
 	StyleCell(style *CellStyle)
 }
 
+// CellFormatter is a simpler alternative to CellStyler for providing the
+// colors, font and image of individual cells of a tabular widget like
+// TableView.
+//
+// col is -1 when the formatting of a whole row is queried.
+type CellFormatter interface {
+	// CellTextColor returns the text color of a cell and whether it should
+	// be used instead of the default.
+	CellTextColor(row, col int) (Color, bool)
+
+	// CellBackgroundColor returns the background color of a cell and
+	// whether it should be used instead of the default.
+	CellBackgroundColor(row, col int) (Color, bool)
+
+	// CellFont returns the font of a cell or nil for the default font.
+	CellFont(row, col int) *Font
+
+	// CellImage returns the image of a cell or nil for no image. The
+	// supported types are the same as for CellStyle.Image.
+	CellImage(row, col int) interface{}
+}
+
+// CellStylerFromFormatter returns a CellStyler that styles cells according
+// to formatter.
+func CellStylerFromFormatter(formatter CellFormatter) CellStyler {
+	if formatter == nil {
+		return nil
+	}
+
+	return &cellFormatterStyler{formatter}
+}
+
+type cellFormatterStyler struct {
+	formatter CellFormatter
+}
+
+func (cfs *cellFormatterStyler) StyleCell(style *CellStyle) {
+	if style.row < 0 {
+		// We leave the header alone.
+		return
+	}
+
+	if color, ok := cfs.formatter.CellTextColor(style.row, style.col); ok {
+		style.TextColor = color
+	}
+	if color, ok := cfs.formatter.CellBackgroundColor(style.row, style.col); ok {
+		style.BackgroundColor = color
+	}
+	if font := cfs.formatter.CellFont(style.row, style.col); font != nil {
+		style.Font = font
+	}
+	if style.col > -1 {
+		if image := cfs.formatter.CellImage(style.row, style.col); image != nil {
+			style.Image = image
+		}
+	}
+}
+
 // CellStyle carries information about the display style of a cell in a tabular widget
 // like TableView.
 type CellStyle struct {
@@ -517,6 +599,33 @@ type Sorter interface {
 	SortOrder() SortOrder
 }
 
+// SortColumn is a sort key of a multi-column sort.
+type SortColumn struct {
+	// Index is the index of the column.
+	Index int
+
+	// Order is the order by which the column is sorted.
+	Order SortOrder
+}
+
+// MultiSorter is the interface that a model can implement in addition to
+// Sorter to support sorting by multiple columns with a widget like
+// TableView.
+type MultiSorter interface {
+	Sorter
+
+	// SortMulti sorts by the columns in columns. Rows that compare equal by a
+	// column are ordered by the next one.
+	//
+	// SortedColumn and SortOrder must reflect the first column afterwards.
+	// An empty columns means no column is to be sorted. SortMulti must
+	// publish the event returned from SortChanged() after sorting.
+	SortMulti(columns []SortColumn) error
+
+	// SortColumns returns the columns the model is currently sorted by.
+	SortColumns() []SortColumn
+}
+
 // SorterBase implements the Sorter interface.
 //
 // You still need to provide your own implementation of at least the Sort method
@@ -579,6 +688,16 @@ type TreeItem interface {
 	ChildAt(index int) TreeItem
 }
 
+// HasChilder enables widgets like TreeView to determine if an item has any
+// child, without enumerating all of them.
+//
+// This is only consulted for models that use lazy population. Returning true
+// from HasChild while ChildCount still returns 0 makes the TreeView show an
+// expand button, so children can be loaded once the item is first expanded.
+type HasChilder interface {
+	HasChild() bool
+}
+
 // TreeModel provides widgets like TreeView with item data.
 type TreeModel interface {
 	// LazyPopulation returns if the model prefers on-demand population.
@@ -27,6 +27,9 @@ func notifyIconWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (resul
 	case win.WM_LBUTTONUP:
 		ni.publishMouseEvent(&ni.mouseUpPublisher, LeftButton)
 
+	case win.WM_LBUTTONDBLCLK:
+		ni.doubleClickedPublisher.Publish()
+
 	case win.WM_RBUTTONDOWN:
 		ni.publishMouseEvent(&ni.mouseDownPublisher, RightButton)
 
@@ -63,6 +66,15 @@ func notifyIconWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (resul
 		}
 
 		return 0
+	case win.NIN_BALLOONSHOW:
+		ni.messageShownPublisher.Publish()
+
+	case win.NIN_BALLOONHIDE:
+		ni.messageHiddenPublisher.Publish()
+
+	case win.NIN_BALLOONTIMEOUT:
+		ni.messageTimedOutPublisher.Publish()
+
 	case win.NIN_BALLOONUSERCLICK:
 		ni.messageClickedPublisher.Publish()
 	}
@@ -72,16 +84,20 @@ func notifyIconWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (resul
 
 // NotifyIcon represents an icon in the taskbar notification area.
 type NotifyIcon struct {
-	id                      uint32
-	hWnd                    win.HWND
-	lastDPI                 int
-	contextMenu             *Menu
-	icon                    Image
-	toolTip                 string
-	visible                 bool
-	mouseDownPublisher      MouseEventPublisher
-	mouseUpPublisher        MouseEventPublisher
-	messageClickedPublisher EventPublisher
+	id                       uint32
+	hWnd                     win.HWND
+	lastDPI                  int
+	contextMenu              *Menu
+	icon                     Image
+	toolTip                  string
+	visible                  bool
+	mouseDownPublisher       MouseEventPublisher
+	mouseUpPublisher         MouseEventPublisher
+	messageClickedPublisher  EventPublisher
+	messageShownPublisher    EventPublisher
+	messageHiddenPublisher   EventPublisher
+	messageTimedOutPublisher EventPublisher
+	doubleClickedPublisher   EventPublisher
 }
 
 // NewNotifyIcon creates and returns a new NotifyIcon.
@@ -190,6 +206,13 @@ func (ni *NotifyIcon) Dispose() error {
 }
 
 func (ni *NotifyIcon) showMessage(title, info string, iconType uint32, icon Image) error {
+	if iconType == win.NIIF_USER && icon != nil {
+		// Prefer a balloon icon, which leaves the icon of the NotifyIcon alone.
+		if err := ni.showMessageWithBalloonIcon(title, info, icon); err == nil {
+			return nil
+		}
+	}
+
 	nid := ni.notifyIconData()
 	nid.UFlags = win.NIF_INFO
 	nid.DwInfoFlags = iconType
@@ -218,6 +241,36 @@ func (ni *NotifyIcon) showMessage(title, info string, iconType uint32, icon Imag
 	return nil
 }
 
+// showMessageWithBalloonIcon shows a message with icon as balloon icon, which
+// requires the complete NOTIFYICONDATA of Windows Vista and later.
+func (ni *NotifyIcon) showMessageWithBalloonIcon(title, info string, icon Image) error {
+	dpi := ni.DPI()
+	ic, err := iconCache.Icon(icon, dpi)
+	if err != nil {
+		return err
+	}
+
+	nid := ni.notifyIconData()
+	nid.CbSize = uint32(unsafe.Sizeof(*nid))
+	nid.UFlags = win.NIF_INFO
+	nid.DwInfoFlags = win.NIIF_USER
+	nid.HBalloonIcon = ic.handleForDPI(dpi)
+	if ic.Size().Width >= 32 {
+		nid.DwInfoFlags |= win.NIIF_LARGE_ICON
+	}
+	if title16, err := syscall.UTF16FromString(title); err == nil {
+		copy(nid.SzInfoTitle[:], title16)
+	}
+	if info16, err := syscall.UTF16FromString(info); err == nil {
+		copy(nid.SzInfo[:], info16)
+	}
+	if !win.Shell_NotifyIcon(win.NIM_MODIFY, nid) {
+		return newError("Shell_NotifyIcon")
+	}
+
+	return nil
+}
+
 // ShowMessage displays a neutral message balloon above the NotifyIcon.
 //
 // The NotifyIcon must be visible before calling this method.
@@ -248,6 +301,7 @@ func (ni *NotifyIcon) ShowError(title, info string) error {
 
 // ShowCustom displays a custom icon message balloon above the NotifyIcon.
 // If icon is nil, the main notification icon is used instead of a custom one.
+// An icon of 32x32 or more is shown large.
 //
 // The NotifyIcon must be visible before calling this method.
 func (ni *NotifyIcon) ShowCustom(title, info string, icon Image) error {
@@ -372,8 +426,36 @@ func (ni *NotifyIcon) MouseUp() *MouseEvent {
 	return ni.mouseUpPublisher.Event()
 }
 
+// DoubleClicked returns the event that is published when the NotifyIcon is
+// double clicked with the left mouse button.
+func (ni *NotifyIcon) DoubleClicked() *Event {
+	return ni.doubleClickedPublisher.Event()
+}
+
 // MessageClicked occurs when the user clicks a message shown with ShowMessage or
 // one of its iconed variants.
+//
+// On Windows 10 and later, messages are shown as toast notifications, which
+// may be clicked in the action center long after they disappeared from the
+// screen and MessageTimedOut occurred.
 func (ni *NotifyIcon) MessageClicked() *Event {
 	return ni.messageClickedPublisher.Event()
 }
+
+// MessageShown occurs when a message shown with ShowMessage or one of its
+// iconed variants appears. Messages may be queued while others are shown.
+func (ni *NotifyIcon) MessageShown() *Event {
+	return ni.messageShownPublisher.Event()
+}
+
+// MessageTimedOut occurs when a message disappears because it timed out or
+// the user closed it.
+func (ni *NotifyIcon) MessageTimedOut() *Event {
+	return ni.messageTimedOutPublisher.Event()
+}
+
+// MessageHidden occurs when a message disappears for another reason, e.g.
+// because the NotifyIcon was hidden.
+func (ni *NotifyIcon) MessageHidden() *Event {
+	return ni.messageHiddenPublisher.Event()
+}
@@ -10,9 +10,8 @@ import (
 	"bytes"
 	"fmt"
 	"math"
-	"strconv"
-	"strings"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/lxn/win"
@@ -117,6 +116,15 @@ func NewNumberEdit(parent Container) (*NumberEdit, error) {
 		},
 		ne.suffixChangedPublisher.Event()))
 
+	ne.MustRegisterProperty("ThousandsSeparator", NewProperty(
+		func() interface{} {
+			return ne.ThousandsSeparator()
+		},
+		func(v interface{}) error {
+			return ne.SetThousandsSeparator(v.(bool))
+		},
+		nil))
+
 	ne.MustRegisterProperty("Value", NewProperty(
 		func() interface{} {
 			return ne.Value()
@@ -167,6 +175,44 @@ func (ne *NumberEdit) SetDecimals(decimals int) error {
 	return ne.SetValue(ne.edit.value)
 }
 
+// DisplayFormat returns the NumberDisplayFormat used to display the value of
+// the NumberEdit while it is not being edited.
+func (ne *NumberEdit) DisplayFormat() NumberDisplayFormat {
+	return ne.edit.displayFormat
+}
+
+// SetDisplayFormat sets the NumberDisplayFormat used to display the value of
+// the NumberEdit while it is not being edited.
+//
+// Regardless of the display format, text entered by the user may use fixed or
+// scientific notation.
+func (ne *NumberEdit) SetDisplayFormat(format NumberDisplayFormat) error {
+	if format < NumberDisplayFixed || format > NumberDisplayAuto {
+		return newError("invalid display format")
+	}
+
+	ne.edit.displayFormat = format
+
+	return ne.edit.setTextFromValue(ne.edit.value)
+}
+
+// ThousandsSeparator returns whether digits in front of the decimal separator
+// are grouped using the thousands separator of the current locale.
+func (ne *NumberEdit) ThousandsSeparator() bool {
+	return ne.edit.grouped()
+}
+
+// SetThousandsSeparator sets whether digits in front of the decimal separator
+// are grouped using the thousands separator of the current locale.
+//
+// If this is never called, grouping is used if Decimals is greater than 0.
+func (ne *NumberEdit) SetThousandsSeparator(enabled bool) error {
+	ne.edit.grouping = enabled
+	ne.edit.groupingSet = true
+
+	return ne.edit.setTextFromValue(ne.edit.value)
+}
+
 // Prefix returns the text that appears in the NumberEdit before the number.
 func (ne *NumberEdit) Prefix() string {
 	return syscall.UTF16ToString(ne.edit.prefix)
@@ -444,14 +490,23 @@ type numberLineEdit struct {
 	maxValue              float64
 	increment             float64
 	decimals              int
+	displayFormat         NumberDisplayFormat
+	accelerationEnabled   bool
+	accelerations         []NumberEditAcceleration
+	coarseInc             float64
+	spinStart             time.Time
+	grouping              bool
+	groupingSet           bool
 	valueChangedPublisher EventPublisher
 	inEditMode            bool
 }
 
 func newNumberLineEdit(parent Widget) (*numberLineEdit, error) {
 	nle := &numberLineEdit{
-		buf:       new(bytes.Buffer),
-		increment: 1,
+		buf:                 new(bytes.Buffer),
+		increment:           1,
+		accelerationEnabled: true,
+		accelerations:       defaultNumberEditAccelerations,
 	}
 
 	var err error
@@ -510,11 +565,7 @@ func (nle *numberLineEdit) setTextFromValue(value float64) error {
 
 	nle.buf.WriteString(syscall.UTF16ToString(nle.prefix))
 
-	if nle.decimals > 0 {
-		nle.buf.WriteString(FormatFloatGrouped(value, nle.decimals))
-	} else {
-		nle.buf.WriteString(FormatFloat(value, nle.decimals))
-	}
+	nle.buf.WriteString(nle.formatValue(value))
 
 	nle.buf.WriteString(syscall.UTF16ToString(nle.suffix))
 
@@ -535,19 +586,14 @@ func (nle *numberLineEdit) processChar(text []uint16, start, end int, key Key, c
 	hadSelection := start != end
 
 	if !nle.inEditMode {
-		var groupSepsBeforeStart int
-		if nle.decimals > 0 {
-			groupSepsBeforeStart = uint16CountUint16(text[:start], groupSepUint16)
-		}
+		groupSepsBeforeStart := uint16CountUint16(text[:start], groupSepUint16)
 
 		if hadSelection {
 			text = append(text[:start], text[end:]...)
 		}
 
-		if nle.decimals > 0 {
-			text = uint16RemoveUint16(text, groupSepUint16)
-			start -= groupSepsBeforeStart
-		}
+		text = uint16RemoveUint16(text, groupSepUint16)
+		start -= groupSepsBeforeStart
 
 		nle.inEditMode = true
 	} else {
@@ -598,14 +644,7 @@ func (nle *numberLineEdit) tryUpdateValue(setText bool) bool {
 	t := nle.textUTF16()
 	t = t[len(nle.prefix) : len(t)-len(nle.suffix)]
 
-	text := strings.Replace(syscall.UTF16ToString(t), decimalSepS, ".", 1)
-
-	switch text {
-	case "", ".":
-		text = "0"
-	}
-
-	if value, err := strconv.ParseFloat(text, 64); err == nil {
+	if value, err := parseNumberEditText(syscall.UTF16ToString(t)); err == nil {
 		if nle.minValue == nle.maxValue || value >= nle.minValue && value <= nle.maxValue {
 			return nle.setValue(value, setText) == nil
 		}
@@ -675,7 +714,7 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 
 		switch char {
 		case uint16('0'), uint16('1'), uint16('2'), uint16('3'), uint16('4'), uint16('5'), uint16('6'), uint16('7'), uint16('8'), uint16('9'):
-			if start == end && nle.decimals > 0 {
+			if start == end && nle.decimals > 0 && !uint16ContainsExponent(text) {
 				if i := uint16IndexUint16(text, decimalSepUint16); i > -1 && i < len(text)-nle.decimals && start > i {
 					return 0
 				}
@@ -689,6 +728,15 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
+			if start > 0 && isExponentChar(text[start-1]) {
+				if uint16ContainsUint16(text[start:], uint16('-')) || uint16ContainsUint16(text[start:], uint16('+')) {
+					return 0
+				}
+
+				nle.processChar(text, start, end, 0, char)
+				return 0
+			}
+
 			if start > 0 || uint16ContainsUint16(text, uint16('-')) && end == 0 {
 				return 0
 			}
@@ -705,7 +753,11 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
-			if end < len(text)-nle.decimals {
+			if uint16ContainsExponent(text[:start]) {
+				return 0
+			}
+
+			if end < len(text)-nle.decimals && !uint16ContainsExponent(text) {
 				return 0
 			}
 
@@ -716,6 +768,26 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 			nle.processChar(text, start, end, 0, char)
 			return 0
 
+		case uint16('e'), uint16('E'):
+			if start == 0 || uint16ContainsExponent(text[:start]) || uint16ContainsExponent(text[end:]) {
+				return 0
+			}
+
+			nle.processChar(text, start, end, 0, uint16('E'))
+			return 0
+
+		case uint16('+'):
+			if start == 0 || !isExponentChar(text[start-1]) {
+				return 0
+			}
+
+			if uint16ContainsUint16(text[start:], uint16('-')) || uint16ContainsUint16(text[start:], uint16('+')) {
+				return 0
+			}
+
+			nle.processChar(text, start, end, 0, char)
+			return 0
+
 		default:
 			return 0
 		}
@@ -747,7 +819,7 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
-			nle.incrementValue(-nle.increment)
+			nle.incrementValue(-nle.spinIncrement(lParam&(1<<30) != 0))
 			return 0
 
 		case KeyEnd:
@@ -812,7 +884,7 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 				return 0
 			}
 
-			nle.incrementValue(nle.increment)
+			nle.incrementValue(nle.spinIncrement(lParam&(1<<30) != 0))
 			return 0
 		}
 
@@ -870,8 +942,13 @@ func (nle *numberLineEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uin
 			break
 		}
 
+		increment := nle.increment
+		if ControlDown() {
+			increment = nle.coarseIncrement()
+		}
+
 		delta := float64(int16(win.HIWORD(uint32(wParam))))
-		nle.incrementValue(delta / 120 * nle.increment)
+		nle.incrementValue(delta / 120 * increment)
 		return 0
 
 	case win.WM_PASTE:
@@ -0,0 +1,130 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"time"
+)
+
+// NumberEditAcceleration is an entry of the acceleration table of a
+// NumberEdit. Once the user has kept spinning the value for at least Delay,
+// the increment is multiplied by Factor.
+type NumberEditAcceleration struct {
+	Delay  time.Duration
+	Factor float64
+}
+
+var defaultNumberEditAccelerations = []NumberEditAcceleration{
+	{0, 1},
+	{1500 * time.Millisecond, 10},
+	{3 * time.Second, 100},
+}
+
+// AccelerationEnabled returns if the increment of the NumberEdit accelerates
+// while the user holds down the KeyUp or KeyDown key.
+func (ne *NumberEdit) AccelerationEnabled() bool {
+	return ne.edit.accelerationEnabled
+}
+
+// SetAccelerationEnabled sets if the increment of the NumberEdit accelerates
+// while the user holds down the KeyUp or KeyDown key.
+func (ne *NumberEdit) SetAccelerationEnabled(enabled bool) {
+	ne.edit.accelerationEnabled = enabled
+}
+
+// Accelerations returns the acceleration table of the NumberEdit.
+func (ne *NumberEdit) Accelerations() []NumberEditAcceleration {
+	accels := make([]NumberEditAcceleration, len(ne.edit.accelerations))
+	copy(accels, ne.edit.accelerations)
+
+	return accels
+}
+
+// SetAccelerations sets the acceleration table of the NumberEdit.
+//
+// Entries must be sorted by Delay in ascending order and have a positive
+// Factor. A nil table restores the default of 1, 10 and 100 times the
+// increment.
+func (ne *NumberEdit) SetAccelerations(accels []NumberEditAcceleration) error {
+	if accels == nil {
+		ne.edit.accelerations = defaultNumberEditAccelerations
+		return nil
+	}
+
+	for i, accel := range accels {
+		if accel.Factor <= 0 {
+			return newError("acceleration factor must be > 0")
+		}
+		if i > 0 && accel.Delay < accels[i-1].Delay {
+			return newError("accelerations must be sorted by delay")
+		}
+	}
+
+	ne.edit.accelerations = make([]NumberEditAcceleration, len(accels))
+	copy(ne.edit.accelerations, accels)
+
+	return nil
+}
+
+// CoarseIncrement returns the amount by which the NumberEdit increments or
+// decrements its value, when the user presses the KeyDown or KeyUp keys, or
+// rotates the mouse wheel, while holding down the control key.
+//
+// If no coarse increment was set, 10 times Increment is returned.
+func (ne *NumberEdit) CoarseIncrement() float64 {
+	return ne.edit.coarseIncrement()
+}
+
+// SetCoarseIncrement sets the amount by which the NumberEdit increments or
+// decrements its value, when the user presses the KeyDown or KeyUp keys, or
+// rotates the mouse wheel, while holding down the control key.
+func (ne *NumberEdit) SetCoarseIncrement(increment float64) error {
+	if increment < 0 {
+		return newError("increment must be >= 0")
+	}
+
+	ne.edit.coarseInc = increment
+
+	return nil
+}
+
+func (nle *numberLineEdit) coarseIncrement() float64 {
+	if nle.coarseInc > 0 {
+		return nle.coarseInc
+	}
+
+	return nle.increment * 10
+}
+
+// spinIncrement returns the increment to apply for a key press. repeat
+// indicates that the key press was generated by keyboard auto-repeat.
+func (nle *numberLineEdit) spinIncrement(repeat bool) float64 {
+	if ControlDown() {
+		return nle.coarseIncrement()
+	}
+
+	if !repeat || nle.spinStart.IsZero() {
+		nle.spinStart = time.Now()
+	}
+
+	if !nle.accelerationEnabled {
+		return nle.increment
+	}
+
+	elapsed := time.Since(nle.spinStart)
+
+	factor := 1.0
+	for _, accel := range nle.accelerations {
+		if elapsed < accel.Delay {
+			break
+		}
+
+		factor = accel.Factor
+	}
+
+	return nle.increment * factor
+}
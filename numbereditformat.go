@@ -0,0 +1,131 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NumberDisplayFormat specifies how a NumberEdit displays its value.
+type NumberDisplayFormat int
+
+const (
+	// NumberDisplayFixed displays the value with a fixed number of decimals.
+	NumberDisplayFixed NumberDisplayFormat = iota
+
+	// NumberDisplayScientific displays the value as a mantissa with one
+	// digit in front of the decimal separator and an exponent, e.g. 1.23E+04.
+	NumberDisplayScientific
+
+	// NumberDisplayEngineering is like NumberDisplayScientific, but the
+	// exponent is always a multiple of 3, e.g. 12.3E+03.
+	NumberDisplayEngineering
+
+	// NumberDisplayAuto uses NumberDisplayFixed, unless the value is too
+	// large or too small to be displayed meaningfully with the current number
+	// of decimals, in which case NumberDisplayScientific is used.
+	NumberDisplayAuto
+)
+
+const (
+	numberDisplayAutoMax = 1e15
+)
+
+func (nle *numberLineEdit) grouped() bool {
+	if nle.groupingSet {
+		return nle.grouping
+	}
+
+	return nle.decimals > 0
+}
+
+func (nle *numberLineEdit) formatValue(value float64) string {
+	format := nle.displayFormat
+
+	if format == NumberDisplayAuto {
+		format = NumberDisplayFixed
+
+		if abs := math.Abs(value); abs >= numberDisplayAutoMax || abs != 0 && abs < math.Pow10(-nle.decimals) {
+			format = NumberDisplayScientific
+		}
+	}
+
+	switch format {
+	case NumberDisplayScientific:
+		return formatFloatScientific(value, nle.decimals)
+
+	case NumberDisplayEngineering:
+		return formatFloatEngineering(value, nle.decimals)
+	}
+
+	if nle.grouped() {
+		return FormatFloatGrouped(value, nle.decimals)
+	}
+
+	return FormatFloat(value, nle.decimals)
+}
+
+func formatFloatScientific(f float64, prec int) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return FormatFloat(f, prec)
+	}
+
+	return strings.Replace(strconv.FormatFloat(f, 'E', prec, 64), ".", decimalSepS, 1)
+}
+
+func formatFloatEngineering(f float64, prec int) string {
+	if f == 0 || math.IsNaN(f) || math.IsInf(f, 0) {
+		return formatFloatScientific(f, prec)
+	}
+
+	exp := int(math.Floor(math.Log10(math.Abs(f))))
+	exp -= (exp%3 + 3) % 3
+
+	mantissa := strconv.FormatFloat(f/math.Pow10(exp), 'f', prec, 64)
+
+	// Rounding may have produced a mantissa of 1000.
+	if m, err := strconv.ParseFloat(mantissa, 64); err == nil && math.Abs(m) >= 1000 {
+		exp += 3
+		mantissa = strconv.FormatFloat(f/math.Pow10(exp), 'f', prec, 64)
+	}
+
+	return formatFloatString(mantissa, prec, false) + fmt.Sprintf("E%+03d", exp)
+}
+
+// parseNumberEditText parses text in fixed or scientific notation, as
+// entered into a NumberEdit, using the separators of the current locale.
+func parseNumberEditText(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if groupSepS != "" && groupSepS != decimalSepS {
+		text = strings.Replace(text, groupSepS, "", -1)
+	}
+	text = strings.Replace(text, decimalSepS, ".", 1)
+
+	switch text {
+	case "", ".":
+		text = "0"
+	}
+
+	return strconv.ParseFloat(text, 64)
+}
+
+func isExponentChar(c uint16) bool {
+	return c == 'e' || c == 'E'
+}
+
+func uint16ContainsExponent(s []uint16) bool {
+	for _, c := range s {
+		if isExponentChar(c) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,474 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+import (
+	"github.com/lxn/win"
+)
+
+// Printer identifies a printer together with the settings to print with,
+// like the paper orientation and the number of copies.
+//
+// Printers are obtained from DefaultPrinter, PrintDialog and
+// PageSetupDialog.
+type Printer struct {
+	name    string
+	devMode []byte
+}
+
+// DefaultPrinter returns the default printer of the user with its default
+// settings.
+func DefaultPrinter() (*Printer, error) {
+	var psd pageSetupDlg
+	psd.lStructSize = uint32(unsafe.Sizeof(psd))
+	psd.flags = psdReturnDefault
+
+	if !pageSetupDialog(&psd) {
+		if errno := win.CommDlgExtendedError(); errno == win.PDERR_NODEFAULTPRN {
+			return nil, newError("no default printer")
+		} else if errno != 0 {
+			return nil, newError(fmt.Sprintf("PageSetupDlg: Error %d", errno))
+		}
+
+		return nil, newError("PageSetupDlg failed")
+	}
+
+	return printerFromGlobals(psd.hDevMode, psd.hDevNames)
+}
+
+// printerFromGlobals returns a Printer from the DEVMODE and DEVNAMES
+// returned by common dialogs. It frees the global memory in any case.
+func printerFromGlobals(hDevMode, hDevNames win.HGLOBAL) (*Printer, error) {
+	defer freeGlobals(hDevMode, hDevNames)
+
+	p := new(Printer)
+
+	if hDevMode != 0 {
+		data, err := bytesFromHGLOBAL(hDevMode)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) >= int(unsafe.Sizeof(win.DEVMODE{})) {
+			p.devMode = data
+		}
+	}
+
+	if hDevNames != 0 {
+		data, err := bytesFromHGLOBAL(hDevNames)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) >= int(unsafe.Sizeof(win.DEVNAMES{})) {
+			chars := (*[1 << 29]uint16)(unsafe.Pointer(&data[0]))[: len(data)/2 : len(data)/2]
+			dn := (*win.DEVNAMES)(unsafe.Pointer(&data[0]))
+
+			if int(dn.WDeviceOffset) < len(chars) {
+				p.name = syscall.UTF16ToString(chars[dn.WDeviceOffset:])
+			}
+		}
+	}
+
+	if p.name == "" {
+		if dm := p.devModePtr(); dm != nil {
+			p.name = syscall.UTF16ToString(dm.DmDeviceName[:])
+		}
+	}
+
+	if p.name == "" {
+		return nil, newError("no printer selected")
+	}
+
+	return p, nil
+}
+
+func freeGlobals(hMems ...win.HGLOBAL) {
+	for _, hMem := range hMems {
+		if hMem != 0 {
+			win.GlobalFree(hMem)
+		}
+	}
+}
+
+// globals returns the DEVMODE and DEVNAMES of p, as expected by common
+// dialogs. The caller is responsible for freeing them.
+func (p *Printer) globals() (hDevMode, hDevNames win.HGLOBAL, err error) {
+	if p.devMode != nil {
+		if hDevMode, err = hGlobalFromBytes(p.devMode); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	driver := syscall.StringToUTF16("winspool")
+	device := syscall.StringToUTF16(p.name)
+
+	header := int(unsafe.Sizeof(win.DEVNAMES{})) / 2
+
+	chars := make([]uint16, header, header+len(driver)+len(device)+1)
+	chars = append(chars, driver...)
+	chars = append(chars, device...)
+	chars = append(chars, 0)
+
+	dn := (*win.DEVNAMES)(unsafe.Pointer(&chars[0]))
+	dn.WDriverOffset = uint16(header)
+	dn.WDeviceOffset = uint16(header + len(driver))
+	dn.WOutputOffset = uint16(len(chars) - 1)
+
+	data := (*[1 << 30]byte)(unsafe.Pointer(&chars[0]))[: len(chars)*2 : len(chars)*2]
+
+	if hDevNames, err = hGlobalFromBytes(data); err != nil {
+		freeGlobals(hDevMode)
+		return 0, 0, err
+	}
+
+	return hDevMode, hDevNames, nil
+}
+
+func (p *Printer) devModePtr() *win.DEVMODE {
+	if p.devMode == nil {
+		return nil
+	}
+
+	return (*win.DEVMODE)(unsafe.Pointer(&p.devMode[0]))
+}
+
+// Name returns the name of the printer.
+func (p *Printer) Name() string {
+	return p.name
+}
+
+// Copies returns the number of copies to print.
+func (p *Printer) Copies() int {
+	if dm := p.devModePtr(); dm != nil && dm.DmFields&win.DM_COPIES != 0 && dm.DmCopies > 0 {
+		return int(dm.DmCopies)
+	}
+
+	return 1
+}
+
+// SetCopies sets the number of copies to print. Not all printer drivers
+// support printing multiple copies.
+func (p *Printer) SetCopies(copies int) error {
+	dm := p.devModePtr()
+	if dm == nil {
+		return newError("printer settings unavailable")
+	}
+	if copies < 1 || copies > 0x7fff {
+		return newError("copies out of range")
+	}
+
+	dm.DmCopies = int16(copies)
+	dm.DmFields |= win.DM_COPIES
+
+	return nil
+}
+
+// Landscape returns whether pages are printed in landscape orientation.
+func (p *Printer) Landscape() bool {
+	dm := p.devModePtr()
+
+	return dm != nil && dm.DmFields&win.DM_ORIENTATION != 0 && dm.DmOrientation == win.DMORIENT_LANDSCAPE
+}
+
+// SetLandscape sets whether pages are printed in landscape orientation.
+func (p *Printer) SetLandscape(landscape bool) error {
+	dm := p.devModePtr()
+	if dm == nil {
+		return newError("printer settings unavailable")
+	}
+
+	if landscape {
+		dm.DmOrientation = win.DMORIENT_LANDSCAPE
+	} else {
+		dm.DmOrientation = win.DMORIENT_PORTRAIT
+	}
+	dm.DmFields |= win.DM_ORIENTATION
+
+	return nil
+}
+
+// PrintDocument prints pages drawn on a Canvas, so the code that draws a
+// report on screen can also print it.
+//
+// Print publishes BeginPrint, then PrintPage for each page until a handler
+// reports that there are no more pages, then EndPrint. Like on screen, the
+// Canvas uses 1/96" units, so drawings keep their physical size on printers
+// with a different resolution.
+type PrintDocument struct {
+	// Name is the name of the document shown in the print queue.
+	Name string
+
+	// Printer is the printer to print to. If it is nil, the default printer
+	// is used.
+	Printer *Printer
+
+	// Margins are the distances of the page bounds from the edges of the
+	// paper, in 1/96" units. They are enlarged to the area the printer can
+	// print on, if necessary.
+	Margins Margins
+
+	// FromPage and ToPage limit the pages printed, if ToPage is not 0.
+	// PrintPage is published for the pages before FromPage, too, so
+	// handlers can paginate, but their drawing is discarded.
+	FromPage int
+	ToPage   int
+
+	beginPrintPublisher EventPublisher
+	printPagePublisher  PrintPageEventPublisher
+	endPrintPublisher   EventPublisher
+}
+
+// BeginPrint returns the event that is published before the first page is
+// printed.
+func (doc *PrintDocument) BeginPrint() *Event {
+	return doc.beginPrintPublisher.Event()
+}
+
+// PrintPage returns the event that is published to draw a page.
+func (doc *PrintDocument) PrintPage() *PrintPageEvent {
+	return doc.printPagePublisher.Event()
+}
+
+// EndPrint returns the event that is published after the last page was
+// printed, or printing was canceled or failed.
+func (doc *PrintDocument) EndPrint() *Event {
+	return doc.endPrintPublisher.Event()
+}
+
+// Print prints the document.
+func (doc *PrintDocument) Print() error {
+	printer := doc.Printer
+	if printer == nil {
+		var err error
+		if printer, err = DefaultPrinter(); err != nil {
+			return err
+		}
+	}
+
+	hdc := win.CreateDC(nil, syscall.StringToUTF16Ptr(printer.name), nil, printer.devModePtr())
+	if hdc == 0 {
+		return newError("CreateDC failed")
+	}
+	defer win.DeleteDC(hdc)
+
+	var di win.DOCINFO
+	di.CbSize = int32(unsafe.Sizeof(di))
+	di.LpszDocName = syscall.StringToUTF16Ptr(doc.Name)
+
+	if win.StartDoc(hdc, &di) <= 0 {
+		return newError("StartDoc failed")
+	}
+
+	doc.beginPrintPublisher.Publish()
+
+	canceled, err := doc.printPages(hdc)
+
+	doc.endPrintPublisher.Publish()
+
+	if err != nil || canceled {
+		win.AbortDoc(hdc)
+		return err
+	}
+
+	if win.EndDoc(hdc) <= 0 {
+		return newError("EndDoc failed")
+	}
+
+	return nil
+}
+
+func (doc *PrintDocument) printPages(hdc win.HDC) (canceled bool, err error) {
+	// Printers may have different resolutions along both axes.
+	dpix := int(win.GetDeviceCaps(hdc, win.LOGPIXELSX))
+	dpiy := int(win.GetDeviceCaps(hdc, win.LOGPIXELSY))
+	pageBounds := doc.pageBounds(hdc, dpix, dpiy)
+
+	for page := 1; ; page++ {
+		args := &PrintPageEventArgs{
+			pageBounds: pageBounds,
+			pageNumber: page,
+		}
+
+		if doc.ToPage == 0 || page >= doc.FromPage {
+			err = doc.printPage(hdc, dpix, dpiy, args)
+		} else {
+			err = doc.skipPage(hdc, dpix, dpiy, args)
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if args.canceled {
+			return true, nil
+		}
+
+		if !args.hasMorePages || doc.ToPage > 0 && page >= doc.ToPage {
+			return false, nil
+		}
+	}
+}
+
+func (doc *PrintDocument) printPage(hdc win.HDC, dpix, dpiy int, args *PrintPageEventArgs) error {
+	if win.StartPage(hdc) <= 0 {
+		return newError("StartPage failed")
+	}
+
+	if err := doc.publishPrintPage(hdc, dpix, dpiy, args); err != nil {
+		return err
+	}
+
+	if win.EndPage(hdc) <= 0 {
+		return newError("EndPage failed")
+	}
+
+	return nil
+}
+
+// skipPage publishes PrintPage for a page outside the range to print,
+// discarding the drawing.
+func (doc *PrintDocument) skipPage(hdc win.HDC, dpix, dpiy int, args *PrintPageEventArgs) error {
+	emfDC := win.CreateEnhMetaFile(hdc, nil, nil, nil)
+	if emfDC == 0 {
+		return newError("CreateEnhMetaFile failed")
+	}
+	defer win.DeleteEnhMetaFile(win.CloseEnhMetaFile(emfDC))
+
+	return doc.publishPrintPage(emfDC, dpix, dpiy, args)
+}
+
+func (doc *PrintDocument) publishPrintPage(hdc win.HDC, dpix, dpiy int, args *PrintPageEventArgs) error {
+	canvas, err := (&Canvas{hdc: hdc, doNotDispose: true, dpix: dpix, dpiy: dpiy}).init()
+	if err != nil {
+		return err
+	}
+	defer canvas.Dispose()
+
+	args.canvas = canvas
+
+	doc.printPagePublisher.Publish(args)
+
+	return nil
+}
+
+// pageBounds returns the area within the margins that the printer can print
+// on, in 1/96" units relative to the origin of the printer canvas.
+func (doc *PrintDocument) pageBounds(hdc win.HDC, dpix, dpiy int) Rectangle {
+	offsetX := int(win.GetDeviceCaps(hdc, win.PHYSICALOFFSETX))
+	offsetY := int(win.GetDeviceCaps(hdc, win.PHYSICALOFFSETY))
+	printableWidth := int(win.GetDeviceCaps(hdc, win.HORZRES))
+	printableHeight := int(win.GetDeviceCaps(hdc, win.VERTRES))
+	paperWidth := int(win.GetDeviceCaps(hdc, win.PHYSICALWIDTH))
+	paperHeight := int(win.GetDeviceCaps(hdc, win.PHYSICALHEIGHT))
+
+	m := doc.Margins
+
+	left := maxi(IntFrom96DPI(m.HNear, dpix)-offsetX, 0)
+	top := maxi(IntFrom96DPI(m.VNear, dpiy)-offsetY, 0)
+	right := mini(paperWidth-IntFrom96DPI(m.HFar, dpix)-offsetX, printableWidth)
+	bottom := mini(paperHeight-IntFrom96DPI(m.VFar, dpiy)-offsetY, printableHeight)
+
+	return Rectangle{
+		X:      IntTo96DPI(left, dpix),
+		Y:      IntTo96DPI(top, dpiy),
+		Width:  IntTo96DPI(maxi(right-left, 0), dpix),
+		Height: IntTo96DPI(maxi(bottom-top, 0), dpiy),
+	}
+}
+
+// PrintPageEventArgs is passed to the handlers of the PrintPage event of a
+// PrintDocument.
+type PrintPageEventArgs struct {
+	canvas       *Canvas
+	pageBounds   Rectangle
+	pageNumber   int
+	hasMorePages bool
+	canceled     bool
+}
+
+// Canvas returns the Canvas to draw the page on. Its origin is the top left
+// corner of the area the printer can print on.
+func (a *PrintPageEventArgs) Canvas() *Canvas {
+	return a.canvas
+}
+
+// PageBounds returns the bounds within the margins of the PrintDocument to
+// draw the page in, in 1/96" units.
+func (a *PrintPageEventArgs) PageBounds() Rectangle {
+	return a.pageBounds
+}
+
+// PageNumber returns the number of the page, starting at 1.
+func (a *PrintPageEventArgs) PageNumber() int {
+	return a.pageNumber
+}
+
+// HasMorePages returns whether another page follows the current one.
+func (a *PrintPageEventArgs) HasMorePages() bool {
+	return a.hasMorePages
+}
+
+// SetHasMorePages sets whether another page follows the current one. It
+// defaults to false, so handlers printing multiple pages must set it.
+func (a *PrintPageEventArgs) SetHasMorePages(value bool) {
+	a.hasMorePages = value
+}
+
+// Canceled returns whether printing is canceled.
+func (a *PrintPageEventArgs) Canceled() bool {
+	return a.canceled
+}
+
+// SetCanceled sets whether to cancel printing. The pages printed so far are
+// discarded.
+func (a *PrintPageEventArgs) SetCanceled(value bool) {
+	a.canceled = value
+}
+
+type PrintPageEventHandler func(args *PrintPageEventArgs)
+
+type PrintPageEvent struct {
+	handlers []PrintPageEventHandler
+}
+
+func (e *PrintPageEvent) Attach(handler PrintPageEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *PrintPageEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type PrintPageEventPublisher struct {
+	event PrintPageEvent
+}
+
+func (p *PrintPageEventPublisher) Event() *PrintPageEvent {
+	return &p.event
+}
+
+func (p *PrintPageEventPublisher) Publish(args *PrintPageEventArgs) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(args)
+		}
+	}
+}
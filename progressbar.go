@@ -7,11 +7,26 @@
 package walk
 
 import (
+	"syscall"
+
 	"github.com/lxn/win"
 )
 
+// ProgressBarState specifies the state of a ProgressBar, which visual styles
+// reflect in the color of the bar.
+type ProgressBarState int
+
+const (
+	ProgressBarStateNormal ProgressBarState = pbstNormal
+	ProgressBarStateError  ProgressBarState = pbstError
+	ProgressBarStatePaused ProgressBarState = pbstPaused
+)
+
 type ProgressBar struct {
 	WidgetBase
+	barColor        Color
+	backgroundColor Color
+	customColors    bool
 }
 
 func NewProgressBar(parent Container) (*ProgressBar, error) {
@@ -63,6 +78,116 @@ func (pb *ProgressBar) SetMarqueeMode(marqueeMode bool) error {
 	return nil
 }
 
+// SetMarquee switches the ProgressBar into or out of marquee mode, which is
+// used for operations of unknown duration. speed is the time in milliseconds
+// between updates of the animation, 0 selects the default.
+func (pb *ProgressBar) SetMarquee(marquee bool, speed int) error {
+	if err := pb.ensureStyleBits(win.PBS_MARQUEE, marquee); err != nil {
+		return err
+	}
+
+	pb.SendMessage(win.PBM_SETMARQUEE, uintptr(win.BoolToBOOL(marquee)), uintptr(speed))
+
+	return nil
+}
+
+// State returns the ProgressBarState of the ProgressBar.
+func (pb *ProgressBar) State() ProgressBarState {
+	return ProgressBarState(pb.SendMessage(pbmGetState, 0, 0))
+}
+
+// SetState sets the ProgressBarState of the ProgressBar.
+//
+// With visual styles, the bar is displayed green, red or yellow for normal,
+// error and paused state, unless custom colors are used.
+func (pb *ProgressBar) SetState(state ProgressBarState) error {
+	switch state {
+	case ProgressBarStateNormal, ProgressBarStateError, ProgressBarStatePaused:
+	default:
+		return newError("invalid state")
+	}
+
+	pb.SendMessage(pbmSetState, uintptr(state), 0)
+
+	return nil
+}
+
+// BarColor returns the custom color of the bar of the ProgressBar.
+func (pb *ProgressBar) BarColor() Color {
+	return pb.barColor
+}
+
+// SetBarColor sets a custom color for the bar of the ProgressBar.
+//
+// Custom colors are not supported by visual styles, so they are disabled for
+// the ProgressBar. Call ResetColors to restore them.
+func (pb *ProgressBar) SetBarColor(color Color) error {
+	if err := pb.ensureCustomColors(); err != nil {
+		return err
+	}
+
+	pb.barColor = color
+	pb.SendMessage(win.PBM_SETBARCOLOR, 0, uintptr(color))
+
+	return nil
+}
+
+// BackgroundColor returns the custom background color of the ProgressBar.
+func (pb *ProgressBar) BackgroundColor() Color {
+	return pb.backgroundColor
+}
+
+// SetBackgroundColor sets a custom background color for the ProgressBar.
+//
+// Custom colors are not supported by visual styles, so they are disabled for
+// the ProgressBar. Call ResetColors to restore them.
+func (pb *ProgressBar) SetBackgroundColor(color Color) error {
+	if err := pb.ensureCustomColors(); err != nil {
+		return err
+	}
+
+	pb.backgroundColor = color
+	pb.SendMessage(win.PBM_SETBKCOLOR, 0, uintptr(color))
+
+	return nil
+}
+
+// ResetColors removes custom colors from the ProgressBar and restores visual
+// styles.
+func (pb *ProgressBar) ResetColors() error {
+	if !pb.customColors {
+		return nil
+	}
+
+	pb.SendMessage(win.PBM_SETBARCOLOR, 0, win.CLR_DEFAULT)
+	pb.SendMessage(win.PBM_SETBKCOLOR, 0, win.CLR_DEFAULT)
+
+	if hr := win.SetWindowTheme(pb.hWnd, nil, nil); win.FAILED(hr) {
+		return errorFromHRESULT("SetWindowTheme", hr)
+	}
+
+	pb.customColors = false
+	pb.barColor = 0
+	pb.backgroundColor = 0
+
+	return nil
+}
+
+func (pb *ProgressBar) ensureCustomColors() error {
+	if pb.customColors {
+		return nil
+	}
+
+	empty := syscall.StringToUTF16Ptr("")
+	if hr := win.SetWindowTheme(pb.hWnd, empty, empty); win.FAILED(hr) {
+		return errorFromHRESULT("SetWindowTheme", hr)
+	}
+
+	pb.customColors = true
+
+	return nil
+}
+
 func (pb *ProgressBar) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 	return &progressBarLayoutItem{
 		idealSize: pb.dialogBaseUnitsToPixels(Size{50, 14}),
@@ -39,6 +39,10 @@ func (r Rectangle) Bottom() int {
 	return r.Y + r.Height - 1
 }
 
+func (r Rectangle) contains(p Point) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
 func (r Rectangle) Location() Point {
 	return Point{r.X, r.Y}
 }
@@ -107,6 +107,7 @@ type dataMembersSetter interface {
 type reflectTableModel struct {
 	TableModelBase
 	sorterBase  *SorterBase
+	sortColumns []SortColumn
 	lessFuncs   []func(i, j int) bool
 	dataMembers []string
 	dataSource  interface{}
@@ -141,9 +142,8 @@ func newReflectTableModel(dataSource interface{}) (TableModel, error) {
 
 			m.PublishRowsReset()
 
-			if is, ok := dataSource.(interceptedSorter); ok {
-				sb := is.sorterBase()
-				m.sort(sb.SortedColumn(), sb.SortOrder())
+			if _, ok := dataSource.(interceptedSorter); ok {
+				m.sortMulti(m.SortColumns())
 			}
 		})
 
@@ -274,18 +274,58 @@ func (m *reflectTableModel) SortOrder() SortOrder {
 }
 
 func (m *reflectTableModel) sort(col int, order SortOrder) error {
+	if col < 0 {
+		return m.sortMulti(nil)
+	}
+
+	return m.sortMulti([]SortColumn{{col, order}})
+}
+
+func (m *reflectTableModel) sortMulti(columns []SortColumn) error {
 	if sb := m.sorterBase; sb != nil {
-		sb.col, sb.order = col, order
+		m.sortColumns = append([]SortColumn(nil), columns...)
 
-		sort.Stable(m)
+		if len(columns) > 0 {
+			sb.col, sb.order = columns[0].Index, columns[0].Order
+
+			sort.Stable(m)
+		} else {
+			sb.col, sb.order = -1, SortAscending
+		}
 
 		sb.changedPublisher.Publish()
 
 		return nil
 	}
 
+	if ms, ok := m.dataSource.(MultiSorter); ok {
+		return ms.SortMulti(columns)
+	}
+
 	if sorter, ok := m.dataSource.(Sorter); ok {
-		return sorter.Sort(col, order)
+		if len(columns) == 0 {
+			return sorter.Sort(-1, SortAscending)
+		}
+
+		return sorter.Sort(columns[0].Index, columns[0].Order)
+	}
+
+	return nil
+}
+
+func (m *reflectTableModel) SortColumns() []SortColumn {
+	if m.sorterBase == nil {
+		if ms, ok := m.dataSource.(MultiSorter); ok {
+			return ms.SortColumns()
+		}
+	}
+
+	if m.sorterBase != nil && len(m.sortColumns) > 0 && m.sortColumns[0].Index == m.sorterBase.col {
+		return append([]SortColumn(nil), m.sortColumns...)
+	}
+
+	if col := m.SortedColumn(); col > -1 {
+		return []SortColumn{{col, m.SortOrder()}}
 	}
 
 	return nil
@@ -296,19 +336,30 @@ func (m *reflectTableModel) Len() int {
 }
 
 func (m *reflectTableModel) Less(i, j int) bool {
-	col := m.SortedColumn()
+	for _, sc := range m.sortColumns {
+		if lt := m.lessFuncs[sc.Index]; lt != nil {
+			ls, gt := lt(i, j), lt(j, i)
+			if ls == gt {
+				continue
+			}
 
-	if lt := m.lessFuncs[col]; lt != nil {
-		ls := lt(i, j)
+			if sc.Order == SortAscending {
+				return ls
+			} else {
+				return gt
+			}
+		}
 
-		if m.SortOrder() == SortAscending {
-			return ls
-		} else {
-			return !ls
+		vi, vj := m.Value(i, sc.Index), m.Value(j, sc.Index)
+		if less(vi, vj, sc.Order) {
+			return true
+		}
+		if less(vj, vi, sc.Order) {
+			return false
 		}
 	}
 
-	return less(m.Value(i, col), m.Value(j, col), m.SortOrder())
+	return false
 }
 
 func (m *reflectTableModel) Swap(i, j int) {
@@ -342,6 +393,10 @@ func (m *sortedReflectTableModel) Sort(col int, order SortOrder) error {
 	return m.reflectTableModel.sort(col, order)
 }
 
+func (m *sortedReflectTableModel) SortMulti(columns []SortColumn) error {
+	return m.reflectTableModel.sortMulti(columns)
+}
+
 type sortedImageReflectTableModel struct {
 	*reflectTableModel
 }
@@ -350,6 +405,10 @@ func (m *sortedImageReflectTableModel) Sort(col int, order SortOrder) error {
 	return m.reflectTableModel.sort(col, order)
 }
 
+func (m *sortedImageReflectTableModel) SortMulti(columns []SortColumn) error {
+	return m.reflectTableModel.sortMulti(columns)
+}
+
 func (m *sortedImageReflectTableModel) Image(index int) interface{} {
 	if m.value.Index(index).IsNil() {
 		return nil
@@ -0,0 +1,249 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"sort"
+)
+
+type responsiveBreakpoint struct {
+	minWidth int
+	layout   Layout
+}
+
+// ResponsiveLayout switches between layouts depending on the width of its
+// container, like media queries in CSS.
+//
+// Each layout is added for a minimum width, the breakpoint. The layout with
+// the largest breakpoint not exceeding the width of the container is active.
+// For example, a form can arrange its widgets in two columns if wide enough
+// and in one column otherwise:
+//
+//	l := walk.NewResponsiveLayout()
+//	l.AddLayout(0, oneColumnLayout)
+//	l.AddLayout(600, twoColumnsLayout)
+//	container.SetLayout(l)
+//
+// The layouts added share the container of the ResponsiveLayout, so layout
+// specific settings like GridLayout.SetRange can be made for each of them.
+type ResponsiveLayout struct {
+	container                    Container
+	breakpoints                  []responsiveBreakpoint
+	active                       Layout
+	containerSizeChangedHandle   int
+	activeLayoutChangedPublisher EventPublisher
+}
+
+// NewResponsiveLayout returns a new ResponsiveLayout without any layouts.
+func NewResponsiveLayout() *ResponsiveLayout {
+	return new(ResponsiveLayout)
+}
+
+// AddLayout adds layout to be active if the container is at least minWidth
+// wide, in 1/96" units.
+//
+// The MinSize of the container is that of the active layout, so minWidth
+// should not be smaller than the width the next narrower layout needs.
+// Otherwise the container may never get narrow enough to switch back.
+func (l *ResponsiveLayout) AddLayout(minWidth int, layout Layout) error {
+	if layout == nil {
+		return newError("layout required")
+	}
+	if layout.asLayoutBase() == nil {
+		return newError("unsupported layout")
+	}
+	if minWidth < 0 {
+		return newError("minWidth must be >= 0")
+	}
+
+	for _, bp := range l.breakpoints {
+		if bp.minWidth == minWidth {
+			return newError("a layout for minWidth exists already")
+		}
+		if bp.layout == layout {
+			return newError("layout added already")
+		}
+	}
+
+	l.breakpoints = append(l.breakpoints, responsiveBreakpoint{minWidth, layout})
+	sort.Slice(l.breakpoints, func(i, j int) bool {
+		return l.breakpoints[i].minWidth < l.breakpoints[j].minWidth
+	})
+
+	l.attachLayout(layout)
+
+	l.updateActiveLayout()
+
+	return nil
+}
+
+// Layouts returns the layouts added, ordered by breakpoint.
+func (l *ResponsiveLayout) Layouts() []Layout {
+	layouts := make([]Layout, len(l.breakpoints))
+
+	for i, bp := range l.breakpoints {
+		layouts[i] = bp.layout
+	}
+
+	return layouts
+}
+
+// ActiveLayout returns the layout that arranges the container at its current
+// width.
+func (l *ResponsiveLayout) ActiveLayout() Layout {
+	return l.active
+}
+
+// ActiveLayoutChanged returns the event that is published when the container
+// crossed a breakpoint and another layout became active.
+func (l *ResponsiveLayout) ActiveLayoutChanged() *Event {
+	return l.activeLayoutChangedPublisher.Event()
+}
+
+func (l *ResponsiveLayout) Container() Container {
+	return l.container
+}
+
+func (l *ResponsiveLayout) SetContainer(value Container) {
+	if value == l.container {
+		return
+	}
+
+	if l.container != nil {
+		l.container.SizeChanged().Detach(l.containerSizeChangedHandle)
+
+		l.container.SetLayout(nil)
+	}
+
+	l.container = value
+
+	for _, bp := range l.breakpoints {
+		l.attachLayout(bp.layout)
+	}
+
+	if value != nil {
+		if value.Layout() != Layout(l) {
+			value.SetLayout(l)
+		}
+
+		l.containerSizeChangedHandle = value.SizeChanged().Attach(func() {
+			if l.updateActiveLayout() {
+				value.RequestLayout()
+			}
+		})
+
+		l.updateActiveLayout()
+
+		value.RequestLayout()
+	}
+}
+
+// attachLayout makes layout arrange the container of the ResponsiveLayout,
+// without making it the layout of the container.
+func (l *ResponsiveLayout) attachLayout(layout Layout) {
+	lb := layout.asLayoutBase()
+
+	lb.container = l.container
+	lb.updateMargins()
+	lb.updateSpacing()
+}
+
+// Margins returns the margins of the active layout.
+func (l *ResponsiveLayout) Margins() Margins {
+	if l.active == nil {
+		return Margins{}
+	}
+
+	return l.active.Margins()
+}
+
+// SetMargins sets the margins of all layouts added.
+func (l *ResponsiveLayout) SetMargins(value Margins) error {
+	for _, bp := range l.breakpoints {
+		if err := bp.layout.SetMargins(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Spacing returns the spacing of the active layout.
+func (l *ResponsiveLayout) Spacing() int {
+	if l.active == nil {
+		return 0
+	}
+
+	return l.active.Spacing()
+}
+
+// SetSpacing sets the spacing of all layouts added.
+func (l *ResponsiveLayout) SetSpacing(value int) error {
+	for _, bp := range l.breakpoints {
+		if err := bp.layout.SetSpacing(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// asLayoutBase returns the LayoutBase of the active layout, so the layout
+// items get its margins, spacing and alignment.
+func (l *ResponsiveLayout) asLayoutBase() *LayoutBase {
+	if l.active == nil {
+		return nil
+	}
+
+	return l.active.asLayoutBase()
+}
+
+func (l *ResponsiveLayout) CreateLayoutItem(ctx *LayoutContext) ContainerLayoutItem {
+	l.updateActiveLayout()
+
+	if l.active == nil {
+		return NewHBoxLayout().CreateLayoutItem(ctx)
+	}
+
+	return l.active.CreateLayoutItem(ctx)
+}
+
+// layoutForWidth returns the layout for a container width in 1/96" units.
+func (l *ResponsiveLayout) layoutForWidth(width int) Layout {
+	var layout Layout
+
+	for _, bp := range l.breakpoints {
+		if bp.minWidth > width && layout != nil {
+			break
+		}
+
+		layout = bp.layout
+	}
+
+	return layout
+}
+
+// updateActiveLayout activates the layout for the current width of the
+// container and returns if that changed the active layout.
+func (l *ResponsiveLayout) updateActiveLayout() bool {
+	var width int
+	if l.container != nil {
+		wb := l.container.AsWindowBase()
+		width = IntTo96DPI(wb.ClientBoundsPixels().Width, wb.DPI())
+	}
+
+	layout := l.layoutForWidth(width)
+	if layout == l.active {
+		return false
+	}
+
+	l.active = layout
+
+	l.activeLayoutChangedPublisher.Publish()
+
+	return true
+}
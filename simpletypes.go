@@ -29,3 +29,12 @@ const (
 	AlignHCenterVFar
 	AlignHFarVFar
 )
+
+type Corner uint
+
+const (
+	CornerTopLeft Corner = iota
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+)
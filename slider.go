@@ -7,7 +7,9 @@
 package walk
 
 import (
+	"sort"
 	"strconv"
+	"unsafe"
 
 	"github.com/lxn/win"
 )
@@ -18,6 +20,8 @@ type Slider struct {
 	layoutFlags           LayoutFlags
 	tracking              bool
 	persistent            bool
+	tickLabels            map[int]string
+	mapper                SliderMapper
 }
 
 type SliderCfg struct {
@@ -153,8 +157,137 @@ func (sl *Slider) SetTracking(tracking bool) {
 	sl.tracking = tracking
 }
 
+// TickLabels returns the labels that are displayed at tick positions of the
+// Slider.
+func (sl *Slider) TickLabels() map[int]string {
+	labels := make(map[int]string, len(sl.tickLabels))
+	for pos, label := range sl.tickLabels {
+		labels[pos] = label
+	}
+
+	return labels
+}
+
+// SetTickLabels sets labels that are displayed at tick positions of the
+// Slider, below the track for horizontal and to its right for vertical
+// Sliders. A tick mark is shown at each labeled position.
+func (sl *Slider) SetTickLabels(labels map[int]string) {
+	sl.SendMessage(tbmClearTics, 0, 0)
+
+	sl.tickLabels = make(map[int]string, len(labels))
+	for pos, label := range labels {
+		sl.tickLabels[pos] = label
+		sl.SendMessage(tbmSetTic, 0, uintptr(pos))
+	}
+
+	sl.RequestLayout()
+	sl.Invalidate()
+}
+
+func (sl *Slider) tickLabelPositions() []int {
+	positions := make([]int, 0, len(sl.tickLabels))
+	for pos := range sl.tickLabels {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+
+	return positions
+}
+
+// tickLabelsSize returns the size in native pixels the tick labels occupy
+// across the track.
+func (sl *Slider) tickLabelsSize() int {
+	var size int
+
+	for _, label := range sl.tickLabels {
+		s := sl.calculateTextSizeImpl(label)
+
+		if sl.hasStyleBits(win.TBS_VERT) {
+			size = maxi(size, s.Width)
+		} else {
+			size = maxi(size, s.Height)
+		}
+	}
+
+	return size
+}
+
+func (sl *Slider) drawTickLabels(hdc win.HDC) {
+	if len(sl.tickLabels) == 0 {
+		return
+	}
+
+	min, max := sl.MinValue(), sl.MaxValue()
+	if max <= min {
+		return
+	}
+
+	var channel, thumb win.RECT
+	sl.SendMessage(tbmGetChannelRect, 0, uintptr(unsafe.Pointer(&channel)))
+	sl.SendMessage(tbmGetThumbRect, 0, uintptr(unsafe.Pointer(&thumb)))
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	vertical := sl.hasStyleBits(win.TBS_VERT)
+	font := sl.Font()
+	color := Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+	if !sl.Enabled() {
+		color = Color(win.GetSysColor(win.COLOR_GRAYTEXT))
+	}
+	cb := sl.ClientBoundsPixels()
+
+	for _, pos := range sl.tickLabelPositions() {
+		if pos < min || pos > max {
+			continue
+		}
+
+		label := sl.tickLabels[pos]
+		size := sl.calculateTextSizeImpl(label)
+
+		var bounds Rectangle
+		if vertical {
+			// For vertical trackbars the channel rect is reported rotated.
+			thumbLen := int(thumb.Bottom - thumb.Top)
+			span := int(channel.Right-channel.Left) - thumbLen
+			y := int(channel.Left) + thumbLen/2 + (pos-min)*span/(max-min)
+
+			bounds = Rectangle{int(thumb.Right), y - size.Height/2, cb.Width - int(thumb.Right), size.Height}
+		} else {
+			thumbLen := int(thumb.Right - thumb.Left)
+			span := int(channel.Right-channel.Left) - thumbLen
+			x := int(channel.Left) + thumbLen/2 + (pos-min)*span/(max-min)
+
+			bounds = Rectangle{x - size.Width/2, int(thumb.Bottom), size.Width, size.Height}
+			if bounds.X < 0 {
+				bounds.X = 0
+			} else if bounds.X+bounds.Width > cb.Width {
+				bounds.X = cb.Width - bounds.Width
+			}
+		}
+
+		canvas.DrawText(label, font, color, bounds.To96DPI(canvas.DPI()), TextLeft|TextSingleLine|TextNoPrefix)
+	}
+}
+
 func (sl *Slider) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
+	case win.WM_NOTIFY:
+		nmcd := (*win.NMCUSTOMDRAW)(unsafe.Pointer(lParam))
+
+		if nmcd.Hdr.Code == win.NM_CUSTOMDRAW && len(sl.tickLabels) > 0 {
+			switch nmcd.DwDrawStage {
+			case win.CDDS_PREPAINT:
+				return win.CDRF_NOTIFYPOSTPAINT
+
+			case win.CDDS_POSTPAINT:
+				sl.drawTickLabels(nmcd.Hdc)
+			}
+		}
+
 	case win.WM_HSCROLL, win.WM_VSCROLL:
 		switch win.LOWORD(uint32(wParam)) {
 		case win.TB_THUMBPOSITION, win.TB_ENDTRACK:
@@ -175,9 +308,19 @@ func (*Slider) NeedsWmSize() bool {
 }
 
 func (sl *Slider) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
+	idealSize := sl.dialogBaseUnitsToPixels(Size{15, 15})
+
+	if labelsSize := sl.tickLabelsSize(); labelsSize > 0 {
+		if sl.hasStyleBits(win.TBS_VERT) {
+			idealSize.Width += labelsSize
+		} else {
+			idealSize.Height += labelsSize
+		}
+	}
+
 	return &sliderLayoutItem{
 		layoutFlags: sl.layoutFlags,
-		idealSize:   sl.dialogBaseUnitsToPixels(Size{15, 15}),
+		idealSize:   idealSize,
 	}
 }
 
@@ -0,0 +1,141 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"math"
+)
+
+// SliderMapper maps between the integer positions of a Slider and the values
+// they represent.
+type SliderMapper interface {
+	ValueFromPosition(position int) float64
+	PositionFromValue(value float64) int
+}
+
+type linearSliderMapper struct {
+	minPos, maxPos     int
+	minValue, maxValue float64
+}
+
+// NewLinearSliderMapper returns a SliderMapper that maps positions in the
+// range [minPos, maxPos] linearly to values in the range [minValue, maxValue].
+func NewLinearSliderMapper(minPos, maxPos int, minValue, maxValue float64) (SliderMapper, error) {
+	if minPos >= maxPos {
+		return nil, newError("minPos must be < maxPos")
+	}
+
+	return &linearSliderMapper{minPos, maxPos, minValue, maxValue}, nil
+}
+
+func (m *linearSliderMapper) ValueFromPosition(position int) float64 {
+	f := float64(position-m.minPos) / float64(m.maxPos-m.minPos)
+
+	return m.minValue + f*(m.maxValue-m.minValue)
+}
+
+func (m *linearSliderMapper) PositionFromValue(value float64) int {
+	if m.maxValue == m.minValue {
+		return m.minPos
+	}
+
+	f := (value - m.minValue) / (m.maxValue - m.minValue)
+
+	return m.minPos + int(math.Round(f*float64(m.maxPos-m.minPos)))
+}
+
+type logSliderMapper struct {
+	minPos, maxPos         int
+	logMinValue, logFactor float64
+}
+
+// NewLogSliderMapper returns a SliderMapper that maps positions in the range
+// [minPos, maxPos] logarithmically to values in the range
+// [minValue, maxValue], so that equal distances on the Slider correspond to
+// equal ratios of values. Both minValue and maxValue must be > 0.
+func NewLogSliderMapper(minPos, maxPos int, minValue, maxValue float64) (SliderMapper, error) {
+	if minPos >= maxPos {
+		return nil, newError("minPos must be < maxPos")
+	}
+	if minValue <= 0 || maxValue <= 0 {
+		return nil, newError("minValue and maxValue must be > 0")
+	}
+
+	logMin := math.Log(minValue)
+
+	return &logSliderMapper{
+		minPos:      minPos,
+		maxPos:      maxPos,
+		logMinValue: logMin,
+		logFactor:   (math.Log(maxValue) - logMin) / float64(maxPos-minPos),
+	}, nil
+}
+
+func (m *logSliderMapper) ValueFromPosition(position int) float64 {
+	return math.Exp(m.logMinValue + float64(position-m.minPos)*m.logFactor)
+}
+
+func (m *logSliderMapper) PositionFromValue(value float64) int {
+	if value <= 0 || m.logFactor == 0 {
+		return m.minPos
+	}
+
+	return m.minPos + int(math.Round((math.Log(value)-m.logMinValue)/m.logFactor))
+}
+
+// Mapper returns the SliderMapper of the Slider, or nil if positions are used
+// as values directly.
+func (sl *Slider) Mapper() SliderMapper {
+	return sl.mapper
+}
+
+// SetMapper sets the SliderMapper of the Slider.
+//
+// The mapper does not change the range of the Slider, which continues to be
+// set in positions using SetRange.
+func (sl *Slider) SetMapper(mapper SliderMapper) {
+	sl.mapper = mapper
+}
+
+// ValueFromPosition returns the value that position represents, according to
+// the SliderMapper of the Slider.
+func (sl *Slider) ValueFromPosition(position int) float64 {
+	if sl.mapper == nil {
+		return float64(position)
+	}
+
+	return sl.mapper.ValueFromPosition(position)
+}
+
+// PositionFromValue returns the position that represents value, according to
+// the SliderMapper of the Slider.
+func (sl *Slider) PositionFromValue(value float64) int {
+	if sl.mapper == nil {
+		return int(math.Round(value))
+	}
+
+	return sl.mapper.PositionFromValue(value)
+}
+
+// MappedValue returns the value that the current position of the Slider
+// represents.
+func (sl *Slider) MappedValue() float64 {
+	return sl.ValueFromPosition(sl.Value())
+}
+
+// SetMappedValue moves the Slider to the position that represents value.
+func (sl *Slider) SetMappedValue(value float64) {
+	pos := sl.PositionFromValue(value)
+
+	if min := sl.MinValue(); pos < min {
+		pos = min
+	} else if max := sl.MaxValue(); pos > max {
+		pos = max
+	}
+
+	sl.SetValue(pos)
+}
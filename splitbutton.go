@@ -16,7 +16,8 @@ import (
 
 type SplitButton struct {
 	Button
-	menu *Menu
+	menu              *Menu
+	dropDownPublisher EventPublisher
 }
 
 func NewSplitButton(parent Container) (*SplitButton, error) {
@@ -77,6 +78,38 @@ func (sb *SplitButton) Menu() *Menu {
 	return sb.menu
 }
 
+// DropDown returns the event that is published right before the menu of the
+// SplitButton is shown, which allows to populate it lazily.
+func (sb *SplitButton) DropDown() *Event {
+	return sb.dropDownPublisher.Event()
+}
+
+// ShowMenu shows the menu of the SplitButton below the button, as if the user
+// had clicked the drop down arrow.
+func (sb *SplitButton) ShowMenu() {
+	cb := sb.ClientBoundsPixels()
+
+	sb.showMenu(win.POINT{X: int32(cb.X), Y: int32(cb.Y + cb.Height)})
+}
+
+func (sb *SplitButton) showMenu(p win.POINT) {
+	sb.dropDownPublisher.Publish()
+
+	if sb.menu.Actions().Len() == 0 {
+		return
+	}
+
+	win.ClientToScreen(sb.hWnd, &p)
+
+	win.TrackPopupMenuEx(
+		sb.menu.hMenu,
+		win.TPM_NOANIMATION,
+		p.X,
+		p.Y,
+		sb.hWnd,
+		nil)
+}
+
 func (sb *SplitButton) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
 	case win.WM_NOTIFY:
@@ -84,17 +117,19 @@ func (sb *SplitButton) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr
 		case win.BCN_DROPDOWN:
 			dd := (*win.NMBCDROPDOWN)(unsafe.Pointer(lParam))
 
-			p := win.POINT{dd.RcButton.Left, dd.RcButton.Bottom}
+			sb.showMenu(win.POINT{dd.RcButton.Left, dd.RcButton.Bottom})
+			return 0
+		}
 
-			win.ClientToScreen(sb.hWnd, &p)
+	case win.WM_SYSKEYDOWN:
+		if Key(wParam) == KeyDown {
+			sb.ShowMenu()
+			return 0
+		}
 
-			win.TrackPopupMenuEx(
-				sb.menu.hMenu,
-				win.TPM_NOANIMATION,
-				p.X,
-				p.Y,
-				sb.hWnd,
-				nil)
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeyF4 {
+			sb.ShowMenu()
 			return 0
 		}
 	}
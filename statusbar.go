@@ -18,7 +18,8 @@ import (
 // StatusBar is a widget that displays status messages.
 type StatusBar struct {
 	WidgetBase
-	items *StatusBarItemList
+	items                *StatusBarItemList
+	itemClickedPublisher IntEventPublisher
 }
 
 // NewStatusBar returns a new StatusBar as child of container parent.
@@ -44,6 +45,33 @@ func (sb *StatusBar) Items() *StatusBarItemList {
 	return sb.items
 }
 
+// ItemClicked returns the event that is published with the index of the
+// StatusBarItem that was clicked.
+func (sb *StatusBar) ItemClicked() *IntEvent {
+	return sb.itemClickedPublisher.Event()
+}
+
+func (sb *StatusBar) Dispose() {
+	for _, item := range sb.items.items {
+		if item.widget != nil {
+			item.widget.Dispose()
+			item.widget = nil
+		}
+	}
+
+	sb.WidgetBase.Dispose()
+}
+
+func (sb *StatusBar) applyFont(font *Font) {
+	sb.WidgetBase.applyFont(font)
+
+	for _, item := range sb.items.items {
+		if item.widget != nil {
+			item.widget.(applyFonter).applyFont(font)
+		}
+	}
+}
+
 // SetVisible sets whether the StatusBar is visible.
 func (sb *StatusBar) SetVisible(visible bool) {
 	sb.WidgetBase.SetVisible(visible)
@@ -54,6 +82,12 @@ func (sb *StatusBar) SetVisible(visible bool) {
 func (sb *StatusBar) ApplyDPI(dpi int) {
 	sb.WidgetBase.ApplyDPI(dpi)
 
+	for _, item := range sb.items.items {
+		if item.widget != nil {
+			applyDPIToDescendants(item.widget, dpi)
+		}
+	}
+
 	sb.update()
 }
 
@@ -70,6 +104,8 @@ func (sb *StatusBar) update() error {
 
 	sb.SetVisible(sb.items.Len() > 0)
 
+	sb.updateWidgets()
+
 	return nil
 }
 
@@ -99,21 +135,77 @@ func (sb *StatusBar) updateParts() error {
 		return newError("SB_SETPARTS")
 	}
 
+	sb.updateWidgets()
+
+	return nil
+}
+
+// updateWidgets moves the widgets of the items into their parts.
+func (sb *StatusBar) updateWidgets() {
+	for i, item := range sb.items.items {
+		if item.widget == nil {
+			continue
+		}
+
+		var r win.RECT
+		if 0 == sb.SendMessage(win.SB_GETRECT, uintptr(i), uintptr(unsafe.Pointer(&r))) {
+			continue
+		}
+
+		inset := int32(sb.IntFrom96DPI(2))
+
+		item.widget.SetBoundsPixels(Rectangle{
+			int(r.Left + inset),
+			int(r.Top + inset),
+			int(r.Right - r.Left - 2*inset),
+			int(r.Bottom - r.Top - 2*inset),
+		})
+	}
+}
+
+// widgetFromHandle returns the widget of an item with the specified handle.
+func (sb *StatusBar) widgetFromHandle(hwnd win.HWND) Widget {
+	for _, item := range sb.items.items {
+		if item.widget != nil && item.widget.Handle() == hwnd {
+			return item.widget
+		}
+	}
+
 	return nil
 }
 
 func (sb *StatusBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	switch msg {
+	case win.WM_COMMAND:
+		if widget := sb.widgetFromHandle(win.HWND(lParam)); widget != nil {
+			// Notification from the widget of an item.
+			widget.WndProc(hwnd, msg, wParam, lParam)
+			return 0
+		}
+
 	case win.WM_NOTIFY:
 		nmhdr := (*win.NMHDR)(unsafe.Pointer(lParam))
 
+		if widget := sb.widgetFromHandle(nmhdr.HwndFrom); widget != nil {
+			// Notification from the widget of an item.
+			return widget.WndProc(hwnd, msg, wParam, lParam)
+		}
+
 		switch nmhdr.Code {
 		case win.NM_CLICK:
 			lpnm := (*win.NMMOUSE)(unsafe.Pointer(lParam))
 			if n := int(lpnm.DwItemSpec); n >= 0 && n < sb.items.Len() {
 				sb.items.At(n).raiseClicked()
+				sb.itemClickedPublisher.Publish(n)
 			}
 		}
+
+	case win.WM_WINDOWPOSCHANGED:
+		wp := (*win.WINDOWPOS)(unsafe.Pointer(lParam))
+
+		if wp.Flags&win.SWP_NOSIZE == 0 {
+			defer sb.updateWidgets()
+		}
 	}
 
 	return sb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
@@ -143,6 +235,7 @@ type StatusBarItem struct {
 	text             string
 	toolTipText      string
 	width            int
+	widget           Widget
 	clickedPublisher EventPublisher
 }
 
@@ -234,6 +327,51 @@ func (sbi *StatusBarItem) SetWidth(width int) error {
 	return nil
 }
 
+// Widget returns the Widget displayed in the StatusBarItem, if any.
+func (sbi *StatusBarItem) Widget() Widget {
+	return sbi.widget
+}
+
+// SetWidget sets a Widget, e.g. a small ProgressBar, to be displayed in the
+// StatusBarItem, covering its icon and text.
+//
+// The widget is taken out of the Children of its parent, has no parent
+// afterwards and its bounds are relative to the StatusBar. Passing nil hides
+// a previously set widget and leaves it without a parent window.
+func (sbi *StatusBarItem) SetWidget(widget Widget) error {
+	if widget == sbi.widget {
+		return nil
+	}
+
+	if old := sbi.widget; old != nil {
+		sbi.widget = nil
+
+		if err := old.AsWidgetBase().unembed(); err != nil {
+			return err
+		}
+	}
+
+	if widget == nil {
+		return nil
+	}
+
+	if sbi.sb == nil {
+		return newError("item must be contained in a StatusBar")
+	}
+
+	if err := widget.AsWidgetBase().embedInto(sbi.sb.hWnd); err != nil {
+		return err
+	}
+
+	widget.(applyFonter).applyFont(sbi.sb.Font())
+
+	sbi.widget = widget
+
+	sbi.sb.updateWidgets()
+
+	return nil
+}
+
 func (sbi *StatusBarItem) Clicked() *Event {
 	return sbi.clickedPublisher.Event()
 }
@@ -341,6 +479,12 @@ func (l *StatusBarItemList) At(index int) *StatusBarItem {
 }
 
 func (l *StatusBarItemList) Clear() error {
+	for _, item := range l.items {
+		if err := item.SetWidget(nil); err != nil {
+			return err
+		}
+	}
+
 	old := l.items
 	l.items = l.items[:0]
 
@@ -421,6 +565,11 @@ func (l *StatusBarItemList) Remove(item *StatusBarItem) error {
 
 func (l *StatusBarItemList) RemoveAt(index int) error {
 	item := l.items[index]
+
+	if err := item.SetWidget(nil); err != nil {
+		return err
+	}
+
 	item.sb = nil
 
 	l.items = append(l.items[:index], l.items[index+1:]...)
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 	"unsafe"
@@ -42,6 +44,12 @@ const (
 	tableViewSelectedIndexesChangedTimerId
 )
 
+type tableViewToolTipCell struct {
+	hwnd    win.HWND
+	item    int32
+	subItem int32
+}
+
 type TableViewCfg struct {
 	Style              uint32
 	CustomHeaderHeight int
@@ -52,6 +60,8 @@ type TableViewCfg struct {
 //
 // TableView is implemented as a virtual mode list view to support quite large
 // amounts of data.
+//
+// Rows can be grouped under collapsible group headers, see GroupBy.
 type TableView struct {
 	WidgetBase
 	hwndFrozenLV                       win.HWND
@@ -68,6 +78,9 @@ type TableView struct {
 	providedModel                      interface{}
 	itemChecker                        ItemChecker
 	imageProvider                      ImageProvider
+	cellToolTipProvider                CellToolTipProvider
+	toolTipCell                        tableViewToolTipCell
+	groupProvider                      TableGroupProvider
 	styler                             CellStyler
 	style                              CellStyle
 	itemFont                           *Font
@@ -92,6 +105,7 @@ type TableView struct {
 	columnsOrderableChangedPublisher   EventPublisher
 	columnsSizableChangedPublisher     EventPublisher
 	itemCountChangedPublisher          EventPublisher
+	groupCollapsedChangedPublisher     StringEventPublisher
 	publishNextSelClear                bool
 	inSetSelectedIndexes               bool
 	lastColumnStretched                bool
@@ -107,9 +121,19 @@ type TableView struct {
 	alternatingRowBGColor              Color
 	alternatingRowTextColor            Color
 	alternatingRowBG                   bool
+	darkMode                           bool
 	delayedCurrentIndexChangedCanceled bool
 	sortedColumnIndex                  int
 	sortOrder                          SortOrder
+	sortColumns                        []SortColumn
+	searchColumn                       int
+	searchPredicate                    func(row int, prefix string) bool
+	groupColumn                        int
+	groupCollapsed                     map[string]bool
+	groups                             []*tableViewGroup
+	groupItems                         []tableViewGroupItem
+	row2Item                           []int
+	row2Group                          []*tableViewGroup
 	formActivatingHandle               int
 	customHeaderHeight                 int
 	customRowHeight                    int
@@ -142,6 +166,7 @@ func NewTableViewWithCfg(parent Container, cfg *TableViewCfg) (*TableView, error
 		imageUintptr2Index:   make(map[uintptr]int32),
 		filePath2IconIndex:   make(map[string]int32),
 		formActivatingHandle: -1,
+		groupColumn:          -1,
 		customHeaderHeight:   cfg.CustomHeaderHeight,
 		customRowHeight:      cfg.CustomRowHeight,
 	}
@@ -453,10 +478,33 @@ func (tv *TableView) ApplySysColors() {
 		})
 	}
 
+	if tv.darkMode {
+		tv.themeNormalBGColor = darkModeControlColor
+		tv.themeNormalTextColor = darkModeTextColor
+		tv.themeSelectedNotFocusedBGColor = darkModeSelectedNotFocusedColor
+		tv.alternatingRowBGColor = darkModeBackgroundColor
+		tv.alternatingRowTextColor = darkModeTextColor
+	}
+
 	win.SendMessage(tv.hwndNormalLV, win.LVM_SETBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
 	win.SendMessage(tv.hwndFrozenLV, win.LVM_SETBKCOLOR, 0, uintptr(tv.themeNormalBGColor))
 }
 
+func (tv *TableView) applyDarkMode(dark bool) {
+	tv.darkMode = dark
+
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+		setDarkModeTheme(hwnd, dark, "DarkMode_Explorer", "Explorer")
+	}
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenHdr, tv.hwndNormalHdr} {
+		setDarkModeTheme(hwnd, dark, "DarkMode_ItemsView", "")
+	}
+
+	tv.ApplySysColors()
+
+	tv.Invalidate()
+}
+
 // ColumnsOrderable returns if the user can reorder columns by dragging and
 // dropping column headers.
 func (tv *TableView) ColumnsOrderable() bool {
@@ -624,6 +672,42 @@ func (tv *TableView) Columns() *TableViewColumnList {
 	return tv.columns
 }
 
+// FrozenColumnCount returns the number of leading columns that are frozen.
+//
+// Frozen columns stay visible while the other columns are scrolled
+// horizontally.
+func (tv *TableView) FrozenColumnCount() int {
+	var count int
+
+	for _, tvc := range tv.columns.items {
+		if !tvc.frozen {
+			break
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// SetFrozenColumnCount freezes the first n columns and unfreezes all others.
+//
+// Frozen columns are displayed in a separate part of the TableView, so they
+// can only be reordered among each other.
+func (tv *TableView) SetFrozenColumnCount(n int) error {
+	if n < 0 || n > tv.columns.Len() {
+		return newError("n out of range")
+	}
+
+	for i, tvc := range tv.columns.items {
+		if err := tvc.SetFrozen(i < n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // VisibleColumnsInDisplayOrder returns a slice of visible columns in display
 // order.
 func (tv *TableView) VisibleColumnsInDisplayOrder() []*TableViewColumn {
@@ -680,6 +764,35 @@ func (tv *TableView) UpdateItem(index int) error {
 		}
 
 		return tv.Invalidate()
+	} else if tv.isGrouped() {
+		if index < 0 || index >= len(tv.row2Group) {
+			return newError("index out of range")
+		}
+
+		if tv.regroupRow(index) {
+			// The row moved to another group, which moves the items of
+			// the groups in between.
+			if err := tv.applyItemCount(); err != nil {
+				return err
+			}
+
+			return tv.Invalidate()
+		}
+
+		// The title of the group may depend on the row, so its header is
+		// redrawn, too.
+		for _, item := range [...]int{tv.row2Group[index].item, tv.rowToItem(index)} {
+			if item < 0 {
+				continue
+			}
+
+			if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_UPDATE, uintptr(item), 0) {
+				return newError("LVM_UPDATE")
+			}
+			if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_UPDATE, uintptr(item), 0) {
+				return newError("LVM_UPDATE")
+			}
+		}
 	} else {
 		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_UPDATE, uintptr(index), 0) {
 			return newError("LVM_UPDATE")
@@ -713,6 +826,8 @@ func (tv *TableView) attachModel() {
 		if from <= i {
 			i += 1 + to - from
 
+			tv.SetCurrentIndex(i)
+		} else if tv.isGrouped() {
 			tv.SetCurrentIndex(i)
 		}
 
@@ -732,7 +847,7 @@ func (tv *TableView) attachModel() {
 			index -= 1 + to - from
 		}
 
-		if index != i {
+		if index != i || tv.isGrouped() {
 			tv.SetCurrentIndex(index)
 		}
 
@@ -741,8 +856,18 @@ func (tv *TableView) attachModel() {
 
 	if sorter, ok := tv.model.(Sorter); ok {
 		tv.sortChangedHandlerHandle = sorter.SortChanged().Attach(func() {
-			col := sorter.SortedColumn()
-			tv.setSortIcon(col, sorter.SortOrder())
+			if ms, ok := sorter.(MultiSorter); ok {
+				tv.sortColumns = append([]SortColumn(nil), ms.SortColumns()...)
+			} else if col := sorter.SortedColumn(); col > -1 {
+				tv.sortColumns = []SortColumn{{col, sorter.SortOrder()}}
+			} else {
+				tv.sortColumns = nil
+			}
+
+			tv.setSortIcons(tv.sortColumns)
+			if tv.isGrouped() {
+				tv.setItemCount()
+			}
 			tv.Invalidate()
 		})
 	}
@@ -809,6 +934,19 @@ func (tv *TableView) SetModel(mdl interface{}) error {
 
 	tv.itemChecker, _ = model.(ItemChecker)
 	tv.imageProvider, _ = model.(ImageProvider)
+	tv.cellToolTipProvider, _ = mdl.(CellToolTipProvider)
+	tv.groupProvider, _ = mdl.(TableGroupProvider)
+	tv.groupCollapsed = nil
+
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+		exStyle := win.SendMessage(hwnd, win.LVM_GETEXTENDEDLISTVIEWSTYLE, 0, 0)
+		if tv.cellToolTipProvider != nil {
+			exStyle |= win.LVS_EX_INFOTIP
+		} else {
+			exStyle &^= win.LVS_EX_INFOTIP
+		}
+		win.SendMessage(hwnd, win.LVM_SETEXTENDEDLISTVIEWSTYLE, 0, exStyle)
+	}
 
 	if model != nil {
 		tv.attachModel()
@@ -877,12 +1015,22 @@ func (tv *TableView) SetCellStyler(styler CellStyler) {
 	tv.styler = styler
 }
 
+// SetCellFormatter sets a CellStyler for the TableView that styles cells
+// according to formatter.
+func (tv *TableView) SetCellFormatter(formatter CellFormatter) {
+	tv.styler = CellStylerFromFormatter(formatter)
+}
+
 func (tv *TableView) setItemCount() error {
-	var count int
+	tv.updateGroups()
 
-	if tv.model != nil {
-		count = tv.model.RowCount()
-	}
+	return tv.applyItemCount()
+}
+
+// applyItemCount passes the number of items to the list views, without
+// regrouping the rows.
+func (tv *TableView) applyItemCount() error {
+	count := tv.itemCount()
 
 	if 0 == win.SendMessage(tv.hwndFrozenLV, win.LVM_SETITEMCOUNT, uintptr(count), win.LVSICF_NOSCROLL) {
 		return newError("SendMessage(LVM_SETITEMCOUNT)")
@@ -891,6 +1039,10 @@ func (tv *TableView) setItemCount() error {
 		return newError("SendMessage(LVM_SETITEMCOUNT)")
 	}
 
+	if tv.isGrouped() {
+		tv.restoreGroupedItemStates()
+	}
+
 	return nil
 }
 
@@ -1017,9 +1169,70 @@ func (tv *TableView) visibleColumns() []*TableViewColumn {
 // 	tv.SendMessage(win.LVM_SETSELECTEDCOLUMN, uintptr(tv.toLVColIdx(index)), 0)
 // }
 
-func (tv *TableView) setSortIcon(index int, order SortOrder) error {
-	idx := int(tv.toLVColIdx(index))
+// SortColumns returns the columns the model of the TableView is currently
+// sorted by, the primary sort column first.
+func (tv *TableView) SortColumns() []SortColumn {
+	return append([]SortColumn(nil), tv.sortColumns...)
+}
+
+// SetSortColumns sorts the model of the TableView by columns, the primary
+// sort column first.
+//
+// Sorting by more than one column requires the model to implement
+// MultiSorter. The user can add columns to the sort by Ctrl-clicking their
+// headers.
+func (tv *TableView) SetSortColumns(columns []SortColumn) error {
+	if ms, ok := tv.model.(MultiSorter); ok {
+		if len(columns) > 0 {
+			tv.sortedColumnIndex = columns[0].Index
+			tv.sortOrder = columns[0].Order
+		}
+
+		return ms.SortMulti(columns)
+	}
+
+	sorter, ok := tv.model.(Sorter)
+	if !ok {
+		return newError("model must implement Sorter")
+	}
+
+	switch len(columns) {
+	case 0:
+		return sorter.Sort(-1, SortAscending)
+
+	case 1:
+		tv.sortedColumnIndex = columns[0].Index
+		tv.sortOrder = columns[0].Order
+
+		return sorter.Sort(columns[0].Index, columns[0].Order)
+	}
+
+	return newError("model must implement MultiSorter to sort by multiple columns")
+}
+
+// sortColumnsAfterCtrlClick returns the sort columns that result from
+// Ctrl-clicking the header of column col: An already sorted column has its
+// order toggled, other columns are appended in ascending order.
+func sortColumnsAfterCtrlClick(columns []SortColumn, col int) []SortColumn {
+	// The slice may belong to the model, so never modify it in place.
+	columns = append([]SortColumn(nil), columns...)
+
+	for i, sc := range columns {
+		if sc.Index == col {
+			if sc.Order == SortAscending {
+				columns[i].Order = SortDescending
+			} else {
+				columns[i].Order = SortAscending
+			}
+
+			return columns
+		}
+	}
+
+	return append(columns, SortColumn{col, SortAscending})
+}
 
+func (tv *TableView) setSortIcons(columns []SortColumn) error {
 	frozenCount := tv.visibleFrozenColumnCount()
 
 	for i, col := range tv.visibleColumns() {
@@ -1043,18 +1256,20 @@ func (tv *TableView) setSortIcon(index int, order SortOrder) error {
 			return newError("SendMessage(HDM_GETITEM)")
 		}
 
-		if i == idx {
-			switch order {
+		item.Fmt &^= win.HDF_SORTDOWN | win.HDF_SORTUP
+
+		for _, sc := range columns {
+			if int(tv.toLVColIdx(sc.Index)) != i {
+				continue
+			}
+
+			switch sc.Order {
 			case SortAscending:
-				item.Fmt &^= win.HDF_SORTDOWN
 				item.Fmt |= win.HDF_SORTUP
 
 			case SortDescending:
-				item.Fmt &^= win.HDF_SORTUP
 				item.Fmt |= win.HDF_SORTDOWN
 			}
-		} else {
-			item.Fmt &^= win.HDF_SORTDOWN | win.HDF_SORTUP
 		}
 
 		if win.SendMessage(headerHwnd, win.HDM_SETITEM, iPtr, itemPtr) == 0 {
@@ -1062,9 +1277,24 @@ func (tv *TableView) setSortIcon(index int, order SortOrder) error {
 		}
 	}
 
+	win.InvalidateRect(tv.hwndFrozenHdr, nil, true)
+	win.InvalidateRect(tv.hwndNormalHdr, nil, true)
+
 	return nil
 }
 
+// sortRank returns the 1-based position of column col among the sort
+// columns, or 0 if it is not sorted.
+func (tv *TableView) sortRank(col int) int {
+	for i, sc := range tv.sortColumns {
+		if sc.Index == col {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
 // ColumnClicked returns the event that is published after a column header was
 // clicked.
 func (tv *TableView) ColumnClicked() *IntEvent {
@@ -1098,6 +1328,12 @@ func (tv *TableView) SetCurrentIndex(index int) error {
 		tv.inSetCurrentIndex = false
 	}()
 
+	if err := tv.expandGroupOfRow(index); err != nil {
+		return err
+	}
+
+	item := tv.rowToItem(index)
+
 	var lvi win.LVITEM
 
 	lvi.StateMask = win.LVIS_FOCUSED | win.LVIS_SELECTED
@@ -1111,30 +1347,30 @@ func (tv *TableView) SetCurrentIndex(index int) error {
 		}
 	}
 
-	if index > -1 {
+	if item > -1 {
 		lvi.State = win.LVIS_FOCUSED | win.LVIS_SELECTED
 	}
 
-	if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_SETITEMSTATE, uintptr(index), uintptr(unsafe.Pointer(&lvi))) {
+	if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_SETITEMSTATE, uintptr(item), uintptr(unsafe.Pointer(&lvi))) {
 		return newError("SendMessage(LVM_SETITEMSTATE)")
 	}
-	if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_SETITEMSTATE, uintptr(index), uintptr(unsafe.Pointer(&lvi))) {
+	if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_SETITEMSTATE, uintptr(item), uintptr(unsafe.Pointer(&lvi))) {
 		return newError("SendMessage(LVM_SETITEMSTATE)")
 	}
 
-	if index != -1 {
-		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_ENSUREVISIBLE, uintptr(index), uintptr(0)) {
+	if item != -1 {
+		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_ENSUREVISIBLE, uintptr(item), uintptr(0)) {
 			return newError("SendMessage(LVM_ENSUREVISIBLE)")
 		}
 		// Windows bug? Sometimes a second LVM_ENSUREVISIBLE is required.
-		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_ENSUREVISIBLE, uintptr(index), uintptr(0)) {
+		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_ENSUREVISIBLE, uintptr(item), uintptr(0)) {
 			return newError("SendMessage(LVM_ENSUREVISIBLE)")
 		}
-		if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(index), uintptr(0)) {
+		if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(item), uintptr(0)) {
 			return newError("SendMessage(LVM_ENSUREVISIBLE)")
 		}
 		// Windows bug? Sometimes a second LVM_ENSUREVISIBLE is required.
-		if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(index), uintptr(0)) {
+		if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(item), uintptr(0)) {
 			return newError("SendMessage(LVM_ENSUREVISIBLE)")
 		}
 	}
@@ -1160,12 +1396,21 @@ func (tv *TableView) CurrentIndexChanged() *Event {
 
 // ItemVisible returns whether the item at position index is visible.
 func (tv *TableView) ItemVisible(index int) bool {
-	return 0 != win.SendMessage(tv.hwndNormalLV, win.LVM_ISITEMVISIBLE, uintptr(index), 0)
+	item := tv.rowToItem(index)
+	if item == -1 {
+		return false
+	}
+
+	return 0 != win.SendMessage(tv.hwndNormalLV, win.LVM_ISITEMVISIBLE, uintptr(item), 0)
 }
 
 // EnsureItemVisible ensures the item at position index is visible, scrolling if necessary.
 func (tv *TableView) EnsureItemVisible(index int) {
-	win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(index), 0)
+	tv.expandGroupOfRow(index)
+
+	if item := tv.rowToItem(index); item > -1 {
+		win.SendMessage(tv.hwndNormalLV, win.LVM_ENSUREVISIBLE, uintptr(item), 0)
+	}
 }
 
 // SelectionHiddenWithoutFocus returns whether selection indicators are visible when the TableView does not have the keyboard input focus.
@@ -1242,10 +1487,13 @@ func (tv *TableView) SetSelectedIndexes(indexes []int) error {
 	selectAll := false
 	lvi.State = win.LVIS_FOCUSED | win.LVIS_SELECTED
 	for _, i := range indexes {
-		val := uintptr(i)
+		val := uintptr(tv.rowToItem(i))
 		if i == -1 {
 			selectAll = true
 			val = ^uintptr(0)
+		} else if val == ^uintptr(0) {
+			// The row is in a collapsed group.
+			continue
 		}
 		if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_SETITEMSTATE, val, lp) && i != -1 {
 			return newError("SendMessage(LVM_SETITEMSTATE)")
@@ -1263,6 +1511,8 @@ func (tv *TableView) SetSelectedIndexes(indexes []int) error {
 		}
 
 		tv.selectedIndexes = idxs
+	} else if tv.isGrouped() {
+		tv.selectedIndexes = tv.selectedRows()
 	} else {
 		count := int(win.SendMessage(tv.hwndNormalLV, win.LVM_GETSELECTEDCOUNT, 0, 0))
 		idxs := make([]int, count)
@@ -1276,14 +1526,7 @@ func (tv *TableView) SetSelectedIndexes(indexes []int) error {
 }
 
 func (tv *TableView) updateSelectedIndexes() {
-	count := int(win.SendMessage(tv.hwndNormalLV, win.LVM_GETSELECTEDCOUNT, 0, 0))
-	indexes := make([]int, count)
-
-	j := -1
-	for i := 0; i < count; i++ {
-		j = int(win.SendMessage(tv.hwndNormalLV, win.LVM_GETNEXTITEM, uintptr(j), win.LVNI_SELECTED))
-		indexes[i] = j
-	}
+	indexes := tv.selectedRows()
 
 	changed := len(indexes) != len(tv.selectedIndexes)
 	if !changed {
@@ -1301,6 +1544,24 @@ func (tv *TableView) updateSelectedIndexes() {
 	}
 }
 
+// selectedRows returns the model rows of the selected list view items.
+func (tv *TableView) selectedRows() []int {
+	count := int(win.SendMessage(tv.hwndNormalLV, win.LVM_GETSELECTEDCOUNT, 0, 0))
+	rows := make([]int, 0, count)
+
+	j := -1
+	for i := 0; i < count; i++ {
+		j = int(win.SendMessage(tv.hwndNormalLV, win.LVM_GETNEXTITEM, uintptr(j), win.LVNI_SELECTED))
+
+		// Group headers may be selected along with rows.
+		if row := tv.itemToRow(j); row > -1 {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows
+}
+
 func (tv *TableView) copySelectedIndexes(hwndTo, hwndFrom win.HWND) error {
 	count := int(win.SendMessage(hwndFrom, win.LVM_GETSELECTEDCOUNT, 0, 0))
 
@@ -1675,10 +1936,12 @@ func (tv *TableView) toggleItemChecked(index int) error {
 		return wrapError(err)
 	}
 
-	if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_UPDATE, uintptr(index), 0) {
+	item := tv.rowToItem(index)
+
+	if win.FALSE == win.SendMessage(tv.hwndFrozenLV, win.LVM_UPDATE, uintptr(item), 0) {
 		return newError("SendMessage(LVM_UPDATE)")
 	}
-	if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_UPDATE, uintptr(index), 0) {
+	if win.FALSE == win.SendMessage(tv.hwndNormalLV, win.LVM_UPDATE, uintptr(item), 0) {
 		return newError("SendMessage(LVM_UPDATE)")
 	}
 
@@ -1767,6 +2030,160 @@ func tableViewNormalLVWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr
 	return tv.lvWndProc(tv.normalLVOrigWndProcPtr, hwnd, msg, wp, lp)
 }
 
+// SearchColumn returns the index of the column that is searched when the user
+// types while the TableView has the keyboard focus.
+//
+// A value of -1 means type-to-find is disabled.
+func (tv *TableView) SearchColumn() int {
+	return tv.searchColumn
+}
+
+// SetSearchColumn sets the index of the column that is searched when the user
+// types while the TableView has the keyboard focus.
+//
+// Typing selects the next row, in the current sort order, whose text in the
+// search column starts with the typed prefix. Pass -1 to disable this.
+func (tv *TableView) SetSearchColumn(col int) {
+	tv.searchColumn = col
+}
+
+// SearchPredicate returns the function used to match rows against the typed
+// prefix, or nil if the default prefix match is used.
+func (tv *TableView) SearchPredicate() func(row int, prefix string) bool {
+	return tv.searchPredicate
+}
+
+// SetSearchPredicate sets a function used to match rows against the typed
+// prefix instead of the default case insensitive prefix match on the search
+// column.
+func (tv *TableView) SetSearchPredicate(predicate func(row int, prefix string) bool) {
+	tv.searchPredicate = predicate
+}
+
+// findItem returns the first list view item at or after start whose row
+// matches prefix, or -1 if there is none.
+func (tv *TableView) findItem(prefix string, start int, wrap bool) int {
+	if tv.model == nil || tv.searchColumn < 0 || (tv.searchPredicate == nil && tv.searchColumn >= tv.columns.Len()) {
+		return -1
+	}
+
+	count := tv.itemCount()
+	if start < 0 || start >= count {
+		start = 0
+	}
+
+	lowerPrefix := strings.ToLower(prefix)
+
+	for i := 0; i < count; i++ {
+		item := start + i
+		if item >= count {
+			if !wrap {
+				break
+			}
+
+			item -= count
+		}
+
+		row := tv.itemToRow(item)
+		if row == -1 {
+			continue
+		}
+
+		if tv.searchPredicate != nil {
+			if tv.searchPredicate(row, prefix) {
+				return item
+			}
+		} else if strings.HasPrefix(strings.ToLower(tv.cellText(row, tv.searchColumn)), lowerPrefix) {
+			return item
+		}
+	}
+
+	return -1
+}
+
+// subItemUnderCursor returns the list view item and subitem of hwnd under
+// the mouse cursor, or -1 if there is none.
+func (tv *TableView) subItemUnderCursor(hwnd win.HWND) (item, subItem int32) {
+	var hti win.LVHITTESTINFO
+	if !win.GetCursorPos(&hti.Pt) || !win.ScreenToClient(hwnd, &hti.Pt) {
+		return -1, -1
+	}
+
+	if -1 == int32(win.SendMessage(hwnd, win.LVM_SUBITEMHITTEST, 0, uintptr(unsafe.Pointer(&hti)))) {
+		return -1, -1
+	}
+
+	return hti.IItem, hti.ISubItem
+}
+
+// updateToolTipCell makes the tool tip of the list view show the text of the
+// cell under the cursor, when the cursor moves to another cell of the same
+// item, which the list view does not notice by itself.
+func (tv *TableView) updateToolTipCell(hwnd win.HWND) {
+	item, subItem := tv.subItemUnderCursor(hwnd)
+
+	cell := tableViewToolTipCell{hwnd, item, subItem}
+	if cell == tv.toolTipCell {
+		return
+	}
+	tv.toolTipCell = cell
+
+	hwndTT := win.HWND(win.SendMessage(hwnd, win.LVM_GETTOOLTIPS, 0, 0))
+	if hwndTT != 0 && win.IsWindowVisible(hwndTT) {
+		win.SendMessage(hwndTT, win.TTM_UPDATE, 0, 0)
+	}
+}
+
+// cellText returns the text that is displayed in the cell at row and col.
+func (tv *TableView) cellText(row, col int) string {
+	value := tv.model.Value(row, col)
+	var text string
+	if format := tv.columns.items[col].formatFunc; format != nil {
+		text = format(value)
+	} else {
+		switch val := value.(type) {
+		case string:
+			text = val
+
+		case float32:
+			prec := tv.columns.items[col].precision
+			if prec == 0 {
+				prec = 2
+			}
+			text = FormatFloatGrouped(float64(val), prec)
+
+		case float64:
+			prec := tv.columns.items[col].precision
+			if prec == 0 {
+				prec = 2
+			}
+			text = FormatFloatGrouped(val, prec)
+
+		case time.Time:
+			if val.Year() > 1601 {
+				text = val.Format(tv.columns.items[col].format)
+			}
+
+		case bool:
+			if val {
+				text = checkmark
+			}
+
+		case *big.Rat:
+			prec := tv.columns.items[col].precision
+			if prec == 0 {
+				prec = 2
+			}
+			text = formatBigRatGrouped(val, prec)
+
+		default:
+			text = fmt.Sprintf(tv.columns.items[col].format, val)
+		}
+	}
+
+	return text
+}
+
 func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 	var hwndOther win.HWND
 	if hwnd == tv.hwndFrozenLV {
@@ -1798,6 +2215,18 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 
 		tv.itemIndexOfLastMouseButtonDown = int(hti.IItem)
 
+		if group := tv.groupHeaderAt(int(hti.IItem)); group != nil {
+			// Clicking a group header toggles the group instead of selecting
+			// the header.
+			win.SetFocus(tv.hwndFrozenLV)
+
+			if msg == win.WM_LBUTTONDOWN || msg == win.WM_LBUTTONDBLCLK {
+				tv.SetGroupCollapsed(group.key, !group.collapsed)
+			}
+
+			return 0
+		}
+
 		if hti.Flags == win.LVHT_NOWHERE {
 			if tv.MultiSelection() {
 				tv.publishNextSelClear = true
@@ -1824,7 +2253,7 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 				tv.itemChecker != nil &&
 				tv.CheckBoxes() {
 
-				tv.toggleItemChecked(int(hti.IItem))
+				tv.toggleItemChecked(tv.itemToRow(int(hti.IItem)))
 			}
 
 		case win.WM_LBUTTONDBLCLK, win.WM_RBUTTONDBLCLK:
@@ -1846,6 +2275,10 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 			tv.inMouseEvent = false
 		}()
 
+		if msg == win.WM_MOUSEMOVE && tv.cellToolTipProvider != nil {
+			tv.updateToolTipCell(hwnd)
+		}
+
 		if msg == win.WM_MOUSEMOVE {
 			y := int(win.GET_Y_LPARAM(lp))
 			lp = uintptr(win.MAKELONG(0, uint16(y)))
@@ -1854,6 +2287,10 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 		win.SendMessage(hwndOther, msg, wp, lp)
 
 	case win.WM_KEYDOWN:
+		if tv.handleGroupKeyDown(hwnd, wp) {
+			return 0
+		}
+
 		if wp == win.VK_SPACE &&
 			tv.currentIndex > -1 &&
 			tv.itemChecker != nil &&
@@ -1877,60 +2314,73 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 		}
 
 		switch nmh.Code {
-		case win.LVN_GETDISPINFO:
-			di := (*win.NMLVDISPINFO)(unsafe.Pointer(lp))
+		case win.LVN_ODFINDITEM:
+			fi := (*nmlvFindItem)(unsafe.Pointer(lp))
 
-			row := int(di.Item.IItem)
-			col := tv.fromLVColIdx(hwnd == tv.hwndFrozenLV, di.Item.ISubItem)
-			if col == -1 {
+			if fi.lvfi.flags&(lvfiString|lvfiPartial) == 0 || fi.lvfi.psz == nil {
+				return ^uintptr(0)
+			}
+
+			item := tv.findItem(win.UTF16PtrToString(fi.lvfi.psz), int(fi.iStart), fi.lvfi.flags&lvfiWrap != 0)
+
+			return uintptr(item)
+
+		case win.LVN_GETINFOTIP:
+			if tv.cellToolTipProvider == nil {
 				break
 			}
 
-			if di.Item.Mask&win.LVIF_TEXT > 0 {
-				value := tv.model.Value(row, col)
-				var text string
-				if format := tv.columns.items[col].formatFunc; format != nil {
-					text = format(value)
-				} else {
-					switch val := value.(type) {
-					case string:
-						text = val
-
-					case float32:
-						prec := tv.columns.items[col].precision
-						if prec == 0 {
-							prec = 2
-						}
-						text = FormatFloatGrouped(float64(val), prec)
+			git := (*nmlvGetInfoTip)(unsafe.Pointer(lp))
 
-					case float64:
-						prec := tv.columns.items[col].precision
-						if prec == 0 {
-							prec = 2
-						}
-						text = FormatFloatGrouped(val, prec)
+			// iSubItem is always 0 in report view, so we find the cell under
+			// the cursor ourselves.
+			subItem := git.iSubItem
+			if item, sub := tv.subItemUnderCursor(hwnd); item == git.iItem {
+				subItem = sub
+			}
 
-					case time.Time:
-						if val.Year() > 1601 {
-							text = val.Format(tv.columns.items[col].format)
-						}
+			col := tv.fromLVColIdx(hwnd == tv.hwndFrozenLV, subItem)
+			if col == -1 || git.pszText == nil || git.cchTextMax < 1 {
+				break
+			}
 
-					case bool:
-						if val {
-							text = checkmark
-						}
+			row := tv.itemToRow(int(git.iItem))
+			if row == -1 {
+				break
+			}
 
-					case *big.Rat:
-						prec := tv.columns.items[col].precision
-						if prec == 0 {
-							prec = 2
-						}
-						text = formatBigRatGrouped(val, prec)
+			text := tv.cellToolTipProvider.CellToolTip(row, col)
 
-					default:
-						text = fmt.Sprintf(tv.columns.items[col].format, val)
-					}
+			buf := (*[1 << 16]uint16)(unsafe.Pointer(git.pszText))[:git.cchTextMax:git.cchTextMax]
+			utf16 := syscall.StringToUTF16(text)
+			if len(utf16) > len(buf) {
+				utf16 = utf16[:len(buf)]
+				utf16[len(utf16)-1] = 0
+			}
+			copy(buf, utf16)
+
+			return 0
+
+		case win.LVN_GETDISPINFO:
+			di := (*win.NMLVDISPINFO)(unsafe.Pointer(lp))
+
+			row := tv.itemToRow(int(di.Item.IItem))
+			col := tv.fromLVColIdx(hwnd == tv.hwndFrozenLV, di.Item.ISubItem)
+			if col == -1 {
+				break
+			}
+
+			if row == -1 {
+				// Group headers are drawn in NM_CUSTOMDRAW.
+				if di.Item.Mask&win.LVIF_TEXT > 0 && di.Item.PszText != nil && di.Item.CchTextMax > 0 {
+					*di.Item.PszText = 0
 				}
+				di.Item.State = 0
+				break
+			}
+
+			if di.Item.Mask&win.LVIF_TEXT > 0 {
+				text := tv.cellText(row, col)
 
 				utf16 := syscall.StringToUTF16(text)
 				buf := (*[264]uint16)(unsafe.Pointer(di.Item.PszText))
@@ -1987,7 +2437,7 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 			nmlvcd := (*win.NMLVCUSTOMDRAW)(unsafe.Pointer(lp))
 
 			if nmlvcd.IIconPhase == 0 {
-				row := int(nmlvcd.Nmcd.DwItemSpec)
+				row := tv.itemToRow(int(nmlvcd.Nmcd.DwItemSpec))
 				col := tv.fromLVColIdx(hwnd == tv.hwndFrozenLV, nmlvcd.ISubItem)
 				if col == -1 {
 					break
@@ -2035,6 +2485,12 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 					return win.CDRF_NOTIFYITEMDRAW
 
 				case win.CDDS_ITEMPREPAINT:
+					if group := tv.groupHeaderAt(int(nmlvcd.Nmcd.DwItemSpec)); group != nil {
+						tv.drawGroupHeader(hwnd, nmlvcd.Nmcd.Hdc, int(nmlvcd.Nmcd.DwItemSpec), group)
+
+						return win.CDRF_SKIPDEFAULT
+					}
+
 					var selected bool
 					if itemState := win.SendMessage(hwnd, win.LVM_GETITEMSTATE, nmlvcd.Nmcd.DwItemSpec, win.LVIS_SELECTED); itemState&win.LVIS_SELECTED != 0 {
 						selected = true
@@ -2140,7 +2596,12 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 
 			col := tv.fromLVColIdx(hwnd == tv.hwndFrozenLV, nmlv.ISubItem)
 
-			if sorter, ok := tv.model.(Sorter); ok && sorter.ColumnSortable(col) {
+			if ms, ok := tv.model.(MultiSorter); ok && ms.ColumnSortable(col) && win.GetKeyState(win.VK_CONTROL) < 0 {
+				columns := sortColumnsAfterCtrlClick(ms.SortColumns(), col)
+				tv.sortedColumnIndex = columns[0].Index
+				tv.sortOrder = columns[0].Order
+				ms.SortMulti(columns)
+			} else if sorter, ok := tv.model.(Sorter); ok && sorter.ColumnSortable(col) {
 				prevCol := sorter.SortedColumn()
 				var order SortOrder
 				if col != prevCol || sorter.SortOrder() == SortDescending {
@@ -2176,22 +2637,33 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 			selectedNow := nmlv.UNewState&win.LVIS_SELECTED > 0
 			selectedBefore := nmlv.UOldState&win.LVIS_SELECTED > 0
 			if tv.itemIndexOfLastMouseButtonDown != -1 && selectedNow && !selectedBefore && ModifiersDown()&(ModControl|ModShift) == 0 {
-				tv.prevIndex = tv.currentIndex
-				tv.currentIndex = int(nmlv.IItem)
-				if tv.itemStateChangedEventDelay > 0 {
-					tv.delayedCurrentIndexChangedCanceled = false
-					if 0 == win.SetTimer(
-						tv.hWnd,
-						tableViewCurrentIndexChangedTimerId,
-						uint32(tv.itemStateChangedEventDelay),
-						0) {
-
-						lastError("SetTimer")
+				row := tv.itemToRow(int(nmlv.IItem))
+
+				if row == -1 {
+					// The keyboard focus moved to a group header.
+					if tv.currentIndex != -1 {
+						tv.prevIndex = tv.currentIndex
+						tv.currentIndex = -1
+						tv.currentIndexChangedPublisher.Publish()
 					}
-
-					tv.SetCurrentIndex(int(nmlv.IItem))
 				} else {
-					tv.SetCurrentIndex(int(nmlv.IItem))
+					tv.prevIndex = tv.currentIndex
+					tv.currentIndex = row
+					if tv.itemStateChangedEventDelay > 0 {
+						tv.delayedCurrentIndexChangedCanceled = false
+						if 0 == win.SetTimer(
+							tv.hWnd,
+							tableViewCurrentIndexChangedTimerId,
+							uint32(tv.itemStateChangedEventDelay),
+							0) {
+
+							lastError("SetTimer")
+						}
+
+						tv.SetCurrentIndex(row)
+					} else {
+						tv.SetCurrentIndex(row)
+					}
 				}
 			}
 
@@ -2217,12 +2689,17 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 		case win.LVN_ITEMACTIVATE:
 			nmia := (*win.NMITEMACTIVATE)(unsafe.Pointer(lp))
 
+			if group := tv.groupHeaderAt(int(nmia.IItem)); group != nil {
+				tv.SetGroupCollapsed(group.key, !group.collapsed)
+				break
+			}
+
 			if tv.itemStateChangedEventDelay > 0 {
 				tv.delayedCurrentIndexChangedCanceled = true
 			}
 
-			if int(nmia.IItem) != tv.currentIndex {
-				tv.SetCurrentIndex(int(nmia.IItem))
+			if row := tv.itemToRow(int(nmia.IItem)); row != tv.currentIndex {
+				tv.SetCurrentIndex(row)
 				tv.currentIndexChangedPublisher.Publish()
 			}
 
@@ -2277,6 +2754,18 @@ func (tv *TableView) lvWndProc(origWndProcPtr uintptr, hwnd win.HWND, msg uint32
 	return win.CallWindowProc(origWndProcPtr, hwnd, msg, wp, lp)
 }
 
+// drawSortRank draws the position of a column in a multi-column sort into
+// the top right corner of its header item.
+func (tv *TableView) drawSortRank(hdc win.HDC, rc win.RECT, rank int) {
+	rc.Right -= int32(IntFrom96DPI(4, tv.DPI()))
+
+	text := syscall.StringToUTF16(strconv.Itoa(rank))
+
+	win.SetBkMode(hdc, win.TRANSPARENT)
+	win.SetTextColor(hdc, win.COLORREF(tv.themeNormalTextColor))
+	win.DrawTextEx(hdc, &text[0], int32(len(text)-1), &rc, win.DT_RIGHT|win.DT_TOP|win.DT_SINGLELINE|win.DT_NOPREFIX, nil)
+}
+
 func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 	tv := (*TableView)(unsafe.Pointer(windowFromHandle(win.GetParent(win.GetParent(hwnd))).AsWindowBase()))
 
@@ -2291,7 +2780,7 @@ func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 	case win.WM_NOTIFY:
 		switch ((*win.NMHDR)(unsafe.Pointer(lp))).Code {
 		case win.NM_CUSTOMDRAW:
-			if tv.customHeaderHeight == 0 {
+			if tv.customHeaderHeight == 0 && len(tv.sortColumns) < 2 {
 				break
 			}
 
@@ -2306,6 +2795,9 @@ func tableViewHdrWndProc(hwnd win.HWND, msg uint32, wp, lp uintptr) uintptr {
 
 			case win.CDDS_ITEMPOSTPAINT:
 				col := tv.fromLVColIdx(hwnd == tv.hwndFrozenHdr, int32(nmcd.DwItemSpec))
+				if rank := tv.sortRank(col); rank > 0 && len(tv.sortColumns) > 1 {
+					tv.drawSortRank(nmcd.Hdc, nmcd.Rc, rank)
+				}
 				if tv.styler != nil && col > -1 {
 					tv.style.row = -1
 					tv.style.col = col
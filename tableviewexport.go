@@ -0,0 +1,87 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVOptions controls how TableView.ExportCSV writes the data.
+type CSVOptions struct {
+	// Delimiter separates the fields of a record. The default is ','. Use
+	// '\t' to write TSV.
+	Delimiter rune
+
+	// IncludeHiddenColumns makes hidden columns be exported after the
+	// visible ones.
+	IncludeHiddenColumns bool
+
+	// SelectedRowsOnly restricts the export to the selected rows.
+	SelectedRowsOnly bool
+}
+
+// ExportCSV writes a header record with the column titles, followed by one
+// record per row, to w.
+//
+// Rows are written in their current (sorted) order and columns in display
+// order. Fields contain the same text as the corresponding cells.
+func (tv *TableView) ExportCSV(w io.Writer, opts CSVOptions) error {
+	cw := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		cw.Comma = opts.Delimiter
+	}
+
+	cols := tv.VisibleColumnsInDisplayOrder()
+	if opts.IncludeHiddenColumns {
+		for _, tvc := range tv.columns.items {
+			if !tvc.visible {
+				cols = append(cols, tvc)
+			}
+		}
+	}
+
+	colIndexes := make([]int, len(cols))
+	record := make([]string, len(cols))
+	for i, tvc := range cols {
+		colIndexes[i] = tv.columns.Index(tvc)
+		record[i] = tvc.TitleEffective()
+	}
+
+	if err := cw.Write(record); err != nil {
+		return wrapError(err)
+	}
+
+	var rows []int
+	if opts.SelectedRowsOnly {
+		rows = tv.SelectedIndexes()
+	} else if tv.model != nil {
+		rowCount := tv.model.RowCount()
+		rows = make([]int, rowCount)
+		for i := range rows {
+			rows[i] = i
+		}
+	}
+
+	for _, row := range rows {
+		for i, col := range colIndexes {
+			record[i] = tv.cellText(row, col)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return wrapError(err)
+		}
+	}
+
+	cw.Flush()
+
+	if err := cw.Error(); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,529 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"fmt"
+	"sort"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// The native group view of the list view is not available in virtual mode,
+// so TableView implements grouping itself, by inserting a list view item for
+// the header of each group. groupItems maps list view items to model rows
+// and row2Item maps model rows back to list view items. row2Group maps model
+// rows to their groups.
+
+type tableViewGroup struct {
+	key       string
+	rows      []int
+	item      int
+	collapsed bool
+}
+
+type tableViewGroupItem struct {
+	group *tableViewGroup
+
+	// row is the model row of the item, or -1 if it is the group header.
+	row int
+}
+
+// GroupColumn returns the index of the column whose values rows are grouped
+// by, or -1 if rows are not grouped.
+func (tv *TableView) GroupColumn() int {
+	return tv.groupColumn
+}
+
+// GroupBy groups the rows that display the same text in the column at index
+// col under a collapsible group header. Pass -1 to stop grouping.
+//
+// Groups appear in the order of their first row in the model, so sorting by
+// the group column also sorts the groups. The titles and the initial
+// collapsed state of the groups can be provided by a model that implements
+// TableGroupProvider.
+//
+// Clicking a group header, or pressing Enter or Space while it has the
+// keyboard focus, collapses or expands the group. Left collapses and Right
+// expands the group of the focused header, while Left on a row moves the
+// focus to the header of its group. Group headers are never part of
+// SelectedIndexes and there is no current item while a header has the focus.
+//
+// Grouping has to look at all rows of the model, so it should not be used
+// with models that populate rows on demand.
+func (tv *TableView) GroupBy(col int) error {
+	if col < -1 || col >= tv.columns.Len() {
+		return newError("col out of range")
+	}
+
+	if col == tv.groupColumn {
+		return nil
+	}
+
+	tv.groupColumn = col
+	tv.groupCollapsed = nil
+
+	if err := tv.setItemCount(); err != nil {
+		return err
+	}
+
+	return tv.SetCurrentIndex(tv.currentIndex)
+}
+
+// GroupCollapsed returns whether the group of rows displaying key in the
+// group column is collapsed.
+func (tv *TableView) GroupCollapsed(key string) bool {
+	if collapsed, ok := tv.groupCollapsed[key]; ok {
+		return collapsed
+	}
+
+	if tv.groupProvider == nil {
+		return false
+	}
+
+	collapsed := tv.groupProvider.GroupCollapsed(key)
+
+	if tv.groupCollapsed == nil {
+		tv.groupCollapsed = make(map[string]bool)
+	}
+	tv.groupCollapsed[key] = collapsed
+
+	return collapsed
+}
+
+// SetGroupCollapsed sets whether the group of rows displaying key in the
+// group column is collapsed.
+//
+// If the current item is in a group that gets collapsed, the keyboard focus
+// moves to the group header and there is no current item anymore.
+func (tv *TableView) SetGroupCollapsed(key string, collapsed bool) error {
+	if collapsed == tv.GroupCollapsed(key) {
+		return nil
+	}
+
+	if tv.groupCollapsed == nil {
+		tv.groupCollapsed = make(map[string]bool)
+	}
+	tv.groupCollapsed[key] = collapsed
+
+	if tv.isGrouped() {
+		tv.layoutGroupItems()
+
+		if err := tv.applyItemCount(); err != nil {
+			return err
+		}
+
+		if tv.currentIndex > -1 && tv.rowToItem(tv.currentIndex) == -1 {
+			if group := tv.groupOfRow(tv.currentIndex); group != nil {
+				tv.focusGroupHeader(group)
+			}
+		}
+	}
+
+	tv.groupCollapsedChangedPublisher.Publish(key)
+
+	return nil
+}
+
+// GroupCollapsedChanged returns the event that is published with the key of
+// a group after it was collapsed or expanded.
+func (tv *TableView) GroupCollapsedChanged() *StringEvent {
+	return tv.groupCollapsedChangedPublisher.Event()
+}
+
+func (tv *TableView) isGrouped() bool {
+	return tv.groupItems != nil
+}
+
+// updateGroups regroups the current model rows and rebuilds the list view
+// items for them.
+func (tv *TableView) updateGroups() {
+	tv.groups = nil
+	tv.groupItems = nil
+	tv.row2Item = nil
+	tv.row2Group = nil
+
+	if tv.model == nil || tv.groupColumn < 0 || tv.groupColumn >= tv.columns.Len() {
+		return
+	}
+
+	count := tv.model.RowCount()
+
+	var groups []*tableViewGroup
+	key2Group := make(map[string]*tableViewGroup)
+	row2Group := make([]*tableViewGroup, count)
+
+	for row := 0; row < count; row++ {
+		key := tv.cellText(row, tv.groupColumn)
+
+		group, ok := key2Group[key]
+		if !ok {
+			group = &tableViewGroup{key: key}
+			key2Group[key] = group
+			groups = append(groups, group)
+		}
+
+		group.rows = append(group.rows, row)
+		row2Group[row] = group
+	}
+
+	tv.groups = groups
+	tv.row2Group = row2Group
+
+	tv.layoutGroupItems()
+}
+
+// layoutGroupItems rebuilds the list view items for the groups and their
+// collapsed state, without looking at the model.
+func (tv *TableView) layoutGroupItems() {
+	count := len(tv.row2Group)
+
+	items := make([]tableViewGroupItem, 0, count+len(tv.groups))
+	row2Item := make([]int, count)
+
+	for _, group := range tv.groups {
+		group.item = len(items)
+		group.collapsed = tv.GroupCollapsed(group.key)
+
+		items = append(items, tableViewGroupItem{group: group, row: -1})
+
+		for _, row := range group.rows {
+			if group.collapsed {
+				row2Item[row] = -1
+			} else {
+				row2Item[row] = len(items)
+				items = append(items, tableViewGroupItem{group: group, row: row})
+			}
+		}
+	}
+
+	tv.groupItems = items
+	tv.row2Item = row2Item
+}
+
+// regroupRow moves row to the group of the text it now displays in the group
+// column and rebuilds the list view items. It returns false, if the row
+// stays in its group.
+func (tv *TableView) regroupRow(row int) bool {
+	old := tv.row2Group[row]
+
+	key := tv.cellText(row, tv.groupColumn)
+	if key == old.key {
+		return false
+	}
+
+	i := sort.SearchInts(old.rows, row)
+	old.rows = append(old.rows[:i], old.rows[i+1:]...)
+
+	var group *tableViewGroup
+	for _, g := range tv.groups {
+		if g.key == key {
+			group = g
+			break
+		}
+	}
+	if group == nil {
+		group = &tableViewGroup{key: key}
+		tv.groups = append(tv.groups, group)
+	}
+
+	i = sort.SearchInts(group.rows, row)
+	group.rows = append(group.rows, 0)
+	copy(group.rows[i+1:], group.rows[i:])
+	group.rows[i] = row
+
+	tv.row2Group[row] = group
+
+	if len(old.rows) == 0 {
+		for i, g := range tv.groups {
+			if g == old {
+				tv.groups = append(tv.groups[:i], tv.groups[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// Groups appear in the order of their first row.
+	sort.SliceStable(tv.groups, func(i, j int) bool {
+		return tv.groups[i].rows[0] < tv.groups[j].rows[0]
+	})
+
+	tv.layoutGroupItems()
+
+	return true
+}
+
+// itemCount returns the number of list view items, including group headers.
+func (tv *TableView) itemCount() int {
+	if tv.isGrouped() {
+		return len(tv.groupItems)
+	}
+
+	if tv.model == nil {
+		return 0
+	}
+
+	return tv.model.RowCount()
+}
+
+// itemToRow returns the model row of the list view item at index item, or -1
+// if it is a group header.
+func (tv *TableView) itemToRow(item int) int {
+	if !tv.isGrouped() {
+		return item
+	}
+
+	if item < 0 || item >= len(tv.groupItems) {
+		return -1
+	}
+
+	return tv.groupItems[item].row
+}
+
+// rowToItem returns the index of the list view item of the model row, or -1
+// if the row is in a collapsed group.
+func (tv *TableView) rowToItem(row int) int {
+	if !tv.isGrouped() {
+		return row
+	}
+
+	if row < 0 || row >= len(tv.row2Item) {
+		return -1
+	}
+
+	return tv.row2Item[row]
+}
+
+// groupHeaderAt returns the group whose header is the list view item at index
+// item, or nil if it is not a group header.
+func (tv *TableView) groupHeaderAt(item int) *tableViewGroup {
+	if !tv.isGrouped() || item < 0 || item >= len(tv.groupItems) {
+		return nil
+	}
+
+	if gi := tv.groupItems[item]; gi.row == -1 {
+		return gi.group
+	}
+
+	return nil
+}
+
+func (tv *TableView) groupOfRow(row int) *tableViewGroup {
+	if !tv.isGrouped() || row < 0 || row >= len(tv.row2Group) {
+		return nil
+	}
+
+	return tv.row2Group[row]
+}
+
+// expandGroupOfRow makes sure the row is not hidden in a collapsed group.
+func (tv *TableView) expandGroupOfRow(row int) error {
+	if row < 0 || tv.rowToItem(row) > -1 {
+		return nil
+	}
+
+	if group := tv.groupOfRow(row); group != nil {
+		return tv.SetGroupCollapsed(group.key, false)
+	}
+
+	return nil
+}
+
+func (tv *TableView) groupTitle(group *tableViewGroup) string {
+	if tv.groupProvider != nil {
+		return tv.groupProvider.GroupTitle(group.key, len(group.rows))
+	}
+
+	return fmt.Sprintf("%s (%d)", group.key, len(group.rows))
+}
+
+// restoreGroupedItemStates reapplies the selection and the current item to
+// the list view items after they were rebuilt.
+func (tv *TableView) restoreGroupedItemStates() {
+	inSetSelectedIndexes, inSetCurrentIndex := tv.inSetSelectedIndexes, tv.inSetCurrentIndex
+	tv.inSetSelectedIndexes, tv.inSetCurrentIndex = true, true
+	currentIndex, prevIndex := tv.currentIndex, tv.prevIndex
+	defer func() {
+		tv.inSetSelectedIndexes, tv.inSetCurrentIndex = inSetSelectedIndexes, inSetCurrentIndex
+		tv.currentIndex, tv.prevIndex = currentIndex, prevIndex
+	}()
+
+	lvi := &win.LVITEM{StateMask: win.LVIS_FOCUSED | win.LVIS_SELECTED}
+	lp := uintptr(unsafe.Pointer(lvi))
+
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+		win.SendMessage(hwnd, win.LVM_SETITEMSTATE, ^uintptr(0), lp)
+	}
+
+	lvi.StateMask = win.LVIS_SELECTED
+	lvi.State = win.LVIS_SELECTED
+
+	for _, row := range tv.selectedIndexes {
+		if item := tv.rowToItem(row); item > -1 {
+			for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+				win.SendMessage(hwnd, win.LVM_SETITEMSTATE, uintptr(item), lp)
+			}
+		}
+	}
+
+	if item := tv.rowToItem(currentIndex); item > -1 {
+		lvi.StateMask = win.LVIS_FOCUSED | win.LVIS_SELECTED
+		lvi.State = win.LVIS_FOCUSED | win.LVIS_SELECTED
+
+		for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+			win.SendMessage(hwnd, win.LVM_SETITEMSTATE, uintptr(item), lp)
+		}
+	}
+}
+
+// focusGroupHeader moves the keyboard focus to the header of group. There is
+// no current item while a group header has the focus.
+func (tv *TableView) focusGroupHeader(group *tableViewGroup) {
+	inSetCurrentIndex := tv.inSetCurrentIndex
+	tv.inSetCurrentIndex = true
+
+	lvi := &win.LVITEM{StateMask: win.LVIS_FOCUSED | win.LVIS_SELECTED}
+	lp := uintptr(unsafe.Pointer(lvi))
+
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+		win.SendMessage(hwnd, win.LVM_SETITEMSTATE, ^uintptr(0), lp)
+	}
+
+	lvi.State = win.LVIS_FOCUSED
+
+	for _, hwnd := range [...]win.HWND{tv.hwndFrozenLV, tv.hwndNormalLV} {
+		win.SendMessage(hwnd, win.LVM_SETITEMSTATE, uintptr(group.item), lp)
+		win.SendMessage(hwnd, win.LVM_ENSUREVISIBLE, uintptr(group.item), 0)
+	}
+
+	tv.inSetCurrentIndex = inSetCurrentIndex
+
+	if tv.currentIndex != -1 {
+		tv.prevIndex = tv.currentIndex
+		tv.currentIndex = -1
+		tv.currentIndexChangedPublisher.Publish()
+	}
+
+	if tv.MultiSelection() {
+		tv.updateSelectedIndexes()
+	}
+}
+
+// handleGroupKeyDown handles the keys that collapse, expand and focus group
+// headers. It returns true if the key was handled.
+func (tv *TableView) handleGroupKeyDown(hwnd win.HWND, key uintptr) bool {
+	if !tv.isGrouped() || ModifiersDown() != 0 {
+		return false
+	}
+
+	item := int(int32(win.SendMessage(hwnd, win.LVM_GETNEXTITEM, ^uintptr(0), win.LVNI_FOCUSED)))
+
+	if group := tv.groupHeaderAt(item); group != nil {
+		switch key {
+		case win.VK_LEFT:
+			tv.SetGroupCollapsed(group.key, true)
+
+		case win.VK_RIGHT:
+			tv.SetGroupCollapsed(group.key, false)
+
+		case win.VK_SPACE, win.VK_RETURN:
+			tv.SetGroupCollapsed(group.key, !group.collapsed)
+
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	if key == win.VK_LEFT && item > -1 && item < len(tv.groupItems) {
+		tv.focusGroupHeader(tv.groupItems[item].group)
+
+		return true
+	}
+
+	return false
+}
+
+// drawGroupHeader draws the header of group, which is the list view item at
+// index item. The title is drawn by the leftmost list view only.
+func (tv *TableView) drawGroupHeader(hwnd win.HWND, hdc win.HDC, item int, group *tableViewGroup) {
+	var rc, rcClient win.RECT
+	rc.Left = win.LVIR_BOUNDS
+	if win.FALSE == win.SendMessage(hwnd, win.LVM_GETITEMRECT, uintptr(item), uintptr(unsafe.Pointer(&rc))) {
+		return
+	}
+	win.GetClientRect(hwnd, &rcClient)
+	rc.Left, rc.Right = rcClient.Left, rcClient.Right
+
+	focused := tv.Focused() &&
+		win.SendMessage(hwnd, win.LVM_GETITEMSTATE, uintptr(item), win.LVIS_FOCUSED)&win.LVIS_FOCUSED != 0
+
+	bgColor := tv.themeNormalBGColor
+	if focused {
+		bgColor = tv.themeSelectedBGColor
+	}
+
+	textColor := Color(win.GetSysColor(win.COLOR_HOTLIGHT))
+	if tv.darkMode {
+		textColor = tv.themeNormalTextColor
+	}
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	fillRect := func(rc win.RECT, color Color) {
+		if brush, _ := NewSolidColorBrush(color); brush != nil {
+			defer brush.Dispose()
+
+			canvas.fillRectanglePixels(brush, rectangleFromRECT(rc))
+		}
+	}
+
+	fillRect(rc, bgColor)
+
+	dpi := tv.DPI()
+	padding := int32(IntFrom96DPI(6, dpi))
+
+	lineLeft := rc.Left
+
+	if !tv.hasFrozenColumn || hwnd == tv.hwndFrozenLV {
+		glyph := "\u25BE"
+		if group.collapsed {
+			glyph = "\u25B8"
+		}
+
+		text := syscall.StringToUTF16(glyph + " " + tv.groupTitle(group))
+
+		rcText := rc
+		rcText.Left += padding
+		rcText.Right -= padding
+
+		win.SetBkMode(hdc, win.TRANSPARENT)
+		win.SetTextColor(hdc, win.COLORREF(textColor))
+
+		format := uint32(win.DT_LEFT | win.DT_VCENTER | win.DT_SINGLELINE | win.DT_NOPREFIX | win.DT_END_ELLIPSIS)
+
+		rcMeasured := rcText
+		win.DrawTextEx(hdc, &text[0], int32(len(text)-1), &rcMeasured, format|win.DT_CALCRECT, nil)
+		win.DrawTextEx(hdc, &text[0], int32(len(text)-1), &rcText, format, nil)
+
+		lineLeft = rcMeasured.Right + padding
+	}
+
+	if lineLeft < rc.Right-padding {
+		y := (rc.Top + rc.Bottom) / 2
+		rcLine := win.RECT{Left: lineLeft, Top: y, Right: rc.Right - padding, Bottom: y + 1}
+
+		fillRect(rcLine, textColor)
+	}
+}
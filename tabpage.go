@@ -29,6 +29,7 @@ type TabPage struct {
 	image                 Image
 	title                 string
 	tabWidget             *TabWidget
+	closable              bool
 	titleChangedPublisher EventPublisher
 	imageChangedPublisher EventPublisher
 }
@@ -0,0 +1,45 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+type TabPageCloseEventHandler func(page *TabPage, canceled *bool)
+
+type TabPageCloseEvent struct {
+	handlers []TabPageCloseEventHandler
+}
+
+func (e *TabPageCloseEvent) Attach(handler TabPageCloseEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *TabPageCloseEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type TabPageCloseEventPublisher struct {
+	event TabPageCloseEvent
+}
+
+func (p *TabPageCloseEventPublisher) Event() *TabPageCloseEvent {
+	return &p.event
+}
+
+func (p *TabPageCloseEventPublisher) Publish(page *TabPage, canceled *bool) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(page, canceled)
+		}
+	}
+}
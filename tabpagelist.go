@@ -15,6 +15,7 @@ type tabPageListObserver interface {
 	onInsertedPage(index int, page *TabPage) error
 	onRemovingPage(index int, page *TabPage) error
 	onRemovedPage(index int, page *TabPage) error
+	onMovedPage(from, to int, page *TabPage) error
 	onClearingPages(pages []*TabPage) error
 	onClearedPages(pages []*TabPage) error
 }
@@ -146,3 +147,28 @@ func (l *TabPageList) RemoveAt(index int) error {
 
 	return nil
 }
+
+// Move moves the TabPage at index from to index to.
+func (l *TabPageList) Move(from, to int) error {
+	if from < 0 || from >= len(l.items) || to < 0 || to >= len(l.items) {
+		return newError("index out of range")
+	}
+
+	if from == to {
+		return nil
+	}
+
+	item := l.items[from]
+	l.items = append(l.items[:from], l.items[from+1:]...)
+	l.insertIntoSlice(to, item)
+
+	if observer := l.observer; observer != nil {
+		if err := observer.onMovedPage(from, to, item); err != nil {
+			l.items = append(l.items[:to], l.items[to+1:]...)
+			l.insertIntoSlice(from, item)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -31,11 +31,20 @@ type TabWidget struct {
 	pages                        *TabPageList
 	currentIndex                 int
 	currentIndexChangedPublisher EventPublisher
+	pageCloseRequestedPublisher  TabPageCloseEventPublisher
+	newTabRequestedPublisher     EventPublisher
+	hotCloseIndex                int
+	newTabButtonHot              bool
+	newTabButtonVisible          bool
+	pagesReorderedPublisher      EventPublisher
+	reorderEnabled               bool
+	tabDrag                      *tabDragState
+	tabPosition                  TabPosition
 	persistent                   bool
 }
 
 func NewTabWidget(parent Container) (*TabWidget, error) {
-	tw := &TabWidget{currentIndex: -1}
+	tw := &TabWidget{currentIndex: -1, hotCloseIndex: -1}
 	tw.pages = newTabPageList(tw)
 
 	if err := InitWidget(
@@ -316,6 +325,14 @@ func (tw *TabWidget) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 
 			tw.onResize(wp.Cx, wp.Cy)
 
+		case win.WM_DRAWITEM:
+			dis := (*win.DRAWITEMSTRUCT)(unsafe.Pointer(lParam))
+
+			if dis.HwndItem == tw.hWndTab {
+				tw.drawVerticalTabItem(dis)
+				return 1
+			}
+
 		case win.WM_NOTIFY:
 			nmhdr := (*win.NMHDR)(unsafe.Pointer(lParam))
 
@@ -334,6 +351,17 @@ var tabWidgetTabWndProcPtr uintptr
 func tabWidgetTabWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
 	tw := (*TabWidget)(unsafe.Pointer(win.GetWindowLongPtr(hwnd, win.GWLP_USERDATA)))
 
+	switch msg {
+	case win.WM_MOUSEMOVE, win.WM_MOUSELEAVE, win.WM_LBUTTONDOWN, win.WM_MBUTTONUP:
+		if tw.handleTabButtonsMessage(hwnd, msg, lParam) {
+			return 0
+		}
+	}
+
+	if tw.handleTabDragMessage(hwnd, msg, wParam, lParam) {
+		return 0
+	}
+
 	switch msg {
 	case win.WM_MOUSEMOVE:
 		win.InvalidateRect(hwnd, nil, true)
@@ -407,7 +435,7 @@ func tabWidgetTabWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 				win.DeleteObject(win.HGDIOBJ(hRgnTab))
 			}
 
-			hRgnRC := win.CreateRectRgn(0, 0, int32(cb.Width), rc.Bottom)
+			hRgnRC := tw.tabStripRgn(rc, cb)
 			win.CombineRgn(hRgn, hRgnRC, hRgn, win.RGN_DIFF)
 			win.DeleteObject(win.HGDIOBJ(hRgnRC))
 
@@ -416,8 +444,8 @@ func tabWidgetTabWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 			}
 		}
 
-		// Draw current tab item.
-		if tw.currentIndex != -1 {
+		// Draw current tab item. Vertical tabs are owner drawn.
+		if tw.currentIndex != -1 && !tw.tabsVertical() {
 			page := tw.pages.At(tw.CurrentIndex())
 
 			if bg, wnd := page.AsWindowBase().backgroundEffective(); bg != nil &&
@@ -482,6 +510,9 @@ func tabWidgetTabWndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uint
 			}
 		}
 
+		tw.drawTabButtons(canvas)
+		tw.drawTabDropIndicator(canvas)
+
 		if !win.BitBlt(hdc, 0, 0, int32(cb.Width), int32(cb.Height), canvas.hdc, 0, 0, win.SRCCOPY) {
 			break
 		}
@@ -658,7 +689,7 @@ func (tw *TabWidget) tcitemFromPage(page *TabPage) *win.TCITEM {
 		}
 	}
 
-	text := syscall.StringToUTF16(page.title)
+	text := syscall.StringToUTF16(tw.titleWithCloseButtonSpace(page))
 
 	item := &win.TCITEM{
 		Mask:       win.TCIF_IMAGE | win.TCIF_TEXT,
@@ -0,0 +1,308 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+const (
+	tabCloseButtonSize96dpi   = 14
+	tabCloseButtonMargin96dpi = 4
+)
+
+// Closable returns if the tab of the TabPage displays a close button.
+func (tp *TabPage) Closable() bool {
+	return tp.closable
+}
+
+// SetClosable sets if the tab of the TabPage displays a close button.
+//
+// Clicking the close button publishes the PageCloseRequested event of the
+// TabWidget, which removes and disposes the TabPage, unless a handler cancels.
+func (tp *TabPage) SetClosable(closable bool) error {
+	if closable == tp.closable {
+		return nil
+	}
+
+	tp.closable = closable
+
+	if tp.tabWidget == nil {
+		return nil
+	}
+
+	return tp.tabWidget.onPageChanged(tp)
+}
+
+// NewTabButtonVisible returns if a "+" button is displayed after the last tab
+// of the TabWidget.
+func (tw *TabWidget) NewTabButtonVisible() bool {
+	return tw.newTabButtonVisible
+}
+
+// SetNewTabButtonVisible sets if a "+" button is displayed after the last tab
+// of the TabWidget. Clicking it publishes the NewTabRequested event.
+func (tw *TabWidget) SetNewTabButtonVisible(visible bool) {
+	tw.newTabButtonVisible = visible
+
+	win.InvalidateRect(tw.hWndTab, nil, true)
+}
+
+// NewTabRequested returns the event that is published when the user clicks
+// the "+" button of the TabWidget.
+func (tw *TabWidget) NewTabRequested() *Event {
+	return tw.newTabRequestedPublisher.Event()
+}
+
+// PageCloseRequested returns the event that is published when the user
+// clicks the close button of a closable TabPage. Handlers may cancel closing.
+func (tw *TabWidget) PageCloseRequested() *TabPageCloseEvent {
+	return tw.pageCloseRequestedPublisher.Event()
+}
+
+// RequestClosePage publishes the PageCloseRequested event for page and, if no
+// handler cancels, removes and disposes it. It returns if page was closed.
+func (tw *TabWidget) RequestClosePage(page *TabPage) bool {
+	if tw.pages.Index(page) == -1 {
+		return false
+	}
+
+	var canceled bool
+	tw.pageCloseRequestedPublisher.Publish(page, &canceled)
+	if canceled {
+		return false
+	}
+
+	// A handler may have removed the page already.
+	if tw.pages.Index(page) != -1 {
+		if err := tw.pages.Remove(page); err != nil {
+			return false
+		}
+	}
+
+	page.Dispose()
+
+	return true
+}
+
+// titleWithCloseButtonSpace returns the title of page, padded with enough
+// no-break spaces to make room for its close button, if any.
+func (tw *TabWidget) titleWithCloseButtonSpace(page *TabPage) string {
+	if !page.closable {
+		return page.title
+	}
+
+	dpi := tw.DPI()
+	needed := IntFrom96DPI(tabCloseButtonSize96dpi+tabCloseButtonMargin96dpi, dpi)
+
+	spaceWidth := calculateTextSize("\u00a0", tw.Font(), dpi, 0, tw.hWndTab).Width
+	if spaceWidth <= 0 {
+		spaceWidth = 1
+	}
+
+	return page.title + strings.Repeat("\u00a0", (needed+spaceWidth-1)/spaceWidth)
+}
+
+func (tw *TabWidget) tabItemRect(index int) (win.RECT, bool) {
+	var rc win.RECT
+
+	ok := 0 != win.SendMessage(tw.hWndTab, win.TCM_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&rc)))
+
+	return rc, ok
+}
+
+// closeButtonBounds returns the bounds of the close button of the tab at
+// index, in native pixels relative to the tab control.
+func (tw *TabWidget) closeButtonBounds(index int) (Rectangle, bool) {
+	if index < 0 || index >= tw.pages.Len() || !tw.pages.At(index).closable {
+		return Rectangle{}, false
+	}
+
+	rc, ok := tw.tabItemRect(index)
+	if !ok {
+		return Rectangle{}, false
+	}
+
+	dpi := tw.DPI()
+	size := IntFrom96DPI(tabCloseButtonSize96dpi, dpi)
+	margin := IntFrom96DPI(tabCloseButtonMargin96dpi, dpi)
+
+	switch tw.tabPosition {
+	case TabPositionLeft:
+		return Rectangle{int(rc.Left+rc.Right)/2 - size/2, int(rc.Top) + margin, size, size}, true
+
+	case TabPositionRight:
+		return Rectangle{int(rc.Left+rc.Right)/2 - size/2, int(rc.Bottom) - margin - size, size, size}, true
+	}
+
+	return Rectangle{int(rc.Right) - margin - size, int(rc.Top+rc.Bottom)/2 - size/2, size, size}, true
+}
+
+// newTabButtonBounds returns the bounds of the "+" button, in native pixels
+// relative to the tab control.
+func (tw *TabWidget) newTabButtonBounds() (Rectangle, bool) {
+	if !tw.newTabButtonVisible {
+		return Rectangle{}, false
+	}
+
+	dpi := tw.DPI()
+	margin := IntFrom96DPI(tabCloseButtonMargin96dpi, dpi)
+
+	if count := tw.pages.Len(); count > 0 {
+		if rc, ok := tw.tabItemRect(count - 1); ok {
+			if tw.tabsVertical() {
+				width := int(rc.Right - rc.Left)
+
+				return Rectangle{int(rc.Left), int(rc.Bottom) + margin, width, width}, true
+			}
+
+			height := int(rc.Bottom - rc.Top)
+
+			return Rectangle{int(rc.Right) + margin, int(rc.Top), height, height}, true
+		}
+	}
+
+	size := IntFrom96DPI(20, dpi)
+
+	return Rectangle{margin, margin, size, size}, true
+}
+
+func (tw *TabWidget) closeButtonIndexAt(x, y int) int {
+	for i := tw.pages.Len() - 1; i >= 0; i-- {
+		if bounds, ok := tw.closeButtonBounds(i); ok && bounds.contains(Point{x, y}) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (tw *TabWidget) drawTabButtons(canvas *Canvas) {
+	color := Color(win.GetSysColor(win.COLOR_BTNTEXT))
+
+	brush, err := NewSolidColorBrush(color)
+	if err != nil {
+		return
+	}
+	defer brush.Dispose()
+
+	pen, err := NewGeometricPen(PenSolid|PenCapRound, maxi(1, IntFrom96DPI(1, tw.DPI())), brush)
+	if err != nil {
+		return
+	}
+	defer pen.Dispose()
+
+	for i := tw.pages.Len() - 1; i >= 0; i-- {
+		if bounds, ok := tw.closeButtonBounds(i); ok {
+			tw.drawTabButton(canvas, pen, bounds, i == tw.hotCloseIndex, false)
+		}
+	}
+
+	if bounds, ok := tw.newTabButtonBounds(); ok {
+		tw.drawTabButton(canvas, pen, bounds, tw.newTabButtonHot, true)
+	}
+}
+
+func (tw *TabWidget) drawTabButton(canvas *Canvas, pen Pen, bounds Rectangle, hot, plus bool) {
+	if hot {
+		canvas.fillRectanglePixels(sysColorBtnFaceBrush, bounds)
+	}
+
+	inset := bounds.Width / 4
+	l, t := bounds.X+inset, bounds.Y+inset
+	r, b := bounds.X+bounds.Width-inset, bounds.Y+bounds.Height-inset
+
+	canvas.withPen(pen, func() error {
+		hdc := canvas.hdc
+
+		if plus {
+			cx, cy := (l+r)/2, (t+b)/2
+
+			win.MoveToEx(hdc, l, cy, nil)
+			win.LineTo(hdc, int32(r), int32(cy))
+			win.MoveToEx(hdc, cx, t, nil)
+			win.LineTo(hdc, int32(cx), int32(b))
+		} else {
+			win.MoveToEx(hdc, l, t, nil)
+			win.LineTo(hdc, int32(r), int32(b))
+			win.MoveToEx(hdc, l, b, nil)
+			win.LineTo(hdc, int32(r), int32(t))
+		}
+
+		return nil
+	})
+}
+
+// updateTabButtonsHot updates the hot state of the close and "+" buttons for
+// the mouse position x, y and reports if anything changed.
+func (tw *TabWidget) updateTabButtonsHot(x, y int) bool {
+	hotCloseIndex := tw.closeButtonIndexAt(x, y)
+
+	var newTabButtonHot bool
+	if bounds, ok := tw.newTabButtonBounds(); ok {
+		newTabButtonHot = bounds.contains(Point{x, y})
+	}
+
+	if hotCloseIndex == tw.hotCloseIndex && newTabButtonHot == tw.newTabButtonHot {
+		return false
+	}
+
+	tw.hotCloseIndex = hotCloseIndex
+	tw.newTabButtonHot = newTabButtonHot
+
+	return true
+}
+
+// handleTabButtonsMessage handles mouse messages of the tab control that
+// concern the close and "+" buttons.
+func (tw *TabWidget) handleTabButtonsMessage(hwnd win.HWND, msg uint32, lParam uintptr) (handled bool) {
+	x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+	switch msg {
+	case win.WM_MOUSEMOVE:
+		tme := win.TRACKMOUSEEVENT{
+			DwFlags:   win.TME_LEAVE,
+			HwndTrack: hwnd,
+		}
+		tme.CbSize = uint32(unsafe.Sizeof(tme))
+		win.TrackMouseEvent(&tme)
+
+		tw.updateTabButtonsHot(x, y)
+
+	case win.WM_MOUSELEAVE:
+		if tw.updateTabButtonsHot(-1, -1) {
+			win.InvalidateRect(hwnd, nil, true)
+		}
+
+	case win.WM_LBUTTONDOWN:
+		if i := tw.closeButtonIndexAt(x, y); i != -1 {
+			tw.RequestClosePage(tw.pages.At(i))
+			return true
+		}
+
+		if bounds, ok := tw.newTabButtonBounds(); ok && bounds.contains(Point{x, y}) {
+			tw.newTabRequestedPublisher.Publish()
+			return true
+		}
+
+	case win.WM_MBUTTONUP:
+		hti := win.TCHITTESTINFO{
+			Pt: win.POINT{X: int32(x), Y: int32(y)},
+		}
+
+		i := int(win.SendMessage(hwnd, win.TCM_HITTEST, 0, uintptr(unsafe.Pointer(&hti))))
+		if i != -1 && tw.pages.At(i).closable {
+			tw.RequestClosePage(tw.pages.At(i))
+			return true
+		}
+	}
+
+	return false
+}
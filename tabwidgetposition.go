@@ -0,0 +1,194 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// TabPosition specifies at which side of a TabWidget the tabs are displayed.
+type TabPosition int
+
+const (
+	TabPositionTop TabPosition = iota
+	TabPositionBottom
+	TabPositionLeft
+	TabPositionRight
+)
+
+const tabPositionStyles = win.TCS_BOTTOM | win.TCS_RIGHT | win.TCS_VERTICAL | win.TCS_MULTILINE | win.TCS_OWNERDRAWFIXED
+
+// TabPosition returns at which side of the TabWidget the tabs are displayed.
+func (tw *TabWidget) TabPosition() TabPosition {
+	return tw.tabPosition
+}
+
+// SetTabPosition sets at which side of the TabWidget the tabs are displayed.
+//
+// Tabs at the left or right side are drawn by the TabWidget itself, with the
+// text rotated, since native vertical tabs don't support visual styles.
+func (tw *TabWidget) SetTabPosition(position TabPosition) error {
+	var style uint32
+
+	switch position {
+	case TabPositionTop:
+
+	case TabPositionBottom:
+		style = win.TCS_BOTTOM
+
+	case TabPositionLeft:
+		style = win.TCS_VERTICAL | win.TCS_MULTILINE | win.TCS_OWNERDRAWFIXED
+
+	case TabPositionRight:
+		style = win.TCS_VERTICAL | win.TCS_RIGHT | win.TCS_MULTILINE | win.TCS_OWNERDRAWFIXED
+
+	default:
+		return newError("invalid tab position")
+	}
+
+	if position == tw.tabPosition {
+		return nil
+	}
+
+	oldStyle := uint32(win.GetWindowLong(tw.hWndTab, win.GWL_STYLE))
+	if oldStyle == 0 {
+		return lastError("GetWindowLong")
+	}
+
+	win.SetLastError(0)
+	if win.SetWindowLong(tw.hWndTab, win.GWL_STYLE, int32(oldStyle&^tabPositionStyles|style)) == 0 {
+		if err := lastError("SetWindowLong"); err != nil {
+			return err
+		}
+	}
+
+	win.SetWindowPos(tw.hWndTab, 0, 0, 0, 0, 0, win.SWP_FRAMECHANGED|win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOZORDER|win.SWP_NOACTIVATE)
+
+	tw.tabPosition = position
+
+	tw.resizePages()
+	tw.RequestLayout()
+	tw.Invalidate()
+	win.InvalidateRect(tw.hWndTab, nil, true)
+
+	return nil
+}
+
+func (tw *TabWidget) tabsVertical() bool {
+	return tw.tabPosition == TabPositionLeft || tw.tabPosition == TabPositionRight
+}
+
+// tabStripRgn returns a region covering the strip of the tab control that
+// holds the tabs, given the rect of the last tab and the client bounds.
+func (tw *TabWidget) tabStripRgn(last win.RECT, cb Rectangle) win.HRGN {
+	switch tw.tabPosition {
+	case TabPositionBottom:
+		return win.CreateRectRgn(0, last.Top, int32(cb.Width), int32(cb.Height))
+
+	case TabPositionLeft:
+		return win.CreateRectRgn(0, 0, last.Right, int32(cb.Height))
+
+	case TabPositionRight:
+		return win.CreateRectRgn(last.Left, 0, int32(cb.Width), int32(cb.Height))
+	}
+
+	return win.CreateRectRgn(0, 0, int32(cb.Width), last.Bottom)
+}
+
+func (tw *TabWidget) drawVerticalTabItem(dis *win.DRAWITEMSTRUCT) {
+	index := int(dis.ItemID)
+	if index < 0 || index >= tw.pages.Len() {
+		return
+	}
+
+	page := tw.pages.At(index)
+	hdc := dis.HDC
+	rc := dis.RcItem
+	selected := dis.ItemState&win.ODS_SELECTED != 0
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	bgColor := SysColorBtnFace
+	if selected {
+		bgColor = SysColorWindow
+	}
+	if bg, err := NewSystemColorBrush(bgColor); err == nil {
+		canvas.fillRectanglePixels(bg, rectangleFromRECT(rc))
+		bg.Dispose()
+	}
+
+	if page.closable {
+		reserve := int32(IntFrom96DPI(tabCloseButtonSize96dpi+tabCloseButtonMargin96dpi, tw.DPI()))
+
+		if tw.tabPosition == TabPositionLeft {
+			rc.Top += reserve
+		} else {
+			rc.Bottom -= reserve
+		}
+	}
+
+	title := syscall.StringToUTF16(page.title)
+	if len(title) < 2 {
+		return
+	}
+
+	hFont := tw.Font().handleForDPI(tw.DPI())
+
+	var lf win.LOGFONT
+	if win.GetObject(win.HGDIOBJ(hFont), unsafe.Sizeof(lf), unsafe.Pointer(&lf)) == 0 {
+		return
+	}
+
+	if tw.tabPosition == TabPositionLeft {
+		lf.LfEscapement = 900
+	} else {
+		lf.LfEscapement = 2700
+	}
+	lf.LfOrientation = lf.LfEscapement
+
+	hRotatedFont := win.CreateFontIndirect(&lf)
+	if hRotatedFont == 0 {
+		return
+	}
+	defer win.DeleteObject(win.HGDIOBJ(hRotatedFont))
+
+	oldFont := win.SelectObject(hdc, win.HGDIOBJ(hFont))
+	defer win.SelectObject(hdc, oldFont)
+
+	var size win.SIZE
+	win.GetTextExtentPoint32(hdc, &title[0], int32(len(title)-1), &size)
+
+	win.SelectObject(hdc, win.HGDIOBJ(hRotatedFont))
+
+	color := win.GetSysColor(win.COLOR_BTNTEXT)
+	if !tw.Enabled() || !page.Enabled() {
+		color = win.GetSysColor(win.COLOR_GRAYTEXT)
+	}
+
+	win.SetBkMode(hdc, win.TRANSPARENT)
+	win.SetTextColor(hdc, win.COLORREF(color))
+
+	cx, cy := (rc.Left+rc.Right)/2, (rc.Top+rc.Bottom)/2
+
+	var x, y int32
+	if tw.tabPosition == TabPositionLeft {
+		// Text runs bottom to top.
+		x, y = cx-size.CY/2, cy+size.CX/2
+	} else {
+		// Text runs top to bottom.
+		x, y = cx+size.CY/2, cy-size.CX/2
+	}
+
+	win.TextOut(hdc, x, y, &title[0], int32(len(title)-1))
+}
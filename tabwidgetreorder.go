@@ -0,0 +1,259 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+type tabDragState struct {
+	index       int // index of the dragged tab
+	start       Point
+	dragging    bool
+	insertIndex int // the dragged tab will be inserted before this index
+}
+
+// ReorderEnabled returns if the user can reorder the tabs of the TabWidget by
+// dragging them.
+func (tw *TabWidget) ReorderEnabled() bool {
+	return tw.reorderEnabled
+}
+
+// SetReorderEnabled sets if the user can reorder the tabs of the TabWidget by
+// dragging them.
+func (tw *TabWidget) SetReorderEnabled(enabled bool) {
+	tw.reorderEnabled = enabled
+
+	if !enabled {
+		tw.cancelTabDrag()
+	}
+}
+
+// PagesReordered returns the event that is published after the user moved a
+// tab to another position by dragging it.
+func (tw *TabWidget) PagesReordered() *Event {
+	return tw.pagesReorderedPublisher.Event()
+}
+
+func (tw *TabWidget) onMovedPage(from, to int, page *TabPage) error {
+	current := tw.currentIndex
+
+	win.SendMessage(tw.hWndTab, win.TCM_DELETEITEM, uintptr(from), 0)
+
+	item := tw.tcitemFromPage(page)
+	if idx := int(win.SendMessage(tw.hWndTab, win.TCM_INSERTITEM, uintptr(to), uintptr(unsafe.Pointer(item)))); idx == -1 {
+		return newError("SendMessage(TCM_INSERTITEM) failed")
+	}
+
+	switch {
+	case current == from:
+		current = to
+
+	case from < current && to >= current:
+		current--
+
+	case from > current && to <= current:
+		current++
+	}
+
+	win.SendMessage(tw.hWndTab, win.TCM_SETCURSEL, uintptr(current), 0)
+
+	if current != tw.currentIndex {
+		tw.currentIndex = current
+		tw.currentIndexChangedPublisher.Publish()
+	}
+
+	tw.Invalidate()
+
+	return nil
+}
+
+func (tw *TabWidget) beginTabDrag(index int, x, y int) {
+	tw.tabDrag = &tabDragState{
+		index:       index,
+		start:       Point{x, y},
+		insertIndex: index,
+	}
+
+	win.SetCapture(tw.hWndTab)
+}
+
+func (tw *TabWidget) cancelTabDrag() {
+	if tw.tabDrag == nil {
+		return
+	}
+
+	tw.tabDrag = nil
+
+	win.ReleaseCapture()
+	win.InvalidateRect(tw.hWndTab, nil, true)
+}
+
+func (tw *TabWidget) finishTabDrag() {
+	drag := tw.tabDrag
+	tw.cancelTabDrag()
+
+	if drag == nil || !drag.dragging {
+		return
+	}
+
+	to := drag.insertIndex
+	if to > drag.index {
+		to--
+	}
+
+	if to == drag.index {
+		return
+	}
+
+	if err := tw.pages.Move(drag.index, to); err != nil {
+		return
+	}
+
+	tw.pagesReorderedPublisher.Publish()
+}
+
+// tabInsertIndexAt returns the index before which a tab dropped at x, y would
+// be inserted.
+func (tw *TabWidget) tabInsertIndexAt(x, y int) int {
+	count := tw.pages.Len()
+
+	for i := 0; i < count; i++ {
+		rc, ok := tw.tabItemRect(i)
+		if !ok {
+			break
+		}
+
+		if tw.tabsVertical() {
+			if y < int(rc.Top+rc.Bottom)/2 {
+				return i
+			}
+		} else if x < int(rc.Left+rc.Right)/2 {
+			return i
+		}
+	}
+
+	return count
+}
+
+func (tw *TabWidget) drawTabDropIndicator(canvas *Canvas) {
+	drag := tw.tabDrag
+	if drag == nil || !drag.dragging {
+		return
+	}
+
+	count := tw.pages.Len()
+	if count == 0 {
+		return
+	}
+
+	var rc win.RECT
+	var ok bool
+	atEnd := drag.insertIndex >= count
+	if atEnd {
+		rc, ok = tw.tabItemRect(count - 1)
+	} else {
+		rc, ok = tw.tabItemRect(drag.insertIndex)
+	}
+	if !ok {
+		return
+	}
+
+	width := maxi(2, IntFrom96DPI(2, tw.DPI()))
+
+	var bounds Rectangle
+	if tw.tabsVertical() {
+		y := int(rc.Top)
+		if atEnd {
+			y = int(rc.Bottom)
+		}
+
+		bounds = Rectangle{int(rc.Left), y - width/2, int(rc.Right - rc.Left), width}
+	} else {
+		x := int(rc.Left)
+		if atEnd {
+			x = int(rc.Right)
+		}
+
+		bounds = Rectangle{x - width/2, int(rc.Top), width, int(rc.Bottom - rc.Top)}
+	}
+
+	brush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_HIGHLIGHT)))
+	if err != nil {
+		return
+	}
+	defer brush.Dispose()
+
+	canvas.fillRectanglePixels(brush, bounds)
+}
+
+// handleTabDragMessage handles messages of the tab control that concern
+// reordering tabs by dragging.
+func (tw *TabWidget) handleTabDragMessage(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (handled bool) {
+	if !tw.reorderEnabled {
+		return false
+	}
+
+	switch msg {
+	case win.WM_LBUTTONDOWN:
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		hti := win.TCHITTESTINFO{
+			Pt: win.POINT{X: int32(x), Y: int32(y)},
+		}
+
+		if i := int(win.SendMessage(hwnd, win.TCM_HITTEST, 0, uintptr(unsafe.Pointer(&hti)))); i != -1 {
+			tw.beginTabDrag(i, x, y)
+		}
+
+	case win.WM_MOUSEMOVE:
+		drag := tw.tabDrag
+		if drag == nil {
+			break
+		}
+
+		x, y := int(win.GET_X_LPARAM(lParam)), int(win.GET_Y_LPARAM(lParam))
+
+		if !drag.dragging {
+			dx := int(win.GetSystemMetrics(win.SM_CXDRAG))
+			dy := int(win.GetSystemMetrics(win.SM_CYDRAG))
+
+			if x > drag.start.X-dx && x < drag.start.X+dx && y > drag.start.Y-dy && y < drag.start.Y+dy {
+				break
+			}
+
+			drag.dragging = true
+		}
+
+		drag.insertIndex = tw.tabInsertIndexAt(x, y)
+
+		win.InvalidateRect(hwnd, nil, true)
+
+		return true
+
+	case win.WM_LBUTTONUP:
+		if tw.tabDrag != nil {
+			tw.finishTabDrag()
+		}
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeyEscape && tw.tabDrag != nil {
+			tw.cancelTabDrag()
+			return true
+		}
+
+	case win.WM_CAPTURECHANGED:
+		if tw.tabDrag != nil && win.HWND(lParam) != hwnd {
+			tw.tabDrag = nil
+			win.InvalidateRect(hwnd, nil, true)
+		}
+	}
+
+	return false
+}
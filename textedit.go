@@ -23,6 +23,13 @@ type TextEdit struct {
 	margins                  Size
 	lastHeight               int
 	origWordbreakProcPtr     uintptr
+	undoer                   editUndoer
+	richEdit                 bool
+	highlighter              func(text string) []TextSpan
+	highlightDirty           bool
+	dirtyFirstLine           int
+	dirtyLastLine            int
+	textDocument             *textDocument
 }
 
 func NewTextEdit(parent Container) (*TextEdit, error) {
@@ -30,12 +37,16 @@ func NewTextEdit(parent Container) (*TextEdit, error) {
 }
 
 func NewTextEditWithStyle(parent Container, style uint32) (*TextEdit, error) {
+	return newTextEdit(parent, "EDIT", style)
+}
+
+func newTextEdit(parent Container, className string, style uint32) (*TextEdit, error) {
 	te := new(TextEdit)
 
 	if err := InitWidget(
 		te,
 		parent,
-		"EDIT",
+		className,
 		win.WS_TABSTOP|win.WS_VISIBLE|win.ES_MULTILINE|win.ES_WANTRETURN|style,
 		win.WS_EX_CLIENTEDGE); err != nil {
 		return nil, err
@@ -150,6 +161,9 @@ func (te *TextEdit) SetText(text string) (err error) {
 			te.RequestLayout()
 		}
 	}
+	if te.highlighter != nil {
+		te.highlight(true)
+	}
 	te.textChangedPublisher.Publish()
 	return
 }
@@ -259,6 +273,38 @@ func (te *TextEdit) SetReadOnly(readOnly bool) error {
 	return nil
 }
 
+// CanUndo returns if the last change to the text can be undone.
+func (te *TextEdit) CanUndo() bool {
+	return te.undoer.canUndo(te)
+}
+
+// CanRedo returns if the last undone change can be redone.
+func (te *TextEdit) CanRedo() bool {
+	return te.undoer.canRedo(te)
+}
+
+// Undo undoes the last change to the text, if possible.
+func (te *TextEdit) Undo() error {
+	return te.undoer.undo(te)
+}
+
+// Redo redoes the last undone change, if possible.
+func (te *TextEdit) Redo() error {
+	return te.undoer.redo(te)
+}
+
+// ClearUndo empties the undo buffer, so neither Undo nor Redo are possible
+// until the text changes again.
+func (te *TextEdit) ClearUndo() {
+	te.undoer.clear(te)
+}
+
+// UndoStateChanged returns the event that is published when the results of
+// CanUndo or CanRedo may have changed.
+func (te *TextEdit) UndoStateChanged() *Event {
+	return te.undoer.stateChangedPublisher.Event()
+}
+
 func (te *TextEdit) TextChanged() *Event {
 	return te.textChangedPublisher.Event()
 }
@@ -273,6 +319,11 @@ func (te *TextEdit) SetTextColor(c Color) {
 	te.Invalidate()
 }
 
+func (te *TextEdit) applyDarkMode(dark bool) {
+	// DarkMode_Explorer makes the scroll bars dark as well.
+	setDarkModeTheme(te.hWnd, dark, "DarkMode_Explorer", "")
+}
+
 // ContextMenuLocation returns carret position in screen coordinates.
 func (te *TextEdit) ContextMenuLocation() Point {
 	idx := int(te.SendMessage(win.EM_GETCARETINDEX, 0, 0))
@@ -291,6 +342,16 @@ func (*TextEdit) NeedsWmSize() bool {
 }
 
 func (te *TextEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if te.undoer.handleMessage(te, msg, wParam) {
+		return 0
+	}
+
+	if te.richEdit {
+		if result, handled := te.handleRichEditMessage(msg, wParam, lParam); handled {
+			return result
+		}
+	}
+
 	switch msg {
 	case win.WM_COMMAND:
 		switch win.HIWORD(uint32(wParam)) {
@@ -300,6 +361,10 @@ func (te *TextEdit) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 					te.RequestLayout()
 				}
 			}
+			te.undoer.textChanged()
+			if te.richEdit {
+				te.scheduleHighlight()
+			}
 			te.textChangedPublisher.Publish()
 		}
 
@@ -0,0 +1,337 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// TextSpan describes how a part of the text of a TextEdit is displayed, as
+// returned by a highlighter.
+type TextSpan struct {
+	// Start and End are byte offsets into the text passed to the highlighter.
+	Start, End int
+
+	TextColor Color
+	Style     FontStyle
+}
+
+const (
+	textEditHighlightTimerId = 1
+	textEditHighlightDelay   = 150 // milliseconds
+
+	// Documents with more characters than this are highlighted line by line,
+	// see SetHighlighter.
+	textEditIncrementalHighlightThreshold = 1 << 16
+)
+
+const (
+	tomSuspend = -9999995
+	tomResume  = -9999994
+)
+
+var iidITextDocument = win.IID{Data1: 0x8CC497C0, Data2: 0xA1DF, Data3: 0x11CE, Data4: [8]byte{0x80, 0x98, 0x00, 0xAA, 0x00, 0x47, 0xBE, 0x5D}}
+
+// textDocument is a minimal binding of the Text Object Model ITextDocument
+// interface of a rich edit control.
+type textDocument struct {
+	vtbl *[24]uintptr
+}
+
+func (doc *textDocument) call(index int, args ...uintptr) {
+	a := [3]uintptr{uintptr(unsafe.Pointer(doc))}
+	copy(a[1:], args)
+
+	syscall.Syscall(doc.vtbl[index], uintptr(len(args)+1), a[0], a[1], a[2])
+}
+
+func (doc *textDocument) freeze() {
+	var count int32
+	doc.call(18, uintptr(unsafe.Pointer(&count)))
+}
+
+func (doc *textDocument) unfreeze() {
+	var count int32
+	doc.call(19, uintptr(unsafe.Pointer(&count)))
+}
+
+func (doc *textDocument) undo(count int32) {
+	doc.call(22, uintptr(count), 0)
+}
+
+func (doc *textDocument) release() {
+	doc.call(2)
+}
+
+var loadMsfteditOnce sync.Once
+
+// NewRichTextEdit creates a TextEdit that is backed by a rich edit control.
+//
+// Unlike a plain TextEdit, it supports multiple levels of undo and redo and
+// colored text via SetHighlighter. Pasted text is always inserted as plain
+// text.
+func NewRichTextEdit(parent Container) (*TextEdit, error) {
+	return NewRichTextEditWithStyle(parent, 0)
+}
+
+// NewRichTextEditWithStyle is like NewRichTextEdit, but with additional window
+// styles, e.g. win.WS_VSCROLL.
+func NewRichTextEditWithStyle(parent Container, style uint32) (*TextEdit, error) {
+	loadMsfteditOnce.Do(func() {
+		syscall.LoadLibrary("Msftedit.dll")
+	})
+
+	te, err := newTextEdit(parent, win.MSFTEDIT_CLASS, style)
+	if err != nil {
+		return nil, err
+	}
+
+	te.richEdit = true
+	te.undoer.multiLevel = true
+
+	te.SendMessage(win.EM_SETEVENTMASK, 0, win.ENM_CHANGE)
+
+	var unk *win.IUnknown
+	if 0 != te.SendMessage(win.EM_GETOLEINTERFACE, 0, uintptr(unsafe.Pointer(&unk))) && unk != nil {
+		syscall.Syscall(unk.LpVtbl.QueryInterface, 3,
+			uintptr(unsafe.Pointer(unk)),
+			uintptr(unsafe.Pointer(&iidITextDocument)),
+			uintptr(unsafe.Pointer(&te.textDocument)))
+
+		syscall.Syscall(unk.LpVtbl.Release, 1, uintptr(unsafe.Pointer(unk)), 0, 0)
+	}
+
+	return te, nil
+}
+
+// RichEdit returns if the TextEdit is backed by a rich edit control, see
+// NewRichTextEdit.
+func (te *TextEdit) RichEdit() bool {
+	return te.richEdit
+}
+
+// SetHighlighter sets a function that splits the text into spans that are
+// displayed in different colors and font styles, like for syntax
+// highlighting. Text not covered by any span is displayed normally.
+//
+// The highlighter is called shortly after the user stopped typing. For large
+// documents, only the lines around the changes are passed to it, so tokens
+// must not span lines there. Line breaks are always passed as "\n".
+//
+// Only TextEdits created by NewRichTextEdit support highlighting.
+func (te *TextEdit) SetHighlighter(highlighter func(text string) []TextSpan) error {
+	if !te.richEdit {
+		return newError("highlighting requires a rich edit control")
+	}
+
+	te.highlighter = highlighter
+
+	te.highlight(true)
+
+	return nil
+}
+
+func (te *TextEdit) Dispose() {
+	if te.textDocument != nil {
+		te.textDocument.release()
+		te.textDocument = nil
+	}
+
+	te.WidgetBase.Dispose()
+}
+
+func (te *TextEdit) lineFromChar(cp int) int {
+	return int(te.SendMessage(win.EM_EXLINEFROMCHAR, 0, uintptr(cp)))
+}
+
+func (te *TextEdit) markLinesDirty(first, last int) {
+	if !te.highlightDirty {
+		te.dirtyFirstLine, te.dirtyLastLine = first, last
+		te.highlightDirty = true
+		return
+	}
+
+	te.dirtyFirstLine = mini(te.dirtyFirstLine, first)
+	te.dirtyLastLine = maxi(te.dirtyLastLine, last)
+}
+
+func (te *TextEdit) scheduleHighlight() {
+	if te.highlighter == nil {
+		return
+	}
+
+	line := te.lineFromChar(-1)
+	te.markLinesDirty(line, line)
+
+	if 0 == win.SetTimer(te.hWnd, textEditHighlightTimerId, textEditHighlightDelay, 0) {
+		lastError("SetTimer")
+	}
+}
+
+func (te *TextEdit) handleRichEditMessage(msg uint32, wParam, lParam uintptr) (uintptr, bool) {
+	switch msg {
+	case win.WM_PASTE:
+		te.SendMessage(win.EM_PASTESPECIAL, win.CF_UNICODETEXT, 0)
+		return 0, true
+
+	case win.WM_CHAR, win.WM_KEYDOWN, win.WM_CUT, win.WM_CLEAR, win.WM_UNDO, win.EM_PASTESPECIAL:
+		if msg == win.WM_KEYDOWN && wParam != win.VK_DELETE {
+			break
+		}
+
+		// Remember where a change is about to happen, the caret position
+		// after the change is added in scheduleHighlight.
+		if te.highlighter != nil {
+			start, end := te.TextSelection()
+			te.markLinesDirty(te.lineFromChar(start), te.lineFromChar(end))
+		}
+
+	case win.WM_TIMER:
+		if wParam != textEditHighlightTimerId {
+			break
+		}
+
+		win.KillTimer(te.hWnd, textEditHighlightTimerId)
+
+		te.highlight(te.TextLength() <= textEditIncrementalHighlightThreshold)
+
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func (te *TextEdit) highlight(full bool) {
+	dirty := te.highlightDirty
+	te.highlightDirty = false
+
+	if !full && !dirty {
+		return
+	}
+
+	cpMin, cpMax := 0, -1
+	if !full {
+		lineCount := int(te.SendMessage(win.EM_GETLINECOUNT, 0, 0))
+
+		first := maxi(0, te.dirtyFirstLine-1)
+		cpMin = int(te.SendMessage(win.EM_LINEINDEX, uintptr(first), 0))
+
+		if last := te.dirtyLastLine + 1; last+1 < lineCount {
+			cpMax = int(te.SendMessage(win.EM_LINEINDEX, uintptr(last+1), 0))
+		}
+	}
+
+	text := te.textRange(cpMin, cpMax)
+	cpMax = cpMin + len(text)
+
+	// Map byte offsets of the UTF-8 text to character positions.
+	var buf []byte
+	byteToCP := make([]int, 0, len(text)+1)
+	cp := cpMin
+	for _, r := range utf16.Decode(text) {
+		n := len(string(r))
+		for j := 0; j < n; j++ {
+			byteToCP = append(byteToCP, cp)
+		}
+		buf = append(buf, string(r)...)
+		cp += len(utf16.Encode([]rune{r}))
+	}
+	byteToCP = append(byteToCP, cp)
+
+	var spans []TextSpan
+	if te.highlighter != nil {
+		spans = te.highlighter(string(buf))
+	}
+
+	if te.textDocument != nil {
+		te.textDocument.freeze()
+		defer te.textDocument.unfreeze()
+
+		// Formatting changes must not end up in the undo buffer.
+		te.textDocument.undo(tomSuspend)
+		defer te.textDocument.undo(tomResume)
+	}
+
+	var sel win.CHARRANGE
+	te.SendMessage(win.EM_EXGETSEL, 0, uintptr(unsafe.Pointer(&sel)))
+	var scrollPos win.POINT
+	te.SendMessage(win.EM_GETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+
+	te.setCharFormat(cpMin, cpMax, nil)
+
+	for i := range spans {
+		span := &spans[i]
+		if span.Start < 0 || span.End >= len(byteToCP) || span.Start >= span.End {
+			continue
+		}
+
+		te.setCharFormat(byteToCP[span.Start], byteToCP[span.End], span)
+	}
+
+	te.SendMessage(win.EM_EXSETSEL, 0, uintptr(unsafe.Pointer(&sel)))
+	te.SendMessage(win.EM_SETSCROLLPOS, 0, uintptr(unsafe.Pointer(&scrollPos)))
+}
+
+// textRange returns the text between the character positions cpMin and cpMax,
+// where a cpMax of -1 means the end of the text. The rich edit control
+// represents line breaks by a single '\r', which we replace by '\n'.
+func (te *TextEdit) textRange(cpMin, cpMax int) []uint16 {
+	if cpMax == -1 {
+		gtl := win.GETTEXTLENGTHEX{Flags: win.GTL_NUMCHARS, Codepage: 1200}
+		cpMax = int(te.SendMessage(win.EM_GETTEXTLENGTHEX, uintptr(unsafe.Pointer(&gtl)), 0))
+	}
+
+	buf := make([]uint16, cpMax-cpMin+1)
+	tr := win.TEXTRANGE{
+		Chrg:      win.CHARRANGE{CpMin: int32(cpMin), CpMax: int32(cpMax)},
+		LpstrText: &buf[0],
+	}
+	n := int(te.SendMessage(win.EM_GETTEXTRANGE, 0, uintptr(unsafe.Pointer(&tr))))
+
+	buf = buf[:n]
+	for i, c := range buf {
+		if c == '\r' {
+			buf[i] = '\n'
+		}
+	}
+
+	return buf
+}
+
+// setCharFormat applies the color and style of span to the text between cpMin
+// and cpMax. A nil span resets the text to the default format.
+func (te *TextEdit) setCharFormat(cpMin, cpMax int, span *TextSpan) {
+	sel := win.CHARRANGE{CpMin: int32(cpMin), CpMax: int32(cpMax)}
+	te.SendMessage(win.EM_EXSETSEL, 0, uintptr(unsafe.Pointer(&sel)))
+
+	var cf win.CHARFORMAT2
+	cf.CbSize = uint32(unsafe.Sizeof(cf))
+	cf.DwMask = win.CFM_COLOR | win.CFM_BOLD | win.CFM_ITALIC | win.CFM_UNDERLINE
+
+	if span == nil {
+		cf.DwEffects = win.CFE_AUTOCOLOR
+	} else {
+		cf.CrTextColor = win.COLORREF(span.TextColor)
+
+		if span.Style&FontBold != 0 {
+			cf.DwEffects |= win.CFE_BOLD
+		}
+		if span.Style&FontItalic != 0 {
+			cf.DwEffects |= win.CFE_ITALIC
+		}
+		if span.Style&FontUnderline != 0 {
+			cf.DwEffects |= win.CFE_UNDERLINE
+		}
+	}
+
+	te.SendMessage(win.EM_SETCHARFORMAT, win.SCF_SELECTION, uintptr(unsafe.Pointer(&cf)))
+}
@@ -1,13 +1,27 @@
 package walk
 
 import (
+	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
+
+	"github.com/lxn/win"
 )
 
 var Threaded bool
 var MsgLoopMutex sync.Mutex
 
+// ThreadDebug enables tracking of which thread holds MsgLoopMutex.
+//
+// When set, LockThread, UnlockThread and RunUnlocked panic on misuse instead
+// of silently corrupting the lock state. It is meant for development only.
+var ThreadDebug bool
+
+// msgLoopOwner is the ID of the thread holding MsgLoopMutex, or 0 if it is
+// not held. Only maintained while ThreadDebug is set.
+var msgLoopOwner uint32
+
 func EnterThread() {
 	if !Threaded {
 		panic("call me only in threaded mode")
@@ -26,17 +40,42 @@ func LeaveThread() {
 
 func LockThread() {
 	if Threaded {
+		if ThreadDebug {
+			tid := win.GetCurrentThreadId()
+			if atomic.LoadUint32(&msgLoopOwner) == tid {
+				panic(fmt.Sprintf("walk: LockThread called by thread %d, which already holds MsgLoopMutex", tid))
+			}
+			MsgLoopMutex.Lock()
+			atomic.StoreUint32(&msgLoopOwner, tid)
+			return
+		}
 		MsgLoopMutex.Lock()
 	}
 }
 
 func UnlockThread() {
 	if Threaded {
+		if ThreadDebug {
+			tid := win.GetCurrentThreadId()
+			switch owner := atomic.LoadUint32(&msgLoopOwner); owner {
+			case 0:
+				panic(fmt.Sprintf("walk: UnlockThread called by thread %d on an unlocked MsgLoopMutex", tid))
+			case tid:
+			default:
+				panic(fmt.Sprintf("walk: UnlockThread called by thread %d, but MsgLoopMutex is held by thread %d", tid, owner))
+			}
+			atomic.StoreUint32(&msgLoopOwner, 0)
+		}
 		MsgLoopMutex.Unlock()
 	}
 }
 
 func RunUnlocked(f func()) {
+	if Threaded && ThreadDebug {
+		if tid := win.GetCurrentThreadId(); atomic.LoadUint32(&msgLoopOwner) != tid {
+			panic(fmt.Sprintf("walk: RunUnlocked called by thread %d without holding MsgLoopMutex", tid))
+		}
+	}
 	UnlockThread()
 	defer LockThread()
 	f()
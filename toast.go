@@ -0,0 +1,716 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+var (
+	iidIAgileObject                    = win.IID{Data1: 0x94EA2B94, Data2: 0xE9CC, Data3: 0x49E0, Data4: [8]byte{0xC0, 0xFF, 0xEE, 0x64, 0xCA, 0x8F, 0x5B, 0x90}}
+	iidIToastActivatedEventArgs        = win.IID{Data1: 0xE3BF92F3, Data2: 0xC197, Data3: 0x436F, Data4: [8]byte{0x82, 0x65, 0x06, 0x25, 0x82, 0x4F, 0x8D, 0xAC}}
+	iidIToastDismissedEventArgs        = win.IID{Data1: 0x3F89D935, Data2: 0xD9CB, Data3: 0x4538, Data4: [8]byte{0xA0, 0xF0, 0xFF, 0xE7, 0x65, 0x99, 0x38, 0xF8}}
+	iidIToastFailedEventArgs           = win.IID{Data1: 0x35176862, Data2: 0xCFD4, Data3: 0x44F8, Data4: [8]byte{0xAD, 0x64, 0xF2, 0xCC, 0xFE, 0x7B, 0x6A, 0xB4}}
+	iidIToastNotificationFactory       = win.IID{Data1: 0x04124B20, Data2: 0x82C6, Data3: 0x4229, Data4: [8]byte{0xB1, 0x09, 0xFD, 0x9E, 0xD4, 0x66, 0x2B, 0x53}}
+	iidIToastNotificationManagerStatic = win.IID{Data1: 0x50AC103F, Data2: 0xD235, Data3: 0x4598, Data4: [8]byte{0xBB, 0xEF, 0x98, 0xFE, 0x4D, 0x1A, 0x3A, 0xD4}}
+	iidIXmlDocument                    = win.IID{Data1: 0xF7F3A506, Data2: 0x1E87, Data3: 0x42D6, Data4: [8]byte{0xBC, 0xFB, 0xB8, 0xC8, 0x09, 0xFA, 0x54, 0x94}}
+	iidIXmlDocumentIO                  = win.IID{Data1: 0x6CD0E74E, Data2: 0xEE65, Data3: 0x4489, Data4: [8]byte{0x9E, 0xBF, 0xCA, 0x43, 0xE8, 0x7B, 0xA6, 0x37}}
+
+	// The IIDs of the TypedEventHandler<ToastNotification, T> instances.
+	iidToastActivatedHandler = win.IID{Data1: 0xAB54DE2D, Data2: 0x97D9, Data3: 0x5528, Data4: [8]byte{0xB6, 0xAD, 0x10, 0x5A, 0xFE, 0x15, 0x65, 0x30}}
+	iidToastDismissedHandler = win.IID{Data1: 0x61C2402F, Data2: 0x0ED0, Data3: 0x5A18, Data4: [8]byte{0xAB, 0x69, 0x59, 0xF4, 0xAA, 0x99, 0xA3, 0x68}}
+	iidToastFailedHandler    = win.IID{Data1: 0x95E3E803, Data2: 0xC969, Data3: 0x5E3A, Data4: [8]byte{0x97, 0x53, 0xEA, 0x2A, 0xD2, 0x2A, 0x9A, 0x33}}
+)
+
+// iInspectableVtbl mirrors the vtable of the WinRT IInspectable interface,
+// which all other WinRT interfaces start with.
+type iInspectableVtbl struct {
+	QueryInterface      uintptr
+	AddRef              uintptr
+	Release             uintptr
+	GetIids             uintptr
+	GetRuntimeClassName uintptr
+	GetTrustLevel       uintptr
+}
+
+type iInspectable struct {
+	lpVtbl *iInspectableVtbl
+}
+
+func (obj *iInspectable) QueryInterface(riid *win.IID, ppvObject *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.QueryInterface, 3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(riid)),
+		uintptr(unsafe.Pointer(ppvObject)))
+
+	return win.HRESULT(ret)
+}
+
+func (obj *iInspectable) Release() uint32 {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.Release, 1,
+		uintptr(unsafe.Pointer(obj)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+func releaseInspectable(p unsafe.Pointer) {
+	if p != nil {
+		(*iInspectable)(p).Release()
+	}
+}
+
+type iToastNotificationManagerStaticsVtbl struct {
+	iInspectableVtbl
+	CreateToastNotifier       uintptr
+	CreateToastNotifierWithId uintptr
+	GetTemplateContent        uintptr
+}
+
+type iToastNotificationManagerStatics struct {
+	lpVtbl *iToastNotificationManagerStaticsVtbl
+}
+
+type iToastNotifierVtbl struct {
+	iInspectableVtbl
+	Show                           uintptr
+	Hide                           uintptr
+	GetSetting                     uintptr
+	AddToSchedule                  uintptr
+	RemoveFromSchedule             uintptr
+	GetScheduledToastNotifications uintptr
+}
+
+type iToastNotifier struct {
+	lpVtbl *iToastNotifierVtbl
+}
+
+type iToastNotificationFactoryVtbl struct {
+	iInspectableVtbl
+	CreateToastNotification uintptr
+}
+
+type iToastNotificationFactory struct {
+	lpVtbl *iToastNotificationFactoryVtbl
+}
+
+type iToastNotificationVtbl struct {
+	iInspectableVtbl
+	GetContent        uintptr
+	PutExpirationTime uintptr
+	GetExpirationTime uintptr
+	AddDismissed      uintptr
+	RemoveDismissed   uintptr
+	AddActivated      uintptr
+	RemoveActivated   uintptr
+	AddFailed         uintptr
+	RemoveFailed      uintptr
+}
+
+type iToastNotification struct {
+	lpVtbl *iToastNotificationVtbl
+}
+
+type iXmlDocumentIOVtbl struct {
+	iInspectableVtbl
+	LoadXml             uintptr
+	LoadXmlWithSettings uintptr
+	SaveToFileAsync     uintptr
+}
+
+type iXmlDocumentIO struct {
+	lpVtbl *iXmlDocumentIOVtbl
+}
+
+// iToastEventArgsVtbl is the vtable of IToastActivatedEventArgs,
+// IToastDismissedEventArgs and IToastFailedEventArgs, which all have a single
+// getter.
+type iToastEventArgsVtbl struct {
+	iInspectableVtbl
+	Get uintptr
+}
+
+type iToastEventArgs struct {
+	lpVtbl *iToastEventArgsVtbl
+}
+
+func callHRESULT(fn uintptr, args ...uintptr) win.HRESULT {
+	for len(args) < 3 {
+		args = append(args, 0)
+	}
+
+	ret, _, _ := syscall.Syscall(fn, uintptr(len(args)), args[0], args[1], args[2])
+
+	return win.HRESULT(ret)
+}
+
+func newHSTRING(s string) (hstring, error) {
+	utf16, err := syscall.UTF16FromString(s)
+	if err != nil {
+		return 0, err
+	}
+
+	var str hstring
+	if hr := windowsCreateString(&utf16[0], uint32(len(utf16)-1), &str); win.FAILED(hr) {
+		return 0, errorFromHRESULT("WindowsCreateString", hr)
+	}
+
+	return str, nil
+}
+
+func stringFromHSTRING(str hstring) string {
+	var length uint32
+	p := windowsGetStringRawBuffer(str, &length)
+	if p == nil || length == 0 {
+		return ""
+	}
+
+	return syscall.UTF16ToString((*[1 << 29]uint16)(unsafe.Pointer(p))[:length:length])
+}
+
+// activationFactory returns the activation factory of the WinRT class
+// className for the interface iid.
+func activationFactory(className string, iid *win.IID) (unsafe.Pointer, error) {
+	name, err := newHSTRING(className)
+	if err != nil {
+		return nil, err
+	}
+	defer windowsDeleteString(name)
+
+	var factory unsafe.Pointer
+	if hr := roGetActivationFactory(name, iid, &factory); win.FAILED(hr) {
+		return nil, errorFromHRESULT("RoGetActivationFactory", hr)
+	}
+
+	return factory, nil
+}
+
+var toastEventHandlerVtbl *iToastEventHandlerVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		toastEventHandlerVtbl = &iToastEventHandlerVtbl{
+			syscall.NewCallback(toastEventHandler_QueryInterface),
+			syscall.NewCallback(toastEventHandler_AddRef),
+			syscall.NewCallback(toastEventHandler_Release),
+			syscall.NewCallback(toastEventHandler_Invoke),
+		}
+	})
+}
+
+// iToastEventHandlerVtbl mirrors the vtable of TypedEventHandler.
+type iToastEventHandlerVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	Invoke         uintptr
+}
+
+// toastEventHandler implements the TypedEventHandler instance identified by
+// iid. It is invoked on a thread of the Windows Runtime.
+type toastEventHandler struct {
+	lpVtbl *iToastEventHandlerVtbl
+	iid    *win.IID
+	invoke func(args *iToastEventArgs)
+	token  int64
+}
+
+func toastEventHandler_QueryInterface(h *toastEventHandler, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	// Being agile, the handler is called directly from any thread.
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &iidIAgileObject) || win.EqualREFIID(riid, h.iid) {
+		*ppvObject = unsafe.Pointer(h)
+	} else {
+		*ppvObject = nil
+		return win.E_NOINTERFACE
+	}
+
+	return win.S_OK
+}
+
+func toastEventHandler_AddRef(h *toastEventHandler) uintptr {
+	return 1
+}
+
+func toastEventHandler_Release(h *toastEventHandler) uintptr {
+	return 1
+}
+
+func toastEventHandler_Invoke(h *toastEventHandler, sender unsafe.Pointer, args *iToastEventArgs) uintptr {
+	h.invoke(args)
+
+	return win.S_OK
+}
+
+// queryToastEventArgs returns args as the interface iid, which the caller
+// must release, or nil.
+func queryToastEventArgs(args *iToastEventArgs, iid *win.IID) *iToastEventArgs {
+	if args == nil {
+		return nil
+	}
+
+	var p unsafe.Pointer
+	if hr := (*iInspectable)(unsafe.Pointer(args)).QueryInterface(iid, &p); win.FAILED(hr) {
+		return nil
+	}
+
+	return (*iToastEventArgs)(p)
+}
+
+var appUserModelID string
+
+// AppUserModelID returns the application user model id set with
+// SetAppUserModelID.
+func AppUserModelID() string {
+	return appUserModelID
+}
+
+// SetAppUserModelID sets the application user model id of the process, which
+// Windows uses to attribute toast notifications, taskbar buttons and jump
+// lists to the application.
+//
+// For toast notifications to be shown, a shortcut to the application with the
+// same id must exist in the start menu, e.g. created by its installer.
+func SetAppUserModelID(id string) error {
+	id16, err := syscall.UTF16PtrFromString(id)
+	if err != nil {
+		return err
+	}
+
+	if err := procSetCurrentProcessExplicitAppUserModelID.Find(); err != nil {
+		return wrapError(err)
+	}
+
+	if hr := setCurrentProcessExplicitAppUserModelID(id16); win.FAILED(hr) {
+		return errorFromHRESULT("SetCurrentProcessExplicitAppUserModelID", hr)
+	}
+
+	appUserModelID = id
+
+	return nil
+}
+
+// ToastAction is a button of a toast notification.
+type ToastAction struct {
+	// Text is the caption of the button.
+	Text string
+
+	// Arguments is passed to the Activated event of the ToastNotification
+	// when the button is clicked.
+	Arguments string
+}
+
+// Toast describes the contents of a toast notification.
+type Toast struct {
+	Title string
+	Body  string
+
+	// ImagePath is the path of an image file to show next to the text. It
+	// is optional.
+	ImagePath string
+
+	// Arguments is passed to the Activated event of the ToastNotification
+	// when the notification itself is clicked.
+	Arguments string
+
+	// Actions are the buttons of the notification, up to five.
+	Actions []ToastAction
+}
+
+// xml returns the toast XML schema representation of t.
+func (t Toast) xml() (string, error) {
+	var buf bytes.Buffer
+
+	escape := func(s string) string {
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	fmt.Fprintf(&buf, `<toast launch="%s"><visual><binding template="ToastGeneric">`, escape(t.Arguments))
+	fmt.Fprintf(&buf, `<text>%s</text>`, escape(t.Title))
+	if t.Body != "" {
+		fmt.Fprintf(&buf, `<text>%s</text>`, escape(t.Body))
+	}
+	if t.ImagePath != "" {
+		path, err := filepath.Abs(t.ImagePath)
+		if err != nil {
+			return "", wrapError(err)
+		}
+
+		u := url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(path)}
+
+		fmt.Fprintf(&buf, `<image placement="appLogoOverride" src="%s"/>`, escape(u.String()))
+	}
+	buf.WriteString(`</binding></visual>`)
+
+	if len(t.Actions) > 0 {
+		buf.WriteString(`<actions>`)
+		for _, action := range t.Actions {
+			fmt.Fprintf(&buf, `<action content="%s" arguments="%s" activationType="foreground"/>`, escape(action.Text), escape(action.Arguments))
+		}
+		buf.WriteString(`</actions>`)
+	}
+
+	buf.WriteString(`</toast>`)
+
+	return buf.String(), nil
+}
+
+// ToastDismissalReason specifies why a toast notification was dismissed.
+type ToastDismissalReason int
+
+const (
+	ToastDismissedByUser ToastDismissalReason = iota
+	ToastHidden
+	ToastTimedOut
+)
+
+var (
+	liveToastsMutex sync.Mutex
+
+	// liveToasts keeps the ToastNotifications, and with them their event
+	// handlers, reachable as long as the handlers are registered with the
+	// Windows Runtime, which may invoke them long after ShowToast returned.
+	liveToasts = make(map[*ToastNotification]struct{})
+)
+
+// ToastNotification is a toast notification shown by ShowToast.
+type ToastNotification struct {
+	form               Form
+	notifier           *iToastNotifier
+	toast              *iToastNotification
+	activatedHandler   *toastEventHandler
+	dismissedHandler   *toastEventHandler
+	failedHandler      *toastEventHandler
+	dismissalReason    ToastDismissalReason
+	activatedPublisher StringEventPublisher
+	dismissedPublisher EventPublisher
+	failedPublisher    ErrorEventPublisher
+}
+
+// ShowToast shows a toast notification in the Windows 10 style, which
+// supersedes the message balloons of NotifyIcon.
+//
+// The events of the returned ToastNotification are published on the thread
+// of form, even if the caller does not keep it. It is disposed automatically
+// after it was activated, dismissed by the user or hidden, or failed.
+// SetAppUserModelID must have been called before. An error is returned if
+// toast notifications are not supported, e.g. before Windows 8.
+func ShowToast(form Form, toast Toast) (*ToastNotification, error) {
+	if appUserModelID == "" {
+		return nil, newError("SetAppUserModelID must be called first")
+	}
+
+	if err := procRoInitialize.Find(); err != nil {
+		return nil, newError("toast notifications require Windows 8 or later")
+	}
+
+	if hr := roInitialize(roInitSingleThreaded); win.FAILED(hr) && uint32(hr) != rpcEChangedMode {
+		return nil, errorFromHRESULT("RoInitialize", hr)
+	}
+
+	text, err := toast.xml()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := newXmlDocument(text)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseInspectable(doc)
+
+	tn := &ToastNotification{form: form}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			tn.Dispose()
+		}
+	}()
+
+	if tn.notifier, err = newToastNotifier(); err != nil {
+		return nil, err
+	}
+
+	if tn.toast, err = newToastNotification(doc); err != nil {
+		return nil, err
+	}
+
+	if err := tn.attachHandlers(); err != nil {
+		return nil, err
+	}
+
+	if hr := callHRESULT(tn.notifier.lpVtbl.Show, uintptr(unsafe.Pointer(tn.notifier)), uintptr(unsafe.Pointer(tn.toast))); win.FAILED(hr) {
+		return nil, errorFromHRESULT("IToastNotifier.Show", hr)
+	}
+
+	succeeded = true
+
+	return tn, nil
+}
+
+// newXmlDocument returns a new Windows.Data.Xml.Dom.XmlDocument as
+// IXmlDocument, loaded from text.
+func newXmlDocument(text string) (unsafe.Pointer, error) {
+	className, err := newHSTRING("Windows.Data.Xml.Dom.XmlDocument")
+	if err != nil {
+		return nil, err
+	}
+	defer windowsDeleteString(className)
+
+	var inspectable unsafe.Pointer
+	if hr := roActivateInstance(className, &inspectable); win.FAILED(hr) {
+		return nil, errorFromHRESULT("RoActivateInstance", hr)
+	}
+	defer releaseInspectable(inspectable)
+
+	var io unsafe.Pointer
+	if hr := (*iInspectable)(inspectable).QueryInterface(&iidIXmlDocumentIO, &io); win.FAILED(hr) {
+		return nil, errorFromHRESULT("QueryInterface", hr)
+	}
+	defer releaseInspectable(io)
+
+	xmlText, err := newHSTRING(text)
+	if err != nil {
+		return nil, err
+	}
+	defer windowsDeleteString(xmlText)
+
+	if hr := callHRESULT((*iXmlDocumentIO)(io).lpVtbl.LoadXml, uintptr(io), uintptr(xmlText)); win.FAILED(hr) {
+		return nil, errorFromHRESULT("IXmlDocumentIO.LoadXml", hr)
+	}
+
+	var doc unsafe.Pointer
+	if hr := (*iInspectable)(inspectable).QueryInterface(&iidIXmlDocument, &doc); win.FAILED(hr) {
+		return nil, errorFromHRESULT("QueryInterface", hr)
+	}
+
+	return doc, nil
+}
+
+func newToastNotifier() (*iToastNotifier, error) {
+	p, err := activationFactory("Windows.UI.Notifications.ToastNotificationManager", &iidIToastNotificationManagerStatic)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseInspectable(p)
+
+	appID, err := newHSTRING(appUserModelID)
+	if err != nil {
+		return nil, err
+	}
+	defer windowsDeleteString(appID)
+
+	var notifier *iToastNotifier
+	if hr := callHRESULT((*iToastNotificationManagerStatics)(p).lpVtbl.CreateToastNotifierWithId, uintptr(p), uintptr(appID), uintptr(unsafe.Pointer(&notifier))); win.FAILED(hr) {
+		return nil, errorFromHRESULT("IToastNotificationManagerStatics.CreateToastNotifierWithId", hr)
+	}
+
+	return notifier, nil
+}
+
+func newToastNotification(doc unsafe.Pointer) (*iToastNotification, error) {
+	p, err := activationFactory("Windows.UI.Notifications.ToastNotification", &iidIToastNotificationFactory)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseInspectable(p)
+
+	var toast *iToastNotification
+	if hr := callHRESULT((*iToastNotificationFactory)(p).lpVtbl.CreateToastNotification, uintptr(p), uintptr(doc), uintptr(unsafe.Pointer(&toast))); win.FAILED(hr) {
+		return nil, errorFromHRESULT("IToastNotificationFactory.CreateToastNotification", hr)
+	}
+
+	return toast, nil
+}
+
+func (tn *ToastNotification) attachHandlers() error {
+	tn.activatedHandler = &toastEventHandler{
+		lpVtbl: toastEventHandlerVtbl,
+		iid:    &iidToastActivatedHandler,
+		invoke: func(args *iToastEventArgs) {
+			var arguments string
+
+			if a := queryToastEventArgs(args, &iidIToastActivatedEventArgs); a != nil {
+				var str hstring
+				if hr := callHRESULT(a.lpVtbl.Get, uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(&str))); win.SUCCEEDED(hr) {
+					arguments = stringFromHSTRING(str)
+					windowsDeleteString(str)
+				}
+				releaseInspectable(unsafe.Pointer(a))
+			}
+
+			tn.form.Synchronize(func() {
+				tn.activatedPublisher.Publish(arguments)
+
+				// Activation removes the notification from the action center.
+				tn.Dispose()
+			})
+		},
+	}
+
+	tn.dismissedHandler = &toastEventHandler{
+		lpVtbl: toastEventHandlerVtbl,
+		iid:    &iidToastDismissedHandler,
+		invoke: func(args *iToastEventArgs) {
+			reason := ToastDismissedByUser
+
+			if a := queryToastEventArgs(args, &iidIToastDismissedEventArgs); a != nil {
+				var r int32
+				if hr := callHRESULT(a.lpVtbl.Get, uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(&r))); win.SUCCEEDED(hr) {
+					reason = ToastDismissalReason(r)
+				}
+				releaseInspectable(unsafe.Pointer(a))
+			}
+
+			tn.form.Synchronize(func() {
+				tn.dismissalReason = reason
+				tn.dismissedPublisher.Publish()
+
+				// A notification that timed out stays in the action center,
+				// where it can still be activated.
+				if reason != ToastTimedOut {
+					tn.Dispose()
+				}
+			})
+		},
+	}
+
+	tn.failedHandler = &toastEventHandler{
+		lpVtbl: toastEventHandlerVtbl,
+		iid:    &iidToastFailedHandler,
+		invoke: func(args *iToastEventArgs) {
+			err := newError("toast notification failed")
+
+			if a := queryToastEventArgs(args, &iidIToastFailedEventArgs); a != nil {
+				var code win.HRESULT
+				if hr := callHRESULT(a.lpVtbl.Get, uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(&code))); win.SUCCEEDED(hr) {
+					err = errorFromHRESULT("ToastNotification", code)
+				}
+				releaseInspectable(unsafe.Pointer(a))
+			}
+
+			tn.form.Synchronize(func() {
+				tn.failedPublisher.Publish(err)
+
+				tn.Dispose()
+			})
+		},
+	}
+
+	liveToastsMutex.Lock()
+	liveToasts[tn] = struct{}{}
+	liveToastsMutex.Unlock()
+
+	vtbl := tn.toast.lpVtbl
+
+	for _, x := range []struct {
+		add     uintptr
+		handler *toastEventHandler
+	}{
+		{vtbl.AddActivated, tn.activatedHandler},
+		{vtbl.AddDismissed, tn.dismissedHandler},
+		{vtbl.AddFailed, tn.failedHandler},
+	} {
+		if hr := callHRESULT(x.add, uintptr(unsafe.Pointer(tn.toast)), uintptr(unsafe.Pointer(x.handler)), uintptr(unsafe.Pointer(&x.handler.token))); win.FAILED(hr) {
+			return errorFromHRESULT("IToastNotification.add_*", hr)
+		}
+	}
+
+	return nil
+}
+
+// Hide removes the notification from the screen and the action center.
+func (tn *ToastNotification) Hide() error {
+	if tn.notifier == nil || tn.toast == nil {
+		return nil
+	}
+
+	if hr := callHRESULT(tn.notifier.lpVtbl.Hide, uintptr(unsafe.Pointer(tn.notifier)), uintptr(unsafe.Pointer(tn.toast))); win.FAILED(hr) {
+		return errorFromHRESULT("IToastNotifier.Hide", hr)
+	}
+
+	return nil
+}
+
+// Dispose stops publishing the events of the ToastNotification and releases
+// its resources. The notification itself stays in the action center, if it
+// is there.
+//
+// A notification that timed out stays alive until Dispose is called, so it
+// can still be activated from the action center.
+func (tn *ToastNotification) Dispose() {
+	if tn.toast != nil {
+		vtbl := tn.toast.lpVtbl
+
+		for _, x := range []struct {
+			remove  uintptr
+			handler *toastEventHandler
+		}{
+			{vtbl.RemoveActivated, tn.activatedHandler},
+			{vtbl.RemoveDismissed, tn.dismissedHandler},
+			{vtbl.RemoveFailed, tn.failedHandler},
+		} {
+			if x.handler != nil && x.handler.token != 0 {
+				// The 64 bit token takes two arguments on 386 and one on
+				// amd64, where the second one is ignored.
+				callHRESULT(x.remove, uintptr(unsafe.Pointer(tn.toast)), uintptr(x.handler.token), uintptr(uint64(x.handler.token)>>32))
+			}
+		}
+
+		releaseInspectable(unsafe.Pointer(tn.toast))
+		tn.toast = nil
+	}
+
+	if tn.notifier != nil {
+		releaseInspectable(unsafe.Pointer(tn.notifier))
+		tn.notifier = nil
+	}
+
+	liveToastsMutex.Lock()
+	delete(liveToasts, tn)
+	liveToastsMutex.Unlock()
+}
+
+// DismissalReason returns why the notification was dismissed, once the
+// Dismissed event was published.
+func (tn *ToastNotification) DismissalReason() ToastDismissalReason {
+	return tn.dismissalReason
+}
+
+// Activated returns the event that is published when the user clicks the
+// notification or one of its actions. The arguments of the Toast or the
+// ToastAction clicked are passed to the handlers.
+//
+// A notification that went to the action center can be activated long after
+// it was dismissed, as long as the application runs.
+func (tn *ToastNotification) Activated() *StringEvent {
+	return tn.activatedPublisher.Event()
+}
+
+// Dismissed returns the event that is published when the notification
+// disappears from the screen without being activated, see DismissalReason.
+func (tn *ToastNotification) Dismissed() *Event {
+	return tn.dismissedPublisher.Event()
+}
+
+// Failed returns the event that is published when the notification could not
+// be shown.
+func (tn *ToastNotification) Failed() *ErrorEvent {
+	return tn.failedPublisher.Event()
+}
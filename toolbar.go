@@ -26,10 +26,15 @@ const (
 type ToolBar struct {
 	WidgetBase
 	imageList          *ImageList
+	imageListHandle    int // Of our HandleChanged handler attached to imageList
 	actions            *ActionList
 	defaultButtonWidth int
 	maxTextRows        int
 	buttonStyle        ToolBarButtonStyle
+	autoOverflow       bool
+	overflowing        bool
+	chevronHot         bool
+	widgets            []toolBarWidget
 }
 
 func NewToolBarWithOrientationAndButtonStyle(parent Container, orientation Orientation, buttonStyle ToolBarButtonStyle) (*ToolBar, error) {
@@ -78,6 +83,10 @@ func NewVerticalToolBar(parent Container) (*ToolBar, error) {
 }
 
 func (tb *ToolBar) Dispose() {
+	for _, w := range tb.widgets {
+		w.widget.Dispose()
+	}
+
 	tb.WidgetBase.Dispose()
 
 	tb.actions.Clear()
@@ -93,6 +102,11 @@ func (tb *ToolBar) applyFont(font *Font) {
 
 	tb.applyDefaultButtonWidth()
 
+	for _, w := range tb.widgets {
+		w.widget.(applyFonter).applyFont(font)
+		tb.onActionChanged(w.action)
+	}
+
 	tb.RequestLayout()
 }
 
@@ -113,13 +127,13 @@ func (tb *ToolBar) ApplyDPI(dpi int) {
 		return
 	}
 
-	tb.SendMessage(win.TB_SETIMAGELIST, 0, uintptr(iml.hIml))
+	old := tb.imageList
 
-	if tb.imageList != nil {
-		tb.imageList.Dispose()
-	}
+	tb.SetImageList(iml)
 
-	tb.imageList = iml
+	if old != nil {
+		old.Dispose()
+	}
 
 	for _, action := range tb.actions.actions {
 		if action.image != nil {
@@ -129,6 +143,11 @@ func (tb *ToolBar) ApplyDPI(dpi int) {
 
 	tb.hFont = tb.Font().handleForDPI(dpi)
 	setWindowFont(tb.hWnd, tb.hFont)
+
+	for _, w := range tb.widgets {
+		applyDPIToDescendants(w.widget, dpi)
+		tb.onActionChanged(w.action)
+	}
 }
 
 func (tb *ToolBar) Orientation() Orientation {
@@ -228,15 +247,28 @@ func (tb *ToolBar) ImageList() *ImageList {
 }
 
 func (tb *ToolBar) SetImageList(value *ImageList) {
+	if tb.imageList != nil {
+		tb.imageList.HandleChanged().Detach(tb.imageListHandle)
+	}
+
+	tb.imageList = value
+
+	if value != nil {
+		// The list gets a new handle on ImageList.ApplyDPI.
+		tb.imageListHandle = value.HandleChanged().Attach(tb.sendImageList)
+	}
+
+	tb.sendImageList()
+}
+
+func (tb *ToolBar) sendImageList() {
 	var hIml win.HIMAGELIST
 
-	if tb.buttonStyle != ToolBarButtonTextOnly && value != nil {
-		hIml = value.hIml
+	if tb.buttonStyle != ToolBarButtonTextOnly && tb.imageList != nil {
+		hIml = tb.imageList.hIml
 	}
 
 	tb.SendMessage(win.TB_SETIMAGELIST, 0, uintptr(hIml))
-
-	tb.imageList = value
 }
 
 func (tb *ToolBar) imageIndex(image *Bitmap) (imageIndex int32, err error) {
@@ -260,6 +292,10 @@ func (tb *ToolBar) imageIndex(image *Bitmap) (imageIndex int32, err error) {
 }
 
 func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if result, handled := tb.handleOverflowMessage(hwnd, msg, wParam, lParam); handled {
+		return result
+	}
+
 	switch msg {
 	case win.WM_MOUSEMOVE, win.WM_MOUSELEAVE, win.WM_LBUTTONDOWN:
 		tb.Invalidate()
@@ -281,7 +317,20 @@ func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) ui
 //	case win.WM_PAINT:
 //		tb.Invalidate()
 
+	case win.WM_CTLCOLOREDIT, win.WM_CTLCOLORSTATIC:
+		if hBrush := tb.handleWMCTLCOLOR(wParam, lParam); hBrush != 0 {
+			return hBrush
+		}
+
 	case win.WM_COMMAND:
+		if lParam != 0 {
+			// Notification from an embedded widget.
+			if widget := tb.widgetFromHandle(win.HWND(lParam)); widget != nil {
+				widget.WndProc(hwnd, msg, wParam, lParam)
+				return 0
+			}
+		}
+
 		switch win.HIWORD(uint32(wParam)) {
 		case win.BN_CLICKED:
 			actionId := uint16(win.LOWORD(uint32(wParam)))
@@ -294,6 +343,11 @@ func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) ui
 	case win.WM_NOTIFY:
 		nmhdr := (*win.NMHDR)(unsafe.Pointer(lParam))
 
+		if widget := tb.widgetFromHandle(nmhdr.HwndFrom); widget != nil {
+			// Notification from an embedded widget.
+			return widget.WndProc(hwnd, msg, wParam, lParam)
+		}
+
 		switch int32(nmhdr.Code) {
 		case win.TBN_DROPDOWN:
 			nmtb := (*win.NMTOOLBAR)(unsafe.Pointer(lParam))
@@ -332,6 +386,9 @@ func (tb *ToolBar) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) ui
 		}
 
 		tb.SendMessage(win.TB_AUTOSIZE, 0, 0)
+
+		tb.updateOverflow()
+		tb.updateWidgets()
 	}
 
 	return tb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
@@ -352,7 +409,7 @@ func (tb *ToolBar) initButtonForAction(action *Action, state, style *byte, image
 		*state |= win.TBSTATE_ENABLED
 	}
 
-	if action.checkable {
+	if action.checkable || action.radioGroup != 0 {
 		*style |= win.BTNS_CHECK
 	}
 
@@ -376,6 +433,13 @@ func (tb *ToolBar) initButtonForAction(action *Action, state, style *byte, image
 		*style = win.BTNS_SEP
 	}
 
+	if widget := tb.widgetForAction(action); widget != nil {
+		// A separator of the width of the widget reserves its slot.
+		*style = win.BTNS_SEP
+		*image = int32(tb.widgetSizePixels(widget).Width)
+		return
+	}
+
 	if tb.buttonStyle != ToolBarButtonTextOnly {
 		var bmp *Bitmap
 
@@ -442,6 +506,9 @@ func (tb *ToolBar) onActionChanged(action *Action) error {
 		return newError("SendMessage(TB_SETBUTTONINFO) failed")
 	}
 
+	tb.updateOverflow()
+	tb.updateWidgets()
+
 	return nil
 }
 
@@ -501,6 +568,9 @@ func (tb *ToolBar) insertAction(action *Action, visibleChanged bool) (err error)
 
 	tb.SendMessage(win.TB_AUTOSIZE, 0, 0)
 
+	tb.updateOverflow()
+	tb.updateWidgets()
+
 	tb.RequestLayout()
 
 	return
@@ -517,6 +587,9 @@ func (tb *ToolBar) removeAction(action *Action, visibleChanged bool) error {
 		return newError("SendMessage(TB_DELETEBUTTON) failed")
 	}
 
+	tb.updateOverflow()
+	tb.updateWidgets()
+
 	tb.RequestLayout()
 
 	return nil
@@ -574,9 +647,23 @@ func (tb *ToolBar) CreateLayoutItem(ctx *LayoutContext) LayoutItem {
 		}
 	}
 
+	idealSize := Size{width, height}
+	minSize := idealSize
+
+	if tb.autoOverflow {
+		// The buttons that do not fit are reachable via the chevron.
+		if wp == win.TRUE {
+			minSize.Height = int(win.HIWORD(buttonSize)) + tb.chevronExtent()
+		} else {
+			layoutFlags |= ShrinkableHorz
+			minSize.Width = int(win.LOWORD(buttonSize)) + tb.chevronExtent()
+		}
+	}
+
 	return &toolBarLayoutItem{
 		layoutFlags: layoutFlags,
-		idealSize:   Size{width, height},
+		idealSize:   idealSize,
+		minSize:     minSize,
 	}
 }
 
@@ -584,6 +671,7 @@ type toolBarLayoutItem struct {
 	LayoutItemBase
 	layoutFlags LayoutFlags
 	idealSize   Size
+	minSize     Size
 }
 
 func (li *toolBarLayoutItem) LayoutFlags() LayoutFlags {
@@ -595,5 +683,5 @@ func (li *toolBarLayoutItem) IdealSize() Size {
 }
 
 func (li *toolBarLayoutItem) MinSize() Size {
-	return li.idealSize
+	return li.minSize
 }
@@ -0,0 +1,398 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+const toolBarChevronExtent96dpi = 14
+
+// AutoOverflow returns if the ToolBar shows an overflow chevron when not all
+// of its buttons fit.
+func (tb *ToolBar) AutoOverflow() bool {
+	return tb.autoOverflow
+}
+
+// SetAutoOverflow sets if the ToolBar shows an overflow chevron when not all
+// of its buttons fit.
+//
+// Clicking the chevron pops up a menu containing the actions of the buttons
+// that are hidden. When enabled, a horizontal ToolBar no longer wraps its
+// buttons into multiple rows.
+func (tb *ToolBar) SetAutoOverflow(enabled bool) error {
+	if enabled == tb.autoOverflow {
+		return nil
+	}
+
+	if tb.Orientation() == Horizontal {
+		if err := tb.ensureStyleBits(win.TBSTYLE_WRAPABLE, !enabled); err != nil {
+			return err
+		}
+	}
+
+	exStyle := tb.SendMessage(win.TB_GETEXTENDEDSTYLE, 0, 0)
+	if enabled {
+		exStyle |= win.TBSTYLE_EX_HIDECLIPPEDBUTTONS
+	} else {
+		exStyle &^= win.TBSTYLE_EX_HIDECLIPPEDBUTTONS
+	}
+	tb.SendMessage(win.TB_SETEXTENDEDSTYLE, 0, exStyle)
+
+	tb.autoOverflow = enabled
+	tb.overflowing = false
+
+	tb.recalcNonClientArea()
+
+	tb.RequestLayout()
+
+	return nil
+}
+
+func (tb *ToolBar) chevronExtent() int {
+	return tb.IntFrom96DPI(toolBarChevronExtent96dpi)
+}
+
+// overflowNeeded returns if the buttons of the ToolBar do not fit into a
+// window of the specified size.
+func (tb *ToolBar) overflowNeeded(width, height int) bool {
+	if !tb.autoOverflow || tb.actions.Len() == 0 {
+		return false
+	}
+
+	var size win.SIZE
+
+	if tb.Orientation() == Vertical {
+		if win.FALSE == tb.SendMessage(win.TB_GETIDEALSIZE, win.TRUE, uintptr(unsafe.Pointer(&size))) {
+			return false
+		}
+
+		return int(size.CY) > height
+	}
+
+	if win.FALSE == tb.SendMessage(win.TB_GETIDEALSIZE, win.FALSE, uintptr(unsafe.Pointer(&size))) {
+		return false
+	}
+
+	return int(size.CX) > width
+}
+
+// updateOverflow makes the ToolBar recalculate its non-client area, where the
+// chevron lives, if the need for it changed.
+func (tb *ToolBar) updateOverflow() {
+	var r win.RECT
+	if !win.GetWindowRect(tb.hWnd, &r) {
+		return
+	}
+
+	if tb.overflowNeeded(int(r.Right-r.Left), int(r.Bottom-r.Top)) == tb.overflowing {
+		return
+	}
+
+	tb.recalcNonClientArea()
+}
+
+func (tb *ToolBar) recalcNonClientArea() {
+	win.SetWindowPos(
+		tb.hWnd,
+		0,
+		0,
+		0,
+		0,
+		0,
+		win.SWP_FRAMECHANGED|win.SWP_NOACTIVATE|win.SWP_NOMOVE|win.SWP_NOSIZE|win.SWP_NOZORDER)
+}
+
+// chevronBounds returns the bounds of the chevron in window coordinates.
+func (tb *ToolBar) chevronBounds() (Rectangle, bool) {
+	if !tb.overflowing {
+		return Rectangle{}, false
+	}
+
+	var r win.RECT
+	if !win.GetWindowRect(tb.hWnd, &r) {
+		return Rectangle{}, false
+	}
+
+	width, height := int(r.Right-r.Left), int(r.Bottom-r.Top)
+	extent := tb.chevronExtent()
+
+	if tb.Orientation() == Vertical {
+		return Rectangle{0, height - extent, width, extent}, true
+	}
+
+	return Rectangle{width - extent, 0, extent, height}, true
+}
+
+func (tb *ToolBar) chevronContainsScreenPoint(x, y int32) bool {
+	bounds, ok := tb.chevronBounds()
+	if !ok {
+		return false
+	}
+
+	var r win.RECT
+	if !win.GetWindowRect(tb.hWnd, &r) {
+		return false
+	}
+
+	return bounds.contains(Point{int(x - r.Left), int(y - r.Top)})
+}
+
+func (tb *ToolBar) drawChevron() {
+	bounds, ok := tb.chevronBounds()
+	if !ok {
+		return
+	}
+
+	hdc := getWindowDC(tb.hWnd)
+	if hdc == 0 {
+		return
+	}
+	defer win.ReleaseDC(tb.hWnd, hdc)
+
+	canvas, err := newCanvasFromHDC(hdc)
+	if err != nil {
+		return
+	}
+	defer canvas.Dispose()
+
+	canvas.fillRectanglePixels(sysColorBtnFaceBrush, bounds)
+
+	if tb.chevronHot {
+		if brush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_3DSHADOW))); err == nil {
+			defer brush.Dispose()
+
+			if pen, err := NewGeometricPen(PenSolid, 1, brush); err == nil {
+				defer pen.Dispose()
+
+				canvas.rectanglePixels(NullBrush(), pen, bounds, 0)
+			}
+		}
+	}
+
+	brush, err := NewSolidColorBrush(Color(win.GetSysColor(win.COLOR_BTNTEXT)))
+	if err != nil {
+		return
+	}
+	defer brush.Dispose()
+
+	pen, err := NewGeometricPen(PenSolid, maxi(1, tb.IntFrom96DPI(1)), brush)
+	if err != nil {
+		return
+	}
+	defer pen.Dispose()
+
+	// Two small arrows pointing in the direction the hidden buttons are.
+	arm := tb.IntFrom96DPI(3)
+	cx, cy := bounds.X+bounds.Width/2, bounds.Y+bounds.Height/2
+	vertical := tb.Orientation() == Vertical
+
+	canvas.withPen(pen, func() error {
+		for i := 0; i < 2; i++ {
+			offset := (i*2 - 1) * arm
+
+			if vertical {
+				x, y := cx, cy+offset
+				win.MoveToEx(hdc, x-arm, y-arm/2, nil)
+				win.LineTo(hdc, int32(x), int32(y+arm/2))
+				win.LineTo(hdc, int32(x+arm+1), int32(y-arm/2-1))
+			} else {
+				x, y := cx+offset, cy
+				win.MoveToEx(hdc, x-arm/2, y-arm, nil)
+				win.LineTo(hdc, int32(x+arm/2), int32(y))
+				win.LineTo(hdc, int32(x-arm/2-1), int32(y+arm+1))
+			}
+		}
+
+		return nil
+	})
+}
+
+func (tb *ToolBar) setChevronHot(hot bool) {
+	if hot == tb.chevronHot {
+		return
+	}
+
+	tb.chevronHot = hot
+
+	win.RedrawWindow(tb.hWnd, nil, 0, win.RDW_FRAME|win.RDW_INVALIDATE)
+}
+
+// isButtonClipped returns if the button at index is not entirely visible in
+// the client area, which means it is hidden by TBSTYLE_EX_HIDECLIPPEDBUTTONS.
+func (tb *ToolBar) isButtonClipped(index int, cb win.RECT) bool {
+	var r win.RECT
+	if 0 == tb.SendMessage(win.TB_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&r))) {
+		return false
+	}
+
+	if tb.Orientation() == Vertical {
+		return r.Bottom > cb.Bottom
+	}
+
+	return r.Right > cb.Right
+}
+
+// showOverflowMenu pops up a menu containing the actions of the buttons that
+// do not fit into the ToolBar.
+func (tb *ToolBar) showOverflowMenu() {
+	bounds, ok := tb.chevronBounds()
+	if !ok {
+		return
+	}
+
+	var cb win.RECT
+	if !win.GetClientRect(tb.hWnd, &cb) {
+		return
+	}
+
+	// The items are inserted directly instead of adding the actions to the
+	// ActionList of the menu, which would make the menu observe them and
+	// toggle the visibility of the separators shared with the ToolBar.
+	var actions []*Action
+	for _, action := range tb.actions.actions {
+		if !action.Visible() || tb.widgetForAction(action) != nil {
+			continue
+		}
+
+		if !tb.isButtonClipped(tb.actions.indexInObserver(action), cb) {
+			continue
+		}
+
+		if action.IsSeparator() && (len(actions) == 0 || actions[len(actions)-1].IsSeparator()) {
+			continue
+		}
+
+		actions = append(actions, action)
+	}
+
+	if n := len(actions); n > 0 && actions[n-1].IsSeparator() {
+		actions = actions[:n-1]
+	}
+
+	if len(actions) == 0 {
+		return
+	}
+
+	menu, err := NewMenu()
+	if err != nil {
+		return
+	}
+	defer func() {
+		// RemoveMenu keeps submenus, which belong to the actions, alive.
+		for i := len(actions) - 1; i >= 0; i-- {
+			win.RemoveMenu(menu.hMenu, uint32(i), win.MF_BYPOSITION)
+		}
+
+		menu.Dispose()
+	}()
+
+	menu.window = tb
+
+	for i, action := range actions {
+		var mii win.MENUITEMINFO
+		menu.initMenuItemInfoFromAction(&mii, action)
+
+		if !win.InsertMenuItem(menu.hMenu, uint32(i), true, &mii) {
+			return
+		}
+
+		if action.Default() {
+			win.SetMenuDefaultItem(menu.hMenu, uint32(i), true)
+		}
+
+		if action.menu != nil {
+			action.menu.updateItemsWithImageForWindow(tb)
+		}
+	}
+
+	var wr win.RECT
+	if !win.GetWindowRect(tb.hWnd, &wr) {
+		return
+	}
+
+	var x, y, flags int32
+	if tb.Orientation() == Vertical {
+		x, y = wr.Right, wr.Top+int32(bounds.Y)
+	} else {
+		x, y, flags = wr.Left+int32(bounds.X+bounds.Width), wr.Bottom, win.TPM_RIGHTALIGN
+	}
+
+	id := win.TrackPopupMenuEx(
+		menu.hMenu,
+		uint32(flags)|win.TPM_NOANIMATION|win.TPM_RETURNCMD,
+		x,
+		y,
+		tb.hWnd,
+		nil)
+
+	if action, ok := actionsById[uint16(id)]; ok && id != 0 {
+		action.raiseTriggered()
+	}
+}
+
+// handleOverflowMessage handles the non-client messages that implement the
+// overflow chevron.
+func (tb *ToolBar) handleOverflowMessage(hwnd win.HWND, msg uint32, wParam, lParam uintptr) (result uintptr, handled bool) {
+	if !tb.autoOverflow {
+		return 0, false
+	}
+
+	switch msg {
+	case win.WM_NCCALCSIZE:
+		result = tb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+
+		r := (*win.RECT)(unsafe.Pointer(lParam))
+
+		tb.overflowing = tb.overflowNeeded(int(r.Right-r.Left), int(r.Bottom-r.Top))
+		if tb.overflowing {
+			if tb.Orientation() == Vertical {
+				r.Bottom -= int32(tb.chevronExtent())
+			} else {
+				r.Right -= int32(tb.chevronExtent())
+			}
+		}
+
+		return result, true
+
+	case win.WM_NCPAINT:
+		result = tb.WidgetBase.WndProc(hwnd, msg, wParam, lParam)
+
+		tb.drawChevron()
+
+		return result, true
+
+	case win.WM_NCHITTEST:
+		if tb.chevronContainsScreenPoint(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam)) {
+			return win.HTBORDER, true
+		}
+
+	case win.WM_NCMOUSEMOVE:
+		tme := win.TRACKMOUSEEVENT{
+			DwFlags:   win.TME_LEAVE | win.TME_NONCLIENT,
+			HwndTrack: hwnd,
+		}
+		tme.CbSize = uint32(unsafe.Sizeof(tme))
+		win.TrackMouseEvent(&tme)
+
+		tb.setChevronHot(tb.chevronContainsScreenPoint(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam)))
+
+	case win.WM_NCMOUSELEAVE:
+		tb.setChevronHot(false)
+
+	case win.WM_NCLBUTTONDOWN:
+		if tb.chevronContainsScreenPoint(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam)) {
+			tb.showOverflowMenu()
+			tb.setChevronHot(false)
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// toolBarWidget associates a Widget embedded into a ToolBar with the action
+// that reserves its slot.
+type toolBarWidget struct {
+	action *Action
+	widget Widget
+}
+
+// AddWidget embeds widget, e.g. a LineEdit or a ComboBox, into the ToolBar
+// after its current items.
+//
+// The widget is taken out of the Children of its parent, has no parent
+// afterwards and its bounds are relative to the ToolBar. It occupies a slot
+// of its ideal width, or its MinSize if larger. The slot is backed by an
+// Action that is appended to Actions, so it can be hidden or removed like any
+// other item.
+func (tb *ToolBar) AddWidget(widget Widget) error {
+	if widget == nil {
+		return newError("widget must not be nil")
+	}
+
+	if tb.actionForWidget(widget) != nil {
+		return newError("widget already added")
+	}
+
+	if err := widget.AsWidgetBase().embedInto(tb.hWnd); err != nil {
+		return err
+	}
+
+	// Let the dialog manager step into the ToolBar for tab navigation.
+	if err := tb.ensureExtendedStyleBits(win.WS_EX_CONTROLPARENT, true); err != nil {
+		return err
+	}
+
+	widget.(applyFonter).applyFont(tb.Font())
+
+	action := NewAction()
+	tb.widgets = append(tb.widgets, toolBarWidget{action, widget})
+
+	if err := tb.actions.Add(action); err != nil {
+		tb.widgets = tb.widgets[:len(tb.widgets)-1]
+		return err
+	}
+
+	tb.updateWidgets()
+
+	return nil
+}
+
+// RemoveWidget removes a widget previously added via AddWidget from the
+// ToolBar. The widget is hidden and left without a parent.
+func (tb *ToolBar) RemoveWidget(widget Widget) error {
+	for i, w := range tb.widgets {
+		if w.widget != widget {
+			continue
+		}
+
+		if err := tb.actions.Remove(w.action); err != nil {
+			return err
+		}
+
+		tb.widgets = append(tb.widgets[:i], tb.widgets[i+1:]...)
+
+		tb.updateWidgets()
+
+		return widget.AsWidgetBase().unembed()
+	}
+
+	return nil
+}
+
+func (tb *ToolBar) actionForWidget(widget Widget) *Action {
+	for _, w := range tb.widgets {
+		if w.widget == widget {
+			return w.action
+		}
+	}
+
+	return nil
+}
+
+func (tb *ToolBar) widgetForAction(action *Action) Widget {
+	for _, w := range tb.widgets {
+		if w.action == action {
+			return w.widget
+		}
+	}
+
+	return nil
+}
+
+// widgetFromHandle returns the embedded widget with the specified handle.
+func (tb *ToolBar) widgetFromHandle(hwnd win.HWND) Widget {
+	for _, w := range tb.widgets {
+		if w.widget.Handle() == hwnd {
+			return w.widget
+		}
+	}
+
+	return nil
+}
+
+// widgetSizePixels returns the size of the slot for an embedded widget.
+func (tb *ToolBar) widgetSizePixels(widget Widget) Size {
+	size := widget.MinSizePixels()
+
+	if li := createLayoutItemForWidget(widget); li != nil {
+		if is, ok := li.(IdealSizer); ok {
+			ideal := is.IdealSize()
+
+			size.Width = maxi(size.Width, ideal.Width)
+			size.Height = maxi(size.Height, ideal.Height)
+		}
+	}
+
+	return size
+}
+
+// updateWidgets makes the buttons tall enough for the embedded widgets and
+// moves the widgets into the slots reserved for them.
+func (tb *ToolBar) updateWidgets() {
+	if len(tb.widgets) == 0 {
+		return
+	}
+
+	var height int
+	for _, w := range tb.widgets {
+		height = maxi(height, tb.widgetSizePixels(w.widget).Height)
+	}
+
+	buttonSize := uint32(tb.SendMessage(win.TB_GETBUTTONSIZE, 0, 0))
+	if height > int(win.HIWORD(buttonSize)) {
+		tb.SendMessage(win.TB_SETBUTTONSIZE, 0, uintptr(win.MAKELONG(win.LOWORD(buttonSize), uint16(height))))
+		tb.SendMessage(win.TB_AUTOSIZE, 0, 0)
+	}
+
+	var cb win.RECT
+	win.GetClientRect(tb.hWnd, &cb)
+
+	for _, w := range tb.widgets {
+		if !w.action.Visible() {
+			w.widget.SetVisible(false)
+			continue
+		}
+
+		var r win.RECT
+		index := tb.actions.indexInObserver(w.action)
+		if 0 == tb.SendMessage(win.TB_GETITEMRECT, uintptr(index), uintptr(unsafe.Pointer(&r))) {
+			continue
+		}
+
+		size := tb.widgetSizePixels(w.widget)
+		height := mini(size.Height, int(r.Bottom-r.Top))
+
+		w.widget.SetBoundsPixels(Rectangle{
+			int(r.Left),
+			int(r.Top) + (int(r.Bottom-r.Top)-height)/2,
+			int(r.Right - r.Left),
+			height,
+		})
+
+		w.widget.SetVisible(!tb.autoOverflow || !tb.isButtonClipped(index, cb))
+	}
+}
@@ -8,6 +8,7 @@ package walk
 
 import (
 	"syscall"
+	"time"
 	"unsafe"
 )
 
@@ -20,8 +21,25 @@ import (
 // 1024 instead.
 const maxToolTipTextLen = 1024 // including NUL terminator
 
+const defaultToolTipMaxWidth = 300
+
+// trackedToolID identifies the tool used by Track. It is not a window handle,
+// so it does not collide with the tools of widgets.
+const trackedToolID = 1
+
 type ToolTip struct {
 	WindowBase
+	title      string
+	titleIcon  uintptr
+	maxWidth   int
+	tool2Title map[win.HWND]toolTipTitle
+	currentDPI int
+	tracking   bool
+}
+
+type toolTipTitle struct {
+	text string
+	icon *Icon
 }
 
 func NewToolTip() (*ToolTip, error) {
@@ -36,7 +54,7 @@ func NewToolTip() (*ToolTip, error) {
 }
 
 func newToolTip(style uint32) (*ToolTip, error) {
-	tt := new(ToolTip)
+	tt := &ToolTip{maxWidth: defaultToolTipMaxWidth}
 
 	if err := InitWindow(
 		tt,
@@ -54,7 +72,7 @@ func newToolTip(style uint32) (*ToolTip, error) {
 		}
 	}()
 
-	tt.SendMessage(win.TTM_SETMAXTIPWIDTH, 0, 300)
+	tt.applyMaxWidth(tt.DPI())
 
 	succeeded = true
 
@@ -92,6 +110,17 @@ func (tt *ToolTip) SetErrorTitle(title string) error {
 }
 
 func (tt *ToolTip) setTitle(title string, icon uintptr) error {
+	if err := tt.sendTitle(title, icon); err != nil {
+		return err
+	}
+
+	tt.title = title
+	tt.titleIcon = icon
+
+	return nil
+}
+
+func (tt *ToolTip) sendTitle(title string, icon uintptr) error {
 	if len(title) > 99 {
 		title = title[:99]
 	}
@@ -103,6 +132,163 @@ func (tt *ToolTip) setTitle(title string, icon uintptr) error {
 	return nil
 }
 
+// ToolTitle returns the title that is shown above the text of tool, if it
+// was set with SetToolTitle.
+func (tt *ToolTip) ToolTitle(tool Widget) string {
+	return tt.tool2Title[tool.Handle()].text
+}
+
+// SetToolTitle sets a title and an optional icon that are shown above the
+// text of tool, instead of the title of the ToolTip. An empty title restores
+// the title of the ToolTip for tool.
+func (tt *ToolTip) SetToolTitle(tool Widget, title string, icon *Icon) error {
+	hwnd := tool.Handle()
+
+	if title == "" {
+		delete(tt.tool2Title, hwnd)
+	} else {
+		if tt.tool2Title == nil {
+			tt.tool2Title = make(map[win.HWND]toolTipTitle)
+		}
+
+		tt.tool2Title[hwnd] = toolTipTitle{title, icon}
+	}
+
+	return nil
+}
+
+// InitialDelay returns the time the mouse must rest on a tool before the
+// ToolTip appears.
+func (tt *ToolTip) InitialDelay() time.Duration {
+	return tt.delayTime(ttdtInitial)
+}
+
+// SetInitialDelay sets the time the mouse must rest on a tool before the
+// ToolTip appears. A negative delay restores the system default.
+func (tt *ToolTip) SetInitialDelay(delay time.Duration) error {
+	return tt.setDelayTime(ttdtInitial, delay)
+}
+
+// AutoPopDelay returns the time the ToolTip stays visible while the mouse
+// rests on a tool.
+func (tt *ToolTip) AutoPopDelay() time.Duration {
+	return tt.delayTime(ttdtAutopop)
+}
+
+// SetAutoPopDelay sets the time the ToolTip stays visible while the mouse
+// rests on a tool. A negative delay restores the system default.
+func (tt *ToolTip) SetAutoPopDelay(delay time.Duration) error {
+	return tt.setDelayTime(ttdtAutopop, delay)
+}
+
+func (tt *ToolTip) delayTime(which uintptr) time.Duration {
+	return time.Duration(tt.SendMessage(win.TTM_GETDELAYTIME, which, 0)) * time.Millisecond
+}
+
+func (tt *ToolTip) setDelayTime(which uintptr, delay time.Duration) error {
+	ms := -1
+	if delay >= 0 {
+		ms = int(delay / time.Millisecond)
+		if ms > 32767 {
+			return newError("delay out of range")
+		}
+	}
+
+	// A delay of -1 restores the default, but only if it is the full lParam.
+	var lParam uintptr
+	if ms < 0 {
+		lParam = ^uintptr(0)
+	} else {
+		lParam = uintptr(win.MAKELONG(uint16(ms), 0))
+	}
+
+	tt.SendMessage(win.TTM_SETDELAYTIME, which, lParam)
+
+	return nil
+}
+
+// MaxWidth returns the width in 1/96" units, beyond which the text of the
+// ToolTip is wrapped.
+func (tt *ToolTip) MaxWidth() int {
+	return tt.maxWidth
+}
+
+// SetMaxWidth sets the width in 1/96" units, beyond which the text of the
+// ToolTip is wrapped. Line breaks in the text are only honored, if the width
+// is positive. A width of -1 disables wrapping.
+func (tt *ToolTip) SetMaxWidth(width int) {
+	tt.maxWidth = width
+
+	tt.applyMaxWidth(tt.currentDPI)
+}
+
+func (tt *ToolTip) applyMaxWidth(dpi int) {
+	if dpi == 0 {
+		dpi = tt.DPI()
+	}
+
+	tt.currentDPI = dpi
+
+	width := tt.maxWidth
+	if width > 0 {
+		width = IntFrom96DPI(width, dpi)
+	}
+
+	tt.SendMessage(win.TTM_SETMAXTIPWIDTH, 0, uintptr(width))
+}
+
+// prepareForTool applies the title and the DPI scaled max width for the tool
+// identified by id, before the ToolTip measures itself to show up for it.
+func (tt *ToolTip) prepareForTool(id uintptr) {
+	hwnd := win.HWND(id)
+	if id == trackedToolID || windowFromHandle(hwnd) == nil {
+		hwnd = 0
+	}
+
+	dpi := tt.currentDPI
+	if hwnd != 0 {
+		dpi = int(win.GetDpiForWindow(hwnd))
+	}
+	if dpi != tt.currentDPI {
+		tt.applyMaxWidth(dpi)
+	}
+
+	if len(tt.tool2Title) == 0 {
+		return
+	}
+
+	if title, ok := tt.tool2Title[hwnd]; ok {
+		icon := uintptr(win.TTI_NONE)
+		if title.icon != nil {
+			icon = uintptr(title.icon.handleForDPI(tt.currentDPI))
+		}
+
+		tt.sendTitle(title.text, icon)
+	} else {
+		tt.sendTitle(tt.title, tt.titleIcon)
+	}
+}
+
+func (tt *ToolTip) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	switch msg {
+	case win.WM_NOTIFY:
+		// The notifications of the ToolTip are sent to its tools, which
+		// forward them here.
+		nmh := (*win.NMHDR)(unsafe.Pointer(lParam))
+		if nmh.HwndFrom != tt.hWnd {
+			break
+		}
+
+		if nmh.Code == ttnShow {
+			tt.prepareForTool(nmh.IdFrom)
+		}
+
+		return 0
+	}
+
+	return tt.WindowBase.WndProc(hwnd, msg, wParam, lParam)
+}
+
 func (tt *ToolTip) track(tool Widget) error {
 	form := tool.Form()
 	if form == nil {
@@ -199,6 +385,8 @@ func (tt *ToolTip) RemoveTool(tool Widget) error {
 
 	tt.SendMessage(win.TTM_DELTOOL, 0, uintptr(unsafe.Pointer(&ti)))
 
+	delete(tt.tool2Title, hwnd)
+
 	return nil
 }
 
@@ -217,6 +405,14 @@ func (tt *ToolTip) SetText(tool Widget, text string) error {
 		return newError("unknown tool")
 	}
 
+	ti.LpszText = syscall.StringToUTF16Ptr(truncateToolTipText(text))
+
+	tt.SendMessage(win.TTM_SETTOOLINFO, 0, uintptr(unsafe.Pointer(ti)))
+
+	return nil
+}
+
+func truncateToolTipText(text string) string {
 	n := 0
 	for i, r := range text {
 		if r < 0x10000 {
@@ -225,14 +421,101 @@ func (tt *ToolTip) SetText(tool Widget, text string) error {
 			n += 2 // surrogate pair
 		}
 		if n >= maxToolTipTextLen {
-			text = text[:i]
-			break
+			return text[:i]
 		}
 	}
 
-	ti.LpszText = syscall.StringToUTF16Ptr(text)
+	return text
+}
 
-	tt.SendMessage(win.TTM_SETTOOLINFO, 0, uintptr(unsafe.Pointer(ti)))
+// Tracking returns if the ToolTip is currently shown by Track.
+func (tt *ToolTip) Tracking() bool {
+	return tt.tracking
+}
+
+// Track shows text in the ToolTip at position, which is in screen
+// coordinates (native pixels), independent of any tool. Calling it again
+// while tracking moves the ToolTip and updates its text, e.g. to follow the
+// mouse cursor over a chart.
+//
+// The ToolTip is placed to the lower right of position, or flipped to the
+// left or top if it would not fit into the work area of the monitor
+// containing position.
+func (tt *ToolTip) Track(position Point, text string) error {
+	var ti win.TOOLINFO
+	ti.CbSize = uint32(unsafe.Sizeof(ti))
+	ti.Hwnd = tt.hWnd
+	ti.UId = trackedToolID
+	ti.UFlags = win.TTF_TRACK | win.TTF_ABSOLUTE
+	ti.LpszText = syscall.StringToUTF16Ptr(truncateToolTipText(text))
+
+	if tt.tracking {
+		tt.SendMessage(win.TTM_UPDATETIPTEXT, 0, uintptr(unsafe.Pointer(&ti)))
+	} else if win.FALSE == tt.SendMessage(win.TTM_ADDTOOL, 0, uintptr(unsafe.Pointer(&ti))) {
+		return newError("TTM_ADDTOOL failed")
+	}
+
+	p := tt.trackPosition(position, &ti)
+
+	tt.SendMessage(win.TTM_TRACKPOSITION, 0, uintptr(win.MAKELONG(uint16(p.X), uint16(p.Y))))
+
+	if !tt.tracking {
+		tt.SendMessage(win.TTM_TRACKACTIVATE, 1, uintptr(unsafe.Pointer(&ti)))
+
+		tt.tracking = true
+	}
+
+	return nil
+}
+
+func (tt *ToolTip) trackPosition(position Point, ti *win.TOOLINFO) Point {
+	var mi win.MONITORINFO
+	mi.CbSize = uint32(unsafe.Sizeof(mi))
+
+	r := win.RECT{Left: int32(position.X), Top: int32(position.Y), Right: int32(position.X + 1), Bottom: int32(position.Y + 1)}
+
+	hMonitor := monitorFromRect(&r, win.MONITOR_DEFAULTTONEAREST)
+	if !win.GetMonitorInfo(hMonitor, &mi) {
+		return position
+	}
+
+	work := rectangleFromRECT(mi.RcWork)
+
+	size := tt.SendMessage(win.TTM_GETBUBBLESIZE, 0, uintptr(unsafe.Pointer(ti)))
+	width, height := int(win.LOWORD(uint32(size))), int(win.HIWORD(uint32(size)))
+
+	p := position
+	if p.X+width > work.X+work.Width {
+		p.X -= width
+	}
+	if p.Y+height > work.Y+work.Height {
+		p.Y -= height
+	}
+	if p.X < work.X {
+		p.X = work.X
+	}
+	if p.Y < work.Y {
+		p.Y = work.Y
+	}
+
+	return p
+}
+
+// Untrack hides the ToolTip shown by Track.
+func (tt *ToolTip) Untrack() error {
+	if !tt.tracking {
+		return nil
+	}
+
+	var ti win.TOOLINFO
+	ti.CbSize = uint32(unsafe.Sizeof(ti))
+	ti.Hwnd = tt.hWnd
+	ti.UId = trackedToolID
+
+	tt.SendMessage(win.TTM_TRACKACTIVATE, 0, uintptr(unsafe.Pointer(&ti)))
+	tt.SendMessage(win.TTM_DELTOOL, 0, uintptr(unsafe.Pointer(&ti)))
+
+	tt.tracking = false
 
 	return nil
 }
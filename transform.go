@@ -0,0 +1,102 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"math"
+)
+
+// Transform is an affine transformation of 2D coordinates, as used by
+// Canvas.SetWorldTransform.
+//
+// A point (x, y) is mapped to
+//
+//	(x*M11 + y*M21 + Dx, x*M12 + y*M22 + Dy).
+//
+// Dx and Dy are in 1/96" units, like all other Canvas coordinates.
+type Transform struct {
+	M11, M12 float64
+	M21, M22 float64
+	Dx, Dy   float64
+}
+
+// IdentityTransform returns the Transform that leaves coordinates unchanged.
+func IdentityTransform() Transform {
+	return Transform{M11: 1, M22: 1}
+}
+
+// TranslationTransform returns a Transform that moves coordinates by dx, dy.
+func TranslationTransform(dx, dy float64) Transform {
+	return Transform{M11: 1, M22: 1, Dx: dx, Dy: dy}
+}
+
+// ScalingTransform returns a Transform that scales coordinates by sx, sy.
+func ScalingTransform(sx, sy float64) Transform {
+	return Transform{M11: sx, M22: sy}
+}
+
+// ShearingTransform returns a Transform that shears coordinates by shx
+// horizontally and shy vertically.
+func ShearingTransform(shx, shy float64) Transform {
+	return Transform{M11: 1, M12: shy, M21: shx, M22: 1}
+}
+
+// RotationTransform returns a Transform that rotates coordinates clockwise
+// by degrees around center.
+func RotationTransform(degrees float64, center Point) Transform {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+
+	cx, cy := float64(center.X), float64(center.Y)
+
+	return TranslationTransform(-cx, -cy).
+		Then(Transform{M11: cos, M12: sin, M21: -sin, M22: cos}).
+		Then(TranslationTransform(cx, cy))
+}
+
+// Then returns the Transform that applies t first and u second.
+func (t Transform) Then(u Transform) Transform {
+	return Transform{
+		M11: t.M11*u.M11 + t.M12*u.M21,
+		M12: t.M11*u.M12 + t.M12*u.M22,
+		M21: t.M21*u.M11 + t.M22*u.M21,
+		M22: t.M21*u.M12 + t.M22*u.M22,
+		Dx:  t.Dx*u.M11 + t.Dy*u.M21 + u.Dx,
+		Dy:  t.Dx*u.M12 + t.Dy*u.M22 + u.Dy,
+	}
+}
+
+// Apply returns p mapped by t.
+func (t Transform) Apply(p Point) Point {
+	x, y := float64(p.X), float64(p.Y)
+
+	return Point{
+		int(math.Round(x*t.M11 + y*t.M21 + t.Dx)),
+		int(math.Round(x*t.M12 + y*t.M22 + t.Dy)),
+	}
+}
+
+func (t Transform) toXFORM(dpi int) xform {
+	return xform{
+		eM11: float32(t.M11),
+		eM12: float32(t.M12),
+		eM21: float32(t.M21),
+		eM22: float32(t.M22),
+		eDx:  float32(t.Dx * float64(dpi) / 96),
+		eDy:  float32(t.Dy * float64(dpi) / 96),
+	}
+}
+
+func transformFromXFORM(xf xform, dpi int) Transform {
+	return Transform{
+		M11: float64(xf.eM11),
+		M12: float64(xf.eM12),
+		M21: float64(xf.eM21),
+		M22: float64(xf.eM22),
+		Dx:  float64(xf.eDx) * 96 / float64(dpi),
+		Dy:  float64(xf.eDy) * 96 / float64(dpi),
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+type TreeItemMoveEventHandler func(move *TreeItemMove)
+
+type TreeItemMoveEvent struct {
+	handlers []TreeItemMoveEventHandler
+}
+
+func (e *TreeItemMoveEvent) Attach(handler TreeItemMoveEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *TreeItemMoveEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type TreeItemMoveEventPublisher struct {
+	event TreeItemMoveEvent
+}
+
+func (p *TreeItemMoveEventPublisher) Event() *TreeItemMoveEvent {
+	return &p.event
+}
+
+func (p *TreeItemMoveEventPublisher) Publish(move *TreeItemMove) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(move)
+		}
+	}
+}
@@ -13,9 +13,12 @@ import (
 	"github.com/lxn/win"
 )
 
+const defaultTreeViewLoadingText = "Loading..."
+
 type treeViewItemInfo struct {
 	handle       win.HTREEITEM
 	child2Handle map[TreeItem]win.HTREEITEM
+	hLoading     win.HTREEITEM
 }
 
 type TreeView struct {
@@ -33,13 +36,23 @@ type TreeView struct {
 	usingSysIml                    bool
 	imageUintptr2Index             map[uintptr]int32
 	filePath2IconIndex             map[string]int32
+	loadingText                    string
+	checkBoxes                     bool
+	item2CheckState                map[TreeItem]CheckState
+	settingCheckState              bool
+	nodeCheckedChangedPublisher    TreeItemEventPublisher
+	itemsReorderable               bool
+	itemMoveValidator              TreeItemMoveValidator
+	drag                           *treeViewDragState
+	itemMovedPublisher             TreeItemMoveEventPublisher
+	beforeExpandPublisher          TreeItemEventPublisher
 	expandedChangedPublisher       TreeItemEventPublisher
 	currentItemChangedPublisher    EventPublisher
 	itemActivatedPublisher         EventPublisher
 }
 
 func NewTreeView(parent Container) (*TreeView, error) {
-	tv := new(TreeView)
+	tv := &TreeView{loadingText: defaultTreeViewLoadingText}
 
 	if err := InitWidget(
 		tv,
@@ -123,6 +136,18 @@ func (tv *TreeView) SetBackground(bg Brush) {
 	tv.SendMessage(win.TVM_SETBKCOLOR, 0, uintptr(color))
 }
 
+func (tv *TreeView) applyDarkMode(dark bool) {
+	setDarkModeTheme(tv.hWnd, dark, "DarkMode_Explorer", "Explorer")
+
+	if dark {
+		tv.SendMessage(win.TVM_SETBKCOLOR, 0, uintptr(darkModeControlColor))
+		tv.SendMessage(win.TVM_SETTEXTCOLOR, 0, uintptr(darkModeTextColor))
+	} else {
+		tv.SetBackground(tv.Background())
+		tv.SendMessage(win.TVM_SETTEXTCOLOR, 0, win.CLR_NONE)
+	}
+}
+
 func (tv *TreeView) Model() TreeModel {
 	return tv.model
 }
@@ -138,6 +163,7 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 	}
 
 	tv.model = model
+	tv.item2CheckState = nil
 
 	if model != nil {
 		tv.lazyPopulation = model.LazyPopulation()
@@ -149,6 +175,10 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 				tv.SetSuspended(true)
 				defer tv.SetSuspended(false)
 
+				if err := tv.removeLoadingPlaceholder(parent); err != nil {
+					return
+				}
+
 				if err := tv.removeDescendants(parent); err != nil {
 					return
 				}
@@ -174,6 +204,13 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 
 			var hInsertAfter win.HTREEITEM
 			parent := item.Parent()
+
+			if tv.item2Info[parent] != nil {
+				if err := tv.removeLoadingPlaceholder(parent); err != nil {
+					return
+				}
+			}
+
 			for i := parent.ChildCount() - 1; i >= 0; i-- {
 				if parent.ChildAt(i) == item {
 					if i > 0 {
@@ -195,6 +232,12 @@ func (tv *TreeView) SetModel(model TreeModel) error {
 			if err := tv.removeItem(item); err != nil {
 				return
 			}
+
+			if tv.item2CheckState != nil {
+				delete(tv.item2CheckState, item)
+
+				tv.updateAncestorCheckStates(item)
+			}
 		})
 	}
 
@@ -392,9 +435,15 @@ func (tv *TreeView) insertItemAfter(item TreeItem, hInsertAfter win.HTREEITEM) (
 	if hItem == 0 {
 		return 0, newError("TVM_INSERTITEM failed")
 	}
-	tv.item2Info[item] = &treeViewItemInfo{hItem, make(map[TreeItem]win.HTREEITEM)}
+	tv.item2Info[item] = &treeViewItemInfo{handle: hItem, child2Handle: make(map[TreeItem]win.HTREEITEM)}
 	tv.handle2Item[hItem] = item
 
+	if tv.checkBoxes {
+		if err := tv.applyCheckState(item); err != nil {
+			return 0, err
+		}
+	}
+
 	if !tv.lazyPopulation {
 		if err := tv.insertChildren(item); err != nil {
 			return 0, err
@@ -469,6 +518,317 @@ func (tv *TreeView) removeDescendants(parent TreeItem) error {
 	return nil
 }
 
+// LoadingText returns the text of the placeholder item that SetItemLoading
+// shows below an item while its children are being loaded.
+func (tv *TreeView) LoadingText() string {
+	return tv.loadingText
+}
+
+// SetLoadingText sets the text of the placeholder item that SetItemLoading
+// shows below an item while its children are being loaded.
+//
+// The default is "Loading...". Placeholders that are already shown keep their
+// text.
+func (tv *TreeView) SetLoadingText(text string) {
+	tv.loadingText = text
+}
+
+// ItemLoading returns if a loading placeholder is currently shown below item.
+func (tv *TreeView) ItemLoading(item TreeItem) bool {
+	info := tv.item2Info[item]
+
+	return info != nil && info.hLoading != 0
+}
+
+// SetItemLoading shows or removes a transient placeholder child below item,
+// displaying LoadingText.
+//
+// This is meant to be called from a BeforeExpand handler that loads the
+// children of item asynchronously. The placeholder is removed automatically
+// as soon as the model publishes ItemsReset for item or ItemInserted for one
+// of its children, which should happen from within Synchronize once the data
+// has arrived.
+func (tv *TreeView) SetItemLoading(item TreeItem, loading bool) error {
+	if !loading {
+		return tv.removeLoadingPlaceholder(item)
+	}
+
+	info := tv.item2Info[item]
+	if info == nil {
+		return newError("invalid item")
+	}
+
+	if info.hLoading != 0 {
+		return nil
+	}
+
+	text, err := syscall.UTF16PtrFromString(tv.loadingText)
+	if err != nil {
+		return wrapError(err)
+	}
+
+	var tvins win.TVINSERTSTRUCT
+	tvins.HParent = info.handle
+	tvins.HInsertAfter = win.TVI_LAST
+	tvins.Item.Mask = win.TVIF_CHILDREN | win.TVIF_TEXT
+	tvins.Item.PszText = uintptr(unsafe.Pointer(text))
+
+	hItem := win.HTREEITEM(tv.SendMessage(win.TVM_INSERTITEM, 0, uintptr(unsafe.Pointer(&tvins))))
+	if hItem == 0 {
+		return newError("TVM_INSERTITEM failed")
+	}
+
+	info.hLoading = hItem
+
+	return nil
+}
+
+func (tv *TreeView) removeLoadingPlaceholder(item TreeItem) error {
+	info := tv.item2Info[item]
+	if info == nil {
+		return newError("invalid item")
+	}
+
+	if info.hLoading == 0 {
+		return nil
+	}
+
+	hItem := info.hLoading
+	info.hLoading = 0
+
+	if 0 == tv.SendMessage(win.TVM_DELETEITEM, 0, uintptr(hItem)) {
+		return newError("SendMessage(TVM_DELETEITEM) failed")
+	}
+
+	return nil
+}
+
+// CheckBoxes returns if the TreeView shows a check box for each item.
+func (tv *TreeView) CheckBoxes() bool {
+	return tv.checkBoxes
+}
+
+// SetCheckBoxes sets if the TreeView shows a check box for each item.
+//
+// Check boxes are tri-state: Checking or unchecking an item applies the same
+// state to all of its descendants, while the check box of an ancestor shows
+// the partial state if only some of its descendants are checked.
+func (tv *TreeView) SetCheckBoxes(checkBoxes bool) error {
+	if checkBoxes == tv.checkBoxes {
+		return nil
+	}
+
+	var exStyle uintptr
+	if checkBoxes {
+		exStyle = win.TVS_EX_PARTIALCHECKBOXES
+	}
+	if hr := win.HRESULT(tv.SendMessage(win.TVM_SETEXTENDEDSTYLE, win.TVS_EX_PARTIALCHECKBOXES, exStyle)); win.FAILED(hr) {
+		return errorFromHRESULT("TVM_SETEXTENDEDSTYLE", hr)
+	}
+
+	// TVS_CHECKBOXES has to be set after the control has been created.
+	if err := tv.ensureStyleBits(win.TVS_CHECKBOXES, checkBoxes); err != nil {
+		return err
+	}
+
+	tv.checkBoxes = checkBoxes
+
+	if checkBoxes {
+		for item := range tv.item2Info {
+			if err := tv.applyCheckState(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// NodeChecked returns if item is checked.
+//
+// Items in the partial state are not considered checked.
+func (tv *TreeView) NodeChecked(item TreeItem) bool {
+	return tv.NodeCheckState(item) == CheckChecked
+}
+
+// NodeCheckState returns the check state of item, which is CheckIndeterminate
+// if only some of its descendants are checked.
+//
+// Items that have never been checked or unchecked explicitly inherit the
+// state of their parent, unless the parent is in the partial state.
+func (tv *TreeView) NodeCheckState(item TreeItem) CheckState {
+	if state, ok := tv.item2CheckState[item]; ok {
+		return state
+	}
+
+	if parent := item.Parent(); parent != nil {
+		if state := tv.NodeCheckState(parent); state != CheckIndeterminate {
+			return state
+		}
+	}
+
+	return CheckUnchecked
+}
+
+// SetNodeChecked checks or unchecks item and all of its descendants and
+// updates the check state of its ancestors accordingly.
+//
+// NodeCheckedChanged is published for every item whose check state changed.
+func (tv *TreeView) SetNodeChecked(item TreeItem, checked bool) error {
+	state := CheckUnchecked
+	if checked {
+		state = CheckChecked
+	}
+
+	if err := tv.setCheckStateRecursive(item, state); err != nil {
+		return err
+	}
+
+	return tv.updateAncestorCheckStates(item)
+}
+
+// NodeCheckedChanged returns the event that is published when the check state
+// of an item changed, either by the user or programmatically.
+func (tv *TreeView) NodeCheckedChanged() *TreeItemEvent {
+	return tv.nodeCheckedChangedPublisher.Event()
+}
+
+func (tv *TreeView) setCheckState(item TreeItem, state CheckState) (changed bool, err error) {
+	changed = tv.NodeCheckState(item) != state
+
+	if tv.item2CheckState == nil {
+		tv.item2CheckState = make(map[TreeItem]CheckState)
+	}
+	tv.item2CheckState[item] = state
+
+	if err := tv.applyCheckState(item); err != nil {
+		return false, err
+	}
+
+	if changed {
+		tv.nodeCheckedChangedPublisher.Publish(item)
+	}
+
+	return changed, nil
+}
+
+func (tv *TreeView) setCheckStateRecursive(item TreeItem, state CheckState) error {
+	if _, err := tv.setCheckState(item, state); err != nil {
+		return err
+	}
+
+	// Descendants of a lazily populated item that have not been inserted yet
+	// simply inherit the state once they are.
+	if tv.lazyPopulation {
+		if info := tv.item2Info[item]; info == nil || len(info.child2Handle) == 0 {
+			for descendant := range tv.item2CheckState {
+				for ancestor := descendant.Parent(); ancestor != nil; ancestor = ancestor.Parent() {
+					if ancestor == item {
+						delete(tv.item2CheckState, descendant)
+						break
+					}
+				}
+			}
+
+			return nil
+		}
+	}
+
+	for i := item.ChildCount() - 1; i >= 0; i-- {
+		if err := tv.setCheckStateRecursive(item.ChildAt(i), state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tv *TreeView) updateAncestorCheckStates(item TreeItem) error {
+	for parent := item.Parent(); parent != nil; parent = parent.Parent() {
+		var checked, unchecked bool
+
+		if tv.item2CheckState == nil {
+			tv.item2CheckState = make(map[TreeItem]CheckState)
+		}
+
+		for i := parent.ChildCount() - 1; i >= 0; i-- {
+			child := parent.ChildAt(i)
+
+			childState := tv.NodeCheckState(child)
+			if _, ok := tv.item2CheckState[child]; !ok {
+				// The child inherits its state from parent, whose state
+				// is about to change, so we keep the current one.
+				tv.item2CheckState[child] = childState
+			}
+
+			switch childState {
+			case CheckChecked:
+				checked = true
+
+			case CheckUnchecked:
+				unchecked = true
+
+			default:
+				checked, unchecked = true, true
+			}
+		}
+
+		state := CheckIndeterminate
+		if !unchecked {
+			state = CheckChecked
+		} else if !checked {
+			state = CheckUnchecked
+		}
+
+		if changed, err := tv.setCheckState(parent, state); err != nil {
+			return err
+		} else if !changed {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (tv *TreeView) applyCheckState(item TreeItem) error {
+	info := tv.item2Info[item]
+	if info == nil || !tv.checkBoxes {
+		return nil
+	}
+
+	// State image indexes are 1 for unchecked, 2 for checked and, with
+	// TVS_EX_PARTIALCHECKBOXES, 3 for the partial state.
+	var index uint32
+	switch tv.NodeCheckState(item) {
+	case CheckChecked:
+		index = 2
+
+	case CheckIndeterminate:
+		index = 3
+
+	default:
+		index = 1
+	}
+
+	tvi := &win.TVITEM{
+		Mask:      win.TVIF_STATE,
+		HItem:     info.handle,
+		State:     index << 12,
+		StateMask: win.TVIS_STATEIMAGEMASK,
+	}
+
+	tv.settingCheckState = true
+	defer func() {
+		tv.settingCheckState = false
+	}()
+
+	if 0 == tv.SendMessage(win.TVM_SETITEM, 0, uintptr(unsafe.Pointer(tvi))) {
+		return newError("SendMessage(TVM_SETITEM) failed")
+	}
+
+	return nil
+}
+
 func (tv *TreeView) ensureItemAndAncestorsInserted(item TreeItem) error {
 	if item == nil {
 		return newError("invalid item")
@@ -542,6 +902,15 @@ func (tv *TreeView) SetExpanded(item TreeItem, expanded bool) error {
 	return nil
 }
 
+// BeforeExpand returns the event that is published when an item is about to
+// be expanded.
+//
+// For models that use lazy population, handlers may use this to load the
+// children of the item, see SetItemLoading and HasChilder.
+func (tv *TreeView) BeforeExpand() *TreeItemEvent {
+	return tv.beforeExpandPublisher.Event()
+}
+
 func (tv *TreeView) ExpandedChanged() *TreeItemEvent {
 	return tv.expandedChangedPublisher.Event()
 }
@@ -555,6 +924,10 @@ func (tv *TreeView) ItemActivated() *Event {
 }
 
 func (tv *TreeView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) uintptr {
+	if tv.handleDragMessage(msg, wParam, lParam) {
+		return 0
+	}
+
 	switch msg {
 	case win.WM_GETDLGCODE:
 		if wParam == win.VK_RETURN {
@@ -584,16 +957,28 @@ func (tv *TreeView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 				(*buf)[max-1] = 0
 			}
 			if nmtvdi.Item.Mask&win.TVIF_CHILDREN != 0 {
-				nmtvdi.Item.CChildren = int32(item.ChildCount())
+				if hc, ok := item.(HasChilder); ok && tv.lazyPopulation {
+					if hc.HasChild() {
+						nmtvdi.Item.CChildren = 1
+					} else {
+						nmtvdi.Item.CChildren = 0
+					}
+				} else {
+					nmtvdi.Item.CChildren = int32(item.ChildCount())
+				}
 			}
 
 		case win.TVN_ITEMEXPANDING:
 			nmtv := (*win.NMTREEVIEW)(unsafe.Pointer(lParam))
 			item := tv.handle2Item[nmtv.ItemNew.HItem]
 
+			if nmtv.Action == win.TVE_EXPAND {
+				tv.beforeExpandPublisher.Publish(item)
+			}
+
 			if nmtv.Action == win.TVE_EXPAND && tv.lazyPopulation {
 				info := tv.item2Info[item]
-				if len(info.child2Handle) == 0 {
+				if info != nil && len(info.child2Handle) == 0 {
 					tv.insertChildren(item)
 				}
 			}
@@ -616,6 +1001,25 @@ func (tv *TreeView) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr) u
 			case win.TVE_TOGGLE:
 			}
 
+		case win.TVN_ITEMCHANGED:
+			nmtvic := (*nmtvItemChange)(unsafe.Pointer(lParam))
+
+			if !tv.checkBoxes || tv.settingCheckState || (nmtvic.uStateNew^nmtvic.uStateOld)&win.TVIS_STATEIMAGEMASK == 0 {
+				break
+			}
+
+			if item := tv.handle2Item[nmtvic.hItem]; item != nil {
+				// The control cycles through all state images, including
+				// the partial one, so we decide about the new state ourselves.
+				tv.SetNodeChecked(item, tv.NodeCheckState(item) != CheckChecked)
+			}
+
+		case win.TVN_BEGINDRAG:
+			if tv.itemsReorderable {
+				nmtv := (*win.NMTREEVIEW)(unsafe.Pointer(lParam))
+				tv.beginDrag(nmtv.ItemNew.HItem)
+			}
+
 		case win.NM_DBLCLK:
 			tv.itemActivatedPublisher.Publish()
 
@@ -0,0 +1,227 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"unsafe"
+
+	"github.com/lxn/win"
+)
+
+// TreeDropPosition describes where a dragged item is dropped relative to the
+// target item.
+type TreeDropPosition int
+
+const (
+	// TreeDropBefore inserts the item as a sibling before the target.
+	TreeDropBefore TreeDropPosition = iota
+
+	// TreeDropOnto makes the item the last child of the target.
+	TreeDropOnto
+
+	// TreeDropAfter inserts the item as a sibling after the target.
+	TreeDropAfter
+)
+
+// TreeItemMove describes a drag and drop operation within a TreeView.
+type TreeItemMove struct {
+	Item     TreeItem
+	Target   TreeItem
+	Position TreeDropPosition
+}
+
+// NewParent returns the item that would become the parent of Item, which is
+// nil for root items.
+func (m *TreeItemMove) NewParent() TreeItem {
+	if m.Position == TreeDropOnto {
+		return m.Target
+	}
+
+	return m.Target.Parent()
+}
+
+// TreeItemMoveValidator decides if a drag and drop operation is allowed.
+type TreeItemMoveValidator func(move *TreeItemMove) bool
+
+type treeViewDragState struct {
+	item TreeItem
+	move *TreeItemMove
+}
+
+// ItemsReorderable returns if the user can move items by drag and drop.
+func (tv *TreeView) ItemsReorderable() bool {
+	return tv.itemsReorderable
+}
+
+// SetItemsReorderable sets if the user can move items by drag and drop.
+//
+// The TreeView does not move any items itself. Instead it publishes ItemMoved
+// and leaves it to the model to actually move the item and publish the usual
+// change events.
+func (tv *TreeView) SetItemsReorderable(reorderable bool) {
+	tv.itemsReorderable = reorderable
+
+	if !reorderable {
+		tv.cancelDrag()
+	}
+}
+
+// ItemMoveValidator returns the function that decides if a drag and drop
+// operation is allowed.
+func (tv *TreeView) ItemMoveValidator() TreeItemMoveValidator {
+	return tv.itemMoveValidator
+}
+
+// SetItemMoveValidator sets a function that decides if a drag and drop
+// operation is allowed. It is called repeatedly while the user drags an item
+// and once more before it is dropped.
+//
+// Moving an item onto or next to itself or one of its descendants is always
+// rejected, without consulting the validator.
+func (tv *TreeView) SetItemMoveValidator(validator TreeItemMoveValidator) {
+	tv.itemMoveValidator = validator
+}
+
+// ItemMoved returns the event that is published when the user dropped an item
+// at a new position.
+func (tv *TreeView) ItemMoved() *TreeItemMoveEvent {
+	return tv.itemMovedPublisher.Event()
+}
+
+func (tv *TreeView) beginDrag(hItem win.HTREEITEM) {
+	item := tv.handle2Item[hItem]
+	if item == nil {
+		return
+	}
+
+	tv.drag = &treeViewDragState{item: item}
+
+	win.SetCapture(tv.hWnd)
+}
+
+func (tv *TreeView) cancelDrag() {
+	if tv.drag == nil {
+		return
+	}
+
+	tv.drag = nil
+
+	tv.showDropMarker(nil)
+
+	win.ReleaseCapture()
+}
+
+func (tv *TreeView) moveAt(x, y int32) *TreeItemMove {
+	hti := win.TVHITTESTINFO{Pt: win.POINT{X: x, Y: y}}
+	hItem := win.HTREEITEM(tv.SendMessage(win.TVM_HITTEST, 0, uintptr(unsafe.Pointer(&hti))))
+	if hItem == 0 {
+		return nil
+	}
+
+	target := tv.handle2Item[hItem]
+	if target == nil {
+		return nil
+	}
+
+	rc := win.RECT{Left: int32(hItem)}
+	if 0 == tv.SendMessage(win.TVM_GETITEMRECT, 0, uintptr(unsafe.Pointer(&rc))) {
+		return nil
+	}
+
+	// The upper and lower quarters of an item insert before or after it,
+	// the rest drops onto it.
+	move := &TreeItemMove{Item: tv.drag.item, Target: target, Position: TreeDropOnto}
+	if quarter := (rc.Bottom - rc.Top) / 4; y < rc.Top+quarter {
+		move.Position = TreeDropBefore
+	} else if y >= rc.Bottom-quarter {
+		move.Position = TreeDropAfter
+	}
+
+	for item := target; item != nil; item = item.Parent() {
+		if item == move.Item {
+			return nil
+		}
+	}
+
+	if tv.itemMoveValidator != nil && !tv.itemMoveValidator(move) {
+		return nil
+	}
+
+	return move
+}
+
+func (tv *TreeView) showDropMarker(move *TreeItemMove) {
+	var hDropHilite, hInsertMark win.HTREEITEM
+	var after uintptr
+
+	if move != nil {
+		hItem := tv.item2Info[move.Target].handle
+
+		switch move.Position {
+		case TreeDropOnto:
+			hDropHilite = hItem
+
+		case TreeDropAfter:
+			after = 1
+			fallthrough
+
+		default:
+			hInsertMark = hItem
+		}
+	}
+
+	tv.SendMessage(win.TVM_SELECTITEM, tvgnDropHilite, uintptr(hDropHilite))
+	tv.SendMessage(win.TVM_SETINSERTMARK, after, uintptr(hInsertMark))
+}
+
+func (tv *TreeView) handleDragMessage(msg uint32, wParam, lParam uintptr) bool {
+	if tv.drag == nil {
+		return false
+	}
+
+	switch msg {
+	case win.WM_MOUSEMOVE:
+		move := tv.moveAt(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam))
+		tv.drag.move = move
+
+		tv.showDropMarker(move)
+
+		if move == nil {
+			win.SetCursor(CursorNo().handle())
+		} else {
+			win.SetCursor(CursorArrow().handle())
+		}
+
+		return true
+
+	case win.WM_LBUTTONUP:
+		move := tv.moveAt(win.GET_X_LPARAM(lParam), win.GET_Y_LPARAM(lParam))
+
+		tv.cancelDrag()
+
+		if move != nil {
+			tv.itemMovedPublisher.Publish(move)
+		}
+
+		return true
+
+	case win.WM_KEYDOWN:
+		if Key(wParam) == KeyEscape {
+			tv.cancelDrag()
+
+			return true
+		}
+
+	case win.WM_CAPTURECHANGED:
+		// Some other window took the capture, so there is nothing to release.
+		tv.drag = nil
+
+		tv.showDropMarker(nil)
+	}
+
+	return false
+}
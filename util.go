@@ -324,6 +324,18 @@ func walkDescendants(window Window, f func(w Window) bool) {
 			children = append(children, w.edit.AsWidgetBase())
 		}
 
+	case *StatusBar:
+		for _, item := range w.items.items {
+			if item.widget != nil {
+				children = append(children, item.widget.AsWidgetBase())
+			}
+		}
+
+	case *ToolBar:
+		for _, tbw := range w.widgets {
+			children = append(children, tbw.widget.AsWidgetBase())
+		}
+
 	case *TabWidget:
 		for _, p := range w.Pages().items {
 			children = append(children, p.AsWidgetBase())
@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ErrInvalidType = errors.New("invalid type")
+	ErrInvalidType   = errors.New("invalid type")
+	ErrSyncQueueFull = errors.New("synchronize queue full")
 )
 
 func LogErrors() bool {
@@ -24,6 +24,9 @@ func init() {
 	})
 }
 
+// WebView hosts the MSHTML (Internet Explorer) WebBrowser control. The Edge
+// WebView2 runtime is not supported, as it requires a native loader that walk
+// does not ship.
 type WebView struct {
 	WidgetBase
 	clientSite                               webViewIOleClientSite // IMPORTANT: Must remain first member after WidgetBase
@@ -63,6 +66,13 @@ type WebView struct {
 	statusTextChangedPublisher               EventPublisher
 	documentTitle                            string
 	documentTitleChangedPublisher            EventPublisher
+	navigationCompletedPublisher             WebViewNavigationCompletedEventPublisher
+	postMessageReceivedPublisher             WebViewPostMessageReceivedEventPublisher
+	navigationFailed                         bool
+	pendingHTML                              string
+	hasPendingHTML                           bool
+	awaitingHTMLDocumentCompleted            bool
+	external                                 webViewExternal
 }
 
 func NewWebView(parent Container) (*WebView, error) {
@@ -99,6 +109,12 @@ func NewWebView(parent Container) (*WebView, error) {
 		shortcutsEnabled:         false,
 		nativeContextMenuEnabled: false,
 	}
+	wv.external = webViewExternal{
+		IDispatch: win.IDispatch{
+			LpVtbl: webViewExternalVtbl,
+		},
+		webView: wv,
+	}
 
 	if err := InitWidget(
 		wv,
@@ -238,6 +254,11 @@ func (wv *WebView) URL() (url string, err error) {
 }
 
 func (wv *WebView) SetURL(url string) error {
+	return wv.Navigate(url)
+}
+
+// Navigate makes the WebView show the document at url.
+func (wv *WebView) Navigate(url string) error {
 	return wv.withWebBrowser2(func(webBrowser2 *win.IWebBrowser2) error {
 		urlBstr := win.StringToVariantBSTR(url)
 		flags := win.IntToVariantI4(0)
@@ -305,6 +326,13 @@ func (wv *WebView) NavigatedError() *WebViewNavigatedErrorEvent {
 	return wv.navigatedErrorPublisher.Event()
 }
 
+// NavigationCompleted returns the event that is published when the top level
+// document finished loading, including documents shown by LoadHTML, or
+// failed to load.
+func (wv *WebView) NavigationCompleted() *WebViewNavigationCompletedEvent {
+	return wv.navigationCompletedPublisher.Event()
+}
+
 func (wv *WebView) NewWindow() *WebViewNewWindowEvent {
 	return wv.newWindowPublisher.Event()
 }
@@ -411,6 +439,37 @@ func (wv *WebView) Refresh() error {
 	})
 }
 
+// isTopLevelBrowser returns if pDisp, as passed to DWebBrowserEvents2, refers
+// to the top level browser, not to a frame.
+func (wv *WebView) isTopLevelBrowser(pDisp *win.IDispatch) bool {
+	return wv.browserObject != nil && sameCOMObject(unsafe.Pointer(pDisp), unsafe.Pointer(wv.browserObject))
+}
+
+func (wv *WebView) onTopLevelDocumentCompleted(url string) {
+	if wv.hasPendingHTML {
+		// The blank document navigated to by LoadHTML completed. We load the
+		// html into it and wait for that to complete, too.
+		if err := wv.loadPendingHTML(); err != nil {
+			wv.navigationCompletedPublisher.Publish(&WebViewNavigationCompletedEventData{url: url, succeeded: false})
+			return
+		}
+
+		wv.awaitingHTMLDocumentCompleted = true
+		return
+	}
+
+	wv.installWebMessageScript()
+
+	if wv.awaitingHTMLDocumentCompleted && url == "about:blank" {
+		// The html loaded by LoadHTML has been parsed.
+		wv.awaitingHTMLDocumentCompleted = false
+		wv.navigationCompletedPublisher.Publish(&WebViewNavigationCompletedEventData{url: url, succeeded: true})
+		return
+	}
+
+	wv.navigationCompletedPublisher.Publish(&WebViewNavigationCompletedEventData{url: url, succeeded: !wv.navigationFailed})
+}
+
 func (wv *WebView) withWebBrowser2(f func(webBrowser2 *win.IWebBrowser2) error) error {
 	var webBrowser2Ptr unsafe.Pointer
 	if hr := wv.browserObject.QueryInterface(&win.IID_IWebBrowser2, &webBrowser2Ptr); win.FAILED(hr) {
@@ -153,6 +153,10 @@ func webView_DWebBrowserEvents2_Invoke(
 			headers:         (*rgvargPtr)[1].MustPVariant(),
 			cancel:          (*rgvargPtr)[0].MustPBool(),
 		}
+		if wv.isTopLevelBrowser(eventData.pDisp) {
+			wv.navigationFailed = false
+			wv.awaitingHTMLDocumentCompleted = false
+		}
 		wv.navigatingPublisher.Publish(eventData)
 
 	case win.DISPID_NAVIGATECOMPLETE2:
@@ -193,6 +197,10 @@ func webView_DWebBrowserEvents2_Invoke(
 
 		wv.documentCompletedPublisher.Publish(urlStr)
 
+		if wv.isTopLevelBrowser((*rgvargPtr)[1].MustPDispatch()) {
+			wv.onTopLevelDocumentCompleted(urlStr)
+		}
+
 	case win.DISPID_NAVIGATEERROR:
 		rgvargPtr := (*[5]win.VARIANTARG)(unsafe.Pointer(pDispParams.Rgvarg))
 		eventData := &WebViewNavigatedErrorEventData{
@@ -202,6 +210,9 @@ func webView_DWebBrowserEvents2_Invoke(
 			statusCode:      (*rgvargPtr)[1].MustPVariant(),
 			cancel:          (*rgvargPtr)[0].MustPBool(),
 		}
+		if wv.isTopLevelBrowser(eventData.pDisp) {
+			wv.navigationFailed = true
+		}
 		wv.navigatedErrorPublisher.Publish(eventData)
 
 	case win.DISPID_NEWWINDOW3:
@@ -7,6 +7,7 @@
 package walk
 
 import (
+	"encoding/json"
 	"unsafe"
 )
 
@@ -391,3 +392,110 @@ func (p *WebViewWindowClosingEventPublisher) Publish(eventData *WebViewWindowClo
 		}
 	}
 }
+
+type WebViewNavigationCompletedEventData struct {
+	url       string
+	succeeded bool
+}
+
+func (eventData *WebViewNavigationCompletedEventData) Url() string {
+	return eventData.url
+}
+
+func (eventData *WebViewNavigationCompletedEventData) Succeeded() bool {
+	return eventData.succeeded
+}
+
+type WebViewNavigationCompletedEventHandler func(eventData *WebViewNavigationCompletedEventData)
+
+type WebViewNavigationCompletedEvent struct {
+	handlers []WebViewNavigationCompletedEventHandler
+}
+
+func (e *WebViewNavigationCompletedEvent) Attach(handler WebViewNavigationCompletedEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *WebViewNavigationCompletedEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type WebViewNavigationCompletedEventPublisher struct {
+	event WebViewNavigationCompletedEvent
+}
+
+func (p *WebViewNavigationCompletedEventPublisher) Event() *WebViewNavigationCompletedEvent {
+	return &p.event
+}
+
+func (p *WebViewNavigationCompletedEventPublisher) Publish(eventData *WebViewNavigationCompletedEventData) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(eventData)
+		}
+	}
+}
+
+type WebViewPostMessageReceivedEventData struct {
+	json string
+}
+
+// JSON returns the message as posted by the page, usually JSON encoded.
+func (eventData *WebViewPostMessageReceivedEventData) JSON() string {
+	return eventData.json
+}
+
+// Decode decodes the JSON encoded message into v, like json.Unmarshal does.
+func (eventData *WebViewPostMessageReceivedEventData) Decode(v interface{}) error {
+	if err := json.Unmarshal([]byte(eventData.json), v); err != nil {
+		return wrapError(err)
+	}
+
+	return nil
+}
+
+type WebViewPostMessageReceivedEventHandler func(eventData *WebViewPostMessageReceivedEventData)
+
+type WebViewPostMessageReceivedEvent struct {
+	handlers []WebViewPostMessageReceivedEventHandler
+}
+
+func (e *WebViewPostMessageReceivedEvent) Attach(handler WebViewPostMessageReceivedEventHandler) int {
+	for i, h := range e.handlers {
+		if h == nil {
+			e.handlers[i] = handler
+			return i
+		}
+	}
+
+	e.handlers = append(e.handlers, handler)
+	return len(e.handlers) - 1
+}
+
+func (e *WebViewPostMessageReceivedEvent) Detach(handle int) {
+	e.handlers[handle] = nil
+}
+
+type WebViewPostMessageReceivedEventPublisher struct {
+	event WebViewPostMessageReceivedEvent
+}
+
+func (p *WebViewPostMessageReceivedEventPublisher) Event() *WebViewPostMessageReceivedEvent {
+	return &p.event
+}
+
+func (p *WebViewPostMessageReceivedEventPublisher) Publish(eventData *WebViewPostMessageReceivedEventData) {
+	for _, handler := range p.event.handlers {
+		if handler != nil {
+			handler(eventData)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+import (
+	"github.com/lxn/win"
+)
+
+// WebViewFunc is a Go function that can be called from JavaScript, see
+// WebView.Bind.
+type WebViewFunc func(args []interface{}) (interface{}, error)
+
+var webViewExternalVtbl *win.IDispatchVtbl
+
+func init() {
+	AppendToWalkInit(func() {
+		webViewExternalVtbl = &win.IDispatchVtbl{
+			QueryInterface:   syscall.NewCallback(webView_External_QueryInterface),
+			AddRef:           syscall.NewCallback(webView_External_AddRef),
+			Release:          syscall.NewCallback(webView_External_Release),
+			GetTypeInfoCount: syscall.NewCallback(webView_External_GetTypeInfoCount),
+			GetTypeInfo:      syscall.NewCallback(webView_External_GetTypeInfo),
+			GetIDsOfNames:    syscall.NewCallback(webView_External_GetIDsOfNames),
+			Invoke:           syscall.NewCallback(webView_External_Invoke),
+		}
+	})
+}
+
+// webViewPostMessageDispID is the DISPID of window.external.postMessage,
+// which is not subject to Bind.
+const webViewPostMessageDispID = 1 << 30
+
+// webViewExternal is the window.external object of the documents shown by a
+// WebView. It exposes the functions bound with WebView.Bind.
+type webViewExternal struct {
+	win.IDispatch
+	webView     *WebView
+	name2DispID map[string]win.DISPID
+	dispID2Func map[win.DISPID]WebViewFunc
+	lastDispID  win.DISPID
+}
+
+func webView_External_QueryInterface(external *webViewExternal, riid win.REFIID, ppvObject *unsafe.Pointer) uintptr {
+	if win.EqualREFIID(riid, &win.IID_IUnknown) || win.EqualREFIID(riid, &win.IID_IDispatch) {
+		*ppvObject = unsafe.Pointer(external)
+	} else {
+		*ppvObject = nil
+		return win.E_NOINTERFACE
+	}
+
+	return win.S_OK
+}
+
+func webView_External_AddRef(external *webViewExternal) uintptr {
+	return 1
+}
+
+func webView_External_Release(external *webViewExternal) uintptr {
+	return 1
+}
+
+func webView_External_GetTypeInfoCount(external *webViewExternal, pctinfo *uint32) uintptr {
+	*pctinfo = 0
+
+	return win.S_OK
+}
+
+func webView_External_GetTypeInfo(external *webViewExternal, iTInfo, lcid uintptr, ppTInfo *unsafe.Pointer) uintptr {
+	*ppTInfo = nil
+
+	return win.E_NOTIMPL
+}
+
+func webView_External_GetIDsOfNames(external *webViewExternal, riid win.REFIID, rgszNames **uint16, cNames, lcid uintptr, rgDispId *win.DISPID) uintptr {
+	names := (*[1 << 16]*uint16)(unsafe.Pointer(rgszNames))[:cNames:cNames]
+	dispIDs := (*[1 << 16]win.DISPID)(unsafe.Pointer(rgDispId))[:cNames:cNames]
+
+	hr := uintptr(win.S_OK)
+
+	for i, name := range names {
+		name := win.UTF16PtrToString(name)
+
+		if i == 0 && name == "postMessage" {
+			dispIDs[i] = webViewPostMessageDispID
+		} else if dispID, ok := external.name2DispID[name]; ok && i == 0 {
+			dispIDs[i] = dispID
+		} else {
+			// Named arguments are not supported.
+			dispIDs[i] = dispidUnknown
+			hr = dispEUnknownName
+		}
+	}
+
+	return hr
+}
+
+func webView_External_Invoke(
+	external *webViewExternal,
+	dispIdMember uintptr,
+	riid uintptr,
+	lcid uintptr,
+	wFlags uintptr,
+	pDispParams *win.DISPPARAMS,
+	pVarResult *win.VARIANT,
+	pExcepInfo *excepInfo,
+	puArgErr *uint32) uintptr {
+
+	dispID := win.DISPID(int32(dispIdMember))
+
+	f, ok := external.dispID2Func[dispID]
+	if !ok && dispID != webViewPostMessageDispID {
+		return win.DISP_E_MEMBERNOTFOUND
+	}
+
+	if uint16(wFlags)&dispatchMethod == 0 {
+		return win.DISP_E_MEMBERNOTFOUND
+	}
+
+	if pDispParams.CNamedArgs > 0 {
+		return dispEBadParamCount
+	}
+
+	args := make([]interface{}, pDispParams.CArgs)
+	if len(args) > 0 {
+		vargs := (*[1 << 16]win.VARIANTARG)(unsafe.Pointer(pDispParams.Rgvarg))[:len(args):len(args)]
+
+		// The arguments are passed in reverse order.
+		for i := range args {
+			arg, err := valueFromVariant(&vargs[len(args)-1-i].VARIANT)
+			if err != nil {
+				if puArgErr != nil {
+					*puArgErr = uint32(len(args) - 1 - i)
+				}
+				return dispETypeMismatch
+			}
+
+			args[i] = arg
+		}
+	}
+
+	if dispID == webViewPostMessageDispID {
+		if len(args) != 1 {
+			return dispEBadParamCount
+		}
+
+		message, ok := args[0].(string)
+		if !ok {
+			if puArgErr != nil {
+				*puArgErr = 0
+			}
+			return dispETypeMismatch
+		}
+
+		external.webView.receivePostMessage(message)
+
+		return win.S_OK
+	}
+
+	result, err := f(args)
+	if err == nil && pVarResult != nil {
+		err = setVariantFromValue(pVarResult, result)
+	}
+
+	if err != nil {
+		if pExcepInfo != nil {
+			*pExcepInfo = excepInfo{
+				bstrSource:      win.StringToBSTR("Walk.WebView"),
+				bstrDescription: win.StringToBSTR(err.Error()),
+				scode:           win.E_FAIL,
+			}
+		}
+
+		return dispEException
+	}
+
+	return win.S_OK
+}
+
+// Bind makes f callable from the JavaScript of the documents shown by the
+// WebView, as window.external.name(...). f is called on the UI thread.
+//
+// The arguments are passed to f as string, float64, bool or nil, like
+// encoding/json does for values of type interface{}. Pass objects and arrays
+// JSON encoded. The result of f is returned to JavaScript as string, number
+// or bool, other values are returned JSON encoded. If f returns an error,
+// an exception with its message is thrown in JavaScript.
+func (wv *WebView) Bind(name string, f WebViewFunc) error {
+	if name == "" {
+		return newError("name must not be empty")
+	}
+	if name == "postMessage" {
+		return newError("postMessage is reserved for PostMessageReceived")
+	}
+	if f == nil {
+		return newError("f must not be nil")
+	}
+
+	ext := &wv.external
+
+	if ext.name2DispID == nil {
+		ext.name2DispID = make(map[string]win.DISPID)
+		ext.dispID2Func = make(map[win.DISPID]WebViewFunc)
+	}
+
+	dispID, ok := ext.name2DispID[name]
+	if !ok {
+		ext.lastDispID++
+		dispID = ext.lastDispID
+		ext.name2DispID[name] = dispID
+	}
+
+	ext.dispID2Func[dispID] = f
+
+	return nil
+}
+
+// Unbind removes the function bound with Bind as name.
+func (wv *WebView) Unbind(name string) {
+	ext := &wv.external
+
+	if dispID, ok := ext.name2DispID[name]; ok {
+		delete(ext.name2DispID, name)
+		delete(ext.dispID2Func, dispID)
+	}
+}
@@ -135,9 +135,17 @@ func webView_IDocHostUIHandler_GetDropTarget(docHostUIHandler *webViewIDocHostUI
 }
 
 func webView_IDocHostUIHandler_GetExternal(docHostUIHandler *webViewIDocHostUIHandler, ppDispatch *uintptr) uintptr {
-	*ppDispatch = 0
+	var webViewInPlaceSite webViewIOleInPlaceSite
+	var iOleClientSite win.IOleClientSite
+	var wb WidgetBase
+	webView := (*WebView)(unsafe.Pointer(uintptr(unsafe.Pointer(docHostUIHandler)) -
+		uintptr(unsafe.Sizeof(webViewInPlaceSite)) -
+		uintptr(unsafe.Sizeof(iOleClientSite)) -
+		uintptr(unsafe.Sizeof(wb))))
 
-	return win.S_FALSE
+	*ppDispatch = uintptr(unsafe.Pointer(&webView.external))
+
+	return win.S_OK
 }
 
 func webView_IDocHostUIHandler_TranslateUrl(docHostUIHandler *webViewIDocHostUIHandler, dwTranslate uint32, pchURLIn *uint16, ppchURLOut **uint16) uintptr {
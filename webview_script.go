@@ -0,0 +1,461 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"syscall"
+	"unsafe"
+)
+
+import (
+	"github.com/lxn/win"
+)
+
+var iidIPersistStreamInit = win.IID{Data1: 0x7FD52380, Data2: 0x4E07, Data3: 0x101B, Data4: [8]byte{0xAE, 0x2D, 0x08, 0x00, 0x2B, 0x2E, 0xC7, 0x13}}
+
+type iUnknownVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+}
+
+// iUnknown allows calling the IUnknown methods of any COM object.
+type iUnknown struct {
+	lpVtbl *iUnknownVtbl
+}
+
+func (obj *iUnknown) QueryInterface(riid *win.IID, ppvObject *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.QueryInterface, 3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(riid)),
+		uintptr(unsafe.Pointer(ppvObject)))
+
+	return win.HRESULT(ret)
+}
+
+func (obj *iUnknown) Release() uint32 {
+	ret, _, _ := syscall.Syscall(obj.lpVtbl.Release, 1,
+		uintptr(unsafe.Pointer(obj)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+// sameCOMObject returns if a and b are interfaces of the same COM object.
+func sameCOMObject(a, b unsafe.Pointer) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	var unkA, unkB unsafe.Pointer
+	if hr := (*iUnknown)(a).QueryInterface(&win.IID_IUnknown, &unkA); win.FAILED(hr) {
+		return false
+	}
+	defer (*iUnknown)(unkA).Release()
+
+	if hr := (*iUnknown)(b).QueryInterface(&win.IID_IUnknown, &unkB); win.FAILED(hr) {
+		return false
+	}
+	defer (*iUnknown)(unkB).Release()
+
+	return unkA == unkB
+}
+
+type iPersistStreamInitVtbl struct {
+	iUnknownVtbl
+	GetClassID uintptr
+	IsDirty    uintptr
+	Load       uintptr
+	Save       uintptr
+	GetSizeMax uintptr
+	InitNew    uintptr
+}
+
+type iPersistStreamInit struct {
+	lpVtbl *iPersistStreamInitVtbl
+}
+
+func (psi *iPersistStreamInit) InitNew() win.HRESULT {
+	ret, _, _ := syscall.Syscall(psi.lpVtbl.InitNew, 1,
+		uintptr(unsafe.Pointer(psi)),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func (psi *iPersistStreamInit) Load(stream unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(psi.lpVtbl.Load, 2,
+		uintptr(unsafe.Pointer(psi)),
+		uintptr(stream),
+		0)
+
+	return win.HRESULT(ret)
+}
+
+// variantValue gives access to the value of a VARIANT, which is at the same
+// offset on all architectures.
+type variantValue struct {
+	vt  win.VARTYPE
+	_   [6]byte
+	val uint64
+}
+
+// valueFromVariant converts v to a string, float64, bool or nil, like
+// encoding/json does for values of type interface{}.
+func valueFromVariant(v *win.VARIANT) (interface{}, error) {
+	vv := (*variantValue)(unsafe.Pointer(v))
+	p := unsafe.Pointer(&vv.val)
+
+	switch vv.vt {
+	case win.VT_EMPTY, win.VT_NULL:
+		return nil, nil
+
+	case win.VT_BSTR:
+		if bstr := *(**uint16)(p); bstr != nil {
+			return win.BSTRToString(bstr), nil
+		}
+		return "", nil
+
+	case win.VT_BOOL:
+		return *(*win.VARIANT_BOOL)(p) != win.VARIANT_FALSE, nil
+
+	case win.VT_I1:
+		return float64(*(*int8)(p)), nil
+
+	case win.VT_UI1:
+		return float64(*(*uint8)(p)), nil
+
+	case win.VT_I2:
+		return float64(*(*int16)(p)), nil
+
+	case win.VT_UI2:
+		return float64(*(*uint16)(p)), nil
+
+	case win.VT_I4, win.VT_INT:
+		return float64(*(*int32)(p)), nil
+
+	case win.VT_UI4, win.VT_UINT:
+		return float64(*(*uint32)(p)), nil
+
+	case win.VT_I8:
+		return float64(*(*int64)(p)), nil
+
+	case win.VT_UI8:
+		return float64(vv.val), nil
+
+	case win.VT_R4:
+		return float64(*(*float32)(p)), nil
+
+	case win.VT_R8:
+		return math.Float64frombits(vv.val), nil
+
+	case win.VT_BYREF | win.VT_VARIANT:
+		return valueFromVariant(*(**win.VARIANT)(p))
+	}
+
+	return nil, newError(fmt.Sprintf("unsupported VARIANT type %d", vv.vt))
+}
+
+// setVariantFromValue stores value in v, which must be empty. Numbers become
+// VT_I4 or VT_R8, values that are no string, number or bool are stored as
+// JSON encoded string.
+func setVariantFromValue(v *win.VARIANT, value interface{}) error {
+	vv := (*variantValue)(unsafe.Pointer(v))
+	p := unsafe.Pointer(&vv.val)
+
+	setNumber := func(f float64) {
+		if i := int32(f); float64(i) == f {
+			vv.vt = win.VT_I4
+			*(*int32)(p) = i
+		} else {
+			vv.vt = win.VT_R8
+			vv.val = math.Float64bits(f)
+		}
+	}
+
+	switch val := value.(type) {
+	case nil:
+		vv.vt = win.VT_EMPTY
+
+	case string:
+		vv.vt = win.VT_BSTR
+		*(**uint16)(p) = win.StringToBSTR(val)
+
+	case bool:
+		vv.vt = win.VT_BOOL
+		if val {
+			*(*win.VARIANT_BOOL)(p) = win.VARIANT_TRUE
+		} else {
+			*(*win.VARIANT_BOOL)(p) = win.VARIANT_FALSE
+		}
+
+	case int:
+		setNumber(float64(val))
+
+	case int8:
+		setNumber(float64(val))
+
+	case int16:
+		setNumber(float64(val))
+
+	case int32:
+		setNumber(float64(val))
+
+	case int64:
+		setNumber(float64(val))
+
+	case uint:
+		setNumber(float64(val))
+
+	case uint8:
+		setNumber(float64(val))
+
+	case uint16:
+		setNumber(float64(val))
+
+	case uint32:
+		setNumber(float64(val))
+
+	case uint64:
+		setNumber(float64(val))
+
+	case float32:
+		setNumber(float64(val))
+
+	case float64:
+		setNumber(val)
+
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return wrapError(err)
+		}
+
+		vv.vt = win.VT_BSTR
+		*(**uint16)(p) = win.StringToBSTR(string(data))
+	}
+
+	return nil
+}
+
+// dispatchInvoke invokes the member name of disp with string arguments.
+// The caller must clear the returned VARIANT with variantClear.
+func dispatchInvoke(disp *win.IDispatch, name string, flags uint16, args ...string) (*win.VARIANT, error) {
+	name16, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	var dispID win.DISPID
+	if ret, _, _ := syscall.Syscall6(disp.LpVtbl.GetIDsOfNames, 6,
+		uintptr(unsafe.Pointer(disp)),
+		uintptr(unsafe.Pointer(&win.IID{})),
+		uintptr(unsafe.Pointer(&name16)),
+		1,
+		uintptr(win.LOCALE_USER_DEFAULT),
+		uintptr(unsafe.Pointer(&dispID))); win.FAILED(win.HRESULT(ret)) {
+		return nil, errorFromHRESULT(fmt.Sprintf("IDispatch.GetIDsOfNames(%s)", name), win.HRESULT(ret))
+	}
+
+	// The arguments are passed in reverse order.
+	vargs := make([]win.VARIANTARG, len(args))
+	for i, arg := range args {
+		setVariantFromValue(&vargs[len(args)-1-i].VARIANT, arg)
+	}
+	defer func() {
+		for i := range vargs {
+			variantClear(&vargs[i].VARIANT)
+		}
+	}()
+
+	var params win.DISPPARAMS
+	if len(vargs) > 0 {
+		params.Rgvarg = &vargs[0]
+		params.CArgs = int32(len(vargs))
+	}
+
+	var result win.VARIANT
+	var ei excepInfo
+	var argErr uint32
+
+	ret, _, _ := syscall.Syscall9(disp.LpVtbl.Invoke, 9,
+		uintptr(unsafe.Pointer(disp)),
+		uintptr(dispID),
+		uintptr(unsafe.Pointer(&win.IID{})),
+		uintptr(win.LOCALE_USER_DEFAULT),
+		uintptr(flags),
+		uintptr(unsafe.Pointer(&params)),
+		uintptr(unsafe.Pointer(&result)),
+		uintptr(unsafe.Pointer(&ei)),
+		uintptr(unsafe.Pointer(&argErr)))
+
+	if hr := win.HRESULT(ret); win.FAILED(hr) {
+		if uint32(hr) == dispEException {
+			return nil, errorFromExcepInfo(&ei)
+		}
+
+		return nil, errorFromHRESULT(fmt.Sprintf("IDispatch.Invoke(%s)", name), hr)
+	}
+
+	return &result, nil
+}
+
+func errorFromExcepInfo(ei *excepInfo) error {
+	var description string
+	if ei.bstrDescription != nil {
+		description = win.BSTRToString(ei.bstrDescription)
+	}
+
+	for _, bstr := range []*uint16{ei.bstrSource, ei.bstrDescription, ei.bstrHelpFile} {
+		if bstr != nil {
+			win.SysFreeString(bstr)
+		}
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("exception 0x%x", uint32(ei.scode))
+	}
+
+	return newError("script error: " + description)
+}
+
+// withDocument calls f with the IDispatch of the current document, if any.
+func (wv *WebView) withDocument(f func(document *win.IDispatch) error) error {
+	return wv.withWebBrowser2(func(webBrowser2 *win.IWebBrowser2) error {
+		var document *win.IDispatch
+		if ret, _, _ := syscall.Syscall(webBrowser2.LpVtbl.Get_Document, 2,
+			uintptr(unsafe.Pointer(webBrowser2)),
+			uintptr(unsafe.Pointer(&document)),
+			0); win.FAILED(win.HRESULT(ret)) {
+			return errorFromHRESULT("IWebBrowser2.Get_Document", win.HRESULT(ret))
+		}
+		if document == nil {
+			return newError("no document loaded")
+		}
+		defer (*iUnknown)(unsafe.Pointer(document)).Release()
+
+		return f(document)
+	})
+}
+
+// ExecuteScript evaluates script in the global scope of the current document
+// and returns the value of its last expression.
+//
+// Like with the WebView2 control of Microsoft Edge, the value is returned
+// JSON encoded, if the document mode provides JSON, otherwise as converted
+// to a string by JavaScript. Undefined values are returned as "null".
+func (wv *WebView) ExecuteScript(script string) (string, error) {
+	literal, err := json.Marshal(script)
+	if err != nil {
+		return "", wrapError(err)
+	}
+
+	wrapper := fmt.Sprintf(`(function() {
+	var r = (0, eval)(%s);
+	if (r === undefined) {
+		return "null";
+	}
+	return window.JSON ? JSON.stringify(r) : String(r);
+})()`, literal)
+
+	var value string
+
+	err = wv.withDocument(func(document *win.IDispatch) error {
+		windowVar, err := dispatchInvoke(document, "parentWindow", dispatchPropertyGet)
+		if err != nil {
+			return err
+		}
+		defer variantClear(windowVar)
+
+		window, err := windowVar.PDispatch()
+		if err != nil || window == nil {
+			return newError("no window")
+		}
+
+		result, err := dispatchInvoke(window, "eval", dispatchMethod, wrapper)
+		if err != nil {
+			return err
+		}
+		defer variantClear(result)
+
+		v, err := valueFromVariant(result)
+		if err != nil {
+			return err
+		}
+
+		if s, ok := v.(string); ok {
+			value = s
+		} else {
+			value = "null"
+		}
+
+		return nil
+	})
+
+	return value, err
+}
+
+// LoadHTML shows html in the WebView, as if it was navigated to a document
+// with that content. Relative URLs in html are resolved against about:blank.
+func (wv *WebView) LoadHTML(html string) error {
+	wv.pendingHTML = html
+	wv.hasPendingHTML = true
+
+	if err := wv.Navigate("about:blank"); err != nil {
+		wv.hasPendingHTML = false
+		return err
+	}
+
+	return nil
+}
+
+// loadPendingHTML loads the html passed to LoadHTML into the blank document
+// navigated to for that purpose.
+func (wv *WebView) loadPendingHTML() error {
+	html := wv.pendingHTML
+	wv.pendingHTML = ""
+	wv.hasPendingHTML = false
+
+	// The byte order mark tells MSHTML the encoding.
+	data := append([]byte("\xEF\xBB\xBF"), html...)
+
+	hMem, err := hGlobalFromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	var stream unsafe.Pointer
+	if hr := createStreamOnHGlobal(hMem, true, &stream); win.FAILED(hr) {
+		win.GlobalFree(hMem)
+		return errorFromHRESULT("CreateStreamOnHGlobal", hr)
+	}
+	defer (*iUnknown)(stream).Release()
+
+	return wv.withDocument(func(document *win.IDispatch) error {
+		var psiPtr unsafe.Pointer
+		if hr := (*iUnknown)(unsafe.Pointer(document)).QueryInterface(&iidIPersistStreamInit, &psiPtr); win.FAILED(hr) {
+			return errorFromHRESULT("IDispatch.QueryInterface(IID_IPersistStreamInit)", hr)
+		}
+		psi := (*iPersistStreamInit)(psiPtr)
+		defer (*iUnknown)(psiPtr).Release()
+
+		if hr := psi.InitNew(); win.FAILED(hr) {
+			return errorFromHRESULT("IPersistStreamInit.InitNew", hr)
+		}
+
+		if hr := psi.Load(stream); win.FAILED(hr) {
+			return errorFromHRESULT("IPersistStreamInit.Load", hr)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"encoding/json"
+)
+
+// webMessageScript provides window.chrome.webview.postMessage and the
+// "message" event of window.chrome.webview, like the WebView2 control of
+// Microsoft Edge does, so pages can use the same code for both.
+const webMessageScript = `(function() {
+	if (window.chrome && window.chrome.webview) {
+		return;
+	}
+
+	var listeners = [];
+
+	window.chrome = window.chrome || {};
+	window.chrome.webview = {
+		postMessage: function(message) {
+			window.external.postMessage(window.JSON ? JSON.stringify(message) : String(message));
+		},
+		addEventListener: function(type, listener) {
+			if (type === "message") {
+				listeners.push(listener);
+			}
+		},
+		removeEventListener: function(type, listener) {
+			for (var i = 0; i < listeners.length; i++) {
+				if (type === "message" && listeners[i] === listener) {
+					listeners.splice(i, 1);
+					return;
+				}
+			}
+		},
+		walkDispatchMessage: function(data) {
+			var event = {data: data};
+			var ls = listeners.slice(0);
+			for (var i = 0; i < ls.length; i++) {
+				ls[i](event);
+			}
+		}
+	};
+})()`
+
+func (wv *WebView) installWebMessageScript() {
+	// Documents without script support fail here, which is fine.
+	wv.ExecuteScript(webMessageScript)
+}
+
+func (wv *WebView) receivePostMessage(message string) {
+	// Like with WebView2, the page does not wait for the handlers.
+	wv.Synchronize(func() {
+		wv.postMessageReceivedPublisher.Publish(&WebViewPostMessageReceivedEventData{json: message})
+	})
+}
+
+// PostMessageReceived returns the event that is published when the page
+// posts a message to the application. Pages post messages by calling
+// window.chrome.webview.postMessage(value), which is available once a
+// document completed loading, or window.external.postMessage(jsonString).
+func (wv *WebView) PostMessageReceived() *WebViewPostMessageReceivedEvent {
+	return wv.postMessageReceivedPublisher.Event()
+}
+
+// PostWebMessage sends message, which must be valid JSON, to the current
+// document. The page receives it via the "message" event of
+// window.chrome.webview, with the decoded value as the data property of the
+// event.
+func (wv *WebView) PostWebMessage(message string) error {
+	if !json.Valid([]byte(message)) {
+		return newError("message is not valid JSON")
+	}
+
+	wv.installWebMessageScript()
+
+	_, err := wv.ExecuteScript("window.chrome.webview.walkDispatchMessage(" + message + ")")
+
+	return err
+}
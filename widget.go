@@ -159,6 +159,10 @@ func (wb *WidgetBase) Dispose() {
 		return
 	}
 
+	// Pending layout results may refer to this widget. Removing it from its
+	// parent below requests a fresh layout anyway.
+	wb.group.cancelSynchronizedLayoutOf(wb.hWnd)
+
 	if wb.parent != nil && win.GetParent(wb.hWnd) == wb.parent.Handle() {
 		wb.SetParent(nil)
 	}
@@ -190,9 +194,17 @@ func (wb *WidgetBase) Bounds() Rectangle {
 func (wb *WidgetBase) BoundsPixels() Rectangle {
 	b := wb.WindowBase.BoundsPixels()
 
+	var hwndParent win.HWND
 	if wb.parent != nil {
+		hwndParent = wb.parent.Handle()
+	} else if win.GetWindowLong(wb.hWnd, win.GWL_STYLE)&win.WS_CHILD != 0 {
+		// Embedded into a Widget that is no Container, like a ToolBar.
+		hwndParent = win.GetParent(wb.hWnd)
+	}
+
+	if hwndParent != 0 {
 		p := win.POINT{int32(b.X), int32(b.Y)}
-		if !win.ScreenToClient(wb.parent.Handle(), &p) {
+		if !win.ScreenToClient(hwndParent, &p) {
 			newError("ScreenToClient failed")
 			return Rectangle{}
 		}
@@ -380,6 +392,56 @@ func (wb *WidgetBase) SetParent(parent Container) (err error) {
 	return nil
 }
 
+// embedInto takes the WidgetBase out of the Children of its parent and makes
+// it a child window of hwnd, which belongs to a Widget that is no Container,
+// like a ToolBar. The WidgetBase has no parent afterwards and its bounds are
+// relative to hwnd.
+func (wb *WidgetBase) embedInto(hwnd win.HWND) error {
+	if parent := wb.parent; parent != nil {
+		wb.parent = nil
+		if err := parent.Children().Remove(wb.window.(Widget)); err != nil {
+			wb.parent = parent
+			return err
+		}
+	}
+
+	return wb.setParentWindow(hwnd)
+}
+
+// unembed hides a WidgetBase embedded via embedInto and leaves it without a
+// parent window, like SetParent(nil) does for children of a Container.
+func (wb *WidgetBase) unembed() error {
+	wb.SetVisible(false)
+
+	return wb.setParentWindow(0)
+}
+
+func (wb *WidgetBase) setParentWindow(hwnd win.HWND) error {
+	style := uint32(win.GetWindowLong(wb.hWnd, win.GWL_STYLE))
+	if style == 0 {
+		return lastError("GetWindowLong")
+	}
+
+	if hwnd == 0 {
+		style &^= win.WS_CHILD
+		style |= win.WS_POPUP
+	} else {
+		style |= win.WS_CHILD
+		style &^= win.WS_POPUP
+	}
+
+	win.SetLastError(0)
+	if win.SetWindowLong(wb.hWnd, win.GWL_STYLE, int32(style)) == 0 {
+		return lastError("SetWindowLong")
+	}
+
+	if win.SetParent(wb.hWnd, hwnd) == 0 {
+		return lastError("SetParent")
+	}
+
+	return nil
+}
+
 func (wb *WidgetBase) ForEachAncestor(f func(window Window) bool) {
 	hwnd := win.GetParent(wb.hWnd)
 
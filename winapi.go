@@ -0,0 +1,774 @@
+// Copyright 2019 The Walk Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build windows
+
+package walk
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/lxn/win"
+)
+
+// This file contains bindings for Win32 functions that are not (yet)
+// provided by github.com/lxn/win.
+
+var (
+	libCombase  = windows.NewLazySystemDLL("combase.dll")
+	libComCtl32 = windows.NewLazySystemDLL("comctl32.dll")
+	libComDlg32 = windows.NewLazySystemDLL("comdlg32.dll")
+	libDwmapi   = windows.NewLazySystemDLL("dwmapi.dll")
+	libGdi32    = windows.NewLazySystemDLL("gdi32.dll")
+	libKernel32 = windows.NewLazySystemDLL("kernel32.dll")
+	libOle32    = windows.NewLazySystemDLL("ole32.dll")
+	libOleAut32 = windows.NewLazySystemDLL("oleaut32.dll")
+	libShell32  = windows.NewLazySystemDLL("shell32.dll")
+	libUser32   = windows.NewLazySystemDLL("user32.dll")
+
+	procRoActivateInstance        = libCombase.NewProc("RoActivateInstance")
+	procRoGetActivationFactory    = libCombase.NewProc("RoGetActivationFactory")
+	procRoInitialize              = libCombase.NewProc("RoInitialize")
+	procWindowsCreateString       = libCombase.NewProc("WindowsCreateString")
+	procWindowsDeleteString       = libCombase.NewProc("WindowsDeleteString")
+	procWindowsGetStringRawBuffer = libCombase.NewProc("WindowsGetStringRawBuffer")
+
+	procImageListGetImageCount = libComCtl32.NewProc("ImageList_GetImageCount")
+	procImageListRemove        = libComCtl32.NewProc("ImageList_Remove")
+
+	procPageSetupDlg = libComDlg32.NewProc("PageSetupDlgW")
+
+	procDwmSetWindowAttribute = libDwmapi.NewProc("DwmSetWindowAttribute")
+
+	procArc               = libGdi32.NewProc("Arc")
+	procBeginPath         = libGdi32.NewProc("BeginPath")
+	procCloseFigure       = libGdi32.NewProc("CloseFigure")
+	procEndPath           = libGdi32.NewProc("EndPath")
+	procFillPath          = libGdi32.NewProc("FillPath")
+	procGetClipBox        = libGdi32.NewProc("GetClipBox")
+	procGetWorldTransform = libGdi32.NewProc("GetWorldTransform")
+	procPie               = libGdi32.NewProc("Pie")
+	procPolyBezierTo      = libGdi32.NewProc("PolyBezierTo")
+	procPolygon           = libGdi32.NewProc("Polygon")
+	procSetArcDirection   = libGdi32.NewProc("SetArcDirection")
+	procSetGraphicsMode   = libGdi32.NewProc("SetGraphicsMode")
+	procSetPolyFillMode   = libGdi32.NewProc("SetPolyFillMode")
+	procSetWorldTransform = libGdi32.NewProc("SetWorldTransform")
+	procStrokePath        = libGdi32.NewProc("StrokePath")
+
+	procEnumResourceNames = libKernel32.NewProc("EnumResourceNamesW")
+	procGlobalSize        = libKernel32.NewProc("GlobalSize")
+
+	procCreateStreamOnHGlobal = libOle32.NewProc("CreateStreamOnHGlobal")
+	procDoDragDrop            = libOle32.NewProc("DoDragDrop")
+	procRegisterDragDrop      = libOle32.NewProc("RegisterDragDrop")
+	procReleaseStgMedium      = libOle32.NewProc("ReleaseStgMedium")
+	procRevokeDragDrop        = libOle32.NewProc("RevokeDragDrop")
+
+	procVariantClear = libOleAut32.NewProc("VariantClear")
+
+	procExtractIconEx                           = libShell32.NewProc("ExtractIconExW")
+	procSHCreateStdEnumFmtEtc                   = libShell32.NewProc("SHCreateStdEnumFmtEtc")
+	procSetCurrentProcessExplicitAppUserModelID = libShell32.NewProc("SetCurrentProcessExplicitAppUserModelID")
+
+	procGetWindowDC                = libUser32.NewProc("GetWindowDC")
+	procMonitorFromRect            = libUser32.NewProc("MonitorFromRect")
+	procPostThreadMessage          = libUser32.NewProc("PostThreadMessageW")
+	procRegisterClipboardFormat    = libUser32.NewProc("RegisterClipboardFormatW")
+	procRegisterHotKey             = libUser32.NewProc("RegisterHotKey")
+	procSetLayeredWindowAttributes = libUser32.NewProc("SetLayeredWindowAttributes")
+	procUnregisterHotKey           = libUser32.NewProc("UnregisterHotKey")
+)
+
+const (
+	adCounterClockwise = 1
+	adClockwise        = 2
+)
+
+const (
+	gmCompatible = 1
+	gmAdvanced   = 2
+)
+
+const tvgnDropHilite = 0x0008
+
+const sizeMinimized = 1
+
+const lwaAlpha = 0x00000002
+
+const (
+	modAlt      = 0x0001
+	modControl  = 0x0002
+	modShift    = 0x0004
+	modNoRepeat = 0x4000
+)
+
+const errorHotKeyAlreadyRegistered = 1409
+
+// dropFiles mirrors the Win32 DROPFILES structure.
+type dropFiles struct {
+	pFiles uint32
+	pt     win.POINT
+	fNC    win.BOOL
+	fWide  win.BOOL
+}
+
+const mkAlt = 0x0020
+
+const (
+	dvaspectContent = 1
+	tymedHGlobal    = 1
+	datadirGet      = 1
+)
+
+const (
+	dataSSameFormatEtc         = 0x00040130
+	dragdropSDrop              = 0x00040100
+	dragdropSCancel            = 0x00040101
+	dragdropSUseDefaultCursors = 0x00040102
+	dvEFormatEtc               = 0x80040064
+	oleEAdviseNotSupported     = 0x80040003
+)
+
+// formatEtc mirrors the Win32 FORMATETC structure.
+type formatEtc struct {
+	cfFormat uint16
+	ptd      uintptr
+	dwAspect uint32
+	lindex   int32
+	tymed    uint32
+}
+
+// stgMedium mirrors the Win32 STGMEDIUM structure. Only the hGlobal member
+// of its union is used.
+type stgMedium struct {
+	tymed          uint32
+	hGlobal        win.HGLOBAL
+	pUnkForRelease uintptr
+}
+
+// shDragImage mirrors the Win32 SHDRAGIMAGE structure.
+type shDragImage struct {
+	sizeDragImage win.SIZE
+	ptOffset      win.POINT
+	hbmpDragImage win.HBITMAP
+	crColorKey    win.COLORREF
+}
+
+// hstring is a handle to a Windows Runtime string.
+type hstring uintptr
+
+const roInitSingleThreaded = 0
+
+const rpcEChangedMode = 0x80010106
+
+const (
+	dispatchMethod      = 1
+	dispatchPropertyGet = 2
+)
+
+const dispidUnknown = -1
+
+const (
+	dispEBadParamCount = 0x8002000E
+	dispEException     = 0x80020009
+	dispETypeMismatch  = 0x80020005
+	dispEUnknownName   = 0x80020006
+)
+
+// excepInfo mirrors the Win32 EXCEPINFO structure.
+type excepInfo struct {
+	wCode             uint16
+	wReserved         uint16
+	bstrSource        *uint16
+	bstrDescription   *uint16
+	bstrHelpFile      *uint16
+	dwHelpContext     uint32
+	pvReserved        uintptr
+	pfnDeferredFillIn uintptr
+	scode             uint32
+}
+
+const (
+	psdInThousandthsOfInches = 0x00000004
+	psdMargins               = 0x00000002
+	psdReturnDefault         = 0x00000400
+)
+
+// pageSetupDlg mirrors the Win32 PAGESETUPDLG structure.
+type pageSetupDlg struct {
+	lStructSize             uint32
+	hwndOwner               win.HWND
+	hDevMode                win.HGLOBAL
+	hDevNames               win.HGLOBAL
+	flags                   uint32
+	ptPaperSize             win.POINT
+	rtMinMargin             win.RECT
+	rtMargin                win.RECT
+	hInstance               win.HINSTANCE
+	lCustData               uintptr
+	lpfnPageSetupHook       uintptr
+	lpfnPagePaintHook       uintptr
+	lpPageSetupTemplateName *uint16
+	hPageSetupTemplate      win.HGLOBAL
+}
+
+const spiGetClientAreaAnimation = 0x1042
+
+const (
+	ttdtAutopop = 2
+	ttdtInitial = 3
+)
+
+const ttnShow = ^uint32(520) // TTN_FIRST - 1
+
+const (
+	loadLibraryAsDatafile      = 0x00000002
+	loadLibraryAsImageResource = 0x00000020
+)
+
+const rtGroupIcon = 14
+
+const (
+	dwmwaUseImmersiveDarkModeBefore20H1 = 19
+	dwmwaUseImmersiveDarkMode           = 20
+)
+
+const (
+	odtMenu   = 1
+	odsGrayed = 0x0002
+)
+
+// hbmMenuCallback is HBMMENU_CALLBACK, which makes a menu item bitmap owner
+// drawn.
+const hbmMenuCallback = ^win.HBITMAP(0)
+
+const (
+	emShowBalloonTip = 0x1503
+	emHideBalloonTip = 0x1504
+)
+
+// editBalloonTip mirrors the Win32 EDITBALLOONTIP structure.
+type editBalloonTip struct {
+	cbStruct uint32
+	pszTitle *uint16
+	pszText  *uint16
+	ttiIcon  int32
+}
+
+const (
+	tbmSetTic         = win.WM_USER + 4
+	tbmClearTics      = win.WM_USER + 9
+	tbmGetThumbRect   = win.WM_USER + 25
+	tbmGetChannelRect = win.WM_USER + 26
+)
+
+const (
+	pbmSetState = win.WM_USER + 16
+	pbmGetState = win.WM_USER + 17
+)
+
+const (
+	pbstNormal = 0x0001
+	pbstError  = 0x0002
+	pbstPaused = 0x0003
+)
+
+// buttonImageList mirrors the Win32 BUTTON_IMAGELIST structure.
+type buttonImageList struct {
+	himl   win.HIMAGELIST
+	margin win.RECT
+	uAlign uint32
+}
+
+const (
+	buttonImageListAlignLeft   = 0
+	buttonImageListAlignRight  = 1
+	buttonImageListAlignTop    = 2
+	buttonImageListAlignBottom = 3
+)
+
+const (
+	cbSetCueBanner = 0x1703
+	cbGetCueBanner = 0x1704
+)
+
+const (
+	lvfiString  = 0x0002
+	lvfiPartial = 0x0008
+	lvfiWrap    = 0x0020
+)
+
+// lvFindInfo mirrors the Win32 LVFINDINFO structure.
+type lvFindInfo struct {
+	flags       uint32
+	psz         *uint16
+	lParam      uintptr
+	pt          win.POINT
+	vkDirection uint32
+}
+
+// nmlvFindItem mirrors the Win32 NMLVFINDITEM structure.
+type nmlvFindItem struct {
+	hdr    win.NMHDR
+	iStart int32
+	lvfi   lvFindInfo
+}
+
+// nmtvItemChange mirrors the Win32 NMTVITEMCHANGE structure.
+type nmtvItemChange struct {
+	hdr       win.NMHDR
+	uChanged  uint32
+	hItem     win.HTREEITEM
+	uStateNew uint32
+	uStateOld uint32
+	lParam    uintptr
+}
+
+// nmlvGetInfoTip mirrors the Win32 NMLVGETINFOTIP structure.
+type nmlvGetInfoTip struct {
+	hdr        win.NMHDR
+	dwFlags    uint32
+	pszText    *uint16
+	cchTextMax int32
+	iItem      int32
+	iSubItem   int32
+	lParam     uintptr
+}
+
+// xform mirrors the Win32 XFORM structure.
+type xform struct {
+	eM11 float32
+	eM12 float32
+	eM21 float32
+	eM22 float32
+	eDx  float32
+	eDy  float32
+}
+
+func arc(hdc win.HDC, left, top, right, bottom, xStart, yStart, xEnd, yEnd int32) bool {
+	ret, _, _ := syscall.Syscall9(procArc.Addr(), 9,
+		uintptr(hdc),
+		uintptr(left),
+		uintptr(top),
+		uintptr(right),
+		uintptr(bottom),
+		uintptr(xStart),
+		uintptr(yStart),
+		uintptr(xEnd),
+		uintptr(yEnd))
+
+	return ret != 0
+}
+
+func pie(hdc win.HDC, left, top, right, bottom, xStart, yStart, xEnd, yEnd int32) bool {
+	ret, _, _ := syscall.Syscall9(procPie.Addr(), 9,
+		uintptr(hdc),
+		uintptr(left),
+		uintptr(top),
+		uintptr(right),
+		uintptr(bottom),
+		uintptr(xStart),
+		uintptr(yStart),
+		uintptr(xEnd),
+		uintptr(yEnd))
+
+	return ret != 0
+}
+
+func setArcDirection(hdc win.HDC, dir int32) int32 {
+	ret, _, _ := syscall.Syscall(procSetArcDirection.Addr(), 2,
+		uintptr(hdc),
+		uintptr(dir),
+		0)
+
+	return int32(ret)
+}
+
+func beginPath(hdc win.HDC) bool {
+	ret, _, _ := syscall.Syscall(procBeginPath.Addr(), 1,
+		uintptr(hdc),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func closeFigure(hdc win.HDC) bool {
+	ret, _, _ := syscall.Syscall(procCloseFigure.Addr(), 1,
+		uintptr(hdc),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func getClipBox(hdc win.HDC, rect *win.RECT) int32 {
+	ret, _, _ := syscall.Syscall(procGetClipBox.Addr(), 2,
+		uintptr(hdc),
+		uintptr(unsafe.Pointer(rect)),
+		0)
+
+	return int32(ret)
+}
+
+func endPath(hdc win.HDC) bool {
+	ret, _, _ := syscall.Syscall(procEndPath.Addr(), 1,
+		uintptr(hdc),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func fillPath(hdc win.HDC) bool {
+	ret, _, _ := syscall.Syscall(procFillPath.Addr(), 1,
+		uintptr(hdc),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func polyBezierTo(hdc win.HDC, lppt *win.POINT, cPoints uint32) bool {
+	ret, _, _ := syscall.Syscall(procPolyBezierTo.Addr(), 3,
+		uintptr(hdc),
+		uintptr(unsafe.Pointer(lppt)),
+		uintptr(cPoints))
+
+	return ret != 0
+}
+
+func polygon(hdc win.HDC, lppt *win.POINT, cPoints int32) bool {
+	ret, _, _ := syscall.Syscall(procPolygon.Addr(), 3,
+		uintptr(hdc),
+		uintptr(unsafe.Pointer(lppt)),
+		uintptr(cPoints))
+
+	return ret != 0
+}
+
+func setPolyFillMode(hdc win.HDC, iPolyFillMode int32) int32 {
+	ret, _, _ := syscall.Syscall(procSetPolyFillMode.Addr(), 2,
+		uintptr(hdc),
+		uintptr(iPolyFillMode),
+		0)
+
+	return int32(ret)
+}
+
+func strokePath(hdc win.HDC) bool {
+	ret, _, _ := syscall.Syscall(procStrokePath.Addr(), 1,
+		uintptr(hdc),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func getWorldTransform(hdc win.HDC, lpxf *xform) bool {
+	ret, _, _ := syscall.Syscall(procGetWorldTransform.Addr(), 2,
+		uintptr(hdc),
+		uintptr(unsafe.Pointer(lpxf)),
+		0)
+
+	return ret != 0
+}
+
+func setGraphicsMode(hdc win.HDC, iMode int32) int32 {
+	ret, _, _ := syscall.Syscall(procSetGraphicsMode.Addr(), 2,
+		uintptr(hdc),
+		uintptr(iMode),
+		0)
+
+	return int32(ret)
+}
+
+func setWorldTransform(hdc win.HDC, lpxf *xform) bool {
+	ret, _, _ := syscall.Syscall(procSetWorldTransform.Addr(), 2,
+		uintptr(hdc),
+		uintptr(unsafe.Pointer(lpxf)),
+		0)
+
+	return ret != 0
+}
+
+func extractIconEx(lpszFile *uint16, nIconIndex int32, phiconLarge, phiconSmall *win.HICON, nIcons uint32) uint32 {
+	ret, _, _ := syscall.Syscall6(procExtractIconEx.Addr(), 5,
+		uintptr(unsafe.Pointer(lpszFile)),
+		uintptr(nIconIndex),
+		uintptr(unsafe.Pointer(phiconLarge)),
+		uintptr(unsafe.Pointer(phiconSmall)),
+		uintptr(nIcons),
+		0)
+
+	return uint32(ret)
+}
+
+func imageListGetImageCount(hIml win.HIMAGELIST) int32 {
+	ret, _, _ := syscall.Syscall(procImageListGetImageCount.Addr(), 1,
+		uintptr(hIml),
+		0,
+		0)
+
+	return int32(ret)
+}
+
+func imageListRemove(hIml win.HIMAGELIST, i int32) bool {
+	ret, _, _ := syscall.Syscall(procImageListRemove.Addr(), 2,
+		uintptr(hIml),
+		uintptr(i),
+		0)
+
+	return ret != 0
+}
+
+func getWindowDC(hWnd win.HWND) win.HDC {
+	ret, _, _ := syscall.Syscall(procGetWindowDC.Addr(), 1,
+		uintptr(hWnd),
+		0,
+		0)
+
+	return win.HDC(ret)
+}
+
+func monitorFromRect(lprc *win.RECT, dwFlags uint32) win.HMONITOR {
+	ret, _, _ := syscall.Syscall(procMonitorFromRect.Addr(), 2,
+		uintptr(unsafe.Pointer(lprc)),
+		uintptr(dwFlags),
+		0)
+
+	return win.HMONITOR(ret)
+}
+
+func postThreadMessage(threadID uint32, msg uint32, wParam, lParam uintptr) bool {
+	ret, _, _ := syscall.Syscall6(procPostThreadMessage.Addr(), 4,
+		uintptr(threadID),
+		uintptr(msg),
+		wParam,
+		lParam,
+		0,
+		0)
+
+	return ret != 0
+}
+
+func setLayeredWindowAttributes(hwnd win.HWND, crKey win.COLORREF, bAlpha byte, dwFlags uint32) bool {
+	ret, _, _ := syscall.Syscall6(procSetLayeredWindowAttributes.Addr(), 4,
+		uintptr(hwnd),
+		uintptr(crKey),
+		uintptr(bAlpha),
+		uintptr(dwFlags),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func pageSetupDialog(lppsd *pageSetupDlg) bool {
+	ret, _, _ := syscall.Syscall(procPageSetupDlg.Addr(), 1,
+		uintptr(unsafe.Pointer(lppsd)),
+		0,
+		0)
+
+	return ret != 0
+}
+
+func dwmSetWindowAttribute(hwnd win.HWND, dwAttribute uint32, pvAttribute unsafe.Pointer, cbAttribute uint32) bool {
+	// dwmapi.dll is missing from some Windows editions.
+	if procDwmSetWindowAttribute.Find() != nil {
+		return false
+	}
+
+	ret, _, _ := syscall.Syscall6(procDwmSetWindowAttribute.Addr(), 4,
+		uintptr(hwnd),
+		uintptr(dwAttribute),
+		uintptr(pvAttribute),
+		uintptr(cbAttribute),
+		0,
+		0)
+
+	return win.SUCCEEDED(win.HRESULT(ret))
+}
+
+func registerHotKey(hwnd win.HWND, id int32, fsModifiers, vk uint32) (bool, syscall.Errno) {
+	ret, _, errno := syscall.Syscall6(procRegisterHotKey.Addr(), 4,
+		uintptr(hwnd),
+		uintptr(id),
+		uintptr(fsModifiers),
+		uintptr(vk),
+		0,
+		0)
+
+	return ret != 0, errno
+}
+
+func unregisterHotKey(hwnd win.HWND, id int32) bool {
+	ret, _, _ := syscall.Syscall(procUnregisterHotKey.Addr(), 2,
+		uintptr(hwnd),
+		uintptr(id),
+		0)
+
+	return ret != 0
+}
+
+func enumResourceNames(hModule win.HMODULE, lpType *uint16, lpEnumFunc, lParam uintptr) bool {
+	ret, _, _ := syscall.Syscall6(procEnumResourceNames.Addr(), 4,
+		uintptr(hModule),
+		uintptr(unsafe.Pointer(lpType)),
+		lpEnumFunc,
+		lParam,
+		0,
+		0)
+
+	return ret != 0
+}
+
+func globalSize(hMem win.HGLOBAL) uintptr {
+	ret, _, _ := syscall.Syscall(procGlobalSize.Addr(), 1,
+		uintptr(hMem),
+		0,
+		0)
+
+	return ret
+}
+
+func registerClipboardFormat(lpszFormat *uint16) uint32 {
+	ret, _, _ := syscall.Syscall(procRegisterClipboardFormat.Addr(), 1,
+		uintptr(unsafe.Pointer(lpszFormat)),
+		0,
+		0)
+
+	return uint32(ret)
+}
+
+func registerDragDrop(hwnd win.HWND, pDropTarget unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procRegisterDragDrop.Addr(), 2,
+		uintptr(hwnd),
+		uintptr(pDropTarget),
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func revokeDragDrop(hwnd win.HWND) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procRevokeDragDrop.Addr(), 1,
+		uintptr(hwnd),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func releaseStgMedium(medium *stgMedium) {
+	syscall.Syscall(procReleaseStgMedium.Addr(), 1,
+		uintptr(unsafe.Pointer(medium)),
+		0,
+		0)
+}
+
+func doDragDrop(pDataObj, pDropSource unsafe.Pointer, dwOKEffects uint32, pdwEffect *uint32) win.HRESULT {
+	ret, _, _ := syscall.Syscall6(procDoDragDrop.Addr(), 4,
+		uintptr(pDataObj),
+		uintptr(pDropSource),
+		uintptr(dwOKEffects),
+		uintptr(unsafe.Pointer(pdwEffect)),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func shCreateStdEnumFmtEtc(cfmt uint32, afmt *formatEtc, ppenumFormatEtc *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procSHCreateStdEnumFmtEtc.Addr(), 3,
+		uintptr(cfmt),
+		uintptr(unsafe.Pointer(afmt)),
+		uintptr(unsafe.Pointer(ppenumFormatEtc)))
+
+	return win.HRESULT(ret)
+}
+
+func setCurrentProcessExplicitAppUserModelID(appID *uint16) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procSetCurrentProcessExplicitAppUserModelID.Addr(), 1,
+		uintptr(unsafe.Pointer(appID)),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func roInitialize(initType uint32) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procRoInitialize.Addr(), 1,
+		uintptr(initType),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func roGetActivationFactory(activatableClassId hstring, iid *win.IID, factory *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procRoGetActivationFactory.Addr(), 3,
+		uintptr(activatableClassId),
+		uintptr(unsafe.Pointer(iid)),
+		uintptr(unsafe.Pointer(factory)))
+
+	return win.HRESULT(ret)
+}
+
+func roActivateInstance(activatableClassId hstring, instance *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procRoActivateInstance.Addr(), 2,
+		uintptr(activatableClassId),
+		uintptr(unsafe.Pointer(instance)),
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func windowsCreateString(sourceString *uint16, length uint32, str *hstring) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procWindowsCreateString.Addr(), 3,
+		uintptr(unsafe.Pointer(sourceString)),
+		uintptr(length),
+		uintptr(unsafe.Pointer(str)))
+
+	return win.HRESULT(ret)
+}
+
+func windowsDeleteString(str hstring) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procWindowsDeleteString.Addr(), 1,
+		uintptr(str),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
+
+func windowsGetStringRawBuffer(str hstring, length *uint32) *uint16 {
+	ret, _, _ := syscall.Syscall(procWindowsGetStringRawBuffer.Addr(), 2,
+		uintptr(str),
+		uintptr(unsafe.Pointer(length)),
+		0)
+
+	return *(**uint16)(unsafe.Pointer(&ret))
+}
+
+func createStreamOnHGlobal(hGlobal win.HGLOBAL, fDeleteOnRelease bool, ppstm *unsafe.Pointer) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procCreateStreamOnHGlobal.Addr(), 3,
+		uintptr(hGlobal),
+		uintptr(win.BoolToBOOL(fDeleteOnRelease)),
+		uintptr(unsafe.Pointer(ppstm)))
+
+	return win.HRESULT(ret)
+}
+
+func variantClear(pvarg *win.VARIANT) win.HRESULT {
+	ret, _, _ := syscall.Syscall(procVariantClear.Addr(), 1,
+		uintptr(unsafe.Pointer(pvarg)),
+		0,
+		0)
+
+	return win.HRESULT(ret)
+}
@@ -450,6 +450,11 @@ type WindowBase struct {
 	disposables               []Disposable
 	disposingPublisher        EventPublisher
 	dropFilesPublisher        DropFilesEventPublisher
+	dropTarget                *dropTarget
+	dragEnterPublisher        DragEventPublisher
+	dragOverPublisher         DragEventPublisher
+	dragLeavePublisher        EventPublisher
+	dropPublisher             DragEventPublisher
 	keyDownPublisher          KeyEventPublisher
 	keyPressPublisher         KeyEventPublisher
 	keyUpPublisher            KeyEventPublisher
@@ -1457,6 +1462,11 @@ func (wb *WindowBase) SetVisible(visible bool) {
 	if widget, ok := wb.window.(Widget); ok {
 		wb := widget.AsWidgetBase()
 		wb.invalidateBorderInParent()
+		if wb.parent != nil {
+			if cb := wb.parent.AsContainerBase(); cb != nil {
+				cb.requestLayoutAnimation()
+			}
+		}
 		wb.RequestLayout()
 	}
 
@@ -2373,9 +2383,15 @@ func (wb *WindowBase) handleWMCTLCOLOR(wParam, lParam uintptr) uintptr {
 	} else if tc, ok := wnd.(TextColorer); ok {
 		color := tc.TextColor()
 		if color == 0 {
-			color = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+			if darkModeEnabledForWindow(wnd) {
+				color = darkModeTextColor
+			} else {
+				color = Color(win.GetSysColor(win.COLOR_WINDOWTEXT))
+			}
 		}
 		win.SetTextColor(hdc, win.COLORREF(color))
+	} else if darkModeEnabledForWindow(wnd) {
+		win.SetTextColor(hdc, win.COLORREF(darkModeTextColor))
 	}
 
 	if bg, wnd := wnd.AsWindowBase().backgroundEffective(); bg != nil {
@@ -2394,6 +2410,12 @@ func (wb *WindowBase) handleWMCTLCOLOR(wParam, lParam uintptr) uintptr {
 
 	switch wnd.(type) {
 	case *LineEdit, *numberLineEdit, *TextEdit:
+		if darkModeEnabledForWindow(wnd) && darkModeControlBrush != nil {
+			win.SetBkColor(hdc, win.COLORREF(darkModeControlColor))
+
+			return uintptr(darkModeControlBrush.handle())
+		}
+
 		type ReadOnlyer interface {
 			ReadOnly() bool
 		}
@@ -2453,6 +2475,13 @@ func (wb *WindowBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 			return window.WndProc(hwnd, msg, wParam, lParam)
 		}
 
+	case win.WM_NOTIFY:
+		nmh := (*win.NMHDR)(unsafe.Pointer(lParam))
+		if tt, ok := windowFromHandle(nmh.HwndFrom).(*ToolTip); ok && nmh.HwndFrom != hwnd {
+			// A ToolTip notifies its tools, but shall handle it itself.
+			return tt.WndProc(hwnd, msg, wParam, lParam)
+		}
+
 	case win.WM_LBUTTONDOWN, win.WM_MBUTTONDOWN, win.WM_RBUTTONDOWN:
 		if msg == win.WM_LBUTTONDOWN && wb.origWndProcPtr == 0 {
 			// Only call SetCapture if this is no subclassed control.
@@ -2547,6 +2576,19 @@ func (wb *WindowBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 			return 0
 		}
 
+	case win.WM_INITMENUPOPUP:
+		handleInitMenuPopup(win.HMENU(wParam), wb.window)
+
+	case win.WM_MEASUREITEM:
+		if measureMenuItemImage((*win.MEASUREITEMSTRUCT)(unsafe.Pointer(lParam))) {
+			return win.TRUE
+		}
+
+	case win.WM_DRAWITEM:
+		if drawMenuItemImage((*win.DRAWITEMSTRUCT)(unsafe.Pointer(lParam))) {
+			return win.TRUE
+		}
+
 	case win.WM_KEYDOWN:
 		wb.handleKeyDown(wParam, lParam)
 
@@ -2585,6 +2627,8 @@ func (wb *WindowBase) WndProc(hwnd win.HWND, msg uint32, wParam, lParam uintptr)
 		wb.window.(ApplySysColorser).ApplySysColors()
 
 	case win.WM_DESTROY:
+		wb.revokeDropTarget()
+
 		if wb.origWndProcPtr != 0 {
 			// As we subclass all windows of system classes, we prevented the
 			// clean-up code in the WM_NCDESTROY handlers of some windows from
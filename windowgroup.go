@@ -7,7 +7,12 @@
 package walk
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
+
+	"github.com/lxn/win"
 )
 
 // The global window group manager instance.
@@ -84,28 +89,37 @@ func (m *windowGroupManager) removeGroup(threadID uint32) {
 // the group. When the number of references reaches zero, the
 // group is disposed of.
 type WindowGroup struct {
-	refs       int // Tracks the number of windows that rely on this group
-	ignored    int // Tracks the number of refs created by the group itself
-	threadID   uint32
-	completion func(uint32) // Used to tell the window group manager to remove this group
-	removed    bool         // Has this group been removed from its manager? (used for race detection)
-	toolTip    *ToolTip
-	activeForm Form
+	refs        int // Tracks the number of windows that rely on this group
+	ignored     int // Tracks the number of refs created by the group itself
+	threadID    uint32
+	completion  func(uint32) // Used to tell the window group manager to remove this group
+	removed     bool         // Has this group been removed from its manager? (used for race detection)
+	toolTip     *ToolTip
+	activeForms []Form // Stack of active forms, the top is the currently active one
 
 	syncMutex       sync.Mutex
+	syncDrained     *sync.Cond     // Signaled when RunSynchronized takes the queued functions
 	syncFuncs       []func()       // Functions queued to run on the group's thread
+	syncQueueLimit  int            // Maximum length of syncFuncs, 0 means unlimited
 	layoutResults   []LayoutResult // Layout computations queued for application on the group's thread
 	layoutStopwatch *stopwatch     // Timing information for the layout computations
+
+	lastLayoutDuration     time.Duration // How long applying the most recent layout results took
+	layoutAppliedPublisher DurationEventPublisher
+	disposingPublisher     EventPublisher
 }
 
 // newWindowGroup returns a new window group for the given thread ID.
 //
 // The completion function will be called when the group is disposed of.
 func newWindowGroup(threadID uint32, completion func(uint32)) *WindowGroup {
-	return &WindowGroup{
+	g := &WindowGroup{
 		threadID:   threadID,
 		completion: completion,
 	}
+	g.syncDrained = sync.NewCond(&g.syncMutex)
+
+	return g
 }
 
 // ThreadID identifies the thread that the group is affiliated with.
@@ -145,11 +159,139 @@ func (g *WindowGroup) Done() {
 // Synchronize adds f to the group's function queue, to be executed
 // by the message loop running on the the group's thread.
 //
+// If a queue limit has been set and the queue is full, Synchronize blocks
+// until the group's thread has drained the queue. Calls made by the group's
+// thread itself never block.
+//
 // Synchronize can be called from any thread.
 func (g *WindowGroup) Synchronize(f func()) {
 	g.syncMutex.Lock()
 	defer g.syncMutex.Unlock()
+
+	if g.syncQueueFull() && win.GetCurrentThreadId() != g.threadID {
+		g.wake()
+
+		for g.syncQueueFull() {
+			g.syncDrained.Wait()
+		}
+	}
+
+	g.syncFuncs = append(g.syncFuncs, f)
+}
+
+// SynchronizeTry is like Synchronize, but instead of blocking it returns
+// ErrSyncQueueFull if the queue limit has been reached.
+//
+// SynchronizeTry can be called from any thread.
+func (g *WindowGroup) SynchronizeTry(f func()) error {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	if g.syncQueueFull() {
+		return ErrSyncQueueFull
+	}
+
 	g.syncFuncs = append(g.syncFuncs, f)
+
+	return nil
+}
+
+// SynchronizeContext is like Synchronize, but f is skipped if ctx has been
+// canceled or has expired by the time the message loop gets to run it.
+//
+// SynchronizeContext can be called from any thread.
+func (g *WindowGroup) SynchronizeContext(ctx context.Context, f func()) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	g.Synchronize(func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		f()
+	})
+}
+
+// SyncQueueLimit returns the maximum number of functions that may be queued
+// by Synchronize. A value of 0 means unlimited, which is the default.
+func (g *WindowGroup) SyncQueueLimit() int {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	return g.syncQueueLimit
+}
+
+// SetSyncQueueLimit sets the maximum number of functions that may be queued
+// by Synchronize. A value of 0 means unlimited.
+func (g *WindowGroup) SetSyncQueueLimit(n int) {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	g.syncQueueLimit = n
+
+	g.syncDrained.Broadcast()
+}
+
+// QueueLength returns the number of functions currently queued by
+// Synchronize.
+//
+// QueueLength can be called from any thread.
+func (g *WindowGroup) QueueLength() int {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	return len(g.syncFuncs)
+}
+
+// syncQueueFull must be called with syncMutex held.
+func (g *WindowGroup) syncQueueFull() bool {
+	return g.syncQueueLimit > 0 && len(g.syncFuncs) >= g.syncQueueLimit
+}
+
+// SynchronizeWait adds f to the group's function queue and blocks until the
+// message loop running on the group's thread has executed it.
+//
+// SynchronizeWait returns the error returned by f, or an error describing
+// the panic, if f panicked.
+//
+// If SynchronizeWait is called by the group's thread, f is run immediately.
+func (g *WindowGroup) SynchronizeWait(f func() error) error {
+	if win.GetCurrentThreadId() == g.threadID {
+		return callRecovered(f)
+	}
+
+	done := make(chan error, 1)
+
+	g.Synchronize(func() {
+		done <- callRecovered(f)
+	})
+
+	g.wake()
+
+	return <-done
+}
+
+// wake posts a message to the group's thread, so its message loop runs the
+// queued functions even if no window of the group receives a message.
+func (g *WindowGroup) wake() {
+	postThreadMessage(g.threadID, syncMsgId, 0, 0)
+}
+
+// callRecovered calls f, turning a panic into an error.
+func callRecovered(f func() error) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			if e, ok := x.(error); ok {
+				err = wrapErrorNoPanic(e)
+			} else {
+				err = newErrorNoPanic(fmt.Sprint(x))
+			}
+		}
+	}()
+
+	return f()
 }
 
 // SynchronizeLayout causes the given layout computations to be applied
@@ -166,6 +308,44 @@ func (g *WindowGroup) SynchronizeLayout(results []LayoutResult, stopwatch *stopw
 	g.syncMutex.Unlock()
 }
 
+// CancelSynchronizedLayout discards any layout computations queued by
+// SynchronizeLayout that have not yet been applied.
+//
+// CancelSynchronizedLayout can be called from any thread.
+func (g *WindowGroup) CancelSynchronizedLayout() {
+	g.syncMutex.Lock()
+	g.layoutResults = nil
+	g.layoutStopwatch = nil
+	g.syncMutex.Unlock()
+}
+
+// cancelSynchronizedLayoutOf removes the layout computations for the window
+// with handle hwnd, as a container or as an item, from those queued by
+// SynchronizeLayout, keeping the ones for other windows.
+func (g *WindowGroup) cancelSynchronizedLayoutOf(hwnd win.HWND) {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	var results []LayoutResult
+	for _, result := range g.layoutResults {
+		if result.container.Handle() == hwnd {
+			continue
+		}
+
+		var items []LayoutResultItem
+		for _, item := range result.items {
+			if item.Item.Handle() != hwnd {
+				items = append(items, item)
+			}
+		}
+		result.items = items
+
+		results = append(results, result)
+	}
+
+	g.layoutResults = results
+}
+
 // RunSynchronized runs all of the function calls queued by Synchronize
 // and applies any layout changes queued by SynchronizeLayoutResults.
 //
@@ -179,16 +359,54 @@ func (g *WindowGroup) RunSynchronized() {
 	g.syncFuncs = nil
 	g.layoutResults = nil
 	g.layoutStopwatch = nil
+	g.syncDrained.Broadcast()
 	g.syncMutex.Unlock()
 
 	if len(results) > 0 {
+		started := time.Now()
+
 		applyLayoutResults(results, stopwatch)
+
+		duration := time.Since(started)
+
+		g.syncMutex.Lock()
+		g.lastLayoutDuration = duration
+		g.syncMutex.Unlock()
+
+		g.layoutAppliedPublisher.Publish(duration)
 	}
 	for _, f := range funcs {
 		f()
 	}
 }
 
+// LastLayoutDuration returns how long applying the most recent layout
+// results took.
+//
+// LastLayoutDuration can be called from any thread.
+func (g *WindowGroup) LastLayoutDuration() time.Duration {
+	g.syncMutex.Lock()
+	defer g.syncMutex.Unlock()
+
+	return g.lastLayoutDuration
+}
+
+// LayoutApplied returns the event that is published by RunSynchronized after
+// queued layout results have been applied, carrying how long that took.
+func (g *WindowGroup) LayoutApplied() *DurationEvent {
+	return g.layoutAppliedPublisher.Event()
+}
+
+// Disposing returns the event that is published when the group is about to
+// be disposed of, before its tool tip is destroyed.
+//
+// Handlers run on the group's thread. A panicking handler does not keep the
+// remaining handlers from running or the group from being disposed of; the
+// panic is reported through Application.Panicking instead.
+func (g *WindowGroup) Disposing() *Event {
+	return g.disposingPublisher.Event()
+}
+
 // ToolTip returns the tool tip control for the group, if one exists.
 func (g *WindowGroup) ToolTip() *ToolTip {
 	return g.toolTip
@@ -227,12 +445,52 @@ func (g *WindowGroup) CreateToolTip() (*ToolTip, error) {
 // ActiveForm returns the currently active form for the group. If no
 // form is active it returns nil.
 func (g *WindowGroup) ActiveForm() Form {
-	return g.activeForm
+	if len(g.activeForms) == 0 {
+		return nil
+	}
+
+	return g.activeForms[len(g.activeForms)-1]
 }
 
 // SetActiveForm updates the currently active form for the group.
+//
+// It replaces the top of the active form stack, leaving forms pushed
+// by enclosing modal dialogs untouched.
 func (g *WindowGroup) SetActiveForm(form Form) {
-	g.activeForm = form
+	if len(g.activeForms) == 0 {
+		if form != nil {
+			g.activeForms = append(g.activeForms, form)
+		}
+		return
+	}
+
+	g.activeForms[len(g.activeForms)-1] = form
+}
+
+// PushActiveForm makes form the active form of the group, remembering the
+// previously active form so it can be restored by PopActiveForm.
+func (g *WindowGroup) PushActiveForm(form Form) {
+	g.activeForms = append(g.activeForms, form)
+}
+
+// PopActiveForm removes the top of the active form stack and returns it.
+//
+// The form that was active before the matching PushActiveForm call is
+// activated again, which restores its focused widget.
+func (g *WindowGroup) PopActiveForm() Form {
+	if len(g.activeForms) == 0 {
+		return nil
+	}
+
+	top := g.activeForms[len(g.activeForms)-1]
+	g.activeForms[len(g.activeForms)-1] = nil
+	g.activeForms = g.activeForms[:len(g.activeForms)-1]
+
+	if prev := g.ActiveForm(); prev != nil && !prev.IsDisposed() {
+		win.SetActiveWindow(prev.Handle())
+	}
+
+	return top
 }
 
 // ignore changes the number of references that the group will ignore.
@@ -256,6 +514,24 @@ func (g *WindowGroup) ignore(delta int) {
 
 // dispose releases any resources consumed by the group.
 func (g *WindowGroup) dispose() {
+	if win.GetCurrentThreadId() != g.threadID {
+		panic("walk: WindowGroup disposed of on a thread other than its own")
+	}
+
+	for _, handler := range g.disposingPublisher.event.handlers {
+		if handler == nil {
+			continue
+		}
+
+		err := callRecovered(func() error {
+			handler()
+			return nil
+		})
+		if err != nil && len(App().panickingPublisher.event.handlers) > 0 {
+			App().panickingPublisher.Publish(err)
+		}
+	}
+
 	if g.toolTip != nil {
 		g.toolTip.Dispose()
 		g.toolTip = nil